@@ -18,7 +18,8 @@ func NewCmd(app *application.App) *cobra.Command {
 		Example: app.Example(`  $ {cmd} new
   $ {cmd} add <URL>
   $ {cmd} new <URL> --title <title>
-  $ {cmd} new <URL> --title <title> --tags <golang,awesome>`),
+  $ {cmd} new <URL> --title <title> --tags <golang,awesome>
+  $ {cmd} new <URL> --private`),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			d, cancel, err := cmdutil.SetupDeps(cmd, &args)
 			if err != nil {
@@ -0,0 +1,39 @@
+// Package browse navigates the bookmarks by tags.
+package browse
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/mateconpizza/gm/cmd/cmdutil"
+	"github.com/mateconpizza/gm/internal/application"
+	"github.com/mateconpizza/gm/internal/cli"
+	"github.com/mateconpizza/gm/internal/handler"
+	"github.com/mateconpizza/gm/internal/ui/formatter"
+)
+
+func NewCmd(app *application.App) *cobra.Command {
+	c := &cobra.Command{
+		Use:     "browse [query]",
+		Aliases: []string{"b"},
+		Short:   "browse bookmarks by tags",
+		Long: `browse bookmarks by tags.
+
+Pick one or more tags to list the bookmarks tagged with all of them. Leaving
+the list shows the tags of those bookmarks to narrow them down further, esc
+goes back up one level.`,
+		Example: app.Example(`  $ {cmd} browse
+  $ {cmd} browse <query>
+  $ {cmd} browse --tag golang
+  $ {cmd} browse --sort favorite`),
+		Annotations: cli.SkipGitSync,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmdutil.Execute(cmd, args, nil, handler.Browse(args))
+		},
+	}
+
+	cmdutil.FlagSort(c, app, handler.SortSupported)
+	cmdutil.FlagsFilter(c, app)
+	cmdutil.FlagOutput(c, app, app.Format, formatter.ValidFormats())
+
+	return c
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 
-	menu "github.com/mateconpizza/go-fzf"
 	"github.com/spf13/cobra"
 
 	"github.com/mateconpizza/gm/internal/application"
@@ -41,7 +40,10 @@ func SetupDeps(cmd *cobra.Command, args *[]string) (*deps.Deps, func(), error) {
 		return nil, nil, err
 	}
 
-	terminal.ReadPipedInput(args)
+	// the menu source runs inside the menu, its stdin is not a query
+	if !app.Flags.MenuSource {
+		terminal.ReadPipedInput(args)
+	}
 
 	c := ui.NewDefaultConsole(func(err error) {
 		r.Close()
@@ -57,7 +59,7 @@ func SetupDeps(cmd *cobra.Command, args *[]string) (*deps.Deps, func(), error) {
 	return d, r.Close, nil
 }
 
-func Execute(cmd *cobra.Command, args []string, m *menu.Menu[bookmark.Bookmark], action BookmarkAction, filters ...Filter) error {
+func Execute(cmd *cobra.Command, args []string, m *picker.Menu[bookmark.Bookmark], action BookmarkAction, filters ...Filter) error {
 	d, cleanup, err := SetupDeps(cmd, &args)
 	if err != nil {
 		return err
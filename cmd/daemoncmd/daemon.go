@@ -0,0 +1,105 @@
+// Package daemoncmd provides the Cobra subcommands for running the background
+// sync daemon.
+package daemoncmd
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mateconpizza/gm/internal/application"
+	"github.com/mateconpizza/gm/internal/cli"
+	"github.com/mateconpizza/gm/internal/daemon"
+	"github.com/mateconpizza/gm/internal/ui"
+)
+
+// NewCmd is the daemon command.
+func NewCmd(app *application.App) *cobra.Command {
+	c := &cobra.Command{
+		Use:         "daemon",
+		Short:       "watch the database, commit and push changes",
+		Annotations: cli.SkipGitSync,
+		Example: app.Example(`  $ {cmd} daemon &
+  $ {cmd} daemon --debounce 10s --pull 5m --status 24h &
+  $ {cmd} daemon status
+  $ {cmd} daemon stop`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg := app.Daemon
+			d := daemon.New(app, daemon.Options{
+				Debounce:        durationOr(app.Flags.Debounce, cfg.Debounce, daemon.DefaultDebounce),
+				PullInterval:    durationOr(app.Flags.PullInterval, cfg.PullInterval, daemon.DefaultPullInterval),
+				StatusInterval:  durationOr(app.Flags.StatusInterval, cfg.StatusInterval, 0),
+				FaviconInterval: durationOr(app.Flags.FaviconInterval, cfg.FaviconInterval, 0),
+			})
+
+			return d.Run(cmd.Context())
+		},
+	}
+
+	f := c.Flags()
+	f.DurationVar(&app.Flags.Debounce, "debounce", 0,
+		fmt.Sprintf("wait after the last change before syncing (default %s)", daemon.DefaultDebounce))
+	f.DurationVar(&app.Flags.PullInterval, "pull", 0,
+		fmt.Sprintf("pull remote changes every (default %s)", daemon.DefaultPullInterval))
+	f.DurationVar(&app.Flags.StatusInterval, "status", 0, "check bookmarks status every (default disabled)")
+	f.DurationVar(&app.Flags.FaviconInterval, "favicon", 0, "fetch missing favicons every (default disabled)")
+
+	c.AddCommand(
+		newStatusCmd(app),
+		newStopCmd(app),
+	)
+
+	return c
+}
+
+func newStatusCmd(app *application.App) *cobra.Command {
+	return &cobra.Command{
+		Use:         "status",
+		Short:       "show the daemon state",
+		Annotations: cli.ChainAnnotations(cli.SkipDBCheck, cli.SkipGitSync),
+		Example:     app.Example(`  $ {cmd} daemon status`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := ui.DefaultConsole
+			err := daemon.PrintStatus(c, app.Path.DaemonState())
+			if errors.Is(err, daemon.ErrNotRunning) {
+				fmt.Fprintln(c.Writer(), c.InfoMesg("daemon is not running"))
+				return nil
+			}
+
+			return err
+		},
+	}
+}
+
+func newStopCmd(app *application.App) *cobra.Command {
+	return &cobra.Command{
+		Use:         "stop",
+		Short:       "stop the running daemon",
+		Annotations: cli.ChainAnnotations(cli.SkipDBCheck, cli.SkipGitSync),
+		Example:     app.Example(`  $ {cmd} daemon stop`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := daemon.Stop(app.Path.DaemonState()); err != nil {
+				return err
+			}
+
+			fmt.Fprintln(ui.DefaultConsole.Writer(), ui.DefaultConsole.SuccessMesg("daemon stopped"))
+
+			return nil
+		},
+	}
+}
+
+// durationOr returns the flag value, the config value or def, whichever is
+// set first.
+func durationOr(flag, cfg, def time.Duration) time.Duration {
+	switch {
+	case flag != 0:
+		return flag
+	case cfg != 0:
+		return cfg
+	default:
+		return def
+	}
+}
@@ -0,0 +1,113 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	files "github.com/mateconpizza/gofiles"
+	"github.com/spf13/cobra"
+
+	"github.com/mateconpizza/gm/internal/application"
+	"github.com/mateconpizza/gm/internal/cli"
+	"github.com/mateconpizza/gm/internal/locker/agent"
+	"github.com/mateconpizza/gm/internal/ui"
+)
+
+func newAgentCmd(app *application.App) *cobra.Command {
+	c := &cobra.Command{
+		Use:         "agent",
+		Short:       "unlock agent, caches database keys",
+		Annotations: cli.ChainAnnotations(cli.SkipDBCheck, cli.SkipGitSync),
+	}
+
+	c.AddCommand(
+		newAgentStartCmd(app),
+		newAgentStopCmd(app),
+		newAgentStatusCmd(app),
+	)
+
+	return c
+}
+
+func newAgentStartCmd(app *application.App) *cobra.Command {
+	c := &cobra.Command{
+		Use:   "start",
+		Short: "start the unlock agent in the foreground",
+		Example: app.Example(`  $ {cmd} db agent start &
+  $ {cmd} db agent start --ttl 1h &`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ttl := app.Flags.AgentTTL
+			if ttl == 0 {
+				ttl = app.Locker.TTL()
+			}
+
+			s := agent.NewServer(app.Path.Agent(), ttl)
+			if err := s.Listen(); err != nil {
+				return err
+			}
+
+			go func() {
+				<-cmd.Context().Done()
+				s.Stop()
+			}()
+
+			return s.Serve()
+		},
+	}
+
+	c.Flags().DurationVar(&app.Flags.AgentTTL, "ttl", 0,
+		fmt.Sprintf("key lifetime (default %s)", agent.DefaultTTL))
+
+	return c
+}
+
+func newAgentStopCmd(app *application.App) *cobra.Command {
+	return &cobra.Command{
+		Use:     "stop",
+		Short:   "stop the unlock agent and forget all keys",
+		Example: app.Example(`  $ {cmd} db agent stop`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := agent.NewClient(app.Path.Agent()).Stop(); err != nil {
+				return err
+			}
+
+			fmt.Fprintln(ui.DefaultConsole.Writer(), ui.DefaultConsole.SuccessMesg("agent stopped"))
+
+			return nil
+		},
+	}
+}
+
+func newAgentStatusCmd(app *application.App) *cobra.Command {
+	return &cobra.Command{
+		Use:     "status",
+		Short:   "show the unlock agent cached keys",
+		Example: app.Example(`  $ {cmd} db agent status`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := ui.DefaultConsole
+			entries, err := agent.NewClient(app.Path.Agent()).Status()
+			if errors.Is(err, agent.ErrNotRunning) {
+				fmt.Fprintln(c.Writer(), c.InfoMesg("agent is not running"))
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+
+			if len(entries) == 0 {
+				fmt.Fprintln(c.Writer(), c.InfoMesg("agent running, no cached keys"))
+				return nil
+			}
+
+			for _, e := range entries {
+				name := files.StripExts(filepath.Base(e.DB))
+				left := time.Until(e.Expires).Round(time.Second)
+				fmt.Fprintln(c.Writer(), c.InfoMesg(fmt.Sprintf("%s: expires in %s", name, left)))
+			}
+
+			return nil
+		},
+	}
+}
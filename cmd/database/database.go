@@ -39,6 +39,7 @@ func NewCmd(app *application.App) *cobra.Command {
 		newDropCmd(app),           // most destructive
 		newLockCmd(app),           // restrict access
 		newUnlockCmd(app),         // restore access
+		newAgentCmd(app),          // cache unlock keys
 		newImportCmd(app),         // data in
 		newExportCmd(app),         // data out
 		newReorderCmd(app),        // reorder IDs
@@ -169,8 +170,18 @@ func newLockCmd(app *application.App) *cobra.Command {
 		Short:       "lock a database",
 		Annotations: cli.SkipGitSync,
 		Example: app.Example(`  $ {cmd} db lock --db {db}
-  $ {cmd} db lock --db work`),
+  $ {cmd} db lock --db work
+  $ {cmd} db lock --db work --rekey`),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if app.Flags.Rekey {
+				d := deps.New(
+					deps.WithApplication(app),
+					deps.WithConsole(ui.DefaultConsole),
+				)
+
+				return dbops.Rekey(cmd.Context(), d, app.Path.DB())
+			}
+
 			d, cancel, err := cmdutil.SetupDeps(cmd, &args)
 			if err != nil {
 				return err
@@ -182,6 +193,7 @@ func newLockCmd(app *application.App) *cobra.Command {
 	}
 
 	cmdutil.FlagDBRequired(c, app)
+	c.Flags().BoolVar(&app.Flags.Rekey, "rekey", false, "change the password of a locked database")
 
 	return c
 }
@@ -10,27 +10,53 @@ import (
 
 	"github.com/mateconpizza/gm/cmd/cmdutil"
 	"github.com/mateconpizza/gm/internal/application"
+	"github.com/mateconpizza/gm/internal/bookmark/port"
+	"github.com/mateconpizza/gm/internal/bookmark/vault"
 	"github.com/mateconpizza/gm/internal/deps"
+	"github.com/mateconpizza/gm/internal/gitops"
 	"github.com/mateconpizza/gm/internal/handler"
 	"github.com/mateconpizza/gm/internal/picker"
 	"github.com/mateconpizza/gm/internal/picker/menucfg"
 	"github.com/mateconpizza/gm/internal/ui/formatter"
-	"github.com/mateconpizza/gm/internal/ui/printer"
 	"github.com/mateconpizza/gm/pkg/bookio"
 	"github.com/mateconpizza/gm/pkg/bookmark"
 )
 
+const defaultExportFormat = "json"
+
 func newExportCmd(app *application.App) *cobra.Command {
 	c := &cobra.Command{
 		Use:     "export [id|query]",
 		Short:   "export bookmarks",
 		Aliases: []string{"ex", "x"},
+		Example: app.Example(`  $ {cmd} db export --format html -o bookmarks.html
+  $ {cmd} db export golang --format csv --fields url,title
+  $ {cmd} db export -t rust -o rust.json`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format := app.Flags.Format
+			if format == "" {
+				format = defaultExportFormat
+			}
+
+			m := setupMenu(app, " export to "+strings.ToUpper(format)+" ")
+			return cmdutil.Execute(cmd, args, m, exportAction(app, format, app.Flags.OutFile))
+		},
 	}
 
+	c.Flags().StringVar(&app.Flags.Format, "format", "",
+		"file format: "+strings.Join(bookio.FormatNames(true), ", ")+" (default "+defaultExportFormat+")")
+	c.Flags().StringVarP(&app.Flags.OutFile, "output", "o", "", "output file (default stdout)")
+	cmdutil.FlagSort(c, app, handler.SortSupported)
+	cmdutil.FlagMenu(c, app)
+	cmdutil.FlagsFilter(c, app)
+	cmdutil.FlagFields(c, app, "csv columns: all,"+wrapFields(bookmark.Fields(), ",", 50))
+
 	cmds := []func(*application.App) *cobra.Command{
 		newExportHTMLCmd,
 		newExportJSONCmd,
+		newExportJSONLCmd,
 		newExportCSVCmd,
+		newExportBrowserCmd,
 	}
 
 	for i := range cmds {
@@ -41,6 +67,8 @@ func newExportCmd(app *application.App) *cobra.Command {
 		cmdutil.FlagOutput(cmd, app, app.Format, formatter.ValidFormats())
 		c.AddCommand(cmd)
 	}
+	c.AddCommand(newExportVaultCmd(app), newExportFeedCmd(app), newExportSiteCmd(app))
+
 	return c
 }
 
@@ -50,9 +78,7 @@ func newExportHTMLCmd(app *application.App) *cobra.Command {
 		Short: "export to HTML Netscape",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			m := setupMenu(app, " export to HTML ")
-			return cmdutil.Execute(cmd, args, m, func(ctx context.Context, d *deps.Deps, bs []*bookmark.Bookmark) error {
-				return bookio.ExportToNetscapeHTML(bs, os.Stdout)
-			})
+			return cmdutil.Execute(cmd, args, m, exportAction(app, "html", ""))
 		},
 	}
 	return c
@@ -64,9 +90,35 @@ func newExportJSONCmd(app *application.App) *cobra.Command {
 		Short: "export to JSON",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			m := setupMenu(app, " export to JSON ")
-			return cmdutil.Execute(cmd, args, m, func(ctx context.Context, d *deps.Deps, bs []*bookmark.Bookmark) error {
-				return printer.RecordsJSON(bs)
-			})
+			return cmdutil.Execute(cmd, args, m, exportAction(app, "json", ""))
+		},
+	}
+	return c
+}
+
+func newExportJSONLCmd(app *application.App) *cobra.Command {
+	c := &cobra.Command{
+		Use:   "jsonl [id|query]",
+		Short: "export to JSON Lines, one bookmark per line",
+		Example: app.Example(`  $ {cmd} db export jsonl > bookmarks.jsonl
+  $ {cmd} db export jsonl | jq -c 'select(.tags | index("later"))'`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			d, cancel, err := cmdutil.SetupDeps(cmd, &args)
+			if err != nil {
+				return err
+			}
+
+			// the whole database is streamed, a selection goes through the
+			// usual pipeline
+			f := app.Flags
+			if len(args) == 0 && !f.Menu && len(f.Tags) == 0 && f.Head == 0 && f.Tail == 0 && f.Sort == "" {
+				defer cancel()
+				return port.StreamJSONL(cmd.Context(), d, os.Stdout)
+			}
+			cancel()
+
+			m := setupMenu(app, " export to JSONL ")
+			return cmdutil.Execute(cmd, args, m, exportAction(app, "jsonl", ""))
 		},
 	}
 	return c
@@ -78,16 +130,144 @@ func newExportCSVCmd(app *application.App) *cobra.Command {
 		Short: "export to CSV",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			m := setupMenu(app, " export to CSV ")
-			a := func(ctx context.Context, d *deps.Deps, bs []*bookmark.Bookmark) error {
-				return bookio.ExportToCSV(bs, os.Stdout, parseCSVFields(app.Flags.Field))
-			}
-			return cmdutil.Execute(cmd, args, m, a)
+			return cmdutil.Execute(cmd, args, m, exportAction(app, "csv", ""))
 		},
 	}
 	cmdutil.FlagFields(c, app, "all,"+wrapFields(bookmark.Fields(), ",", 50))
 	return c
 }
 
+func newExportBrowserCmd(app *application.App) *cobra.Command {
+	c := &cobra.Command{
+		Use:   "browser [id|query]",
+		Short: "export to a browser profile \"gm\" folder",
+		Example: app.Example(`  $ {cmd} db export browser --browser firefox --profile default
+  $ {cmd} db export browser -b chromium -t golang`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			m := setupMenu(app, " export to browser ")
+			return cmdutil.Execute(cmd, args, m, func(ctx context.Context, d *deps.Deps, bs []*bookmark.Bookmark) error {
+				return port.ExportBrowser(ctx, d, app.Flags.Browser, app.Flags.Profile, bs)
+			})
+		},
+	}
+
+	f := c.Flags()
+	f.StringVarP(&app.Flags.Browser, "browser", "b", "", "browser name, e.g. firefox, chromium (default menu)")
+	f.StringVar(&app.Flags.Profile, "profile", "", "browser profile name (default the only profile)")
+
+	return c
+}
+
+func newExportVaultCmd(app *application.App) *cobra.Command {
+	c := &cobra.Command{
+		Use:   "vault <dir>",
+		Short: "export to a folder of Markdown or Org notes",
+		Args:  cobra.ExactArgs(1),
+		Example: app.Example(`  $ {cmd} db export vault ~/notes/bookmarks
+  $ {cmd} db export vault ~/org/bookmarks --format org -t golang
+  $ {cmd} db export vault ~/notes/bookmarks --watch`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			d, cancel, err := cmdutil.SetupDeps(cmd, &args)
+			if err != nil {
+				return err
+			}
+			defer cancel()
+
+			// edited notes are committed like any other edit
+			onImport := func(ctx context.Context, olds, fresh []*bookmark.Bookmark) error {
+				return gitops.UpdateMany(ctx, app, olds, fresh)
+			}
+
+			return port.Vault(cmd.Context(), d, args[0], app.Flags.Format, app.Flags.Tags, app.Flags.Watch, onImport)
+		},
+	}
+
+	f := c.Flags()
+	f.StringVar(&app.Flags.Format, "format", "",
+		"note format: "+strings.Join(vault.Formats(), ", ")+" (default "+vault.FormatMarkdown+")")
+	f.BoolVarP(&app.Flags.Watch, "watch", "w", false, "keep syncing the notes and the database")
+	f.StringSliceVarP(&app.Flags.Tags, "tag", "t", nil, "filter by tag(s)")
+
+	return c
+}
+
+func newExportFeedCmd(app *application.App) *cobra.Command {
+	c := &cobra.Command{
+		Use:   "feed [id|query]",
+		Short: "export to an Atom, RSS or JSON feed",
+		Example: app.Example(`  $ {cmd} db export feed -t team --title "Team links" -o team.atom
+  $ {cmd} db export feed golang --format rss --link https://example.org/go.rss
+  $ {cmd} db export feed --format jsonfeed --limit 20`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := bookio.ValidFeedFormat(app.Flags.Format)
+			if err != nil {
+				return err
+			}
+
+			opts := &bookio.FeedOptions{
+				Title: app.Flags.Title,
+				Link:  app.Flags.Link,
+				Limit: app.Flags.Limit,
+			}
+			if opts.Title == "" {
+				opts.Title = app.Name + " " + app.DBBaseName()
+			}
+
+			m := setupMenu(app, " export to "+strings.ToUpper(format)+" ")
+			return cmdutil.Execute(cmd, args, m, func(ctx context.Context, d *deps.Deps, bs []*bookmark.Bookmark) error {
+				return port.Feed(ctx, d, format, app.Flags.OutFile, opts, bs)
+			})
+		},
+	}
+
+	f := c.Flags()
+	f.StringVar(&app.Flags.Format, "format", "",
+		"feed format: "+strings.Join(bookio.FeedFormats(), ", ")+" (default "+bookio.FeedAtom+")")
+	f.StringVar(&app.Flags.Title, "title", "", "feed title (default gomarks <db>)")
+	f.StringVar(&app.Flags.Link, "link", "", "self link, the URL the feed is served from")
+	f.IntVar(&app.Flags.Limit, "limit", 0, "newest N bookmarks by creation date (default all)")
+	f.StringVarP(&app.Flags.OutFile, "output", "o", "", "output file (default stdout)")
+	cmdutil.FlagMenu(c, app)
+	cmdutil.FlagsFilter(c, app)
+
+	return c
+}
+
+func newExportSiteCmd(app *application.App) *cobra.Command {
+	c := &cobra.Command{
+		Use:   "site [id|query]",
+		Short: "export to a static, searchable website",
+		Example: app.Example(`  $ {cmd} db export site -o public/
+  $ {cmd} db export site -t team --title "Team links" -o public/
+  $ {cmd} db export site -o public/ --templates ~/.config/gomarks/site`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			m := setupMenu(app, " export to site ")
+			return cmdutil.Execute(cmd, args, m, func(ctx context.Context, d *deps.Deps, bs []*bookmark.Bookmark) error {
+				return port.Site(ctx, d, app.Flags.OutFile, app.Flags.Title, app.Flags.Templates, bs)
+			})
+		},
+	}
+
+	f := c.Flags()
+	f.StringVarP(&app.Flags.OutFile, "output", "o", "", "output directory")
+	f.StringVar(&app.Flags.Title, "title", "", "site title (default Bookmarks)")
+	f.StringVar(&app.Flags.Templates, "templates", "", "directory with templates and assets replacing the builtin ones")
+	cmdutil.FlagSort(c, app, handler.SortSupported)
+	cmdutil.FlagMenu(c, app)
+	cmdutil.FlagsFilter(c, app)
+	_ = c.MarkFlagRequired("output")
+
+	return c
+}
+
+// exportAction writes the selected bookmarks in the registered format to
+// path, stdout when empty.
+func exportAction(app *application.App, format, path string) cmdutil.BookmarkAction {
+	return func(ctx context.Context, d *deps.Deps, bs []*bookmark.Bookmark) error {
+		return port.Export(ctx, d, format, path, parseCSVFields(app.Flags.Field), bs)
+	}
+}
+
 func wrapFields(fields []string, sep string, maxLen int) string {
 	var sb strings.Builder
 	line := ""
@@ -148,7 +328,7 @@ func parseCSVFields(f string) []string {
 	return out
 }
 
-func setupMenu(app *application.App, label string) *menu.Menu[bookmark.Bookmark] {
+func setupMenu(app *application.App, label string) *picker.Menu[bookmark.Bookmark] {
 	fm := app.Formatter()
 	p := fm.Menu.Placeholder()
 
@@ -1,6 +1,12 @@
 package database
 
 import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
 	files "github.com/mateconpizza/gofiles"
 	"github.com/spf13/cobra"
 
@@ -10,24 +16,64 @@ import (
 	"github.com/mateconpizza/gm/internal/bookmark/port"
 	"github.com/mateconpizza/gm/internal/cli"
 	"github.com/mateconpizza/gm/internal/dbops"
+	"github.com/mateconpizza/gm/internal/sys/browser"
+	"github.com/mateconpizza/gm/pkg/bookio"
 	"github.com/mateconpizza/gm/pkg/db"
 )
 
+// defaultMinVisits is the minimum number of visits for a history page to be
+// proposed as a bookmark.
+const defaultMinVisits = 5
+
+var ErrInvalidAge = errors.New("invalid age")
+
 func newImportCmd(app *application.App) *cobra.Command {
 	c := &cobra.Command{
-		Use:                "import",
+		Use:                "import [file]",
 		Aliases:            []string{"imp", "i"},
 		Short:              "import bookmarks",
+		Args:               cobra.MaximumNArgs(1),
 		PersistentPostRunE: cli.HookGitSync(app),
+		Example: app.Example(`  $ {cmd} db import bookmarks.html
+  $ {cmd} db import export.csv
+  $ {cmd} db import data.txt --format json
+  $ {cmd} db import datastore.php
+  $ {cmd} db import export.json --mode merge --dry-run
+  $ {cmd} db import browser --mode overwrite`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return cmd.Help()
+			}
+
+			path := args[0]
+			d, cancel, err := cmdutil.SetupDeps(cmd, &args)
+			if err != nil {
+				return err
+			}
+			defer cancel()
+
+			return port.ImportFile(cmd.Context(), d, path, app.Flags.Format)
+		},
 	}
 
+	c.Flags().StringVar(&app.Flags.Format, "format", "",
+		"file format: "+strings.Join(bookio.FormatNames(false), ", ")+" (default detected)")
+
+	pf := c.PersistentFlags()
+	pf.StringVar(&app.Flags.ImportMode, "mode", port.ModeSkip,
+		"existing bookmarks: "+strings.Join(port.Modes(), ", "))
+	pf.BoolVar(&app.Flags.DryRun, "dry-run", false, "show the changes without saving them")
+
 	c.AddCommand(
 		newImportHTMLCmd(app),
 		newImportBrowserCmd(app),
+		newImportHistoryCmd(app),
+		newImportBukuCmd(app),
 		newImportFromDatabaseCmd(app),
 		newImportFromBackupCmd(app),
 		newImportFromGit(app),
 		newImportFromJSON(app),
+		newImportJSONLCmd(app),
 	)
 
 	return c
@@ -117,10 +163,12 @@ func newImportFromBackupCmd(app *application.App) *cobra.Command {
 	return c
 }
 
-func newImportBrowserCmd(_ *application.App) *cobra.Command {
+func newImportBrowserCmd(app *application.App) *cobra.Command {
 	c := &cobra.Command{
 		Use:   "browser",
 		Short: "import from browser",
+		Example: app.Example(`  $ {cmd} db import browser
+  $ {cmd} db import browser --tag-today`),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			d, cancel, err := cmdutil.SetupDeps(cmd, &args)
 			if err != nil {
@@ -132,9 +180,101 @@ func newImportBrowserCmd(_ *application.App) *cobra.Command {
 		},
 	}
 
+	c.Flags().BoolVar(&app.Flags.TodayTag, "tag-today", false, "tag the bookmarks with the import date")
+
+	return c
+}
+
+func newImportBukuCmd(app *application.App) *cobra.Command {
+	c := &cobra.Command{
+		Use:   "buku [file]",
+		Short: "import from a buku database",
+		Args:  cobra.MaximumNArgs(1),
+		Example: app.Example(`  $ {cmd} db import buku
+  $ {cmd} db import buku ~/backup/bookmarks.db --mode merge`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var path string
+			if len(args) > 0 {
+				path = args[0]
+			}
+
+			d, cancel, err := cmdutil.SetupDeps(cmd, &args)
+			if err != nil {
+				return err
+			}
+			defer cancel()
+
+			return port.Buku(cmd.Context(), d, path)
+		},
+	}
+
 	return c
 }
 
+func newImportHistoryCmd(app *application.App) *cobra.Command {
+	c := &cobra.Command{
+		Use:   "history",
+		Short: "import frequently visited pages from browser history",
+		Example: app.Example(`  $ {cmd} db import history
+  $ {cmd} db import history --browser firefox --min-visits 5 --since 90d`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts := browser.Options{
+				Force:     app.Flags.Yes,
+				TodayTag:  app.Flags.TodayTag,
+				MinVisits: app.Flags.MinVisits,
+			}
+
+			if app.Flags.Since != "" {
+				age, err := parseAge(app.Flags.Since)
+				if err != nil {
+					return err
+				}
+				opts.Since = time.Now().Add(-age)
+			}
+
+			d, cancel, err := cmdutil.SetupDeps(cmd, &args)
+			if err != nil {
+				return err
+			}
+			defer cancel()
+
+			return port.History(cmd.Context(), d, app.Flags.Browser, opts)
+		},
+	}
+
+	f := c.Flags()
+	f.StringVarP(&app.Flags.Browser, "browser", "b", "", "browser name, e.g. firefox, chromium (default menu)")
+	f.IntVar(&app.Flags.MinVisits, "min-visits", defaultMinVisits, "minimum visits to propose a page")
+	f.StringVar(&app.Flags.Since, "since", "", "only count visits newer than, e.g. 90d, 12w, 36h (default all)")
+	f.BoolVar(&app.Flags.TodayTag, "tag-today", false, "tag the bookmarks with the import date")
+
+	return c
+}
+
+// parseAge parses a duration that also accepts days and weeks, e.g. "90d" or
+// "2w".
+func parseAge(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+
+	units := map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour}
+	for suffix, unit := range units {
+		if n, ok := strings.CutSuffix(s, suffix); ok {
+			v, err := strconv.Atoi(n)
+			if err != nil || v < 0 {
+				return 0, fmt.Errorf("%w: %q, use e.g. 90d, 2w or 36h", ErrInvalidAge, s)
+			}
+			return time.Duration(v) * unit, nil
+		}
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("%w: %q, use e.g. 90d, 2w or 36h", ErrInvalidAge, s)
+	}
+
+	return d, nil
+}
+
 func newImportHTMLCmd(app *application.App) *cobra.Command {
 	c := &cobra.Command{
 		Use:   "html",
@@ -146,7 +286,7 @@ func newImportHTMLCmd(app *application.App) *cobra.Command {
 			}
 			defer cancel()
 
-			return port.ImportFromHTML(cmd.Context(), d, app.Flags.Path)
+			return port.ImportFile(cmd.Context(), d, app.Flags.Path, "html")
 		},
 	}
 
@@ -190,6 +330,39 @@ func newImportFromGit(app *application.App) *cobra.Command {
 	return c
 }
 
+func newImportJSONLCmd(app *application.App) *cobra.Command {
+	c := &cobra.Command{
+		Use:   "jsonl <file|->",
+		Short: "import JSON Lines, updating the bookmarks with the same URL",
+		Args:  cobra.ExactArgs(1),
+		Example: app.Example(`  $ {cmd} db export jsonl | jq -c '.tags += ["later"]' | {cmd} db import jsonl -
+  $ {cmd} db import jsonl bookmarks.jsonl --mode merge --dry-run`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// upsert by URL unless asked otherwise
+			if !cmd.Flags().Changed("mode") {
+				app.Flags.ImportMode = port.ModeOverwrite
+			}
+
+			// read stdin before the setup takes it as a query
+			path := args[0]
+			f, bs, err := port.ExtractFromFile(path, "jsonl")
+			if err != nil {
+				return err
+			}
+
+			d, cancel, err := cmdutil.SetupDeps(cmd, &args)
+			if err != nil {
+				return err
+			}
+			defer cancel()
+
+			return port.ImportBookmarks(cmd.Context(), d, f, path, bs)
+		},
+	}
+
+	return c
+}
+
 func newImportFromJSON(app *application.App) *cobra.Command {
 	c := &cobra.Command{
 		Use:   "json",
@@ -201,7 +374,7 @@ func newImportFromJSON(app *application.App) *cobra.Command {
 			}
 			defer cancel()
 
-			return port.ImportFromJSON(cmd.Context(), d, app.Flags.Path)
+			return port.ImportFile(cmd.Context(), d, app.Flags.Path, "json")
 		},
 	}
 
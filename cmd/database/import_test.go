@@ -0,0 +1,47 @@
+package database
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseAge(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{input: "90d", want: 90 * 24 * time.Hour},
+		{input: "2w", want: 14 * 24 * time.Hour},
+		{input: " 36h ", want: 36 * time.Hour},
+		{input: "1h30m", want: 90 * time.Minute},
+		{input: "0d", want: 0},
+		{input: "d", wantErr: true},
+		{input: "-3d", wantErr: true},
+		{input: "-1h", wantErr: true},
+		{input: "soon", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parseAge(tt.input)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidAge) {
+					t.Fatalf("expected ErrInvalidAge, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/mateconpizza/gm/internal/picker"
 	"github.com/mateconpizza/gm/internal/picker/menucfg"
 	"github.com/mateconpizza/gm/internal/ui/formatter"
+	"github.com/mateconpizza/gm/pkg/bookmark"
 )
 
 // FIX: NewCmd menu: current functionality exits the menu after editing a bookmark.
@@ -25,6 +26,7 @@ func NewCmd(app *application.App) *cobra.Command {
   $ {cmd} edit --menu --sort favorite
   $ {cmd} edit --tag golang,awesome
   $ {cmd} edit --tag golang --json
+  $ {cmd} edit --tag golang --bulk
   $ {cmd} edit --tag golang --tag awesome`),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			fm := app.Formatter()
@@ -58,8 +60,11 @@ func NewCmd(app *application.App) *cobra.Command {
 
 			var strategy editor.EditStrategy
 			strategy = editor.NewBookmarkStrategy()
-			if app.Flags.JSON {
+			switch {
+			case app.Flags.JSON:
 				strategy = editor.NewJSONStrategy()
+			case app.Flags.Bulk:
+				strategy = editor.NewBulkStrategy()
 			}
 
 			return cmdutil.Execute(cmd, args, m, handler.Edit(cmd.Context(), strategy))
@@ -67,12 +72,14 @@ func NewCmd(app *application.App) *cobra.Command {
 	}
 
 	c.Flags().BoolVarP(&app.Flags.JSON, "json", "j", false, "JSON format")
+	c.Flags().BoolVarP(&app.Flags.Bulk, "bulk", "B", false, "edit all bookmarks in a single buffer")
+	c.MarkFlagsMutuallyExclusive("json", "bulk")
 	cmdutil.FlagSort(c, app, handler.SortSupported)
 	cmdutil.FlagMenu(c, app)
 	cmdutil.FlagsFilter(c, app)
 	cmdutil.FlagOutput(c, app, app.Format, formatter.ValidFormats())
 
-	c.AddCommand(newEditNotesCmd(app))
+	c.AddCommand(newEditNotesCmd(app), newFavoriteCmd(app), newAddTagCmd(app))
 
 	return c
 }
@@ -110,3 +117,61 @@ func newEditNotesCmd(app *application.App) *cobra.Command {
 
 	return c
 }
+
+func newFavoriteCmd(app *application.App) *cobra.Command {
+	c := &cobra.Command{
+		Use:     "favorite [query]",
+		Aliases: []string{"fav"},
+		Short:   "toggle favorite",
+		Example: app.Example(`  $ {cmd} edit favorite <id> or <query>
+  $ {cmd} edit favorite --menu --tag golang`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmdutil.Execute(cmd, args, selectMenu(app, " favorite "), handler.ToggleFavorite)
+		},
+	}
+
+	cmdutil.FlagMenu(c, app)
+	cmdutil.FlagSort(c, app, handler.SortSupported)
+	cmdutil.FlagsFilter(c, app)
+	cmdutil.FlagOutput(c, app, app.Format, formatter.ValidFormats())
+
+	return c
+}
+
+func newAddTagCmd(app *application.App) *cobra.Command {
+	c := &cobra.Command{
+		Use:   "tag [query]",
+		Short: "add tags",
+		Example: app.Example(`  $ {cmd} edit tag <id> or <query>
+  $ {cmd} edit tag --add golang,cli <id>
+  $ {cmd} edit tag --add golang --menu`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmdutil.Execute(cmd, args, selectMenu(app, " add tags "), handler.AddTags)
+		},
+	}
+
+	c.Flags().StringVar(&app.Flags.TagsStr, "add", "", "tags to add (tag1,tag2)")
+	cmdutil.FlagMenu(c, app)
+	cmdutil.FlagSort(c, app, handler.SortSupported)
+	cmdutil.FlagsFilter(c, app)
+	cmdutil.FlagOutput(c, app, app.Format, formatter.ValidFormats())
+
+	return c
+}
+
+// selectMenu returns a menu to select the records an edit runs on.
+func selectMenu(app *application.App, label string) *picker.Menu[bookmark.Bookmark] {
+	fm := app.Formatter()
+	p := fm.Menu.Placeholder()
+
+	return picker.NewWithFormatter(
+		app,
+		fm,
+		menu.WithMultiSelection(),
+		menu.WithHeader("select record/s"),
+		menu.WithHeaderLabel(label),
+		menu.WithPreviewCmd(picker.PreviewCmd(app.Command(), app.DBBaseName(), p.Single())),
+		menu.WithKeybinds(menu.KeymapToggleAll(), menu.KeymapTogglePreview()),
+		menu.WithHeaderKeymaps(),
+	)
+}
@@ -2,6 +2,7 @@ package gitcmd
 
 import (
 	"log/slog"
+	"strings"
 
 	"github.com/spf13/cobra"
 
@@ -48,6 +49,8 @@ func NewCmd(app *application.App) *cobra.Command {
 		newPushCmd(app),     // push bookmark changes to a remote
 		newSyncCmd(app),     // synchronize bookmarks with the repository
 		newInfoCmd(app),     // show repository status and configuration
+		newLogCmd(app),      // show bookmark history
+		newRestoreCmd(app),  // restore bookmarks from a past revision
 		newRawCmd(app),      // run arbitrary git commands
 	)
 
@@ -72,7 +75,7 @@ func newCommitCmd(app *application.App) *cobra.Command {
 			}
 
 			gr := gitops.NewRepo(m, r.Name(), git.WithRepoStore(r))
-			return m.SaveChanges(cmd.Context(), gr, cmd.Short)
+			return gitops.SaveChanges(cmd.Context(), app, m, gr, r, cmd.Short)
 		},
 	}
 }
@@ -163,6 +166,8 @@ func newCloneCmd(app *application.App) *cobra.Command {
 		},
 	}
 
+	c.Flags().BoolVar(&app.Flags.Team, "team", false, "shared repository, attribute bookmarks to their authors")
+
 	return c
 }
 
@@ -299,3 +304,46 @@ func newInfoCmd(app *application.App) *cobra.Command {
 
 	return c
 }
+
+func newLogCmd(app *application.App) *cobra.Command {
+	c := &cobra.Command{
+		Use:   "log [query]",
+		Short: "show bookmark history",
+		Example: app.Example(`  $ {cmd} git log
+  $ {cmd} git log golang`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			d, cleanup, err := cmdutil.SetupDeps(cmd, &args)
+			if err != nil {
+				return err
+			}
+			defer cleanup()
+
+			return gitops.LogCmd(cmd.Context(), d, strings.Join(args, " "))
+		},
+	}
+
+	cmdutil.HideFlag(c, "color", "yes", "force")
+
+	return c
+}
+
+func newRestoreCmd(app *application.App) *cobra.Command {
+	c := &cobra.Command{
+		Use:   "restore <rev> [query]",
+		Short: "restore bookmarks from a past revision",
+		Args:  cobra.MinimumNArgs(1),
+		Example: app.Example(`  $ {cmd} git restore HEAD~3
+  $ {cmd} git restore a1b2c3d golang`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			d, cleanup, err := cmdutil.SetupDeps(cmd, &args)
+			if err != nil {
+				return err
+			}
+			defer cleanup()
+
+			return gitops.Restore(cmd.Context(), d, args[0], strings.Join(args[1:], " "))
+		},
+	}
+
+	return c
+}
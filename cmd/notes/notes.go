@@ -109,7 +109,7 @@ func onlyNotes(bs []*bookmark.Bookmark) []*bookmark.Bookmark {
 	return filtered
 }
 
-func setupMenu(app *application.App, opts ...menu.Option) *menu.Menu[bookmark.Bookmark] {
+func setupMenu(app *application.App, opts ...menu.Option) *picker.Menu[bookmark.Bookmark] {
 	p := app.Formatter().Menu.Placeholder()
 
 	opts = append(
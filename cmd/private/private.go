@@ -0,0 +1,63 @@
+// Package private provides the commands to mark bookmarks as private.
+package private
+
+import (
+	menu "github.com/mateconpizza/go-fzf"
+	"github.com/spf13/cobra"
+
+	"github.com/mateconpizza/gm/cmd/cmdutil"
+	"github.com/mateconpizza/gm/internal/application"
+	"github.com/mateconpizza/gm/internal/handler"
+	"github.com/mateconpizza/gm/internal/picker"
+	"github.com/mateconpizza/gm/internal/ui/formatter"
+	"github.com/mateconpizza/gm/pkg/bookmark"
+)
+
+func NewCmd(app *application.App) *cobra.Command {
+	c := &cobra.Command{
+		Use:   "private",
+		Short: "encrypt URL, description and notes of bookmarks",
+	}
+
+	c.AddCommand(
+		newPrivateCmd(app, "set", "mark bookmarks as private", true),
+		newPrivateCmd(app, "unset", "mark private bookmarks as public", false),
+	)
+
+	return c
+}
+
+func newPrivateCmd(app *application.App, use, short string, private bool) *cobra.Command {
+	c := &cobra.Command{
+		Use:   use + " [query]",
+		Short: short,
+		Example: app.Example(`  $ {cmd} private ` + use + ` <id> or <query>
+  $ {cmd} private ` + use + ` --menu
+  $ {cmd} private ` + use + ` --tag intranet`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmdutil.Execute(cmd, args, setupMenu(app, " "+use+" private "), handler.SetPrivate(private))
+		},
+	}
+
+	cmdutil.FlagSort(c, app, handler.SortSupported)
+	cmdutil.FlagMenu(c, app)
+	cmdutil.FlagsFilter(c, app)
+	cmdutil.FlagOutput(c, app, app.Format, formatter.ValidFormats())
+
+	return c
+}
+
+func setupMenu(app *application.App, label string) *picker.Menu[bookmark.Bookmark] {
+	fm := app.Formatter()
+
+	return picker.NewWithFormatter(
+		app,
+		fm,
+		menu.WithMultiSelection(),
+		menu.WithHeader("select record/s"),
+		menu.WithHeaderLabel(label),
+		menu.WithHeaderKeymaps(),
+		menu.WithPreviewCmd(picker.PreviewCmd(app.Command(), app.DBBaseName(), fm.Menu.Placeholder().Single())),
+		menu.WithKeybinds(menu.KeymapToggleAll(), menu.KeymapTogglePreview()),
+	)
+}
@@ -102,7 +102,7 @@ func newGenQR(app *application.App) *cobra.Command {
 	return c
 }
 
-func setupMenu(app *application.App) *menu.Menu[bookmark.Bookmark] {
+func setupMenu(app *application.App) *picker.Menu[bookmark.Bookmark] {
 	fm := app.Formatter()
 	p := fm.Menu.Placeholder()
 
@@ -6,15 +6,19 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/mateconpizza/gm/cmd/add"
+	"github.com/mateconpizza/gm/cmd/browse"
 	"github.com/mateconpizza/gm/cmd/cmdutil"
 	"github.com/mateconpizza/gm/cmd/config"
+	"github.com/mateconpizza/gm/cmd/daemoncmd"
 	"github.com/mateconpizza/gm/cmd/database"
 	"github.com/mateconpizza/gm/cmd/edit"
 	"github.com/mateconpizza/gm/cmd/gitcmd"
 	"github.com/mateconpizza/gm/cmd/notes"
 	"github.com/mateconpizza/gm/cmd/open"
+	"github.com/mateconpizza/gm/cmd/private"
 	"github.com/mateconpizza/gm/cmd/qrcmd"
 	"github.com/mateconpizza/gm/cmd/rm"
+	"github.com/mateconpizza/gm/cmd/run"
 	"github.com/mateconpizza/gm/cmd/setup"
 	"github.com/mateconpizza/gm/cmd/tag"
 	urlcmd "github.com/mateconpizza/gm/cmd/url"
@@ -36,13 +40,17 @@ func Setup(root *cobra.Command, app *application.App) {
 		edit.NewCmd,
 		rm.NewCmd,
 		open.NewCmd,
+		browse.NewCmd,
 		yank.NewCmd,
 		notes.NewCmd,
+		private.NewCmd,
 		qrcmd.NewCmd,
+		run.NewCmd,
 		urlcmd.NewCmd,
 		tag.NewCmd,
 		database.NewCmd,
 		gitcmd.NewCmd,
+		daemoncmd.NewCmd,
 		config.NewCmd,
 		setup.NewCmd,
 	}
@@ -91,6 +99,8 @@ func registerRootFlags(c *cobra.Command, app *application.App) {
 	// local
 	// limit results (head/tail semantics)
 	cmdutil.FlagsFilter(c, app)
+	// teammate attribution
+	c.Flags().StringVar(&app.Flags.By, "by", "", "filter by contributor")
 	// interactive mode
 	cmdutil.FlagMenu(c, app)
 	// output formatting
@@ -98,8 +108,15 @@ func registerRootFlags(c *cobra.Command, app *application.App) {
 	// sorting strategy (domain-specific ordering options)
 	cmdutil.FlagSort(c, app, handler.SortSupported)
 	// field selection for output projection
-	fields := []string{"id", "url", "title", "tags", "desc"}
-	cmdutil.FlagFields(c, app, strings.Join(fields, ", "))
+	fields := []string{
+		"id", "url", "title", "tags", "desc", "notes", "domain", "status", "visits",
+		"created", "updated", "visited", "checked", "archive", "favorite", "private", "by",
+	}
+	cmdutil.FlagFields(c, app, strings.Join(fields, ", ")+" (name[:width[:end|middle|none]])")
+	c.Flags().BoolVar(&app.Flags.TSV, "tsv", false, "print the fields as tab separated values")
+	c.Flags().BoolVar(&app.Flags.CSV, "csv", false, "print the fields as comma separated values")
+	c.Flags().BoolVar(&app.Flags.NoHeader, "no-header", false, "omit the header of the fields")
+	c.MarkFlagsMutuallyExclusive("tsv", "csv")
 
 	// global
 	g := c.PersistentFlags()
@@ -111,6 +128,8 @@ func registerRootFlags(c *cobra.Command, app *application.App) {
 	g.BoolVarP(&app.Flags.Yes, "yes", "y", false, "assume yes")
 	// force execution even if safeguards would prevent it
 	g.BoolVar(&app.Flags.Force, "force", false, "force action")
+	// private bookmarks
+	g.BoolVar(&app.Flags.Private, "private", false, "unlock private bookmarks")
 	// verbosity level
 	g.CountVarP(&app.Flags.Verbose, "verbose", "v", "increase verbosity (-v, -vv, -vvv)")
 
@@ -122,4 +141,6 @@ func registerRootFlags(c *cobra.Command, app *application.App) {
 	_ = g.MarkHidden("help")
 	g.StringVar(&app.Flags.Preview, "preview", "", "")
 	_ = g.MarkHidden("preview")
+	c.Flags().BoolVar(&app.Flags.MenuSource, "menu-source", false, "")
+	_ = c.Flags().MarkHidden("menu-source")
 }
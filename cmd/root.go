@@ -4,7 +4,9 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
 
 	"github.com/spf13/cobra"
 
@@ -12,9 +14,12 @@ import (
 	"github.com/mateconpizza/gm/internal/application"
 	"github.com/mateconpizza/gm/internal/cli"
 	"github.com/mateconpizza/gm/internal/deps"
+	"github.com/mateconpizza/gm/internal/handler"
 	"github.com/mateconpizza/gm/internal/picker"
 	"github.com/mateconpizza/gm/internal/sys"
+	"github.com/mateconpizza/gm/internal/ui/preview"
 	"github.com/mateconpizza/gm/internal/ui/printer"
+	"github.com/mateconpizza/gm/internal/ui/table"
 	"github.com/mateconpizza/gm/pkg/bookmark"
 	"github.com/mateconpizza/gm/pkg/git"
 )
@@ -28,6 +33,7 @@ func NewRootCmd(app *application.App) *cobra.Command {
 		PersistentPreRunE: cli.ChainHooks(
 			cli.HookInjectApp(app),
 			cli.HookEnsureDatabase(app),
+			cli.HookUnlockPrivate(app),
 			cli.HookFormatter(app),
 		),
 		RunE: rootCmdFunc(app),
@@ -68,13 +74,19 @@ func rootCmdFunc(app *application.App) cli.HookE {
 			return nil
 		}
 
-		m := picker.NewMainMenu(app)
+		if app.Flags.MenuSource {
+			return menuSource(cmd, args, app)
+		}
+
+		m := picker.NewMainMenu(app, args)
 		a := func(ctx context.Context, d *deps.Deps, bs []*bookmark.Bookmark) error {
 			t, f := d.Console(), app.Flags
 
 			switch {
-			case app.Flags.Field != "":
-				return printer.ByField(ctx, t, f.Field, bs) // TODO: experimental
+			case f.Field != "" || f.TSV || f.CSV:
+				return printer.ByField(ctx, t, f.Field, tableMode(f), !f.NoHeader, bs)
+			case app.Flags.Preview == preview.Format:
+				return printer.RichPreview(ctx, d, bs)
 			case app.Flags.Preview != "":
 				return printer.MenuPreview(t, bs, f.Preview)
 			case app.Flags.Output == application.OutputFormat:
@@ -88,6 +100,33 @@ func rootCmdFunc(app *application.App) cli.HookE {
 	}
 }
 
+// tableMode returns the output mode of the fields table.
+func tableMode(f *application.Flags) table.Mode {
+	switch {
+	case f.TSV:
+		return table.TSV
+	case f.CSV:
+		return table.CSV
+	default:
+		return table.Text
+	}
+}
+
+// menuSource prints the items of the main menu, the menu runs it to reload
+// them after a keymap changed the records.
+func menuSource(cmd *cobra.Command, args []string, app *application.App) error {
+	a := func(_ context.Context, _ *deps.Deps, bs []*bookmark.Bookmark) error {
+		return picker.WriteItems(os.Stdout, app, bs)
+	}
+
+	err := cmdutil.Execute(cmd, args, nil, a)
+	if errors.Is(err, handler.ErrNoItems) {
+		return nil // empty the menu
+	}
+
+	return err
+}
+
 func setupRootCmd(c *cobra.Command) {
 	// Add custom template function used inside usage/help templates
 	cobra.AddTemplateFunc("hasFlags", cmdutil.HasFlags)
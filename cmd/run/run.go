@@ -0,0 +1,94 @@
+// Package run runs the user defined actions of the menu config.
+package run
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	menu "github.com/mateconpizza/go-fzf"
+	"github.com/spf13/cobra"
+
+	"github.com/mateconpizza/gm/cmd/cmdutil"
+	"github.com/mateconpizza/gm/internal/application"
+	"github.com/mateconpizza/gm/internal/cli"
+	"github.com/mateconpizza/gm/internal/handler"
+	"github.com/mateconpizza/gm/internal/picker"
+	"github.com/mateconpizza/gm/internal/picker/menucfg"
+	"github.com/mateconpizza/gm/internal/ui/formatter"
+	"github.com/mateconpizza/gm/pkg/bookmark"
+)
+
+func NewCmd(app *application.App) *cobra.Command {
+	c := &cobra.Command{
+		Use:   "run <action> [query]",
+		Short: "run a user defined action",
+		Long: `run a user defined action on the bookmarks.
+
+Actions are declared in the config file under menu.actions, without
+arguments the configured actions are listed.`,
+		Example: app.Example(`  $ {cmd} run
+  $ {cmd} run mpv <query>
+  $ {cmd} run mpv --menu --tag video
+  $ {cmd} run md-link 42`),
+		Annotations: cli.SkipGitSync,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return listActions(os.Stdout, app.Menu.Actions)
+			}
+
+			a, err := app.Menu.Action(args[0])
+			if err != nil {
+				return err
+			}
+
+			return cmdutil.Execute(cmd, args[1:], setupMenu(app, a), handler.RunAction(a))
+		},
+	}
+
+	cmdutil.FlagSort(c, app, handler.SortSupported)
+	cmdutil.FlagMenu(c, app)
+	cmdutil.FlagsFilter(c, app)
+	cmdutil.FlagOutput(c, app, app.Format, formatter.ValidFormats())
+
+	return c
+}
+
+func setupMenu(app *application.App, a *menucfg.Action) *picker.Menu[bookmark.Bookmark] {
+	fm := app.Formatter()
+	p := fm.Menu.Placeholder()
+
+	opts := []menu.Option{
+		menu.WithHeader("select record/s"),
+		menu.WithHeaderLabel(" " + a.Description() + " "),
+		menu.WithPreviewCmd(picker.PreviewCmd(app.Command(), app.DBBaseName(), p.Single())),
+		menu.WithKeybinds(menu.KeymapTogglePreview()),
+	}
+	if a.Multi {
+		opts = append(opts, menu.WithMultiSelection(), menu.WithKeybinds(menu.KeymapToggleAll()))
+	}
+
+	return picker.NewWithFormatter(app, fm, opts...)
+}
+
+// listActions prints the configured actions.
+func listActions(w io.Writer, actions []*menucfg.Action) error {
+	if len(actions) == 0 {
+		return fmt.Errorf("%w: none configured in menu.actions", menucfg.ErrActionNotFound)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, a := range actions {
+		bind, mode := string(a.Bind), "single"
+		if bind == "" {
+			bind = "-"
+		}
+		if a.Multi {
+			mode = "multi"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", a.Name, bind, mode, a.Cmd)
+	}
+
+	return tw.Flush()
+}
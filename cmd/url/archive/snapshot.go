@@ -102,7 +102,7 @@ func onlySnapshots(bs []*bookmark.Bookmark) []*bookmark.Bookmark {
 	return result
 }
 
-func setupMenu(app *application.App) *menu.Menu[bookmark.Bookmark] {
+func setupMenu(app *application.App) *picker.Menu[bookmark.Bookmark] {
 	fm, _ := formatter.New(formatter.ArchiveURL)
 
 	p := fm.Menu.Placeholder()
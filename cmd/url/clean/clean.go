@@ -79,7 +79,7 @@ func newCleanURLUser(app *application.App) *cobra.Command {
 	return c
 }
 
-func setupMenu(app *application.App) *menu.Menu[bookmark.Bookmark] {
+func setupMenu(app *application.App) *picker.Menu[bookmark.Bookmark] {
 	fm, _ := formatter.New(formatter.Parameters)
 	p := fm.Menu.Placeholder()
 	fm.Menu.Opts = append(
@@ -27,28 +27,33 @@ var (
 )
 
 const (
-	Name           string = "gomarks"        // Default name of the application
-	Command        string = "gm"             // Default name of the executable
-	MainDBName     string = "main.db"        // Default name of the main database
-	ConfigFilename string = "config.yml"     // Default config filename
-	OutputFormat   string = "frame"          // Default output format
-	EnvHome        string = "GOMARKS_HOME"   // Default Environment variable for app home
-	EnvEditor      string = "GOMARKS_EDITOR" // Default Environment variable for app editor
+	Name           string = "gomarks"            // Default name of the application
+	Command        string = "gm"                 // Default name of the executable
+	MainDBName     string = "main.db"            // Default name of the main database
+	ConfigFilename string = "config.yml"         // Default config filename
+	OutputFormat   string = "frame"              // Default output format
+	EnvHome        string = "GOMARKS_HOME"       // Default Environment variable for app home
+	EnvEditor      string = "GOMARKS_EDITOR"     // Default Environment variable for app editor
+	EnvPassphrase  string = "GOMARKS_PASSPHRASE" // Default Environment variable for database passphrase
 )
 
 type (
 	App struct {
-		Name   string          `json:"name"          yaml:"-"`             // Name of the application
-		DBName string          `json:"db"            yaml:"db,omitempty"`  // Database name
-		Cmd    string          `json:"cmd"           yaml:"cmd"`           // Name of the executable
-		Format string          `json:"format"        yaml:"format"`        // Output bookmark format
-		Info   *Information    `json:"data"          yaml:"-"`             // Application information
-		Env    *Env            `json:"env"           yaml:"-"`             // Application environment variables
-		Path   *Path           `json:"path"          yaml:"-"`             // Application path
-		Flags  *Flags          `json:"-"             yaml:"-"`             // Command line flags
-		Menu   *menucfg.Config `json:"menu"          yaml:"menu"`          // Menu configuration
-		Git    *Git            `json:"git,omitempty" yaml:"git,omitempty"` // Git configuration
-		UI     *UI             `json:"-"             yaml:"-"`             // UI
+		Name    string                     `json:"name"              yaml:"-"`                 // Name of the application
+		DBName  string                     `json:"db"                yaml:"db,omitempty"`      // Database name
+		Cmd     string                     `json:"cmd"               yaml:"cmd"`               // Name of the executable
+		Format  string                     `json:"format"            yaml:"format"`            // Output bookmark format
+		Info    *Information               `json:"data"              yaml:"-"`                 // Application information
+		Env     *Env                       `json:"env"               yaml:"-"`                 // Application environment variables
+		Path    *Path                      `json:"path"              yaml:"-"`                 // Application path
+		Flags   *Flags                     `json:"-"                 yaml:"-"`                 // Command line flags
+		Menu    *menucfg.Config            `json:"menu"              yaml:"menu"`              // Menu configuration
+		Git     *Git                       `json:"git,omitempty"     yaml:"git,omitempty"`     // Git configuration
+		Locker  *Locker                    `json:"locker"            yaml:"locker"`            // Locker configuration
+		Daemon  *Daemon                    `json:"daemon"            yaml:"daemon"`            // Daemon configuration
+		Feeds   []*Feed                    `json:"feeds,omitempty"   yaml:"feeds,omitempty"`   // Feeds written on git sync
+		Formats map[string]*TemplateFormat `json:"formats,omitempty" yaml:"formats,omitempty"` // Output formats rendered by templates
+		UI      *UI                        `json:"-"                 yaml:"-"`                 // UI
 
 		initialized bool
 	}
@@ -68,8 +73,9 @@ type (
 	}
 
 	Env struct {
-		Home   string `json:"home"`   // Environment variable for the home directory
-		Editor string `json:"editor"` // Environment variable for the preferred editor
+		Home       string `json:"home"`       // Environment variable for the home directory
+		Editor     string `json:"editor"`     // Environment variable for the preferred editor
+		Passphrase string `json:"passphrase"` // Environment variable for the database passphrase
 	}
 )
 
@@ -122,6 +128,7 @@ func (app *App) Load() error {
 	}
 
 	app.Git.Load()
+	app.registerFormats()
 	app.Flags.Output = app.Format
 
 	return app.SetDatabase(app.DBName)
@@ -242,10 +249,13 @@ func New(info *Information) *App {
 			writer:  os.Stdout,
 		},
 		Env: &Env{
-			Home:   EnvHome,
-			Editor: EnvEditor,
+			Home:       EnvHome,
+			Editor:     EnvEditor,
+			Passphrase: EnvPassphrase,
 		},
-		Menu: menucfg.NewDefault(),
+		Locker: &Locker{},
+		Daemon: &Daemon{},
+		Menu:   menucfg.NewDefault(),
 	}
 }
 
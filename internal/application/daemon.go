@@ -0,0 +1,10 @@
+package application
+
+import "time"
+
+type Daemon struct {
+	Debounce        time.Duration `json:"debounce,omitempty"         yaml:"debounce,omitempty"`         // Wait after the last change before syncing
+	PullInterval    time.Duration `json:"pull_interval,omitempty"    yaml:"pull_interval,omitempty"`    // Pull remote changes every
+	StatusInterval  time.Duration `json:"status_interval,omitempty"  yaml:"status_interval,omitempty"`  // Check bookmarks status every
+	FaviconInterval time.Duration `json:"favicon_interval,omitempty" yaml:"favicon_interval,omitempty"` // Fetch missing favicons every
+}
@@ -0,0 +1,13 @@
+package application
+
+// Feed is a feed file written into the git repository on every sync.
+type Feed struct {
+	Name   string   `json:"name"            yaml:"name"`            // File name, without extension
+	Format string   `json:"format"          yaml:"format"`          // atom, rss or jsonfeed
+	Title  string   `json:"title"           yaml:"title"`           // Feed title
+	Link   string   `json:"link,omitempty"  yaml:"link,omitempty"`  // Self link, where the feed is served from
+	DB     string   `json:"db,omitempty"    yaml:"db,omitempty"`    // Database, defaults to the main one
+	Query  string   `json:"query,omitempty" yaml:"query,omitempty"` // Bookmarks matching the query
+	Tags   []string `json:"tags,omitempty"  yaml:"tags,omitempty"`  // Bookmarks with all the tags
+	Limit  int      `json:"limit,omitempty" yaml:"limit,omitempty"` // Newest bookmarks to include
+}
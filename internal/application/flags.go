@@ -14,17 +14,28 @@ type Flags struct {
 	Print bool // Print something
 	All   bool // Include all items
 
+	MenuSource bool // Print the main menu items, used to reload the menu
+
 	// Output format
-	Output  string // Output
-	Field   string // Bookmarks fields
-	JSON    bool   // JSON output
-	Preview string // Menu preview
-	Sort    string // Sort by
+	Output    string // Output
+	Field     string // Bookmarks fields
+	TSV       bool   // Fields as tab separated values
+	CSV       bool   // Fields as comma separated values
+	NoHeader  bool   // Omit the header of the fields
+	JSON      bool   // JSON output
+	Preview   string // Menu preview
+	Sort      string // Sort by
+	Format    string // Import/export file format
+	OutFile   string // Output file path
+	Watch     bool   // Keep the export in sync
+	Link      string // Feed self link
+	Templates string // Templates directory
 
 	// Filtering and pagination
 	Head int      // Head limit
 	Tags []string // Tags list to filter bookmarks
 	Tail int      // Tail limit
+	By   string   // Contributor to filter bookmarks
 
 	// Bookmark operations
 	Limit   int           // Limit to N
@@ -33,6 +44,16 @@ type Flags struct {
 	Title   string        // Bookmark's title
 	TagsStr string        // Bookmark's tags (tag1,tag2,...)
 	Timeout time.Duration // Timeout ops
+	Bulk    bool          // Edit all bookmarks in a single buffer
+
+	// import
+	ImportMode string // How to import duplicates: skip, merge or overwrite
+	DryRun     bool   // Report the changes without saving them
+	TodayTag   bool   // Tag browser imports with the import date
+	Browser    string // Browser name
+	MinVisits  int    // Minimum visits to propose a history page
+	Since      string // Only count the history visits newer than, e.g. 90d
+	Profile    string // Browser profile name
 
 	// Configuration and behavior
 	Color    bool   // Application color enable
@@ -45,6 +66,18 @@ type Flags struct {
 
 	// git
 	Reinit bool // Reinitialize existing repository
+	Team   bool // Shared repository, keep attribution
+
+	// locker
+	Rekey    bool          // Change the passphrase of a locked database
+	AgentTTL time.Duration // Unlock agent key lifetime
+	Private  bool          // Unlock private bookmarks, mark new ones as private
+
+	// daemon
+	Debounce        time.Duration // Wait after the last change before syncing
+	PullInterval    time.Duration // Pull remote changes every
+	StatusInterval  time.Duration // Check bookmarks status every
+	FaviconInterval time.Duration // Fetch missing favicons every
 }
 
 func SetVerbosity(verbose int) {
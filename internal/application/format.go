@@ -0,0 +1,40 @@
+package application
+
+import (
+	"log/slog"
+
+	"github.com/mateconpizza/gm/internal/ui/formatter"
+)
+
+// TemplateFormat is an output format declared in the config, rendered by a
+// text/template.
+type TemplateFormat struct {
+	Template    string `json:"template"              yaml:"template"`              // text/template executed with the bookmark
+	Placeholder string `json:"placeholder,omitempty" yaml:"placeholder,omitempty"` // fzf field holding the bookmark ID
+	WithNth     string `json:"with_nth,omitempty"    yaml:"with_nth,omitempty"`    // fzf fields shown in the menu
+	Multiline   bool   `json:"multiline,omitempty"   yaml:"multiline,omitempty"`   // Items span several lines
+}
+
+// registerFormats registers the output formats declared in the config.
+// Invalid templates and names of builtin formats are skipped.
+func (app *App) registerFormats() {
+	for name, tf := range app.Formats {
+		if _, ok := formatter.Formatters[formatter.Format(name)]; ok {
+			slog.Warn("config: format name taken by a builtin format, skipping", "format", name)
+			continue
+		}
+
+		fm, err := formatter.NewTemplate(name, &formatter.TemplateSpec{
+			Template:    tf.Template,
+			Placeholder: tf.Placeholder,
+			WithNth:     tf.WithNth,
+			Multiline:   tf.Multiline,
+		})
+		if err != nil {
+			slog.Warn("config: skipping format", "error", err)
+			continue
+		}
+
+		formatter.RegisterFormatter(name, fm)
+	}
+}
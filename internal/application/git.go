@@ -6,6 +6,8 @@ type Git struct {
 	Enabled bool   `json:"enabled" yaml:"enabled"` // Enable git
 	Log     bool   `json:"logging" yaml:"logging"` // Enable logging
 	Remote  string `json:"remote"  yaml:"remote"`  // Remote repo
+	Author  string `json:"author"  yaml:"author"`  // Name used to attribute bookmarks
+	Team    bool   `json:"team"    yaml:"team"`    // Shared repository, attribute new bookmarks
 
 	writer io.Writer // Writer for logging
 }
@@ -0,0 +1,16 @@
+package application
+
+import "time"
+
+type Locker struct {
+	PassphraseCmd string        `json:"passphrase_cmd,omitempty" yaml:"passphrase_cmd,omitempty"` // Command printing the passphrase
+	AgentTTL      time.Duration `json:"agent_ttl,omitempty"      yaml:"agent_ttl,omitempty"`      // Unlock agent key lifetime
+}
+
+// TTL returns the agent key lifetime, zero means the agent default.
+func (l *Locker) TTL() time.Duration {
+	if l == nil || l.AgentTTL < 0 {
+		return 0
+	}
+	return l.AgentTTL
+}
@@ -20,12 +20,19 @@ func NewPath() *Path {
 	return &Path{}
 }
 
-func (p *Path) Home() string       { return p.Data }
-func (p *Path) Git() string        { return filepath.Join(p.Data, "git") }
-func (p *Path) Backup() string     { return filepath.Join(p.Data, "backup") }
-func (p *Path) DB() string         { return p.Database }
-func (p *Path) ConfigFile() string { return filepath.Join(p.Data, ConfigFilename) }
-func (p *Path) setup() error       { return files.MkdirAll(p.Home()) }
+func (p *Path) Home() string        { return p.Data }
+func (p *Path) Git() string         { return filepath.Join(p.Data, "git") }
+func (p *Path) Backup() string      { return filepath.Join(p.Data, "backup") }
+func (p *Path) DB() string          { return p.Database }
+func (p *Path) ConfigFile() string  { return filepath.Join(p.Data, ConfigFilename) }
+func (p *Path) Agent() string       { return filepath.Join(p.Data, "agent.sock") }
+func (p *Path) DaemonLog() string   { return filepath.Join(p.Data, "daemon-"+p.dbStem()+".log") }
+func (p *Path) DaemonState() string { return filepath.Join(p.Data, "daemon-"+p.dbStem()+".json") }
+func (p *Path) setup() error        { return files.MkdirAll(p.Home()) }
+
+// dbStem returns the database name without extensions, the daemon runs per
+// database.
+func (p *Path) dbStem() string { return files.StripExts(filepath.Base(p.Database)) }
 
 // dataPath returns the data path for the application.
 func dataPath(appName string) (string, error) {
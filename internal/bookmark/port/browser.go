@@ -39,7 +39,10 @@ func Browser(ctx context.Context, d *deps.Deps) error {
 	}
 
 	// find bookmarks
-	bs, err := br.Import(ctx, d.Console(), app.Flags.Yes)
+	bs, err := br.Import(ctx, d.Console(), browser.Options{
+		Force:    app.Flags.Yes,
+		TodayTag: app.Flags.TodayTag,
+	})
 	if err != nil {
 		return fmt.Errorf("import from browser %q: %w", strings.ToLower(br.Name()), err)
 	}
@@ -53,19 +56,113 @@ func Browser(ctx context.Context, d *deps.Deps) error {
 	return importPipeline(ctx, d, "from browser", br.Name(), bs)
 }
 
+// History imports the frequently visited pages of a browser history. The
+// browser is selected from a menu when name is empty.
+func History(ctx context.Context, d *deps.Deps, name string, opts browser.Options) error {
+	app, err := d.Application(ctx)
+	if err != nil {
+		return err
+	}
+
+	c := d.Console()
+
+	var br browser.Browser
+	if name != "" {
+		br, err = findBrowser(name)
+	} else {
+		br, err = selectBrowser(ctx, app, c)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := br.LoadPaths(); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	bs, err := br.History(ctx, c, opts)
+	if err != nil {
+		return fmt.Errorf("import history from %q: %w", strings.ToLower(br.Name()), err)
+	}
+
+	if len(bs) == 0 {
+		c.Frame().Error(ErrNothingToImport.Error() + "\n").Flush()
+		return sys.ErrExitFailure
+	}
+
+	return importPipeline(ctx, d, "from history", br.Name(), bs)
+}
+
+// ExportBrowser writes bs into the "gm" folder of a browser profile.
+func ExportBrowser(ctx context.Context, d *deps.Deps, name, profile string, bs []*bookmark.Bookmark) error {
+	app, err := d.Application(ctx)
+	if err != nil {
+		return err
+	}
+
+	c := d.Console()
+
+	var br browser.Browser
+	if name != "" {
+		br, err = findBrowser(name)
+	} else {
+		br, err = selectBrowser(ctx, app, c)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := br.LoadPaths(); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	if err := br.Export(ctx, profile, bs); err != nil {
+		return fmt.Errorf("export to %q: %w", strings.ToLower(br.Name()), err)
+	}
+
+	n := len(bookmark.WithoutPrivate(bs))
+
+	return c.Print(ctx, c.SuccessMesg(fmt.Sprintf("exported %d bookmarks to %s folder %q\n",
+		n, br.Name(), browser.ExportFolder)))
+}
+
+// findBrowser returns the supported browser matching name, ignoring case,
+// spaces and dashes.
+func findBrowser(name string) (browser.Browser, error) {
+	norm := func(s string) string {
+		return strings.NewReplacer(" ", "", "-", "", "_", "").Replace(strings.ToLower(s))
+	}
+
+	for _, s := range browsers() {
+		if norm(s.Browser.Name()) == norm(name) {
+			return s.Browser, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %q", browser.ErrBrowserUnsupported, name)
+}
+
 // parseFoundInBrowser processes the bookmarks found from the import
 // browser process.
 func parseFoundInBrowser(ctx context.Context, d *deps.Deps, bs []*bookmark.Bookmark) ([]*bookmark.Bookmark, error) {
 	c := d.Console()
 
-	r, err := d.Repository()
+	app, err := d.Application(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	bs, err = DeduplicateReport(ctx, c, r, bs)
-	if err != nil {
-		return nil, err
+	// duplicates are only kept when they can update the existing bookmarks
+	if mode, _ := importMode(app.Flags.ImportMode); mode == ModeSkip {
+		r, err := d.Repository()
+		if err != nil {
+			return nil, err
+		}
+
+		bs, err = DeduplicateReport(ctx, c, r, bs)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	if len(bs) == 0 {
@@ -76,11 +173,6 @@ func parseFoundInBrowser(ctx context.Context, d *deps.Deps, bs []*bookmark.Bookm
 		return bs, sys.ErrExitFailure
 	}
 
-	app, err := d.Application(ctx)
-	if err != nil {
-		return nil, err
-	}
-
 	if !app.Flags.Yes &&
 		!c.Confirm(ctx, fmt.Sprintf("scrape missing data from %d bookmarks found?", len(bs)), "n") {
 		return bs, nil
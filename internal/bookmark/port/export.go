@@ -0,0 +1,94 @@
+package port
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	files "github.com/mateconpizza/gofiles"
+
+	"github.com/mateconpizza/gm/internal/deps"
+	"github.com/mateconpizza/gm/pkg/bookio"
+	"github.com/mateconpizza/gm/pkg/bookmark"
+)
+
+// Export writes the bookmarks in the registered format to path, or to
+// stdout when path is empty or "-".
+func Export(ctx context.Context, d *deps.Deps, format, path string, fields []string, bs []*bookmark.Bookmark) error {
+	if path == "" || path == "-" {
+		return bookio.Export(os.Stdout, format, bs, fields)
+	}
+
+	app, err := d.Application(ctx)
+	if err != nil {
+		return err
+	}
+
+	f, err := files.New(path, app.Flags.Force)
+	if err != nil {
+		return fmt.Errorf("%w: %q, use --force to overwrite", err, path)
+	}
+
+	err = bookio.Export(f, format, bs, fields)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+
+	c := d.Console()
+	n := len(bookmark.WithoutPrivate(bs))
+
+	return c.Print(ctx, c.SuccessMesg(fmt.Sprintf("exported %d bookmarks to %s\n", n, files.CollapseHomeDir(path))))
+}
+
+// StreamJSONL writes all the bookmarks to w as JSON Lines while they are
+// read from the database, private bookmarks are skipped.
+func StreamJSONL(ctx context.Context, d *deps.Deps, w io.Writer) error {
+	r, err := d.Repository()
+	if err != nil {
+		return err
+	}
+
+	jw := bookio.NewJSONLWriter(w)
+	if err := r.Each(ctx, jw.Write); err != nil {
+		return err
+	}
+
+	slog.DebugContext(ctx, "streamed bookmarks", "count", jw.Count())
+
+	return nil
+}
+
+// Feed writes the bookmarks as an Atom, RSS or JSON feed to path, or to
+// stdout when path is empty or "-".
+func Feed(ctx context.Context, d *deps.Deps, format, path string, opts *bookio.FeedOptions, bs []*bookmark.Bookmark) error {
+	if path == "" || path == "-" {
+		return bookio.ExportFeed(os.Stdout, format, bs, opts)
+	}
+
+	app, err := d.Application(ctx)
+	if err != nil {
+		return err
+	}
+
+	f, err := files.New(path, app.Flags.Force)
+	if err != nil {
+		return fmt.Errorf("%w: %q, use --force to overwrite", err, path)
+	}
+
+	err = bookio.ExportFeed(f, format, bs, opts)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+
+	c := d.Console()
+
+	return c.Print(ctx, c.SuccessMesg(fmt.Sprintf("feed written to %s\n", files.CollapseHomeDir(path))))
+}
@@ -3,7 +3,6 @@ package port
 import (
 	"context"
 	"fmt"
-	"log"
 	"log/slog"
 	"os"
 	"strings"
@@ -31,68 +30,78 @@ func ImportFromDatabase(ctx context.Context, d *deps.Deps, f string) error {
 	return importPipeline(ctx, d, "from file", f, bs)
 }
 
-// ImportFromHTML import bookmarks from HTML Netscape file.
-func ImportFromHTML(ctx context.Context, d *deps.Deps, f string) error {
-	bs, err := ExtractFromHTML(f)
+// ImportFile imports bookmarks from a file in any registered format, the
+// format is detected when empty. A buku database is also detected.
+func ImportFile(ctx context.Context, d *deps.Deps, path, format string) error {
+	if format == "" && bookio.IsBukuDatabase(ctx, path) {
+		return Buku(ctx, d, path)
+	}
+
+	f, bs, err := ExtractFromFile(path, format)
 	if err != nil {
 		return err
 	}
 
-	return importPipeline(ctx, d, "from HTML", f, bs)
+	return importPipeline(ctx, d, "from "+strings.ToUpper(f.Name), path, bs)
 }
 
-func ImportFromJSON(ctx context.Context, d *deps.Deps, path string) error {
-	bs, err := ExtractFromJSON(path)
-	if err != nil {
-		return err
+// ImportBookmarks imports the bookmarks read from path, stdin when "-".
+// Stdin has to be read before the dependencies setup consumes it, and once
+// read there is nothing left to answer the prompts, so they are assumed.
+func ImportBookmarks(ctx context.Context, d *deps.Deps, f *bookio.Format, path string, bs []*bookmark.Bookmark) error {
+	if path == "-" {
+		app, err := d.Application(ctx)
+		if err != nil {
+			return err
+		}
+
+		app.Flags.Yes = true
+		path = "stdin"
 	}
 
-	return importPipeline(ctx, d, "from JSON", path, bs)
+	return importPipeline(ctx, d, "from "+strings.ToUpper(f.Name), path, bs)
 }
 
-// ExtractFromHTML encapsulates all HTML-specific extraction logic.
-func ExtractFromHTML(f string) ([]*bookmark.Bookmark, error) {
-	file, err := os.Open(f)
-	if err != nil {
-		log.Printf("Error opening file: %v, %q\n", err, f)
-		return nil, err
-	}
-	defer func() {
-		if err := file.Close(); err != nil {
-			slog.Error("Err closing file", "file", f)
+// Buku imports bookmarks from a buku database, the default one when path
+// is empty.
+func Buku(ctx context.Context, d *deps.Deps, path string) error {
+	if path == "" {
+		var err error
+		if path, err = bookio.BukuPath(); err != nil {
+			return err
 		}
-	}()
-
-	if err := bookio.IsValidNetscapeFile(file); err != nil {
-		return nil, err
 	}
 
-	bp := bookio.NewHTMLParser()
-	nbs, err := bp.ParseHTML(file)
-	if err != nil {
-		return nil, err
+	if err := files.ExistsErr(path); err != nil {
+		return fmt.Errorf("%w: %q", err, files.CollapseHomeDir(path))
 	}
 
-	bs := make([]*bookmark.Bookmark, 0, len(nbs))
-	for i := range nbs {
-		bs = append(bs, bookio.FromNetscape(&nbs[i]))
+	bs, err := bookio.ImportFromBuku(ctx, path)
+	if err != nil {
+		return err
 	}
 
-	return bs, nil
+	return importPipeline(ctx, d, "from buku", path, bs)
 }
 
-func ExtractFromJSON(path string) ([]*bookmark.Bookmark, error) {
-	var jbs []*bookmark.BookmarkJSON
-	if err := files.ReadJSON(path, &jbs); err != nil {
-		return nil, err
+// ExtractFromFile reads the bookmarks of a file using the registered
+// format, stdin when path is "-".
+func ExtractFromFile(path, format string) (*bookio.Format, []*bookmark.Bookmark, error) {
+	if path == "-" {
+		return bookio.Import(os.Stdin, "stdin", format)
 	}
 
-	bs := make([]*bookmark.Bookmark, 0, len(jbs))
-	for i := range jbs {
-		bs = append(bs, bookmark.NewFromJSON(jbs[i]))
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
 	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			slog.Error("Err closing file", "file", path)
+		}
+	}()
 
-	return bs, nil
+	return bookio.Import(file, path, format)
 }
 
 func ExtractFromDatabase(ctx context.Context, f string) ([]*bookmark.Bookmark, error) {
@@ -114,7 +123,8 @@ func ExtractFromDatabase(ctx context.Context, f string) ([]*bookmark.Bookmark, e
 	return bs, nil
 }
 
-// importPipeline handles deduplication, user prompting, and persistence.
+// importPipeline handles duplicates according to the import mode, user
+// prompting, and persistence.
 func importPipeline(ctx context.Context, d *deps.Deps, source, from string, bs []*bookmark.Bookmark) error {
 	c := d.Console()
 
@@ -123,35 +133,52 @@ func importPipeline(ctx context.Context, d *deps.Deps, source, from string, bs [
 		return err
 	}
 
+	app, err := d.Application(ctx)
+	if err != nil {
+		return err
+	}
+
+	mode, err := importMode(app.Flags.ImportMode)
+	if err != nil {
+		return err
+	}
+
 	printImportHeader(c, source, files.StripExts(r.Name()), from, len(bs))
 
-	deduplicated, err := DeduplicateReport(ctx, c, r, bs)
+	plan, err := planImport(ctx, r, mode, bs)
 	if err != nil {
 		return err
 	}
+	reportPlan(c, plan)
 
-	if len(deduplicated) == 0 {
+	if plan.empty() {
 		c.Frame().Error(ErrNothingToImport.Error() + "\n").Flush()
 		return sys.ErrExitFailure
 	}
 
-	app, err := d.Application(ctx)
-	if err != nil {
-		return err
+	if app.Flags.DryRun {
+		return c.Print(ctx, c.InfoMesg(importSummary(plan, true)))
 	}
 
 	if !app.Flags.Force && !app.Flags.Yes {
-		deduplicated, err = promptImportSelection(ctx, d, deduplicated)
-		if err != nil {
-			return err
+		if len(plan.fresh) > 0 {
+			plan.fresh, err = promptImportSelection(ctx, d, plan.fresh)
+			if err != nil {
+				return err
+			}
+		}
+
+		n := len(plan.updates)
+		if n > 0 && !c.Confirm(ctx, fmt.Sprintf("%s %d existing bookmarks?", mode, n), "y") {
+			plan.updates = nil
 		}
 	}
 
-	if err := r.InsertMany(ctx, deduplicated); err != nil {
+	if err := applyPlan(ctx, r, plan); err != nil {
 		return err
 	}
 
-	return c.Print(ctx, c.SuccessMesg("imported ", len(deduplicated), " bookmarks\n"))
+	return c.Print(ctx, c.SuccessMesg(importSummary(plan, false)))
 }
 
 func printImportHeader(c *ui.Console, header, fromName, toName string, n int) {
@@ -64,24 +64,7 @@ func Database(ctx context.Context, d *deps.Deps, srcDB *db.SQLite) error {
 		return err
 	}
 
-	bs, err = DeduplicateReport(ctx, c, destDB, bs)
-	if err != nil {
-		return err
-	}
-
-	if len(bs) == 0 {
-		p := c.Palette()
-		c.Frame().Error("no new bookmark found, ").
-			Textln(p.BrightYellow.Wrap("skipping import", p.Italic)).
-			Flush()
-		return sys.ErrExitFailure
-	}
-
-	if err := destDB.InsertMany(ctx, bs); err != nil {
-		return err
-	}
-
-	return c.Print(ctx, c.SuccessMesg("imported ", len(bs), " record/s from ", srcDB.Name()))
+	return importPipeline(ctx, d, "from database", srcDB.Name(), bs)
 }
 
 // FromBackup imports bookmarks from a backup.
@@ -122,25 +105,7 @@ func FromBackup(ctx context.Context, d *deps.Deps, destDB, srcDB *db.SQLite) err
 	// update which repo to insert
 	d.SetRepo(destDB)
 
-	r, err := d.Repository()
-	if err != nil {
-		return err
-	}
-
-	result, err := DeduplicateReport(ctx, c, r, bookmarks)
-	if err != nil {
-		return err
-	}
-
-	if len(result) == 0 {
-		p := c.Palette()
-		c.Frame().Error("no new bookmark found, ").
-			Textln(p.BrightYellow.Wrap("skipping import", p.Italic)).
-			Flush()
-		return sys.ErrExitFailure
-	}
-
-	return importPipeline(ctx, d, "from backup", srcDB.Name(), result)
+	return importPipeline(ctx, d, "from backup", srcDB.Name(), bookmarks)
 }
 
 // ToJSON converts an interface to JSON.
@@ -155,21 +120,28 @@ func ToJSON(data any) ([]byte, error) {
 
 // DeduplicateReport removes duplicate bookmarks and reports skipped entries to the console.
 func DeduplicateReport(ctx context.Context, c *ui.Console, r *db.SQLite, bs []*bookmark.Bookmark) ([]*bookmark.Bookmark, error) {
-	const maxItemsToShow = 10
-
 	existing, err := r.All(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	fresh, duplicates := bookmark.Deduplicate(bs, existing)
+	reportDuplicates(c, duplicates, len(bs))
+
+	return fresh, nil
+}
+
+// reportDuplicates prints the skipped duplicate bookmarks.
+func reportDuplicates(c *ui.Console, duplicates []*bookmark.Bookmark, total int) {
+	const maxItemsToShow = 10
+
 	if len(duplicates) == 0 {
-		return fresh, nil
+		return
 	}
 
 	p := c.Palette()
 	skip := p.BrightYellow.Sprint("skipping")
-	c.Warning(fmt.Sprintf("%s %d/%d duplicate bookmarks\n", skip, len(duplicates), len(bs))).
+	c.Warning(fmt.Sprintf("%s %d/%d duplicate bookmarks\n", skip, len(duplicates), total)).
 		Flush()
 
 	f := c.Frame()
@@ -185,6 +157,4 @@ func DeduplicateReport(ctx context.Context, c *ui.Console, r *db.SQLite, bs []*b
 
 	f.Rowln().
 		Flush()
-
-	return fresh, nil
 }
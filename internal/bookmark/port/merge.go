@@ -0,0 +1,244 @@
+package port
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mateconpizza/gm/internal/deps"
+	"github.com/mateconpizza/gm/internal/ui"
+	"github.com/mateconpizza/gm/internal/ui/txt"
+	"github.com/mateconpizza/gm/pkg/bookmark"
+	"github.com/mateconpizza/gm/pkg/db"
+)
+
+// Import modes, how bookmarks already in the database are handled.
+const (
+	ModeSkip      = "skip"      // keep the existing bookmark untouched
+	ModeMerge     = "merge"     // union tags, fill empty fields, keep the newer title
+	ModeOverwrite = "overwrite" // replace the existing content
+)
+
+var ErrImportMode = errors.New("invalid import mode")
+
+// Modes returns the supported import modes.
+func Modes() []string {
+	return []string{ModeSkip, ModeMerge, ModeOverwrite}
+}
+
+// importUpdate is an existing bookmark changed by the import.
+type importUpdate struct {
+	old, new *bookmark.Bookmark
+	diffs    []bookmark.FieldDiff
+}
+
+// importPlan holds the changes an import makes to the database.
+type importPlan struct {
+	mode    string
+	total   int
+	fresh   []*bookmark.Bookmark
+	updates []*importUpdate
+	skipped []*bookmark.Bookmark // duplicates left untouched
+	locked  []*bookmark.Bookmark // private bookmarks set aside while locked
+}
+
+func (p *importPlan) empty() bool {
+	return len(p.fresh) == 0 && len(p.updates) == 0
+}
+
+// updated returns the bookmarks to update.
+func (p *importPlan) updated() []*bookmark.Bookmark {
+	bs := make([]*bookmark.Bookmark, 0, len(p.updates))
+	for _, u := range p.updates {
+		bs = append(bs, u.new)
+	}
+
+	return bs
+}
+
+// importMode returns the validated mode, defaults to skip.
+func importMode(mode string) (string, error) {
+	mode = strings.ToLower(strings.TrimSpace(mode))
+	if mode == "" {
+		return ModeSkip, nil
+	}
+
+	for _, m := range Modes() {
+		if m == mode {
+			return m, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: %q (use %s)", ErrImportMode, mode, strings.Join(Modes(), ", "))
+}
+
+// planImport splits bs into new bookmarks and updates to the existing ones,
+// according to mode. The locked private bookmarks cannot be matched, so the
+// import is refused while there are any, it would duplicate them.
+func planImport(ctx context.Context, r *db.SQLite, mode string, bs []*bookmark.Bookmark) (*importPlan, error) {
+	if err := r.CheckMatchable(ctx); err != nil {
+		return nil, err
+	}
+
+	existing, err := r.All(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	fresh, duplicates := bookmark.Deduplicate(bs, existing)
+	for _, b := range fresh {
+		if b.Checksum == "" {
+			b.GenChecksum()
+		}
+	}
+
+	plan := &importPlan{mode: mode, total: len(bs), fresh: fresh}
+
+	// private bookmarks are stored encrypted, keep them out while locked
+	if !r.PrivateUnlocked() {
+		plan.fresh = bookmark.WithoutPrivate(fresh)
+		for _, b := range fresh {
+			if b.Private {
+				plan.locked = append(plan.locked, b)
+			}
+		}
+	}
+
+	if mode == ModeSkip {
+		plan.skipped = duplicates
+		return plan, nil
+	}
+
+	byURL := make(map[string]*bookmark.Bookmark, len(existing))
+	for _, b := range existing {
+		byURL[b.URL] = b
+	}
+
+	for _, b := range duplicates {
+		old := byURL[b.URL]
+
+		var nb *bookmark.Bookmark
+		if mode == ModeOverwrite {
+			nb = bookmark.Overwrite(old, b)
+		} else {
+			nb = bookmark.Merge(old, b)
+		}
+
+		diffs := bookmark.Diff(old, nb)
+		if len(diffs) == 0 {
+			plan.skipped = append(plan.skipped, b)
+			continue
+		}
+
+		plan.updates = append(plan.updates, &importUpdate{old: old, new: nb, diffs: diffs})
+	}
+
+	return plan, nil
+}
+
+// reportPlan prints the skipped duplicates and the per-field changes made to
+// the existing bookmarks.
+func reportPlan(c *ui.Console, plan *importPlan) {
+	const maxItemsToShow = 10
+
+	reportDuplicates(c, plan.skipped, plan.total)
+	if n := len(plan.locked); n > 0 {
+		c.Warning(fmt.Sprintf("skipping %d private bookmarks, use --private to unlock\n", n)).Flush()
+	}
+
+	if len(plan.updates) == 0 {
+		return
+	}
+
+	p := c.Palette()
+	verb := map[string]string{ModeMerge: "merging", ModeOverwrite: "overwriting"}[plan.mode]
+	c.Warning(fmt.Sprintf("%s %d/%d existing bookmarks\n", p.BrightYellow.Sprint(verb), len(plan.updates), plan.total)).
+		Flush()
+
+	f := c.Frame()
+	width := c.MinWidth()
+
+	for i, u := range plan.updates {
+		if i >= maxItemsToShow {
+			f.Midln(p.Dim.With(p.Italic).Sprintf(" ... and %d more", len(plan.updates)-i))
+			break
+		}
+
+		f.Midln(p.Dim.Wrap(" "+txt.Shorten(u.old.URL, width), p.Italic))
+		for _, d := range u.diffs {
+			field := p.BrightBlue.Sprintf("   %-8s", d.Field)
+			f.Midln(field + " " + p.BrightRed.Sprint(txt.Shorten(oneLine(d.Old), width/3)) +
+				p.Dim.Sprint(" → ") + p.BrightGreen.Sprint(txt.Shorten(oneLine(d.New), width/3)))
+		}
+	}
+
+	f.Rowln().
+		Flush()
+}
+
+// applyPlan stores the new bookmarks and the updates in a single
+// transaction, the git repo is synced once afterwards by the command hook.
+func applyPlan(ctx context.Context, r *db.SQLite, plan *importPlan) error {
+	return r.InsertAndUpdate(ctx, plan.fresh, plan.updated())
+}
+
+// Save imports bs into the current repository using the import mode and
+// dry-run flags, without prompting.
+func Save(ctx context.Context, d *deps.Deps, bs []*bookmark.Bookmark) error {
+	app, err := d.Application(ctx)
+	if err != nil {
+		return err
+	}
+
+	mode, err := importMode(app.Flags.ImportMode)
+	if err != nil {
+		return err
+	}
+
+	r, err := d.Repository()
+	if err != nil {
+		return err
+	}
+
+	c := d.Console()
+
+	plan, err := planImport(ctx, r, mode, bs)
+	if err != nil {
+		return err
+	}
+	reportPlan(c, plan)
+
+	if plan.empty() {
+		return c.Print(ctx, c.Warning(ErrNothingToImport.Error()+"\n").StringReset())
+	}
+
+	if app.Flags.DryRun {
+		return c.Print(ctx, c.InfoMesg(importSummary(plan, true)))
+	}
+
+	if err := applyPlan(ctx, r, plan); err != nil {
+		return err
+	}
+
+	return c.Print(ctx, c.SuccessMesg(importSummary(plan, false)))
+}
+
+// importSummary returns a line with the counts of the plan.
+func importSummary(plan *importPlan, dryRun bool) string {
+	if dryRun {
+		return fmt.Sprintf("dry run, would import %d and update %d bookmarks, nothing saved\n",
+			len(plan.fresh), len(plan.updates))
+	}
+
+	return fmt.Sprintf("imported %d and updated %d bookmarks\n", len(plan.fresh), len(plan.updates))
+}
+
+// oneLine collapses the line breaks of s.
+func oneLine(s string) string {
+	if s == "" {
+		return "-"
+	}
+
+	return strings.Join(strings.Fields(s), " ")
+}
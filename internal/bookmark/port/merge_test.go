@@ -0,0 +1,57 @@
+package port
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/mateconpizza/gm/internal/testutil"
+	"github.com/mateconpizza/gm/pkg/db"
+)
+
+func TestPlanImport_LockedPrivate(t *testing.T) {
+	t.Parallel()
+
+	// no cipher is registered, private bookmarks are locked
+	r := testutil.SetupInitializedEmptyDB(t, filepath.Join(t.TempDir(), "main.db"))
+	t.Cleanup(r.Close)
+
+	bs := testutil.BookmarkSlice(3)
+	bs[1].Private = true
+
+	plan, err := planImport(t.Context(), r, ModeSkip, bs)
+	if err != nil {
+		t.Fatalf("planImport: %v", err)
+	}
+
+	if len(plan.locked) != 1 || plan.locked[0] != bs[1] {
+		t.Fatalf("expected the private bookmark set aside, got %d", len(plan.locked))
+	}
+	if len(plan.fresh) != 2 {
+		t.Fatalf("expected 2 new bookmarks, got %d", len(plan.fresh))
+	}
+
+	if err := r.InsertMany(t.Context(), plan.fresh); err != nil {
+		t.Fatalf("InsertMany: %v", err)
+	}
+}
+
+func TestPlanImport_LockedExistingPrivate(t *testing.T) {
+	t.Parallel()
+
+	r := testutil.SetupInitializedEmptyDB(t, filepath.Join(t.TempDir(), "main.db"))
+	t.Cleanup(r.Close)
+
+	bs := testutil.BookmarkSlice(1)
+	if err := r.InsertMany(t.Context(), bs); err != nil {
+		t.Fatalf("InsertMany: %v", err)
+	}
+	if _, err := r.DB.ExecContext(t.Context(), "UPDATE bookmarks SET private = 1"); err != nil {
+		t.Fatal(err)
+	}
+
+	// the private bookmark is invisible, importing it again would duplicate it
+	if _, err := planImport(t.Context(), r, ModeSkip, bs); !errors.Is(err, db.ErrPrivateLocked) {
+		t.Fatalf("expected ErrPrivateLocked, got %v", err)
+	}
+}
@@ -0,0 +1,31 @@
+package port
+
+import (
+	"context"
+	"fmt"
+
+	files "github.com/mateconpizza/gofiles"
+
+	"github.com/mateconpizza/gm/internal/bookmark/site"
+	"github.com/mateconpizza/gm/internal/deps"
+	"github.com/mateconpizza/gm/pkg/bookmark"
+)
+
+// Site renders the bookmarks as a static website in dir, private bookmarks
+// are left out. Templates and assets found in templates replace the builtin
+// ones.
+func Site(ctx context.Context, d *deps.Deps, dir, title, templates string, bs []*bookmark.Bookmark) error {
+	s := site.New(dir, title)
+	s.Templates = templates
+
+	res, err := s.Generate(bs)
+	if err != nil {
+		return err
+	}
+
+	c := d.Console()
+	msg := fmt.Sprintf("site written to %s: %d bookmarks, %d tags, %d favicons\n",
+		files.CollapseHomeDir(dir), res.Bookmarks, res.Tags, res.Favicons)
+
+	return c.Print(ctx, c.SuccessMesg(msg))
+}
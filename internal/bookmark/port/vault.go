@@ -0,0 +1,84 @@
+package port
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	files "github.com/mateconpizza/gofiles"
+
+	"github.com/mateconpizza/gm/internal/bookmark/vault"
+	"github.com/mateconpizza/gm/internal/deps"
+	"github.com/mateconpizza/gm/pkg/bookmark"
+)
+
+// Vault exports the bookmarks as notes in dir, only the ones with all the
+// given tags when not empty. With watch it keeps syncing the notes and the
+// database until ctx is done. onImport records the bookmarks updated from
+// edited notes, e.g. in git.
+func Vault(
+	ctx context.Context,
+	d *deps.Deps,
+	dir, format string,
+	tags []string,
+	watch bool,
+	onImport func(ctx context.Context, olds, fresh []*bookmark.Bookmark) error,
+) error {
+	app, err := d.Application(ctx)
+	if err != nil {
+		return err
+	}
+
+	r, err := d.Repository()
+	if err != nil {
+		return err
+	}
+
+	v, err := vault.New(dir, format)
+	if err != nil {
+		return err
+	}
+	v.OnImport = onImport
+	if len(tags) > 0 {
+		v.Filter = func(b *bookmark.Bookmark) bool {
+			have := strings.Split(b.Tags, ",")
+			for _, t := range tags {
+				if !slices.Contains(have, t) {
+					return false
+				}
+			}
+			return true
+		}
+	}
+
+	c := d.Console()
+	path := files.CollapseHomeDir(dir)
+	report := func(res *vault.Result, err error) {
+		if err != nil {
+			c.Warning(fmt.Sprintf("vault: %v\n", err)).Flush()
+			return
+		}
+		for _, s := range res.Invalid {
+			c.Warning(fmt.Sprintf("skipping %s\n", s)).Flush()
+		}
+		if res.Changed() {
+			_ = c.Print(ctx, c.SuccessMesg(fmt.Sprintf("synced %s: %d written, %d imported, %d removed\n",
+				path, res.Written, res.Imported, res.Removed)))
+		}
+	}
+
+	res, err := v.Sync(ctx, r)
+	if err != nil {
+		return err
+	}
+	report(res, nil)
+
+	if !watch {
+		return nil
+	}
+
+	_ = c.Print(ctx, c.InfoMesg(fmt.Sprintf("watching %s, press ctrl+c to stop\n", path)))
+
+	return v.Watch(ctx, r, app.Path.DB(), report)
+}
@@ -0,0 +1,91 @@
+// Client-side search over window.GM_INDEX, every word has to match the
+// title, URL, description or tags.
+(function () {
+  "use strict";
+
+  var input = document.getElementById("search");
+  var results = document.getElementById("results");
+  var list = document.getElementById("bookmarks");
+  if (!input || !results || !list || !window.GM_INDEX) {
+    return;
+  }
+
+  var root = input.dataset.root || "";
+  var pages = window.GM_TAGS || {};
+  var index = window.GM_INDEX.map(function (b) {
+    return { b: b, text: [b.t, b.u, b.d || "", (b.g || []).join(" ")].join(" ").toLowerCase() };
+  });
+
+  function tagPage(tag) {
+    return root + (pages[tag] || "");
+  }
+
+  // isWebURL checks the scheme again, the index only links http and https
+  // URLs, so a javascript: URL never becomes a link.
+  function isWebURL(u) {
+    return typeof u === "string" && /^https?:/i.test(u);
+  }
+
+  function render(items) {
+    results.replaceChildren();
+    items.forEach(function (b) {
+      var li = document.createElement("li");
+      li.className = "bookmark";
+
+      var title = document.createElement("div");
+      title.className = "title";
+      if (isWebURL(b.h)) {
+        var a = document.createElement("a");
+        a.href = b.h;
+        a.rel = "noopener noreferrer";
+        a.textContent = b.t;
+        title.appendChild(a);
+      } else {
+        title.textContent = b.t;
+      }
+      li.appendChild(title);
+
+      var url = document.createElement("div");
+      url.className = "url";
+      url.textContent = b.u;
+      li.appendChild(url);
+
+      if (b.d) {
+        var desc = document.createElement("p");
+        desc.className = "desc";
+        desc.textContent = b.d;
+        li.appendChild(desc);
+      }
+
+      var meta = document.createElement("div");
+      meta.className = "meta";
+      (b.g || []).forEach(function (t) {
+        var tag = document.createElement("a");
+        tag.className = "tag";
+        tag.href = tagPage(t);
+        tag.textContent = "#" + t;
+        meta.appendChild(tag);
+        meta.appendChild(document.createTextNode(" "));
+      });
+      li.appendChild(meta);
+
+      results.appendChild(li);
+    });
+  }
+
+  input.addEventListener("input", function () {
+    var words = input.value.toLowerCase().split(/\s+/).filter(Boolean);
+    if (words.length === 0) {
+      results.hidden = true;
+      list.hidden = false;
+      return;
+    }
+
+    render(index.filter(function (e) {
+      return words.every(function (w) { return e.text.indexOf(w) !== -1; });
+    }).map(function (e) { return e.b; }));
+
+    results.hidden = false;
+    list.hidden = true;
+  });
+})();
@@ -0,0 +1,477 @@
+// Package site renders the bookmarks as a static website: an index page,
+// one page per tag and a client-side search index.
+package site
+
+import (
+	"bytes"
+	"cmp"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/mateconpizza/gm/pkg/bookmark"
+)
+
+//go:embed templates/*.html assets/*
+var embedded embed.FS
+
+// Site layout.
+const (
+	IndexFile   = "index.html"
+	TagsDir     = "tags"
+	AssetsDir   = "assets"
+	FaviconsDir = "favicons"
+	SearchIndex = "search-index.js"
+	MarkerFile  = ".gm-site" // Marks a directory written by the generator
+)
+
+// pageTemplate is the template rendering every page.
+const pageTemplate = "page.html"
+
+var (
+	ErrTemplates = errors.New("invalid templates")
+	ErrNotSite   = errors.New("directory not empty and not a generated site")
+)
+
+// Site renders bookmarks into Dir.
+type Site struct {
+	Dir       string // Output directory
+	Title     string // Site title
+	Templates string // Directory with templates and assets overriding the builtin ones
+}
+
+// Result reports what was written.
+type Result struct {
+	Bookmarks int
+	Tags      int
+	Favicons  int
+}
+
+// Entry is a bookmark as seen by the templates.
+type Entry struct {
+	ID         int
+	URL        string
+	Title      string
+	Desc       string
+	Notes      string
+	Tags       []string
+	Favorite   bool
+	Created    string
+	Icon       string // Favicon path, relative to the site root
+	ArchiveURL string
+	Status     Status
+}
+
+// Status is the link health badge of an entry.
+type Status struct {
+	Code  int
+	Text  string
+	Class string // ok, redirect, broken or unknown
+}
+
+// Tag is a tag and the number of bookmarks having it.
+type Tag struct {
+	Name  string
+	Count int
+	Page  string // Page path, relative to the site root
+}
+
+// Page is the data passed to the page template.
+type Page struct {
+	Site    string   // Site title
+	Title   string   // Page title
+	Root    string   // Relative path from the page to the site root
+	Tag     string   // Tag of the page, empty on the index
+	Entries []*Entry // Bookmarks of the page
+	Tags    []*Tag   // All the tags
+	Total   int      // Bookmarks in the site
+}
+
+// searchItem is an entry of the client-side search index.
+type searchItem struct {
+	URL   string   `json:"u"`
+	Link  string   `json:"h,omitempty"` // URL, only when it is safe to link to
+	Title string   `json:"t"`
+	Desc  string   `json:"d,omitempty"`
+	Tags  []string `json:"g,omitempty"`
+}
+
+// New returns a site written to dir.
+func New(dir, title string) *Site {
+	return &Site{Dir: dir, Title: cmp.Or(title, "Bookmarks")}
+}
+
+// Generate writes the site. Private bookmarks are left out. The tags and
+// favicons directories are recreated on every run, so pages of removed tags
+// do not linger, which is why only an empty directory or a previously
+// generated site is written to.
+func (s *Site) Generate(bs []*bookmark.Bookmark) (*Result, error) {
+	tmpl, err := s.parseTemplates()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkDir(); err != nil {
+		return nil, err
+	}
+
+	for _, d := range []string{TagsDir, FaviconsDir} {
+		if err := os.RemoveAll(filepath.Join(s.Dir, d)); err != nil {
+			return nil, err
+		}
+	}
+	for _, d := range []string{s.Dir, filepath.Join(s.Dir, TagsDir), filepath.Join(s.Dir, AssetsDir)} {
+		if err := os.MkdirAll(d, dirPerm); err != nil {
+			return nil, err
+		}
+	}
+
+	res := &Result{}
+	entries := make([]*Entry, 0, len(bs))
+	for _, b := range bookmark.WithoutPrivate(bs) {
+		e := newEntry(b)
+		if icon, ok := s.copyFavicon(b); ok {
+			e.Icon = icon
+			res.Favicons++
+		}
+		entries = append(entries, e)
+	}
+	res.Bookmarks = len(entries)
+
+	tags := tagIndex(entries)
+	res.Tags = len(tags)
+
+	index := &Page{Site: s.Title, Title: s.Title, Entries: entries, Tags: tags, Total: len(entries)}
+	if err := s.render(tmpl, IndexFile, index); err != nil {
+		return nil, err
+	}
+
+	for _, t := range tags {
+		p := &Page{
+			Site:    s.Title,
+			Title:   t.Name + " · " + s.Title,
+			Root:    "../",
+			Tag:     t.Name,
+			Entries: withTag(entries, t.Name),
+			Tags:    tags,
+			Total:   len(entries),
+		}
+		if err := s.render(tmpl, t.Page, p); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.writeSearchIndex(entries, tags); err != nil {
+		return nil, err
+	}
+
+	if err := s.copyAssets(); err != nil {
+		return nil, err
+	}
+
+	if err := writeFile(filepath.Join(s.Dir, MarkerFile), nil); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// checkDir makes sure the output directory is empty or a generated site.
+func (s *Site) checkDir() error {
+	entries, err := os.ReadDir(s.Dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+
+	if _, err := os.Stat(filepath.Join(s.Dir, MarkerFile)); err != nil {
+		return fmt.Errorf("%w: %q", ErrNotSite, s.Dir)
+	}
+
+	return nil
+}
+
+func newEntry(b *bookmark.Bookmark) *Entry {
+	e := &Entry{
+		ID:         b.ID,
+		URL:        b.URL,
+		Title:      cmp.Or(b.Title, b.URL),
+		Desc:       b.Desc,
+		Notes:      b.Notes,
+		Favorite:   b.Favorite,
+		Created:    b.CreatedAt,
+		ArchiveURL: b.ArchiveURL,
+		Status:     newStatus(b.HTTPStatusCode, b.HTTPStatusText),
+	}
+
+	for t := range strings.SplitSeq(b.Tags, ",") {
+		if t != "" && t != bookmark.DefaultTag {
+			e.Tags = append(e.Tags, t)
+		}
+	}
+
+	return e
+}
+
+func newStatus(code int, text string) Status {
+	s := Status{Code: code, Text: text, Class: "unknown"}
+	switch {
+	case code >= 200 && code < 300:
+		s.Class = "ok"
+	case code >= 300 && code < 400:
+		s.Class = "redirect"
+	case code >= 400:
+		s.Class = "broken"
+	}
+
+	if s.Text == "" && code != 0 {
+		s.Text = strconv.Itoa(code)
+	}
+
+	return s
+}
+
+// tagIndex returns the tags of the entries sorted by name.
+func tagIndex(entries []*Entry) []*Tag {
+	counts := make(map[string]int)
+	for _, e := range entries {
+		for _, t := range e.Tags {
+			counts[t]++
+		}
+	}
+
+	tags := make([]*Tag, 0, len(counts))
+	for name, n := range counts {
+		tags = append(tags, &Tag{Name: name, Count: n, Page: TagsDir + "/" + tagFile(name)})
+	}
+	slices.SortFunc(tags, func(a, b *Tag) int { return strings.Compare(a.Name, b.Name) })
+
+	return tags
+}
+
+func withTag(entries []*Entry, tag string) []*Entry {
+	var out []*Entry
+	for _, e := range entries {
+		if slices.Contains(e.Tags, tag) {
+			out = append(out, e)
+		}
+	}
+
+	return out
+}
+
+// tagFile returns the page file name of a tag. When the name is not the tag
+// as is, e.g. "c++" and "c--" both become "c--", a short hash of the tag
+// keeps the pages apart. Upper case is folded for case-insensitive file
+// systems, so it gets the hash too.
+func tagFile(tag string) string {
+	name := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_' || r == '.' {
+			return unicode.ToLower(r)
+		}
+		return '-'
+	}, tag)
+	name = strings.TrimLeft(name, ".")
+
+	if name != tag {
+		sum := sha256.Sum256([]byte(tag))
+		name += "-" + hex.EncodeToString(sum[:4])
+	}
+
+	return name + ".html"
+}
+
+// parseTemplates parses the builtin templates, then the ones in the
+// templates directory, which replace the builtin ones with the same name.
+func (s *Site) parseTemplates() (*template.Template, error) {
+	tmpl, err := template.New("").Funcs(funcMap()).ParseFS(embedded, "templates/*.html")
+	if err != nil {
+		return nil, err
+	}
+
+	if s.Templates == "" {
+		return tmpl, nil
+	}
+
+	overrides, err := filepath.Glob(filepath.Join(s.Templates, "*.html"))
+	if err != nil {
+		return nil, err
+	}
+	if len(overrides) == 0 {
+		if _, err := os.Stat(s.Templates); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrTemplates, err)
+		}
+		return tmpl, nil
+	}
+
+	tmpl, err = tmpl.ParseFiles(overrides...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrTemplates, err)
+	}
+
+	return tmpl, nil
+}
+
+func funcMap() template.FuncMap {
+	return template.FuncMap{
+		"tagPage": func(tag string) string { return TagsDir + "/" + tagFile(tag) },
+		"entry": func(e *Entry, root string) map[string]any {
+			return map[string]any{"Entry": e, "Root": root}
+		},
+		"date": func(s string) string {
+			d, _, _ := strings.Cut(s, " ")
+			d, _, _ = strings.Cut(d, "T")
+			return d
+		},
+	}
+}
+
+func (s *Site) render(tmpl *template.Template, name string, p *Page) error {
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, pageTemplate, p); err != nil {
+		return fmt.Errorf("rendering %s: %w", name, err)
+	}
+
+	return writeFile(filepath.Join(s.Dir, name), buf.Bytes())
+}
+
+// writeSearchIndex writes the search index as a script, so the search works
+// when the site is opened from the file system. The tag pages are listed
+// too, their names are not derived from the tag alone.
+func (s *Site) writeSearchIndex(entries []*Entry, tags []*Tag) error {
+	items := make([]searchItem, 0, len(entries))
+	for _, e := range entries {
+		item := searchItem{URL: e.URL, Title: e.Title, Desc: e.Desc, Tags: e.Tags}
+		if isWebURL(e.URL) {
+			item.Link = e.URL
+		}
+		items = append(items, item)
+	}
+
+	data, err := json.Marshal(items)
+	if err != nil {
+		return err
+	}
+
+	pages := make(map[string]string, len(tags))
+	for _, t := range tags {
+		pages[t.Name] = t.Page
+	}
+
+	tagData, err := json.Marshal(pages)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("window.GM_INDEX = ")
+	buf.Write(data)
+	buf.WriteString(";\nwindow.GM_TAGS = ")
+	buf.Write(tagData)
+	buf.WriteString(";\n")
+
+	return writeFile(filepath.Join(s.Dir, AssetsDir, SearchIndex), buf.Bytes())
+}
+
+// isWebURL reports whether u is an http or https URL. The search script
+// builds the links itself, so unlike the pages rendered by html/template,
+// nothing else keeps a javascript: URL from becoming a link.
+func isWebURL(u string) bool {
+	p, err := url.Parse(u)
+	if err != nil {
+		return false
+	}
+
+	return p.Scheme == "http" || p.Scheme == "https"
+}
+
+// copyAssets writes the builtin assets, or their replacement from the
+// templates directory.
+func (s *Site) copyAssets() error {
+	assets, err := fs.ReadDir(embedded, AssetsDir)
+	if err != nil {
+		return err
+	}
+
+	for _, a := range assets {
+		var data []byte
+		if s.Templates != "" {
+			data, err = os.ReadFile(filepath.Join(s.Templates, a.Name()))
+			if err != nil && !errors.Is(err, os.ErrNotExist) {
+				return err
+			}
+		}
+		if data == nil {
+			if data, err = embedded.ReadFile(AssetsDir + "/" + a.Name()); err != nil {
+				return err
+			}
+		}
+
+		if err := writeFile(filepath.Join(s.Dir, AssetsDir, a.Name()), data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// copyFavicon copies the cached favicon of b into the site.
+func (s *Site) copyFavicon(b *bookmark.Bookmark) (string, bool) {
+	if b.FaviconLocal == "" {
+		return "", false
+	}
+
+	src, err := os.Open(b.FaviconLocal)
+	if err != nil {
+		return "", false
+	}
+	defer src.Close()
+
+	ext := cmp.Or(strings.ToLower(filepath.Ext(b.FaviconLocal)), ".ico")
+	name := FaviconsDir + "/" + strconv.Itoa(b.ID) + ext
+
+	if err := os.MkdirAll(filepath.Join(s.Dir, FaviconsDir), dirPerm); err != nil {
+		return "", false
+	}
+
+	dst, err := os.Create(filepath.Join(s.Dir, filepath.FromSlash(name)))
+	if err != nil {
+		return "", false
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		_ = dst.Close()
+		return "", false
+	}
+	if err := dst.Close(); err != nil {
+		return "", false
+	}
+
+	return name, true
+}
+
+const (
+	dirPerm  = 0o755
+	filePerm = 0o644
+)
+
+func writeFile(path string, data []byte) error {
+	return os.WriteFile(path, data, filePerm)
+}
@@ -0,0 +1,223 @@
+package site
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mateconpizza/gm/pkg/bookmark"
+)
+
+func testBookmarks(t *testing.T) []*bookmark.Bookmark {
+	t.Helper()
+
+	icon := filepath.Join(t.TempDir(), "go.png")
+	if err := os.WriteFile(icon, []byte("png"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return []*bookmark.Bookmark{
+		{
+			ID: 1, URL: "https://go.dev/", Title: "Go <home>", Desc: "Go home", Tags: "go,c++ lang,",
+			CreatedAt: "2023-01-15 10:30:00", HTTPStatusCode: 200, HTTPStatusText: "OK",
+			ArchiveURL: "https://web.archive.org/web/2023/https://go.dev/", FaviconLocal: icon,
+		},
+		{ID: 2, URL: "https://gone.example/", Tags: bookmark.DefaultTag, HTTPStatusCode: 404, FaviconLocal: "/nonexistent.ico"},
+		{ID: 3, URL: "https://private.example/", Title: "Private", Tags: "go,secret,", Private: true},
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(data)
+}
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	res, err := New(dir, "Team links").Generate(testBookmarks(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *res != (Result{Bookmarks: 2, Tags: 2, Favicons: 1}) {
+		t.Errorf("unexpected result: %+v", res)
+	}
+
+	index := readFile(t, filepath.Join(dir, IndexFile))
+	for _, want := range []string{
+		"<title>Team links</title>",
+		"Go &lt;home&gt;",
+		`<span class="badge ok" title="OK">200</span>`,
+		`<span class="badge broken" title="404">404</span>`,
+		`href="https://web.archive.org/web/2023/https://go.dev/"`,
+		`src="favicons/1.png"`,
+		`href="tags/` + tagFile("c++ lang") + `"`,
+	} {
+		if !strings.Contains(index, want) {
+			t.Errorf("index missing %q", want)
+		}
+	}
+	if strings.Contains(index, "private.example") || strings.Contains(index, "secret") {
+		t.Error("private bookmark in the index")
+	}
+
+	tag := readFile(t, filepath.Join(dir, TagsDir, "go.html"))
+	for _, want := range []string{`href="../assets/style.css"`, `src="../favicons/1.png"`, "1 of 2 bookmarks"} {
+		if !strings.Contains(tag, want) {
+			t.Errorf("tag page missing %q", want)
+		}
+	}
+
+	search := readFile(t, filepath.Join(dir, AssetsDir, SearchIndex))
+	if !strings.HasPrefix(search, "window.GM_INDEX = [") || strings.Contains(search, "private.example") {
+		t.Errorf("unexpected search index: %s", search)
+	}
+
+	for _, f := range []string{"style.css", "search.js"} {
+		if _, err := os.Stat(filepath.Join(dir, AssetsDir, f)); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestGenerateRemovesStaleTags(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	bs := testBookmarks(t)
+	if _, err := New(dir, "").Generate(bs); err != nil {
+		t.Fatal(err)
+	}
+
+	bs[0].Tags = "go,"
+	if _, err := New(dir, "").Generate(bs); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, TagsDir, tagFile("c++ lang"))); !errors.Is(err, os.ErrNotExist) {
+		t.Error("page of a removed tag kept")
+	}
+}
+
+func TestGenerateUnsafeURL(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	bs := []*bookmark.Bookmark{
+		{ID: 1, URL: "javascript:alert(1)", Title: "Evil"},
+		{ID: 2, URL: "https://go.dev/", Title: "Go"},
+	}
+	if _, err := New(dir, "").Generate(bs); err != nil {
+		t.Fatal(err)
+	}
+
+	if index := readFile(t, filepath.Join(dir, IndexFile)); strings.Contains(index, `href="javascript:`) {
+		t.Error("javascript: URL linked in the index")
+	}
+
+	search := readFile(t, filepath.Join(dir, AssetsDir, SearchIndex))
+	data, _, _ := strings.Cut(strings.TrimPrefix(search, "window.GM_INDEX = "), ";\n")
+
+	var items []searchItem
+	if err := json.Unmarshal([]byte(data), &items); err != nil {
+		t.Fatalf("invalid search index: %v\n%s", err, search)
+	}
+	if len(items) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(items))
+	}
+	if items[0].Link != "" || items[0].URL != "javascript:alert(1)" {
+		t.Errorf("javascript: URL linked in the search index: %+v", items[0])
+	}
+	if items[1].Link != "https://go.dev/" {
+		t.Errorf("https URL not linked in the search index: %+v", items[1])
+	}
+}
+
+func TestTagFile(t *testing.T) {
+	t.Parallel()
+
+	if got := tagFile("go"); got != "go.html" {
+		t.Errorf("expected go.html, got %q", got)
+	}
+
+	seen := make(map[string]string)
+	for _, tag := range []string{"c++", "c--", "c__", ".", "..", "Go", "go", "a/b", "a-b"} {
+		name := tagFile(tag)
+		if prev, ok := seen[strings.ToLower(name)]; ok {
+			t.Errorf("tags %q and %q share the page %q", prev, tag, name)
+		}
+		seen[strings.ToLower(name)] = tag
+
+		if strings.HasPrefix(name, ".") || strings.ContainsAny(name, "/+") {
+			t.Errorf("unexpected page name %q for tag %q", name, tag)
+		}
+	}
+}
+
+func TestGenerateTemplates(t *testing.T) {
+	t.Parallel()
+
+	tmpl := t.TempDir()
+	files := map[string]string{
+		"partials.html": `{{define "bookmark"}}<p>{{.Entry.Title}}</p>{{end}}`,
+		"style.css":     "body { color: red; }\n",
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(tmpl, name), []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dir := t.TempDir()
+	s := New(dir, "")
+	s.Templates = tmpl
+	if _, err := s.Generate(testBookmarks(t)); err != nil {
+		t.Fatal(err)
+	}
+
+	index := readFile(t, filepath.Join(dir, IndexFile))
+	if !strings.Contains(index, "<p>Go &lt;home&gt;</p>") {
+		t.Errorf("template not overridden:\n%s", index)
+	}
+	if !strings.Contains(index, `<ul class="tags">`) {
+		t.Error("builtin template not kept")
+	}
+	if css := readFile(t, filepath.Join(dir, AssetsDir, "style.css")); css != files["style.css"] {
+		t.Errorf("asset not overridden: %q", css)
+	}
+
+	s.Templates = filepath.Join(tmpl, "missing")
+	if _, err := s.Generate(nil); !errors.Is(err, ErrTemplates) {
+		t.Errorf("expected ErrTemplates, got %v", err)
+	}
+}
+
+func TestGenerateNotSite(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	keep := filepath.Join(dir, TagsDir, "keep.txt")
+	if err := os.MkdirAll(filepath.Dir(keep), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keep, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := New(dir, "").Generate(testBookmarks(t)); !errors.Is(err, ErrNotSite) {
+		t.Errorf("expected ErrNotSite, got %v", err)
+	}
+	if _, err := os.Stat(keep); err != nil {
+		t.Error("existing file removed")
+	}
+}
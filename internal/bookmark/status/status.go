@@ -137,6 +137,36 @@ func Check(ctx context.Context, c *ui.Console, bs []*bookmark.Bookmark) ([]*book
 	return results.updated, nil
 }
 
+// Probe checks the status of a slice of bookmarks without any output and
+// returns the bookmarks whose status code changed.
+func Probe(ctx context.Context, bs []*bookmark.Bookmark) ([]*bookmark.Bookmark, error) {
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(runtime.NumCPU())
+
+	results := new(Results)
+	for _, b := range bs {
+		g.Go(func() error {
+			old := b.HTTPStatusCode
+			res := makeRequest(ctx, b)
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			if res.statusCode != old {
+				results.Add(&res)
+			}
+
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	return results.updated, nil
+}
+
 // prettifyURLStatus formats HTTP status codes into colored.
 func prettifyURLStatus(p *ansi.Palette, code int) (status, statusCode string) {
 	statusCategory := code / 100
@@ -0,0 +1,372 @@
+// Package vault exports bookmarks as a folder of Markdown or Org notes, one
+// file per bookmark, and reads back the edits made to them.
+package vault
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/mateconpizza/gm/pkg/bookmark"
+)
+
+// Note formats.
+const (
+	FormatMarkdown = "md"
+	FormatOrg      = "org"
+)
+
+var (
+	ErrFormat      = errors.New("invalid vault format")
+	ErrFrontMatter = errors.New("invalid front matter")
+)
+
+// Formats returns the supported note formats.
+func Formats() []string {
+	return []string{FormatMarkdown, FormatOrg}
+}
+
+// ValidFormat returns the validated format, defaults to Markdown.
+func ValidFormat(format string) (string, error) {
+	switch f := strings.ToLower(strings.TrimSpace(format)); f {
+	case "", FormatMarkdown, "markdown":
+		return FormatMarkdown, nil
+	case FormatOrg:
+		return FormatOrg, nil
+	default:
+		return "", fmt.Errorf("%w: %q (use %s)", ErrFormat, format, strings.Join(Formats(), ", "))
+	}
+}
+
+// frontMatter holds the bookmark fields written in the note header. Dates
+// and status are informative, edits to them are ignored.
+type frontMatter struct {
+	ID       int      `yaml:"id"`
+	URL      string   `yaml:"url"`
+	Title    string   `yaml:"title"`
+	Desc     string   `yaml:"description,omitempty"`
+	Tags     []string `yaml:"tags"`
+	Favorite bool     `yaml:"favorite"`
+	Created  string   `yaml:"created,omitempty"`
+	Updated  string   `yaml:"updated,omitempty"`
+	Visited  string   `yaml:"last_visit,omitempty"`
+	Status   string   `yaml:"status,omitempty"`
+}
+
+func newFrontMatter(b *bookmark.Bookmark) *frontMatter {
+	fm := &frontMatter{
+		ID:       b.ID,
+		URL:      b.URL,
+		Title:    b.Title,
+		Desc:     b.Desc,
+		Tags:     tagList(b.Tags),
+		Favorite: b.Favorite,
+		Created:  b.CreatedAt,
+		Updated:  b.UpdatedAt,
+		Visited:  b.LastVisit,
+	}
+	if b.HTTPStatusCode != 0 {
+		fm.Status = strings.TrimSpace(strconv.Itoa(b.HTTPStatusCode) + " " + b.HTTPStatusText)
+	}
+
+	return fm
+}
+
+// note is the editable content read from a note file.
+type note struct {
+	id       int
+	url      string
+	title    string
+	desc     string
+	tags     []string
+	favorite bool
+	body     string
+}
+
+// apply returns a copy of b with the fields that differ from base, the
+// note as exported. The untouched fields keep the value of b, the file may
+// hold a lossy version of them, e.g. an Org description on a single line.
+func (n *note) apply(b *bookmark.Bookmark, base *note) *bookmark.Bookmark {
+	nb := b.Copy()
+	if n.url != base.url {
+		nb.URL = n.url
+	}
+	if n.title != base.title {
+		nb.Title = n.title
+	}
+	if n.desc != base.desc {
+		nb.Desc = n.desc
+	}
+	if !slices.Equal(n.tags, base.tags) {
+		nb.Tags = bookmark.ParseTags(strings.Join(n.tags, ","))
+	}
+	if n.favorite != base.favorite {
+		nb.Favorite = n.favorite
+	}
+	if n.body != base.body {
+		nb.Notes = n.body
+	}
+
+	return nb
+}
+
+// render returns the note file of b.
+func render(format string, b *bookmark.Bookmark) ([]byte, error) {
+	if format == FormatOrg {
+		return renderOrg(b), nil
+	}
+
+	return renderMarkdown(b)
+}
+
+// parse reads a note file.
+func parse(format string, data []byte) (*note, error) {
+	if format == FormatOrg {
+		return parseOrg(data)
+	}
+
+	return parseMarkdown(data)
+}
+
+// renderMarkdown writes the YAML front matter followed by the notes.
+func renderMarkdown(b *bookmark.Bookmark) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("---\n")
+
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(newFrontMatter(b)); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+
+	buf.WriteString("---\n")
+	writeBody(&buf, b.Notes)
+
+	return buf.Bytes(), nil
+}
+
+func parseMarkdown(data []byte) (*note, error) {
+	data = bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+
+	rest, ok := bytes.CutPrefix(data, []byte("---\n"))
+	if !ok {
+		return nil, fmt.Errorf("%w: missing opening ---", ErrFrontMatter)
+	}
+
+	header, body, ok := bytes.Cut(rest, []byte("\n---\n"))
+	if !ok {
+		// front matter closing at the end of the file
+		header, ok = bytes.CutSuffix(rest, []byte("\n---"))
+		if !ok {
+			return nil, fmt.Errorf("%w: missing closing ---", ErrFrontMatter)
+		}
+		body = nil
+	}
+
+	var fm frontMatter
+	if err := yaml.Unmarshal(header, &fm); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFrontMatter, err)
+	}
+
+	return &note{
+		id:       fm.ID,
+		url:      fm.URL,
+		title:    fm.Title,
+		desc:     fm.Desc,
+		tags:     fm.Tags,
+		favorite: fm.Favorite,
+		body:     readBody(body),
+	}, nil
+}
+
+// Org keywords holding the bookmark fields.
+const (
+	orgTitle    = "title"
+	orgURL      = "url"
+	orgTags     = "filetags"
+	orgDesc     = "description"
+	orgID       = "gm_id"
+	orgFavorite = "favorite"
+	orgCreated  = "created"
+	orgUpdated  = "updated"
+	orgVisited  = "last_visit"
+	orgStatus   = "status"
+)
+
+// renderOrg writes the fields as in-buffer settings followed by the notes.
+// Org keywords hold a single line, so line breaks in the description are
+// replaced by spaces.
+func renderOrg(b *bookmark.Bookmark) []byte {
+	fm := newFrontMatter(b)
+
+	var buf bytes.Buffer
+	kw := func(key, val string) {
+		if val != "" {
+			fmt.Fprintf(&buf, "#+%s: %s\n", key, val)
+		}
+	}
+
+	kw(orgTitle, oneLine(fm.Title))
+	kw(orgURL, fm.URL)
+	if len(fm.Tags) > 0 {
+		kw(orgTags, ":"+strings.Join(fm.Tags, ":")+":")
+	}
+	kw(orgDesc, oneLine(fm.Desc))
+	kw(orgID, strconv.Itoa(fm.ID))
+	kw(orgFavorite, strconv.FormatBool(fm.Favorite))
+	kw(orgCreated, fm.Created)
+	kw(orgUpdated, fm.Updated)
+	kw(orgVisited, fm.Visited)
+	kw(orgStatus, fm.Status)
+	writeBody(&buf, b.Notes)
+
+	return buf.Bytes()
+}
+
+func parseOrg(data []byte) (*note, error) {
+	n := &note{}
+
+	var (
+		body   bytes.Buffer
+		header = true
+		found  bool
+	)
+
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	sc.Buffer(make([]byte, 0, 64*1024), len(data)+1)
+	for sc.Scan() {
+		line := strings.TrimRight(sc.Text(), "\r")
+
+		if header {
+			key, val, ok := orgKeyword(line)
+			if ok {
+				found = true
+				switch key {
+				case orgTitle:
+					n.title = val
+				case orgURL:
+					n.url = val
+				case orgTags:
+					n.tags = strings.FieldsFunc(val, func(r rune) bool { return r == ':' })
+				case orgDesc:
+					n.desc = val
+				case orgID:
+					n.id, _ = strconv.Atoi(val)
+				case orgFavorite:
+					n.favorite = val == "true" || val == "t"
+				}
+				continue
+			}
+			header = false
+		}
+
+		body.WriteString(line)
+		body.WriteByte('\n')
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+
+	if !found {
+		return nil, fmt.Errorf("%w: missing #+ keywords", ErrFrontMatter)
+	}
+
+	n.body = readBody(body.Bytes())
+
+	return n, nil
+}
+
+// orgKeyword splits a "#+key: value" line.
+func orgKeyword(line string) (key, val string, ok bool) {
+	rest, ok := strings.CutPrefix(line, "#+")
+	if !ok {
+		return "", "", false
+	}
+
+	key, val, ok = strings.Cut(rest, ":")
+	if !ok {
+		return "", "", false
+	}
+
+	return strings.ToLower(strings.TrimSpace(key)), strings.TrimSpace(val), true
+}
+
+// writeBody writes the notes after a blank line.
+func writeBody(buf *bytes.Buffer, notes string) {
+	notes = strings.TrimRight(notes, "\n")
+	if notes == "" {
+		return
+	}
+
+	buf.WriteString("\n" + notes + "\n")
+}
+
+// readBody returns the notes without the blank line written before them
+// and the trailing line breaks.
+func readBody(body []byte) string {
+	s := strings.TrimPrefix(string(body), "\n")
+	return strings.TrimRight(s, "\n")
+}
+
+// tagList returns the tags of a bookmark without the default one.
+func tagList(tags string) []string {
+	out := []string{}
+	for t := range strings.SplitSeq(tags, ",") {
+		if t != "" && t != bookmark.DefaultTag {
+			out = append(out, t)
+		}
+	}
+
+	return out
+}
+
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// slug returns a file name friendly version of s.
+func slug(s string) string {
+	const maxLen = 60
+
+	var (
+		sb   strings.Builder
+		dash bool
+		n    int
+	)
+
+	for _, r := range strings.ToLower(s) {
+		if n >= maxLen {
+			break
+		}
+
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			sb.WriteRune(r)
+			dash = false
+			n++
+			continue
+		}
+
+		if !dash && sb.Len() > 0 {
+			sb.WriteByte('-')
+			dash = true
+			n++
+		}
+	}
+
+	out := strings.TrimRight(sb.String(), "-")
+	if out == "" {
+		return "bookmark"
+	}
+
+	return out
+}
@@ -0,0 +1,432 @@
+package vault
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/mateconpizza/gm/internal/sys"
+	"github.com/mateconpizza/gm/pkg/bookmark"
+)
+
+// ManifestFile keeps track of the exported notes, it lives in the vault
+// directory.
+const ManifestFile = ".gm-vault.json"
+
+// indexName is the base name of the index note.
+const indexName = "index"
+
+var (
+	ErrConflict = errors.New("note and bookmark both changed since the last sync, remove the note to export it again")
+	ErrNoteURL  = errors.New("invalid URL")
+)
+
+// Store is the bookmark repository the vault is synced with.
+type Store interface {
+	All(ctx context.Context) ([]*bookmark.Bookmark, error)
+	UpdateMany(ctx context.Context, bs []*bookmark.Bookmark) error
+}
+
+// Vault is a directory of notes, one per bookmark.
+type Vault struct {
+	Dir    string
+	Format string
+
+	// Filter selects the bookmarks to export, nil exports all of them.
+	Filter func(*bookmark.Bookmark) bool
+
+	// OnImport records the bookmarks updated from edited notes once they are
+	// saved, e.g. in the git repository. olds and fresh are matched by index.
+	OnImport func(ctx context.Context, olds, fresh []*bookmark.Bookmark) error
+}
+
+// New returns a vault in dir with the notes in format.
+func New(dir, format string) (*Vault, error) {
+	f, err := ValidFormat(format)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Vault{Dir: dir, Format: f}, nil
+}
+
+// Result reports the changes made by a sync.
+type Result struct {
+	Written  int      // notes written
+	Imported int      // bookmarks updated from edited notes
+	Removed  int      // notes of deleted bookmarks
+	Invalid  []string // edited notes that could not be read
+}
+
+// Changed reports whether the sync changed anything.
+func (r *Result) Changed() bool {
+	return r.Written+r.Imported+r.Removed > 0 || len(r.Invalid) > 0
+}
+
+// entry is an exported note.
+type entry struct {
+	File string `json:"file"`
+	Hash string `json:"hash"` // content as written by the last sync
+}
+
+type manifest struct {
+	Format string         `json:"format"`
+	Notes  map[int]*entry `json:"notes"`
+}
+
+// Sync brings the vault and the store in line. Notes edited since the last
+// sync are read back into the store first, then the notes of changed
+// bookmarks are rewritten, the ones of deleted bookmarks removed and the
+// index regenerated. Private bookmarks are never exported.
+func (v *Vault) Sync(ctx context.Context, s Store) (*Result, error) {
+	if err := os.MkdirAll(v.Dir, 0o750); err != nil {
+		return nil, err
+	}
+
+	m, raw, err := v.loadManifest()
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Result{}
+	bs, err := v.bookmarks(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+
+	// edits made to the notes
+	invalid := make(map[int]bool)
+	skip := func(b *bookmark.Bookmark, err error) {
+		invalid[b.ID] = true
+		res.Invalid = append(res.Invalid, fmt.Sprintf("%s: %v", m.Notes[b.ID].File, err))
+	}
+
+	var olds, edits []*bookmark.Bookmark
+	for _, b := range bs {
+		n, err := v.edited(m.Notes[b.ID])
+		if err != nil {
+			skip(b, err)
+			continue
+		}
+		if n == nil {
+			continue
+		}
+
+		base, err := v.exported(m.Notes[b.ID], b)
+		if err != nil {
+			skip(b, err)
+			continue
+		}
+
+		nb := n.apply(b, base)
+		if len(bookmark.Diff(b, nb)) == 0 {
+			continue
+		}
+		if err := validate(nb); err != nil {
+			skip(b, err)
+			continue
+		}
+
+		olds = append(olds, b)
+		edits = append(edits, nb)
+	}
+
+	if len(edits) > 0 {
+		if err := s.UpdateMany(ctx, edits); err != nil {
+			return res, fmt.Errorf("updating bookmarks: %w", err)
+		}
+		res.Imported = len(edits)
+
+		if v.OnImport != nil {
+			if err := v.OnImport(ctx, olds, edits); err != nil {
+				return res, err
+			}
+		}
+
+		if bs, err = v.bookmarks(ctx, s); err != nil {
+			return res, err
+		}
+	}
+
+	// notes of the current bookmarks
+	keep := make(map[int]bool, len(bs))
+	for _, b := range bs {
+		keep[b.ID] = true
+		if invalid[b.ID] {
+			continue
+		}
+
+		written, err := v.writeNote(m, b)
+		if err != nil {
+			return res, err
+		}
+		if written {
+			res.Written++
+		}
+	}
+
+	// notes of deleted bookmarks, kept when edited
+	for id, e := range m.Notes {
+		if keep[id] {
+			continue
+		}
+		delete(m.Notes, id)
+
+		path := filepath.Join(v.Dir, e.File)
+		data, err := os.ReadFile(path)
+		if err != nil || hash(data) != e.Hash {
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			return res, err
+		}
+		res.Removed++
+	}
+
+	if err := v.writeIndex(m, bs); err != nil {
+		return res, err
+	}
+
+	return res, v.saveManifest(m, raw)
+}
+
+// bookmarks returns the bookmarks to export.
+func (v *Vault) bookmarks(ctx context.Context, s Store) ([]*bookmark.Bookmark, error) {
+	all, err := s.All(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	bs := make([]*bookmark.Bookmark, 0, len(all))
+	for _, b := range bookmark.WithoutPrivate(all) {
+		if v.Filter == nil || v.Filter(b) {
+			bs = append(bs, b)
+		}
+	}
+
+	return bs, nil
+}
+
+// edited returns the note of e when its file changed since the last sync,
+// or nil.
+func (v *Vault) edited(e *entry) (*note, error) {
+	if e == nil {
+		return nil, nil //nolint:nilnil // not exported yet
+	}
+
+	data, err := os.ReadFile(filepath.Join(v.Dir, e.File))
+	if errors.Is(err, os.ErrNotExist) {
+		// a removed note is written again
+		return nil, nil //nolint:nilnil // nothing to read back
+	}
+	if err != nil {
+		return nil, err
+	}
+	if hash(data) == e.Hash {
+		return nil, nil //nolint:nilnil // unchanged
+	}
+
+	return parse(v.Format, data)
+}
+
+// exported returns the note as written by the last sync, which is what b
+// renders to unless b changed in the store since then. In that case the edit
+// would overwrite the change and ErrConflict is returned.
+func (v *Vault) exported(e *entry, b *bookmark.Bookmark) (*note, error) {
+	data, err := render(v.Format, b)
+	if err != nil {
+		return nil, err
+	}
+	if hash(data) != e.Hash {
+		return nil, ErrConflict
+	}
+
+	return parse(v.Format, data)
+}
+
+// validate checks a bookmark read back from a note.
+func validate(b *bookmark.Bookmark) error {
+	if err := bookmark.Validate(b); err != nil {
+		return err
+	}
+
+	u, err := url.Parse(b.URL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return fmt.Errorf("%w: %q", ErrNoteURL, b.URL)
+	}
+
+	return nil
+}
+
+// writeNote writes the note of b when its content changed. The file name is
+// kept across title changes.
+func (v *Vault) writeNote(m *manifest, b *bookmark.Bookmark) (bool, error) {
+	data, err := render(v.Format, b)
+	if err != nil {
+		return false, err
+	}
+
+	e, ok := m.Notes[b.ID]
+	if !ok {
+		e = &entry{File: slug(b.Title) + "-" + strconv.Itoa(b.ID) + "." + v.Format}
+		m.Notes[b.ID] = e
+	}
+
+	path := filepath.Join(v.Dir, e.File)
+	h := hash(data)
+	if h == e.Hash {
+		if _, err := os.Stat(path); err == nil {
+			return false, nil
+		}
+	}
+
+	if err := writeFile(path, data); err != nil {
+		return false, err
+	}
+	e.Hash = h
+
+	return true, nil
+}
+
+// IndexFile returns the base name of the index note.
+func (v *Vault) IndexFile() string {
+	return indexName + "." + v.Format
+}
+
+// writeIndex writes the index note, with the bookmarks grouped by tag.
+func (v *Vault) writeIndex(m *manifest, bs []*bookmark.Bookmark) error {
+	groups := make(map[string][]*bookmark.Bookmark)
+	for _, b := range bs {
+		if _, ok := m.Notes[b.ID]; !ok {
+			continue
+		}
+
+		tags := tagList(b.Tags)
+		if len(tags) == 0 {
+			tags = []string{bookmark.DefaultTag}
+		}
+		for _, t := range tags {
+			groups[t] = append(groups[t], b)
+		}
+	}
+
+	tags := make([]string, 0, len(groups))
+	for t := range groups {
+		tags = append(tags, t)
+	}
+	slices.Sort(tags)
+
+	var buf bytes.Buffer
+	if v.Format == FormatOrg {
+		buf.WriteString("#+title: Bookmarks\n")
+	} else {
+		buf.WriteString("# Bookmarks\n")
+	}
+
+	for _, t := range tags {
+		group := groups[t]
+		slices.SortStableFunc(group, func(a, b *bookmark.Bookmark) int {
+			return strings.Compare(strings.ToLower(a.Title), strings.ToLower(b.Title))
+		})
+
+		if v.Format == FormatOrg {
+			fmt.Fprintf(&buf, "\n* %s\n", t)
+		} else {
+			fmt.Fprintf(&buf, "\n## %s\n\n", t)
+		}
+
+		for _, b := range group {
+			title := oneLine(b.Title)
+			if title == "" {
+				title = b.URL
+			}
+			file := m.Notes[b.ID].File
+
+			if v.Format == FormatOrg {
+				fmt.Fprintf(&buf, "- [[file:%s][%s]]\n", file, title)
+			} else {
+				fmt.Fprintf(&buf, "- [%s](%s)\n", escapeLinkText(title), escapeLinkDest(file))
+			}
+		}
+	}
+
+	path := filepath.Join(v.Dir, v.IndexFile())
+	if old, err := os.ReadFile(path); err == nil && bytes.Equal(old, buf.Bytes()) {
+		return nil
+	}
+
+	return writeFile(path, buf.Bytes())
+}
+
+func (v *Vault) loadManifest() (m *manifest, raw []byte, err error) {
+	m = &manifest{Format: v.Format, Notes: make(map[int]*entry)}
+
+	raw, err = os.ReadFile(filepath.Join(v.Dir, ManifestFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return m, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := json.Unmarshal(raw, m); err != nil {
+		return nil, nil, fmt.Errorf("reading %s: %w", ManifestFile, err)
+	}
+	if m.Notes == nil {
+		m.Notes = make(map[int]*entry)
+	}
+
+	if m.Format != v.Format && len(m.Notes) > 0 {
+		return nil, nil, fmt.Errorf("%w: vault holds %q notes, not %q", ErrFormat, m.Format, v.Format)
+	}
+	m.Format = v.Format
+
+	return m, raw, nil
+}
+
+// saveManifest writes the manifest when it differs from raw, so a watch
+// does not wake up for nothing.
+func (v *Vault) saveManifest(m *manifest, raw []byte) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	if bytes.Equal(data, raw) {
+		return nil
+	}
+
+	return writeFile(filepath.Join(v.Dir, ManifestFile), data)
+}
+
+func hash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+var linkTextReplacer = strings.NewReplacer(`\`, `\\`, `[`, `\[`, `]`, `\]`)
+
+func escapeLinkText(s string) string {
+	return linkTextReplacer.Replace(s)
+}
+
+func escapeLinkDest(s string) string {
+	return strings.NewReplacer(" ", "%20", "(", "%28", ")", "%29").Replace(s)
+}
+
+// writeFile replaces the file at path with data, keeping the mode of the
+// file it replaces.
+func writeFile(path string, data []byte) error {
+	return sys.WriteFileAtomic(path, data, sys.FileMode(path, 0o600))
+}
@@ -0,0 +1,450 @@
+package vault
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mateconpizza/gm/pkg/bookmark"
+)
+
+// memStore is an in-memory Store.
+type memStore struct {
+	bs []*bookmark.Bookmark
+}
+
+func (s *memStore) All(context.Context) ([]*bookmark.Bookmark, error) {
+	out := make([]*bookmark.Bookmark, 0, len(s.bs))
+	for _, b := range s.bs {
+		out = append(out, b.Copy())
+	}
+
+	return out, nil
+}
+
+func (s *memStore) UpdateMany(_ context.Context, bs []*bookmark.Bookmark) error {
+	for _, b := range bs {
+		if err := s.update(b); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *memStore) update(b *bookmark.Bookmark) error {
+	for i := range s.bs {
+		if s.bs[i].ID == b.ID {
+			b.UpdatedAt = "2025-01-01 00:00:00"
+			s.bs[i] = b.Copy()
+			return nil
+		}
+	}
+
+	return bookmark.ErrBookmarkNotFound
+}
+
+func (s *memStore) remove(id int) {
+	for i := range s.bs {
+		if s.bs[i].ID == id {
+			s.bs = append(s.bs[:i], s.bs[i+1:]...)
+			return
+		}
+	}
+}
+
+func testStore() *memStore {
+	return &memStore{bs: []*bookmark.Bookmark{
+		{
+			ID: 1, URL: "https://go.dev/", Title: "The Go Programming Language", Desc: "Go home",
+			Tags: "go,programming,", Notes: "Start with the tour\n\n- then effective go",
+			CreatedAt: "2023-01-15 10:30:00", HTTPStatusCode: 200, HTTPStatusText: "OK", Favorite: true,
+		},
+		{ID: 2, URL: "https://example.org/", Title: "Example: [one]", Tags: bookmark.DefaultTag},
+		{ID: 3, URL: "https://private.example/", Title: "Private", Tags: "secret,", Private: true},
+	}}
+}
+
+func TestNoteRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	for _, format := range Formats() {
+		t.Run(format, func(t *testing.T) {
+			t.Parallel()
+
+			for _, b := range testStore().bs[:2] {
+				data, err := render(format, b)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				n, err := parse(format, data)
+				if err != nil {
+					t.Fatalf("parsing:\n%s\n%v", data, err)
+				}
+				if n.id != b.ID {
+					t.Errorf("id: got %d, want %d", n.id, b.ID)
+				}
+				if n.url != b.URL || n.title != b.Title || n.desc != b.Desc || n.favorite != b.Favorite ||
+					bookmark.ParseTags(strings.Join(n.tags, ",")) != b.Tags || n.body != strings.TrimRight(b.Notes, "\n") {
+					t.Errorf("round trip changed the note %+v:\n%s", n, data)
+				}
+			}
+		})
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		format string
+		data   string
+	}{
+		{FormatMarkdown, "no front matter\n"},
+		{FormatMarkdown, "---\nurl: https://go.dev/\n"},
+		{FormatMarkdown, "---\ntags: [unclosed\n---\n"},
+		{FormatOrg, "* heading\nno keywords\n"},
+	}
+
+	for _, tt := range tests {
+		if _, err := parse(tt.format, []byte(tt.data)); !errors.Is(err, ErrFrontMatter) {
+			t.Errorf("%s %q: expected ErrFrontMatter, got %v", tt.format, tt.data, err)
+		}
+	}
+}
+
+func TestSync(t *testing.T) {
+	t.Parallel()
+
+	for _, format := range Formats() {
+		t.Run(format, func(t *testing.T) {
+			t.Parallel()
+
+			s := testStore()
+			v, err := New(t.TempDir(), format)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			res, err := v.Sync(t.Context(), s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.Written != 2 {
+				t.Errorf("expected 2 notes written, got %d", res.Written)
+			}
+
+			goNote := filepath.Join(v.Dir, "the-go-programming-language-1."+format)
+			if _, err := os.Stat(goNote); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := os.Stat(filepath.Join(v.Dir, "private-3."+format)); !errors.Is(err, os.ErrNotExist) {
+				t.Error("private bookmark exported")
+			}
+
+			index, err := os.ReadFile(filepath.Join(v.Dir, v.IndexFile()))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range []string{"programming", bookmark.DefaultTag, "the-go-programming-language-1." + format} {
+				if !strings.Contains(string(index), want) {
+					t.Errorf("index missing %q:\n%s", want, index)
+				}
+			}
+
+			// nothing changed
+			res, err = v.Sync(t.Context(), s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.Changed() {
+				t.Errorf("expected no changes, got %+v", res)
+			}
+		})
+	}
+}
+
+func TestSyncImportsEdits(t *testing.T) {
+	t.Parallel()
+
+	s := testStore()
+	v, err := New(t.TempDir(), FormatMarkdown)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := v.Sync(t.Context(), s); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(v.Dir, "the-go-programming-language-1.md")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edited := strings.NewReplacer(
+		"title: The Go Programming Language", "title: Go",
+		"  - programming", "  - lang",
+		"favorite: true", "favorite: false",
+		"- then effective go", "- then the spec",
+	).Replace(string(data))
+	if err := os.WriteFile(path, []byte(edited), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := v.Sync(t.Context(), s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Imported != 1 || res.Written != 1 {
+		t.Errorf("expected 1 imported and 1 written, got %+v", res)
+	}
+
+	b := s.bs[0]
+	if b.Title != "Go" || b.Tags != "go,lang," || b.Favorite || !strings.HasSuffix(b.Notes, "- then the spec") {
+		t.Errorf("edit not imported: %+v", b)
+	}
+
+	// the note keeps its file name and shows the update
+	data, err = os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "updated: \"2025-01-01 00:00:00\"") {
+		t.Errorf("note not rewritten:\n%s", data)
+	}
+}
+
+func TestSyncKeepsLossyFields(t *testing.T) {
+	t.Parallel()
+
+	s := testStore()
+	s.bs[0].Desc = "first line\nsecond line"
+	v, err := New(t.TempDir(), FormatOrg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var olds, fresh []*bookmark.Bookmark
+	v.OnImport = func(_ context.Context, o, f []*bookmark.Bookmark) error {
+		olds, fresh = o, f
+		return nil
+	}
+	if _, err := v.Sync(t.Context(), s); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(v.Dir, "the-go-programming-language-1.org")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edited := strings.Replace(string(data), "- then effective go", "- then the spec", 1)
+	if err := os.WriteFile(path, []byte(edited), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := v.Sync(t.Context(), s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Imported != 1 {
+		t.Fatalf("expected 1 imported, got %+v", res)
+	}
+
+	b := s.bs[0]
+	if b.Desc != "first line\nsecond line" || !strings.HasSuffix(b.Notes, "- then the spec") {
+		t.Errorf("unexpected bookmark: %+v", b)
+	}
+	if len(olds) != 1 || len(fresh) != 1 || olds[0].Notes == fresh[0].Notes {
+		t.Errorf("import not recorded: %v, %v", olds, fresh)
+	}
+}
+
+func TestSyncConflict(t *testing.T) {
+	t.Parallel()
+
+	s := testStore()
+	v, err := New(t.TempDir(), FormatMarkdown)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := v.Sync(t.Context(), s); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(v.Dir, "example-one-2.md")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edited := strings.Replace(string(data), "title: 'Example: [one]'", "title: Edited", 1)
+	if err := os.WriteFile(path, []byte(edited), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	s.bs[1].Title = "Changed in the database"
+
+	res, err := v.Sync(t.Context(), s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Imported != 0 || len(res.Invalid) != 1 || !strings.Contains(res.Invalid[0], ErrConflict.Error()) {
+		t.Errorf("expected a conflict, got %+v", res)
+	}
+	if s.bs[1].Title != "Changed in the database" {
+		t.Errorf("database change overwritten: %q", s.bs[1].Title)
+	}
+	if data, _ := os.ReadFile(path); string(data) != edited {
+		t.Errorf("edited note overwritten:\n%s", data)
+	}
+}
+
+func TestSyncInvalidURL(t *testing.T) {
+	t.Parallel()
+
+	s := testStore()
+	v, err := New(t.TempDir(), FormatMarkdown)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := v.Sync(t.Context(), s); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(v.Dir, "example-one-2.md")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edited := strings.Replace(string(data), "url: https://example.org/", "url: not a url", 1)
+	if err := os.WriteFile(path, []byte(edited), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := v.Sync(t.Context(), s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Imported != 0 || len(res.Invalid) != 1 {
+		t.Errorf("expected 1 invalid note, got %+v", res)
+	}
+	if s.bs[1].URL != "https://example.org/" {
+		t.Errorf("invalid URL saved: %q", s.bs[1].URL)
+	}
+}
+
+func TestSyncInvalidNote(t *testing.T) {
+	t.Parallel()
+
+	s := testStore()
+	v, err := New(t.TempDir(), FormatMarkdown)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := v.Sync(t.Context(), s); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(v.Dir, "example-one-2.md")
+	if err := os.WriteFile(path, []byte("broken\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := v.Sync(t.Context(), s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Invalid) != 1 {
+		t.Errorf("expected 1 invalid note, got %+v", res)
+	}
+
+	// the edit is not overwritten
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "broken\n" {
+		t.Errorf("invalid note overwritten:\n%s", data)
+	}
+}
+
+func TestSyncRemoves(t *testing.T) {
+	t.Parallel()
+
+	s := testStore()
+	v, err := New(t.TempDir(), FormatOrg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := v.Sync(t.Context(), s); err != nil {
+		t.Fatal(err)
+	}
+
+	// an edited note outlives its bookmark
+	edited := filepath.Join(v.Dir, "the-go-programming-language-1.org")
+	f, err := os.OpenFile(edited, os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteString("my own notes\n"); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	s.bs = nil
+	res, err := v.Sync(t.Context(), s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Removed != 1 {
+		t.Errorf("expected 1 removed, got %+v", res)
+	}
+	if _, err := os.Stat(filepath.Join(v.Dir, "example-one-2.org")); !errors.Is(err, os.ErrNotExist) {
+		t.Error("note of a deleted bookmark kept")
+	}
+	if _, err := os.Stat(edited); err != nil {
+		t.Errorf("edited note removed: %v", err)
+	}
+}
+
+func TestSyncFormatMismatch(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	md, err := New(dir, FormatMarkdown)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := md.Sync(t.Context(), testStore()); err != nil {
+		t.Fatal(err)
+	}
+
+	org, err := New(dir, FormatOrg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := org.Sync(t.Context(), testStore()); !errors.Is(err, ErrFormat) {
+		t.Errorf("expected ErrFormat, got %v", err)
+	}
+}
+
+func TestSlug(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"The Go Programming Language": "the-go-programming-language",
+		"Example: [one]":              "example-one",
+		"  Ünïcode — títle ":          "ünïcode-títle",
+		"!!!":                         "bookmark",
+	}
+	for in, want := range tests {
+		if got := slug(in); got != want {
+			t.Errorf("slug(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package vault
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// debounce is the quiet time after the last change before a sync.
+const debounce = 500 * time.Millisecond
+
+// Watch syncs the vault each time a note or the database at dbPath changes,
+// until ctx is done. Each sync outcome is passed to report.
+func (v *Vault) Watch(ctx context.Context, s Store, dbPath string, report func(*Result, error)) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("vault: %w", err)
+	}
+	defer watcher.Close()
+
+	// watch the directories, editors and SQLite replace files
+	for _, dir := range []string{v.Dir, filepath.Dir(dbPath)} {
+		if err := watcher.Add(dir); err != nil {
+			return fmt.Errorf("vault: watching %q: %w", dir, err)
+		}
+	}
+
+	timer := time.NewTimer(debounce)
+	timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case ev, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if v.isChange(dbPath, ev) {
+				timer.Reset(debounce)
+			}
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			report(nil, err)
+
+		case <-timer.C:
+			report(v.Sync(ctx, s))
+		}
+	}
+}
+
+// isChange reports whether ev modifies a note or the database.
+func (v *Vault) isChange(dbPath string, ev fsnotify.Event) bool {
+	if !ev.Has(fsnotify.Write) && !ev.Has(fsnotify.Create) &&
+		!ev.Has(fsnotify.Remove) && !ev.Has(fsnotify.Rename) {
+		return false
+	}
+
+	name := filepath.Clean(ev.Name)
+	switch name {
+	case dbPath, dbPath + "-wal", dbPath + "-journal":
+		return true
+	}
+
+	if filepath.Dir(name) != filepath.Clean(v.Dir) {
+		return false
+	}
+
+	// hidden and temporary files, the manifest and the index are ours
+	base := filepath.Base(name)
+	if strings.HasPrefix(base, ".") || base == v.IndexFile() {
+		return false
+	}
+
+	return filepath.Ext(base) == "."+v.Format
+}
@@ -15,9 +15,13 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/mateconpizza/gm/internal/application"
+	"github.com/mateconpizza/gm/internal/dbops"
 	"github.com/mateconpizza/gm/internal/gitops"
 	"github.com/mateconpizza/gm/internal/locker"
+	"github.com/mateconpizza/gm/internal/picker"
+	"github.com/mateconpizza/gm/internal/ui"
 	"github.com/mateconpizza/gm/internal/ui/formatter"
+	"github.com/mateconpizza/gm/internal/ui/preview"
 	"github.com/mateconpizza/gm/pkg/ansi"
 	"github.com/mateconpizza/gm/pkg/db"
 	"github.com/mateconpizza/gm/pkg/git"
@@ -85,6 +89,8 @@ func ChainHooks(hooks ...HookE) HookE {
 
 // HookEnsureDatabase ensures the database exists before command execution.
 // Skips check for unlock operations and commands annotated with "skip-db-check".
+// A locked database is unlocked when the agent or a passphrase provider has
+// its key, and locked again on exit.
 // Returns an error if database is missing, locked, or needs initialization.
 func HookEnsureDatabase(app *application.App) HookE {
 	return func(cmd *cobra.Command, args []string) error {
@@ -120,7 +126,17 @@ func HookEnsureDatabase(app *application.App) HookE {
 		}
 
 		if err := checkDatabaseLocked(app.Path.DB()); err != nil {
-			return err
+			if !errors.Is(err, locker.ErrDBUnlockFirst) {
+				return err
+			}
+
+			// unlock without prompting and relock on exit, if a key is available
+			if err := dbops.AutoUnlock(cmd.Context(), app); err != nil {
+				return err
+			}
+
+			databaseChecked = true
+			return nil
 		}
 
 		i := ansi.BrightYellow.With(ansi.Italic).Sprintf("%s init", app.Cmd)
@@ -128,6 +144,45 @@ func HookEnsureDatabase(app *application.App) HookE {
 	}
 }
 
+// HookUnlockPrivate unlocks private bookmarks when the unlock agent or a
+// passphrase provider has the key. With --private the passphrase is prompted
+// if needed, and the private key is created on first use.
+func HookUnlockPrivate(app *application.App) HookE {
+	return func(cmd *cobra.Command, args []string) error {
+		if exit := dispatch(cmd); exit {
+			return nil
+		}
+
+		for c := cmd; c != nil; c = c.Parent() {
+			if v, ok := c.Annotations[AnnotationSkipDBCheck]; ok && v == "true" {
+				return nil
+			}
+		}
+
+		if !files.Exists(app.Path.DB()) {
+			return nil
+		}
+
+		r, err := db.New(cmd.Context(), app.Path.DB())
+		if err != nil {
+			return err
+		}
+		defer r.Close()
+
+		if app.Flags.Private {
+			return dbops.EnsurePrivate(cmd.Context(), app, r, ui.DefaultConsole)
+		}
+
+		err = dbops.UnlockPrivate(cmd.Context(), app, r, nil)
+		if errors.Is(err, db.ErrPrivateNoKey) || errors.Is(err, db.ErrPrivateLocked) {
+			slog.Debug("private bookmarks remain locked", "reason", err)
+			return nil
+		}
+
+		return err
+	}
+}
+
 // HookCheckIfDatabaseInitialized checks if database file exists and is initialized.
 // Returns error if database already exists to prevent accidental re-initialization.
 func HookCheckIfDatabaseInitialized(cmd *cobra.Command, _ []string) error {
@@ -305,7 +360,7 @@ func HookFormatter(app *application.App) HookE {
 			}
 		}
 
-		if app.Flags.Menu && app.Flags.Output != app.Format {
+		if (app.Flags.Menu || app.Flags.MenuSource) && app.Flags.Output != app.Format {
 			app.Menu.Format = app.Flags.Output
 		}
 
@@ -316,6 +371,21 @@ func HookFormatter(app *application.App) HookE {
 
 		app.UI.Formatter = fm
 
+		if err := app.Menu.ValidateBackend(); err != nil {
+			return err
+		}
+
+		if err := app.Menu.ValidateActions(); err != nil {
+			return err
+		}
+
+		if pf := app.Menu.PreviewFormat; pf != "" {
+			if _, err := formatter.New(formatter.Format(pf)); err != nil && pf != preview.Format {
+				return fmt.Errorf("menu preview: %w", err)
+			}
+			picker.SetPreviewFormat(pf)
+		}
+
 		return nil
 	}
 }
@@ -353,5 +423,11 @@ func dispatch(cmd *cobra.Command) bool {
 		return true
 	}
 
+	// Skip DB check if changing the passphrase of a locked database
+	if rekey, _ := cmd.Flags().GetBool("rekey"); rekey {
+		slog.Debug("ensure database dispatch", "rekey", rekey)
+		return true
+	}
+
 	return false
 }
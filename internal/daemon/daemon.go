@@ -0,0 +1,409 @@
+// Package daemon watches the database for changes and keeps its git
+// repository in sync in the background.
+package daemon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	files "github.com/mateconpizza/gofiles"
+
+	"github.com/mateconpizza/gm/internal/application"
+	"github.com/mateconpizza/gm/internal/bookmark/status"
+	"github.com/mateconpizza/gm/internal/gitops"
+	"github.com/mateconpizza/gm/pkg/bookmark"
+	"github.com/mateconpizza/gm/pkg/db"
+	"github.com/mateconpizza/gm/pkg/git"
+	"github.com/mateconpizza/gm/pkg/scraper"
+)
+
+var (
+	ErrRunning    = errors.New("daemon: already running")
+	ErrNotRunning = errors.New("daemon: not running")
+)
+
+const (
+	DefaultDebounce     = 5 * time.Second  // DefaultDebounce is the wait after the last change before syncing.
+	DefaultPullInterval = 15 * time.Minute // DefaultPullInterval is the time between remote pulls.
+
+	faviconBatch = 50      // max favicons fetched per run
+	logMaxSize   = 1 << 20 // rotate the log after 1 MiB
+	logBackups   = 3       // rotated logs kept
+	filePerm     = 0o644
+)
+
+// Options holds the daemon schedule, a zero interval disables the task.
+type Options struct {
+	Debounce        time.Duration
+	PullInterval    time.Duration
+	StatusInterval  time.Duration
+	FaviconInterval time.Duration
+}
+
+// Daemon watches the database file and runs the scheduled tasks.
+type Daemon struct {
+	app   *application.App
+	opts  Options
+	state *State
+	log   *slog.Logger
+
+	synced string // database stamp at the start of the last sync
+}
+
+// New returns a daemon for the current database of app.
+func New(app *application.App, opts Options) *Daemon {
+	if opts.Debounce <= 0 {
+		opts.Debounce = DefaultDebounce
+	}
+
+	return &Daemon{
+		app:  app,
+		opts: opts,
+	}
+}
+
+// Run watches the database until ctx is canceled.
+func (d *Daemon) Run(ctx context.Context) error {
+	statePath := d.app.Path.DaemonState()
+	if s, err := ReadState(statePath); err == nil && s.Running() {
+		return fmt.Errorf("%w: pid %d", ErrRunning, s.PID)
+	}
+
+	w, err := NewRotatingWriter(d.app.Path.DaemonLog(), logMaxSize, logBackups)
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+
+	level := slog.LevelInfo
+	if d.app.Flags.Verbose > 0 {
+		level = slog.LevelDebug
+	}
+	d.log = slog.New(slog.NewTextHandler(w, &slog.HandlerOptions{Level: level}))
+	slog.SetDefault(d.log)
+	d.app.Git.SetWriter(w)
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("daemon: %w", err)
+	}
+	defer watcher.Close()
+
+	// watch the directory, SQLite replaces and appends to sibling files
+	if err := watcher.Add(filepath.Dir(d.app.Path.DB())); err != nil {
+		return fmt.Errorf("daemon: watching %q: %w", d.app.Path.DB(), err)
+	}
+
+	d.state = &State{
+		PID:     os.Getpid(),
+		Started: time.Now(),
+		DB:      d.app.Path.DB(),
+		Log:     d.app.Path.DaemonLog(),
+	}
+	d.save()
+	defer func() {
+		d.state.PID = 0
+		d.save()
+	}()
+
+	d.log.Info("daemon started", "db", d.app.DBBaseName(), "pid", d.state.PID,
+		"debounce", d.opts.Debounce, "pull", d.opts.PullInterval,
+		"status", d.opts.StatusInterval, "favicon", d.opts.FaviconInterval)
+
+	debounce := time.NewTimer(d.opts.Debounce)
+	debounce.Stop()
+
+	pullC, stopPull := tick(d.opts.PullInterval)
+	defer stopPull()
+	statusC, stopStatus := tick(d.opts.StatusInterval)
+	defer stopStatus()
+	faviconC, stopFavicon := tick(d.opts.FaviconInterval)
+	defer stopFavicon()
+
+	for {
+		select {
+		case <-ctx.Done():
+			d.log.Info("daemon stopped")
+			return nil
+
+		case ev, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if !isDBEvent(d.app.Path.DB(), ev) {
+				continue
+			}
+			d.log.Debug("database changed", "event", ev.String())
+			d.state.Pending = true
+			d.state.LastChange = time.Now()
+			debounce.Reset(d.opts.Debounce)
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			d.fail("watch", err)
+
+		case <-debounce.C:
+			d.state.Pending = false
+			// the tasks themselves open the database, skip their events
+			if dbStamp(d.app.Path.DB()) == d.synced {
+				d.log.Debug("database unchanged, skipping sync")
+				d.save()
+				continue
+			}
+			d.run(ctx, "sync", d.sync)
+
+		case <-pullC:
+			d.run(ctx, "pull", d.pull)
+
+		case <-statusC:
+			d.run(ctx, "status", d.checkStatus)
+
+		case <-faviconC:
+			d.run(ctx, "favicon", d.refreshFavicons)
+		}
+	}
+}
+
+// run executes a task and records its outcome.
+func (d *Daemon) run(ctx context.Context, name string, task func(context.Context) error) {
+	d.log.Debug("running task", "task", name)
+	if err := task(ctx); err != nil {
+		d.fail(name, err)
+	}
+
+	d.save()
+}
+
+// sync commits the database changes and pushes them to the remote.
+func (d *Daemon) sync(ctx context.Context) error {
+	if !files.Exists(d.app.Path.DB()) {
+		d.log.Info("database not found or locked, skipping sync")
+		return nil
+	}
+
+	// changes made from now on are left for the next sync
+	d.synced = dbStamp(d.app.Path.DB())
+
+	msg := fmt.Sprintf("[%s] daemon sync", d.app.DBBaseName())
+	err := gitops.Sync(ctx, d.app, msg)
+	switch {
+	case errors.Is(err, git.ErrGitUpToDate):
+		// other commands commit on their own, push what they left
+	case err != nil:
+		return err
+	default:
+		d.state.LastSync = time.Now()
+		d.log.Info("changes committed")
+	}
+
+	m, err := gitops.NewManager(d.app)
+	if err != nil {
+		return err
+	}
+
+	err = gitops.Push(ctx, d.app, m)
+	if errors.Is(err, git.ErrGitUpToDate) || errors.Is(err, git.ErrGitNoUpstream) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	d.state.LastPush = time.Now()
+	d.log.Info("changes pushed")
+
+	return nil
+}
+
+// pull applies the remote changes to the database.
+func (d *Daemon) pull(ctx context.Context) error {
+	if !files.Exists(d.app.Path.DB()) {
+		d.log.Info("database not found or locked, skipping pull")
+		return nil
+	}
+
+	res, err := gitops.Pull(ctx, d.app)
+	if errors.Is(err, git.ErrGitNoUpstream) {
+		d.log.Debug("no upstream, skipping pull")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	d.state.LastPull = time.Now()
+	if res.Total() > 0 {
+		d.log.Info("remote changes applied", "added", res.Added, "updated", res.Updated, "removed", res.Removed)
+	}
+
+	return nil
+}
+
+// checkStatus refreshes the HTTP status of all bookmarks.
+func (d *Daemon) checkStatus(ctx context.Context) error {
+	n, err := d.update(ctx, func(ctx context.Context, bs []*bookmark.Bookmark) ([]*bookmark.Bookmark, error) {
+		updated, err := status.Probe(ctx, bs)
+		if err != nil {
+			return nil, err
+		}
+
+		var keep []*bookmark.Bookmark
+		for _, b := range updated {
+			if b.HTTPStatusCode != http.StatusTooManyRequests {
+				keep = append(keep, b)
+			}
+		}
+
+		return keep, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	d.state.LastStatus = time.Now()
+	d.log.Info("status checked", "changed", n)
+
+	return nil
+}
+
+// refreshFavicons fetches the favicon of the bookmarks missing one.
+func (d *Daemon) refreshFavicons(ctx context.Context) error {
+	n, err := d.update(ctx, func(ctx context.Context, bs []*bookmark.Bookmark) ([]*bookmark.Bookmark, error) {
+		var updated []*bookmark.Bookmark
+		for _, b := range bs {
+			if b.FaviconURL != "" {
+				continue
+			}
+			if len(updated) == faviconBatch || ctx.Err() != nil {
+				break
+			}
+
+			sc := scraper.New(b.URL)
+			if err := sc.Start(ctx); err != nil {
+				d.log.Debug("favicon: scraping", "url", b.URL, "error", err)
+				continue
+			}
+
+			f, err := sc.Favicon()
+			if err != nil || f == "" {
+				continue
+			}
+
+			b.FaviconURL = f
+			updated = append(updated, b)
+		}
+
+		return updated, ctx.Err()
+	})
+	if err != nil {
+		return err
+	}
+
+	d.state.LastFavicon = time.Now()
+	d.log.Info("favicons refreshed", "updated", n)
+
+	return nil
+}
+
+// update loads all bookmarks, stores the ones returned by fn and syncs the
+// repository when any changed.
+func (d *Daemon) update(
+	ctx context.Context,
+	fn func(context.Context, []*bookmark.Bookmark) ([]*bookmark.Bookmark, error),
+) (int, error) {
+	if !files.Exists(d.app.Path.DB()) {
+		d.log.Info("database not found or locked, skipping")
+		return 0, nil
+	}
+
+	r, err := db.New(ctx, d.app.Path.DB())
+	if err != nil {
+		return 0, err
+	}
+	defer r.Close()
+
+	bs, err := r.All(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	updated, err := fn(ctx, bs)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, b := range updated {
+		if err := r.UpdateOne(ctx, b); err != nil {
+			return 0, err
+		}
+	}
+
+	if len(updated) == 0 {
+		return 0, nil
+	}
+
+	return len(updated), d.sync(ctx)
+}
+
+func (d *Daemon) fail(task string, err error) {
+	d.log.Error("task failed", "task", task, "error", err)
+	d.state.LastError = fmt.Sprintf("%s: %v", task, err)
+	d.state.LastErrorAt = time.Now()
+}
+
+func (d *Daemon) save() {
+	if err := writeState(d.app.Path.DaemonState(), d.state); err != nil {
+		d.log.Error("saving state", "error", err)
+	}
+}
+
+// isDBEvent reports whether ev modifies the database at dbPath or its
+// journal.
+func isDBEvent(dbPath string, ev fsnotify.Event) bool {
+	if !ev.Has(fsnotify.Write) && !ev.Has(fsnotify.Create) &&
+		!ev.Has(fsnotify.Remove) && !ev.Has(fsnotify.Rename) {
+		return false
+	}
+
+	switch filepath.Clean(ev.Name) {
+	case dbPath, dbPath + "-wal", dbPath + "-journal":
+		return true
+	}
+
+	return false
+}
+
+// dbStamp identifies the content of the database at path by the size and
+// modification time of its files.
+func dbStamp(path string) string {
+	var sb strings.Builder
+	for _, p := range []string{path, path + "-wal"} {
+		if fi, err := os.Stat(p); err == nil {
+			fmt.Fprintf(&sb, "%d:%d", fi.Size(), fi.ModTime().UnixNano())
+		}
+		sb.WriteByte(';')
+	}
+
+	return sb.String()
+}
+
+// tick returns the channel of a ticker firing every d, or a nil channel when
+// d is zero.
+func tick(d time.Duration) (<-chan time.Time, func()) {
+	if d <= 0 {
+		return nil, func() {}
+	}
+
+	t := time.NewTicker(d)
+
+	return t.C, t.Stop
+}
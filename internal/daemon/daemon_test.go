@@ -0,0 +1,95 @@
+package daemon
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+func TestIsDBEvent(t *testing.T) {
+	t.Parallel()
+
+	db := "/data/gomarks/main.db"
+	tests := []struct {
+		name string
+		ev   fsnotify.Event
+		want bool
+	}{
+		{"write", fsnotify.Event{Name: db, Op: fsnotify.Write}, true},
+		{"wal", fsnotify.Event{Name: db + "-wal", Op: fsnotify.Write}, true},
+		{"journal removed", fsnotify.Event{Name: db + "-journal", Op: fsnotify.Remove}, true},
+		{"shared memory", fsnotify.Event{Name: db + "-shm", Op: fsnotify.Write}, false},
+		{"chmod", fsnotify.Event{Name: db, Op: fsnotify.Chmod}, false},
+		{"other database", fsnotify.Event{Name: "/data/gomarks/work.db", Op: fsnotify.Write}, false},
+		{"state file", fsnotify.Event{Name: "/data/gomarks/daemon.json", Op: fsnotify.Create}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := isDBEvent(db, tt.ev); got != tt.want {
+				t.Errorf("isDBEvent(%s) = %v, want %v", tt.ev, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestState(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "daemon.json")
+	if _, err := ReadState(path); !errors.Is(err, ErrNotRunning) {
+		t.Fatalf("expected ErrNotRunning, got %v", err)
+	}
+
+	s := &State{
+		PID:      os.Getpid(),
+		Started:  time.Now().Truncate(time.Second),
+		DB:       "main.db",
+		LastSync: time.Now().Truncate(time.Second),
+	}
+	if err := writeState(path, s); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ReadState(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Running() {
+		t.Error("expected current process to be running")
+	}
+	if !got.LastSync.Equal(s.LastSync) || !got.LastPull.IsZero() {
+		t.Errorf("unexpected state: %+v", got)
+	}
+
+	got.PID = 0
+	if got.Running() {
+		t.Error("expected stopped daemon")
+	}
+}
+
+func TestDBStamp(t *testing.T) {
+	t.Parallel()
+
+	db := filepath.Join(t.TempDir(), "main.db")
+	if err := os.WriteFile(db, []byte("a"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	s := dbStamp(db)
+	if s != dbStamp(db) {
+		t.Error("expected the same stamp for unchanged files")
+	}
+
+	if err := os.WriteFile(db+"-wal", []byte("b"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if s == dbStamp(db) {
+		t.Error("expected a new stamp after a journal write")
+	}
+}
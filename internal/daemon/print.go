@@ -0,0 +1,51 @@
+package daemon
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mateconpizza/gm/internal/ui"
+	"github.com/mateconpizza/gm/internal/ui/txt"
+)
+
+// PrintStatus prints the daemon state stored at statePath.
+func PrintStatus(c *ui.Console, statePath string) error {
+	s, err := ReadState(statePath)
+	if err != nil {
+		return err
+	}
+
+	if !s.Running() {
+		return ErrNotRunning
+	}
+
+	f, p := c.Frame(), c.Palette()
+	f.Headerln(p.BrightGreen.Wrap("daemon:", p.Italic)).
+		Rowln(txt.PaddedLine("pid:", s.PID)).
+		Rowln(txt.PaddedLine("uptime:", time.Since(s.Started).Round(time.Second))).
+		Rowln(txt.PaddedLine("db:", s.DB)).
+		Rowln(txt.PaddedLine("log:", s.Log)).
+		Rowln(txt.PaddedLine("pending:", s.Pending)).
+		Rowln(txt.PaddedLine("last change:", since(s.LastChange))).
+		Rowln(txt.PaddedLine("last sync:", since(s.LastSync))).
+		Rowln(txt.PaddedLine("last push:", since(s.LastPush))).
+		Rowln(txt.PaddedLine("last pull:", since(s.LastPull))).
+		Rowln(txt.PaddedLine("last status:", since(s.LastStatus))).
+		Rowln(txt.PaddedLine("last favicon:", since(s.LastFavicon)))
+
+	if s.LastError != "" {
+		f.Error(txt.PaddedLine("last error:", fmt.Sprintf("%s (%s)", s.LastError, since(s.LastErrorAt)))).Ln()
+	}
+
+	f.Flush()
+
+	return nil
+}
+
+func since(t time.Time) string {
+	if t.IsZero() {
+		return "never"
+	}
+
+	return time.Since(t).Round(time.Second).String() + " ago"
+}
@@ -0,0 +1,34 @@
+package daemon
+
+import (
+	"os"
+	"syscall"
+)
+
+const stillActive = 259 // exit code of a running process
+
+// alive reports whether the process pid exists and has not exited.
+func alive(pid int) bool {
+	h, err := syscall.OpenProcess(syscall.PROCESS_QUERY_INFORMATION, false, uint32(pid))
+	if err != nil {
+		return false
+	}
+	defer syscall.CloseHandle(h)
+
+	var code uint32
+	if err := syscall.GetExitCodeProcess(h, &code); err != nil {
+		return false
+	}
+
+	return code == stillActive
+}
+
+// terminate ends the process pid, Windows has no termination signal.
+func terminate(pid int) error {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+
+	return p.Kill()
+}
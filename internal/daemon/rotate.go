@@ -0,0 +1,101 @@
+package daemon
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+// RotatingWriter is a log file that is rotated once it grows past maxSize,
+// keeping up to backups older files (log.1, log.2, ...).
+type RotatingWriter struct {
+	mu      sync.Mutex
+	path    string
+	maxSize int64
+	backups int
+	f       *os.File
+	size    int64
+}
+
+// NewRotatingWriter opens or creates the log file at path.
+func NewRotatingWriter(path string, maxSize int64, backups int) (*RotatingWriter, error) {
+	w := &RotatingWriter{
+		path:    path,
+		maxSize: maxSize,
+		backups: backups,
+	}
+
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
+
+func (w *RotatingWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.size > 0 && w.size+int64(len(p)) > w.maxSize {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := w.f.Write(p)
+	w.size += int64(n)
+
+	return n, err
+}
+
+// Close closes the current log file.
+func (w *RotatingWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.f.Close()
+}
+
+func (w *RotatingWriter) open() error {
+	f, err := os.OpenFile(w.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, filePerm)
+	if err != nil {
+		return fmt.Errorf("opening log: %w", err)
+	}
+
+	fi, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("opening log: %w", err)
+	}
+
+	w.f = f
+	w.size = fi.Size()
+
+	return nil
+}
+
+// rotate shifts the backups by one, dropping the oldest, and starts a new
+// log file.
+func (w *RotatingWriter) rotate() error {
+	if err := w.f.Close(); err != nil {
+		return fmt.Errorf("rotating log: %w", err)
+	}
+
+	for i := w.backups - 1; i > 0; i-- {
+		_ = os.Rename(backupName(w.path, i), backupName(w.path, i+1))
+	}
+
+	if w.backups > 0 {
+		if err := os.Rename(w.path, backupName(w.path, 1)); err != nil {
+			return fmt.Errorf("rotating log: %w", err)
+		}
+	} else if err := os.Remove(w.path); err != nil {
+		return fmt.Errorf("rotating log: %w", err)
+	}
+
+	return w.open()
+}
+
+func backupName(path string, n int) string {
+	return fmt.Sprintf("%s.%d", path, n)
+}
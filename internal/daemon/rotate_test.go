@@ -0,0 +1,75 @@
+package daemon
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRotatingWriter(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "daemon.log")
+	w, err := NewRotatingWriter(path, 10, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		if _, err := w.Write([]byte(line)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		path:                "fourth\n",
+		backupName(path, 1): "third\n",
+		backupName(path, 2): "second\n",
+		backupName(path, 3): "",
+	}
+	for p, content := range want {
+		data, err := os.ReadFile(p)
+		if content == "" {
+			if !os.IsNotExist(err) {
+				t.Errorf("%s: expected no file, got %q", filepath.Base(p), data)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != content {
+			t.Errorf("%s: expected %q, got %q", filepath.Base(p), content, data)
+		}
+	}
+}
+
+func TestRotatingWriterAppends(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "daemon.log")
+	for _, line := range []string{"one\n", "two\n"} {
+		w, err := NewRotatingWriter(path, 1024, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(line)); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Count(string(data), "\n"); got != 2 {
+		t.Errorf("expected 2 lines, got %d: %q", got, data)
+	}
+}
@@ -0,0 +1,86 @@
+package daemon
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/mateconpizza/gm/internal/sys"
+)
+
+// State is the daemon state, written to disk after every task so that
+// `gm daemon status` can report it.
+type State struct {
+	PID     int       `json:"pid"`
+	Started time.Time `json:"started"`
+	DB      string    `json:"db"`
+	Log     string    `json:"log"`
+	Pending bool      `json:"pending"` // changes waiting for the debounce
+
+	LastChange  time.Time `json:"last_change,omitzero"`
+	LastSync    time.Time `json:"last_sync,omitzero"`
+	LastPush    time.Time `json:"last_push,omitzero"`
+	LastPull    time.Time `json:"last_pull,omitzero"`
+	LastStatus  time.Time `json:"last_status,omitzero"`
+	LastFavicon time.Time `json:"last_favicon,omitzero"`
+
+	LastError   string    `json:"last_error,omitempty"`
+	LastErrorAt time.Time `json:"last_error_at,omitzero"`
+}
+
+// Running reports whether the process that wrote the state is alive.
+func (s *State) Running() bool {
+	return s.PID > 0 && alive(s.PID)
+}
+
+// ReadState loads the daemon state stored at path.
+func ReadState(path string) (*State, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrNotRunning
+		}
+		return nil, fmt.Errorf("reading daemon state: %w", err)
+	}
+
+	s := &State{}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("parsing daemon state: %w", err)
+	}
+
+	return s, nil
+}
+
+// writeState stores s at path, replacing the previous state atomically.
+func writeState(path string, s *State) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding daemon state: %w", err)
+	}
+
+	if err := sys.WriteFileAtomic(path, data, 0o600); err != nil {
+		return fmt.Errorf("writing daemon state: %w", err)
+	}
+
+	return nil
+}
+
+// Stop asks the running daemon to terminate.
+func Stop(statePath string) error {
+	s, err := ReadState(statePath)
+	if err != nil {
+		return err
+	}
+
+	if !s.Running() {
+		return ErrNotRunning
+	}
+
+	if err := terminate(s.PID); err != nil {
+		return fmt.Errorf("daemon: %w", err)
+	}
+
+	return nil
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/mateconpizza/gm/internal/application"
 	"github.com/mateconpizza/gm/internal/deps"
 	"github.com/mateconpizza/gm/internal/locker"
+	"github.com/mateconpizza/gm/internal/locker/secret"
 	"github.com/mateconpizza/gm/internal/picker"
 	"github.com/mateconpizza/gm/internal/summary"
 	"github.com/mateconpizza/gm/internal/sys"
@@ -286,12 +287,7 @@ func Lock(ctx context.Context, d *deps.Deps, rToLock string) error {
 		return err
 	}
 
-	pass, err := passwordConfirm(ctx, c)
-	if err != nil {
-		return err
-	}
-
-	if err := locker.Lock(rToLock, pass); err != nil {
+	if err := lockWithSecret(ctx, d, rToLock); err != nil {
 		return err
 	}
 
@@ -343,22 +339,77 @@ func Unlock(ctx context.Context, d *deps.Deps, rToUnlock string) error {
 		return fmt.Errorf("%w", err)
 	}
 
+	app, err := d.Application(ctx)
+	if err != nil {
+		return err
+	}
+
+	// try the unlock agent and passphrase providers before prompting
+	_, err = unlockNonInteractive(ctx, app, rToUnlock)
+	if err == nil {
+		fmt.Fprintln(d.Writer(), c.SuccessMesg("database unlocked"))
+		return nil
+	}
+
+	if !errors.Is(err, secret.ErrNoSecret) {
+		return fmt.Errorf("%w", err)
+	}
+
 	s, err := c.InputPassword(ctx, "Password: ")
 	if err != nil {
 		return fmt.Errorf("%w", err)
 	}
 
-	if err := locker.Unlock(rToUnlock, s); err != nil {
+	k, err := locker.UnlockKey(rToUnlock, s)
+	if err != nil {
 		fmt.Fprintln(d.Writer())
 		return fmt.Errorf("%w", err)
 	}
 
+	cacheKey(app, strings.TrimSuffix(rToUnlock, locker.Extension), k)
+
 	fmt.Fprintln(d.Writer())
 	fmt.Fprintln(d.Writer(), c.SuccessMesg("database unlocked"))
 
 	return nil
 }
 
+// Rekey changes the passphrase of a locked database.
+func Rekey(ctx context.Context, d *deps.Deps, rToRekey string) error {
+	rToRekey = files.EnsureExt(rToRekey, locker.Extension)
+	slog.Debug("rekeying database", "name", rToRekey)
+
+	if !files.Exists(rToRekey) {
+		s := filepath.Base(strings.TrimSuffix(rToRekey, locker.Extension))
+		return fmt.Errorf("%w: %q", locker.ErrFileUnlocked, s)
+	}
+
+	c := d.Console()
+	if err := c.ConfirmErr(ctx, fmt.Sprintf("Change password of %q?", filepath.Base(rToRekey)), "y"); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	old, err := c.InputPassword(ctx, "Current Password: ")
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	fmt.Fprintln(d.Writer())
+
+	pass, err := passwordConfirm(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	if err := locker.Rekey(rToRekey, old, pass); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	fmt.Fprintln(d.Writer(), c.SuccessMesg(fmt.Sprintf("password changed: %q", filepath.Base(rToRekey))))
+
+	return nil
+}
+
 func NewBackup(ctx context.Context, d *deps.Deps) error {
 	app, err := d.Application(ctx)
 	if err != nil {
@@ -507,6 +558,29 @@ func removeSlicePath(ctx context.Context, d *deps.Deps, dbs []string) error {
 	return nil
 }
 
+// lockWithSecret locks the file with the key cached by the unlock agent, a
+// passphrase from the configured providers, or a prompted passphrase.
+func lockWithSecret(ctx context.Context, d *deps.Deps, rToLock string) error {
+	app, err := d.Application(ctx)
+	if err != nil {
+		return err
+	}
+
+	if k, ok := agentKey(app, rToLock); ok {
+		return locker.LockWithKey(rToLock, k)
+	}
+
+	pass, err := secret.Resolve(ctx, files.StripExts(filepath.Base(rToLock)), secretProviders(app)...)
+	if errors.Is(err, secret.ErrNoSecret) {
+		pass, err = passwordConfirm(ctx, d.Console())
+	}
+	if err != nil {
+		return err
+	}
+
+	return locker.Lock(rToLock, pass)
+}
+
 // passwordConfirm prompts user for password input.
 func passwordConfirm(ctx context.Context, c *ui.Console) (string, error) {
 	s, err := c.InputPassword(ctx, "Password: ")
@@ -0,0 +1,170 @@
+package dbops
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+
+	files "github.com/mateconpizza/gofiles"
+
+	"github.com/mateconpizza/gm/internal/application"
+	"github.com/mateconpizza/gm/internal/locker"
+	"github.com/mateconpizza/gm/internal/locker/secret"
+	"github.com/mateconpizza/gm/internal/ui"
+	"github.com/mateconpizza/gm/pkg/db"
+)
+
+// privateCheck is sealed with the private key and stored as verifier.
+var privateCheck = []byte("gomarks-private")
+
+// privateAgentSuffix distinguishes the private fields key from the database
+// lock key in the unlock agent.
+const privateAgentSuffix = "#private"
+
+// privateKeyMeta is stored as JSON in the database metadata table.
+type privateKeyMeta struct {
+	KDF    locker.KDF       `json:"kdf"`
+	Salt   []byte           `json:"salt"`
+	Params locker.KDFParams `json:"params"`
+	Check  []byte           `json:"check"`
+}
+
+// UnlockPrivate makes the private bookmarks of r readable for the rest of
+// the process.
+//
+// The key is taken from the unlock agent or the passphrase providers; when
+// neither has it and c is not nil, the passphrase is prompted.
+func UnlockPrivate(ctx context.Context, app *application.App, r *db.SQLite, c *ui.Console) error {
+	if r.PrivateUnlocked() {
+		return nil
+	}
+
+	meta, err := loadPrivateKeyMeta(r)
+	if err != nil {
+		return err
+	}
+
+	agentName := r.Fullpath() + privateAgentSuffix
+	if k, ok := agentKey(app, agentName); ok {
+		if err := registerPrivate(r, meta, k); err == nil {
+			return nil
+		}
+		slog.Debug("agent: cached private key rejected", "db", r.Name())
+	}
+
+	pass, err := secret.Resolve(ctx, files.StripExts(filepath.Base(r.Fullpath())), secretProviders(app)...)
+	if errors.Is(err, secret.ErrNoSecret) && c != nil {
+		pass, err = c.InputPassword(ctx, "Private Password: ")
+		fmt.Fprintln(c.Writer())
+	}
+	if err != nil {
+		if errors.Is(err, secret.ErrNoSecret) {
+			return fmt.Errorf("%w: %q", db.ErrPrivateLocked, r.Name())
+		}
+		return err
+	}
+
+	k, err := locker.DeriveKey(pass, meta.KDF, meta.Salt, meta.Params)
+	if err != nil {
+		return err
+	}
+
+	if err := registerPrivate(r, meta, k); err != nil {
+		return err
+	}
+
+	cacheKey(app, agentName, k)
+
+	return nil
+}
+
+// EnsurePrivate unlocks the private bookmarks of r, creating the private key
+// on first use.
+func EnsurePrivate(ctx context.Context, app *application.App, r *db.SQLite, c *ui.Console) error {
+	err := UnlockPrivate(ctx, app, r, c)
+	if !errors.Is(err, db.ErrPrivateNoKey) {
+		return err
+	}
+
+	pass, err := secret.Resolve(ctx, files.StripExts(filepath.Base(r.Fullpath())), secretProviders(app)...)
+	if errors.Is(err, secret.ErrNoSecret) {
+		if c == nil {
+			return fmt.Errorf("%w: %q", db.ErrPrivateNoKey, r.Name())
+		}
+		fmt.Fprintln(c.Writer(), c.InfoMesg("set a password for private bookmarks"))
+		pass, err = passwordConfirm(ctx, c)
+	}
+	if err != nil {
+		return err
+	}
+
+	k, err := locker.NewKey(pass)
+	if err != nil {
+		return err
+	}
+
+	fc, err := locker.NewFieldCipher(k)
+	if err != nil {
+		return err
+	}
+
+	check, err := fc.Seal(privateCheck)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(&privateKeyMeta{KDF: k.KDF, Salt: k.Salt, Params: k.Params, Check: check})
+	if err != nil {
+		return err
+	}
+
+	if err := r.SetMetadata(ctx, db.MetaKeyPrivateKey, string(data)); err != nil {
+		return fmt.Errorf("storing private key parameters: %w", err)
+	}
+
+	db.RegisterCipher(r.Fullpath(), fc)
+	cacheKey(app, r.Fullpath()+privateAgentSuffix, k)
+
+	return nil
+}
+
+func loadPrivateKeyMeta(r *db.SQLite) (*privateKeyMeta, error) {
+	s, err := r.Metadata(db.MetaKeyPrivateKey)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, db.ErrPrivateNoKey
+		}
+		return nil, err
+	}
+
+	var meta privateKeyMeta
+	if err := json.Unmarshal([]byte(s), &meta); err != nil {
+		return nil, fmt.Errorf("%w: %w", db.ErrPrivateCorrupt, err)
+	}
+
+	return &meta, nil
+}
+
+// registerPrivate verifies the key against the stored check and registers
+// the field cipher.
+func registerPrivate(r *db.SQLite, meta *privateKeyMeta, k *locker.Key) error {
+	fc, err := locker.NewFieldCipher(k)
+	if err != nil {
+		return err
+	}
+
+	got, err := fc.Open(meta.Check)
+	if err != nil || !bytes.Equal(got, privateCheck) {
+		return fmt.Errorf("%w: wrong password", db.ErrPrivateLocked)
+	}
+
+	db.RegisterCipher(r.Fullpath(), fc)
+	slog.Debug("private bookmarks unlocked", "db", r.Name())
+
+	return nil
+}
@@ -0,0 +1,112 @@
+package dbops
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strings"
+
+	files "github.com/mateconpizza/gofiles"
+
+	"github.com/mateconpizza/gm/internal/application"
+	"github.com/mateconpizza/gm/internal/locker"
+	"github.com/mateconpizza/gm/internal/locker/agent"
+	"github.com/mateconpizza/gm/internal/locker/secret"
+	"github.com/mateconpizza/gm/internal/sys/cleanup"
+	"github.com/mateconpizza/gm/pkg/db"
+)
+
+// AutoUnlock unlocks the current database without prompting, using a key
+// cached by the unlock agent or a passphrase from the configured providers.
+//
+// The database is locked again with the same key when the program exits.
+// Returns locker.ErrDBUnlockFirst when no key is available.
+func AutoUnlock(ctx context.Context, app *application.App) error {
+	dbPath := app.Path.DB()
+	encPath := files.EnsureExt(dbPath, locker.Extension)
+	if !files.Exists(encPath) {
+		return fmt.Errorf("%w: %q", db.ErrDBNotFound, app.DBBaseName())
+	}
+
+	k, err := unlockNonInteractive(ctx, app, encPath)
+	if err != nil {
+		if errors.Is(err, secret.ErrNoSecret) {
+			return locker.ErrDBUnlockFirst
+		}
+		return err
+	}
+
+	slog.Debug("database auto-unlocked", "path", dbPath)
+
+	cleanup.Register(func() error {
+		db.Shutdown()
+		slog.Debug("relocking database", "path", dbPath)
+		return locker.LockWithKey(dbPath, k)
+	})
+
+	return nil
+}
+
+// secretProviders returns the non-interactive passphrase sources.
+func secretProviders(app *application.App) []secret.Provider {
+	ps := []secret.Provider{secret.NewEnv(app.Env.Passphrase)}
+	if app.Locker != nil && app.Locker.PassphraseCmd != "" {
+		ps = append(ps, secret.NewCommand(app.Locker.PassphraseCmd))
+	}
+
+	return ps
+}
+
+// agentKey returns the key cached by the unlock agent, if any.
+func agentKey(app *application.App, dbPath string) (*locker.Key, bool) {
+	k, err := agent.NewClient(app.Path.Agent()).Get(dbPath)
+	if err != nil {
+		slog.Debug("agent: no cached key", "db", dbPath, "error", err)
+		return nil, false
+	}
+
+	return k, true
+}
+
+// cacheKey stores the key in the unlock agent when one is running.
+func cacheKey(app *application.App, dbPath string, k *locker.Key) {
+	if k == nil {
+		return
+	}
+
+	if err := agent.NewClient(app.Path.Agent()).Put(dbPath, k, app.Locker.TTL()); err != nil {
+		slog.Debug("agent: key not cached", "db", dbPath, "error", err)
+	}
+}
+
+// unlockNonInteractive unlocks encPath with the agent key or a provider
+// passphrase, and returns the key used.
+func unlockNonInteractive(ctx context.Context, app *application.App, encPath string) (*locker.Key, error) {
+	dbPath := strings.TrimSuffix(encPath, locker.Extension)
+
+	if k, ok := agentKey(app, dbPath); ok {
+		err := locker.UnlockWithKey(encPath, k)
+		if err == nil {
+			return k, nil
+		}
+
+		slog.Debug("agent: cached key rejected", "db", dbPath, "error", err)
+		_ = agent.NewClient(app.Path.Agent()).Forget(dbPath)
+	}
+
+	pass, err := secret.Resolve(ctx, files.StripExts(filepath.Base(dbPath)), secretProviders(app)...)
+	if err != nil {
+		return nil, err
+	}
+
+	k, err := locker.UnlockKey(encPath, pass)
+	if err != nil {
+		return nil, err
+	}
+
+	cacheKey(app, dbPath, k)
+
+	return k, nil
+}
@@ -263,7 +263,7 @@ func selectBackupsToRemove(ctx context.Context, d *deps.Deps, fs []string) ([]st
 	return m.Select(fs)
 }
 
-func setupMenu[T comparable](app *application.App, formatter menu.FmtFunc[T], opts ...menu.Option) *menu.Menu[T] {
+func setupMenu[T comparable](app *application.App, formatter menu.FmtFunc[T], opts ...menu.Option) *picker.Menu[T] {
 	opts = append(
 		opts,
 		menu.WithCycle(),
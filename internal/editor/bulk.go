@@ -0,0 +1,245 @@
+package editor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/mateconpizza/gm/internal/ui/txt"
+	"github.com/mateconpizza/gm/pkg/bookmark"
+	"github.com/mateconpizza/gm/pkg/db"
+)
+
+var (
+	ErrBulkBuffer     = errors.New("invalid bulk buffer")
+	ErrBulkUnknownID  = errors.New("unknown bookmark ID")
+	ErrBulkDuplicated = errors.New("duplicated entry")
+)
+
+var _ BulkEditStrategy = (*BulkStrategy)(nil)
+
+// BulkStrategy edits many bookmarks in one YAML buffer keyed by ID.
+type BulkStrategy struct{}
+
+func NewBulkStrategy() *BulkStrategy {
+	return &BulkStrategy{}
+}
+
+// bulkEntry holds the editable fields of a bookmark.
+type bulkEntry struct {
+	URL   string   `yaml:"url"`
+	Title string   `yaml:"title"`
+	Tags  []string `yaml:"tags,flow"`
+	Desc  string   `yaml:"desc"`
+}
+
+// bulkFields are the keys accepted in an entry.
+var bulkFields = []string{"url", "title", "tags", "desc"}
+
+func newBulkEntry(b *bookmark.Bookmark) *bulkEntry {
+	e := &bulkEntry{URL: b.URL, Title: b.Title, Desc: b.Desc, Tags: []string{}}
+	for t := range strings.SplitSeq(b.Tags, ",") {
+		if t != "" {
+			e.Tags = append(e.Tags, t)
+		}
+	}
+
+	return e
+}
+
+func (BulkStrategy) BuildBulkBuffer(m *Meta, bs []*bookmark.Bookmark) ([]byte, error) {
+	root := &yaml.Node{Kind: yaml.MappingNode}
+	for _, b := range bs {
+		var v yaml.Node
+		if err := v.Encode(newBulkEntry(b)); err != nil {
+			return nil, fmt.Errorf("bookmark %d: %w", b.ID, err)
+		}
+
+		k := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: strconv.Itoa(b.ID)}
+		root.Content = append(root.Content, k, &v)
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# bulk edition: %d bookmarks\n", len(bs))
+	fmt.Fprintf(&buf, "# database: %s\n", m.DBName)
+	fmt.Fprintf(&buf, "# version:  %s\n", formatVersion(m.Version))
+	buf.WriteString("#\n")
+	buf.WriteString("# Entries are keyed by bookmark ID, IDs cannot be changed.\n")
+	buf.WriteString("# Removing an entry leaves its bookmark untouched.\n\n")
+
+	if len(bs) == 0 {
+		return buf.Bytes(), nil
+	}
+
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(root); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// ParseBulkBuffer validates every entry in the buffer and returns the
+// bookmarks that changed. All problems found are reported together, each
+// one prefixed with the bookmark ID.
+func (BulkStrategy) ParseBulkBuffer(
+	ctx context.Context,
+	buf []byte,
+	originals []*bookmark.Bookmark,
+) ([]*bookmark.Bookmark, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(buf, &doc); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrBulkBuffer, err)
+	}
+	if len(doc.Content) == 0 {
+		return nil, ErrBufferUnchanged
+	}
+
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("%w: line %d: expected entries keyed by ID", ErrBulkBuffer, root.Line)
+	}
+
+	byID := make(map[int]*bookmark.Bookmark, len(originals))
+	for _, b := range originals {
+		byID[b.ID] = b
+	}
+
+	var (
+		errs    []error
+		changed []*bookmark.Bookmark
+		seenID  = make(map[int]bool)
+		seenURL = make(map[string]int)
+	)
+
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		k, v := root.Content[i], root.Content[i+1]
+
+		id, err := strconv.Atoi(k.Value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("line %d: %w %q", k.Line, ErrBulkUnknownID, k.Value))
+			continue
+		}
+
+		og, ok := byID[id]
+		if !ok {
+			errs = append(errs, fmt.Errorf("[%d] line %d: %w", id, k.Line, ErrBulkUnknownID))
+			continue
+		}
+		if seenID[id] {
+			errs = append(errs, fmt.Errorf("[%d] line %d: %w", id, k.Line, ErrBulkDuplicated))
+			continue
+		}
+		seenID[id] = true
+
+		edited, err := parseBulkEntry(v, og)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("[%d] %w", id, err))
+			continue
+		}
+
+		if other, ok := seenURL[edited.URL]; ok {
+			errs = append(errs, fmt.Errorf("[%d] %w: url also used by [%d]", id, ErrBulkDuplicated, other))
+			continue
+		}
+		seenURL[edited.URL] = id
+
+		if !og.Equals(edited) {
+			changed = append(changed, edited)
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	if len(changed) == 0 {
+		return nil, ErrBufferUnchanged
+	}
+
+	return changed, nil
+}
+
+// checkTakenURLs reports the changed URLs already used by another bookmark
+// of the database, the buffer only holds the selected ones.
+func checkTakenURLs(ctx context.Context, r *db.SQLite, byID map[int]*bookmark.Bookmark, bs []*bookmark.Bookmark) error {
+	var errs []error
+	for _, b := range bs {
+		if og, ok := byID[b.ID]; ok && og.URL == b.URL {
+			continue
+		}
+
+		if other, ok := r.Has(ctx, b.URL); ok && other.ID != b.ID {
+			errs = append(errs, fmt.Errorf("[%d] %w: url also used by [%d]", b.ID, ErrBulkDuplicated, other.ID))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// parseBulkEntry applies an entry of the buffer to a copy of og.
+func parseBulkEntry(v *yaml.Node, og *bookmark.Bookmark) (*bookmark.Bookmark, error) {
+	if v.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("line %d: %w: expected %s", v.Line, ErrBulkBuffer, strings.Join(bulkFields, ", "))
+	}
+
+	for i := 0; i < len(v.Content); i += 2 {
+		k := v.Content[i]
+		if !slices.Contains(bulkFields, k.Value) {
+			return nil, fmt.Errorf("line %d: %w: unknown field %q", k.Line, ErrBulkBuffer, k.Value)
+		}
+	}
+
+	var e bulkEntry
+	if err := v.Decode(&e); err != nil {
+		return nil, fmt.Errorf("line %d: %w", v.Line, err)
+	}
+
+	edited := og.Copy()
+	edited.URL = strings.TrimSpace(e.URL)
+	edited.Title = strings.TrimSpace(e.Title)
+	edited.Desc = strings.TrimSpace(e.Desc)
+	edited.Tags = bookmark.ParseTags(strings.Join(e.Tags, ","))
+
+	if err := bookmark.Validate(edited); err != nil {
+		return nil, err
+	}
+
+	return edited, nil
+}
+
+func (BulkStrategy) SaveAll(ctx context.Context, r *db.SQLite, bs []*bookmark.Bookmark) error {
+	return r.UpdateMany(ctx, bs)
+}
+
+func (s BulkStrategy) BuildBuffer(m *Meta, b *bookmark.Bookmark, idx, total int) ([]byte, error) {
+	return s.BuildBulkBuffer(m, []*bookmark.Bookmark{b})
+}
+
+func (s BulkStrategy) ParseBuffer(ctx context.Context, buf []byte, original *bookmark.Bookmark) (*bookmark.Bookmark, error) {
+	bs, err := s.ParseBulkBuffer(ctx, buf, []*bookmark.Bookmark{original})
+	if err != nil {
+		return nil, err
+	}
+
+	return bs[0], nil
+}
+
+func (BulkStrategy) Diff(oldB, newB *bookmark.Bookmark) string {
+	return txt.DiffColorize(txt.Diff(oldB.Buffer(), newB.Buffer()))
+}
+
+func (BulkStrategy) Save(ctx context.Context, r *db.SQLite, b *bookmark.Bookmark) error {
+	return r.UpdateOne(ctx, b)
+}
+
+func (BulkStrategy) FileType() string { return "yaml" }
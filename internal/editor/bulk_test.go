@@ -0,0 +1,192 @@
+package editor
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mateconpizza/gm/pkg/bookmark"
+	"github.com/mateconpizza/gm/pkg/db"
+)
+
+func testBulkBookmarks() []*bookmark.Bookmark {
+	return []*bookmark.Bookmark{
+		{ID: 3, URL: "https://example.com", Title: "Example", Tags: "go,web,", Desc: "line one\nline two"},
+		{ID: 7, URL: "https://golang.org", Title: "Go", Tags: "go,", Notes: "keep me"},
+	}
+}
+
+func TestBulkStrategy(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		test func(t *testing.T)
+	}{
+		{"BuildBuffer_Order", testBulkBuildBufferOrder},
+		{"ParseBuffer_Unchanged", testBulkParseBufferUnchanged},
+		{"ParseBuffer_Changed", testBulkParseBufferChanged},
+		{"ParseBuffer_RemovedEntry", testBulkParseBufferRemovedEntry},
+		{"ParseBuffer_Errors", testBulkParseBufferErrors},
+		{"CheckTakenURLs", testBulkCheckTakenURLs},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			tt.test(t)
+		})
+	}
+}
+
+func testBulkBuildBufferOrder(t *testing.T) {
+	t.Helper()
+	bs := testBulkBookmarks()
+	bs[0], bs[1] = bs[1], bs[0]
+
+	buf, err := BulkStrategy{}.BuildBulkBuffer(&Meta{DBName: "main", Version: "dev"}, bs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	i7, i3 := bytes.Index(buf, []byte("\n7:\n")), bytes.Index(buf, []byte("\n3:\n"))
+	if i7 == -1 || i3 == -1 || i7 > i3 {
+		t.Errorf("expected entries in selection order, got:\n%s", buf)
+	}
+	if !bytes.Contains(buf, []byte("tags: [go, web]")) {
+		t.Errorf("expected inline tags, got:\n%s", buf)
+	}
+}
+
+func testBulkParseBufferUnchanged(t *testing.T) {
+	t.Helper()
+	bs := testBulkBookmarks()
+	s := BulkStrategy{}
+
+	buf, err := s.BuildBulkBuffer(&Meta{}, bs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := s.ParseBulkBuffer(t.Context(), buf, bs); !errors.Is(err, ErrBufferUnchanged) {
+		t.Errorf("expected ErrBufferUnchanged, got %v", err)
+	}
+}
+
+func testBulkParseBufferChanged(t *testing.T) {
+	t.Helper()
+	bs := testBulkBookmarks()
+	s := BulkStrategy{}
+
+	buf, err := s.BuildBulkBuffer(&Meta{}, bs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	buf = bytes.Replace(buf, []byte("tags: [go]"), []byte("tags: [golang, lang]"), 1)
+
+	got, err := s.ParseBulkBuffer(t.Context(), buf, bs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("expected 1 changed bookmark, got %d", len(got))
+	}
+	if got[0].ID != 7 || got[0].Tags != "golang,lang," {
+		t.Errorf("unexpected bookmark: id=%d tags=%q", got[0].ID, got[0].Tags)
+	}
+	if got[0].Notes != "keep me" {
+		t.Errorf("expected notes to be kept, got %q", got[0].Notes)
+	}
+	if bs[1].Tags != "go," {
+		t.Errorf("original bookmark modified: %q", bs[1].Tags)
+	}
+}
+
+func testBulkParseBufferRemovedEntry(t *testing.T) {
+	t.Helper()
+	bs := testBulkBookmarks()
+	s := BulkStrategy{}
+
+	buf, err := s.BuildBulkBuffer(&Meta{}, bs[1:])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := s.ParseBulkBuffer(t.Context(), buf, bs); !errors.Is(err, ErrBufferUnchanged) {
+		t.Errorf("expected ErrBufferUnchanged, got %v", err)
+	}
+}
+
+func testBulkParseBufferErrors(t *testing.T) {
+	t.Helper()
+	bs := testBulkBookmarks()
+	buf := []byte(`
+3:
+  url: ""
+  title: Example
+  tags: [go]
+9:
+  url: https://new.com
+  title: New
+  tags: [x]
+7:
+  url: https://golang.org
+  titel: Go
+  tags: [go]
+`)
+
+	_, err := BulkStrategy{}.ParseBulkBuffer(t.Context(), buf, bs)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+
+	for _, want := range []error{bookmark.ErrBookmarkURLEmpty, ErrBulkUnknownID, ErrBulkBuffer} {
+		if !errors.Is(err, want) {
+			t.Errorf("expected %v to be reported, got %v", want, err)
+		}
+	}
+	for _, id := range []string{"[3]", "[9]", "[7]"} {
+		if !strings.Contains(err.Error(), id) {
+			t.Errorf("expected error for %s, got %v", id, err)
+		}
+	}
+}
+
+func testBulkCheckTakenURLs(t *testing.T) {
+	t.Helper()
+	r, err := db.Init(t.Context(), filepath.Join(t.TempDir(), "main.db"))
+	if err != nil {
+		t.Fatalf("init db: %v", err)
+	}
+	t.Cleanup(r.Close)
+	if err := r.Init(t.Context()); err != nil {
+		t.Fatalf("init schema: %v", err)
+	}
+
+	outside := &bookmark.Bookmark{URL: "https://outside.com", Title: "Outside", Tags: "x,"}
+	outside.GenChecksum()
+	if _, err := r.InsertOne(t.Context(), outside); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+
+	bs := testBulkBookmarks()
+	byID := map[int]*bookmark.Bookmark{bs[0].ID: bs[0], bs[1].ID: bs[1]}
+
+	// the URL is owned by a bookmark outside the selection
+	edited := bs[0].Copy()
+	edited.URL = outside.URL
+	err = checkTakenURLs(t.Context(), r, byID, []*bookmark.Bookmark{edited})
+	if !errors.Is(err, ErrBulkDuplicated) {
+		t.Fatalf("expected ErrBulkDuplicated, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "[3]") {
+		t.Errorf("expected error for [3], got %v", err)
+	}
+
+	edited.URL = "https://free.com"
+	if err := checkTakenURLs(t.Context(), r, byID, []*bookmark.Bookmark{edited}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
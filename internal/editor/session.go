@@ -25,6 +25,8 @@ func NewMeta(s, ver string) *Meta {
 
 type postRunEditionFunc func(original, updated *bookmark.Bookmark) error
 
+type postRunBulkEditionFunc func(originals, updated []*bookmark.Bookmark) error
+
 type SessionOption func(*EditSession)
 
 // EditSession build -> edit -> parse -> confirm -> save.
@@ -33,6 +35,7 @@ type EditSession struct {
 	Editor      *TextEditor
 	DB          *db.SQLite
 	postEdition postRunEditionFunc
+	postBulk    postRunBulkEditionFunc
 	meta        *Meta
 }
 
@@ -61,6 +64,14 @@ func WithPostEditionRunE(fn postRunEditionFunc) SessionOption {
 	}
 }
 
+// WithPostBulkEditionRunE runs fn once after a bulk edition is saved,
+// originals and updated are matched by index.
+func WithPostBulkEditionRunE(fn postRunBulkEditionFunc) SessionOption {
+	return func(es *EditSession) {
+		es.postBulk = fn
+	}
+}
+
 func WithMeta(m *Meta) SessionOption {
 	return func(es *EditSession) {
 		es.meta = m
@@ -69,6 +80,10 @@ func WithMeta(m *Meta) SessionOption {
 
 // Run processes records for editing using the specified strategy.
 func (e *EditSession) Run(ctx context.Context, bs []*bookmark.Bookmark, strategy EditStrategy) error {
+	if bulk, ok := strategy.(BulkEditStrategy); ok {
+		return e.processBulk(ctx, bs, bulk)
+	}
+
 	n := len(bs)
 	for i, b := range bs {
 		if err := e.processSingleRecord(ctx, b, i+1, n, strategy); err != nil {
@@ -97,13 +112,7 @@ func (e *EditSession) processSingleRecord(ctx context.Context, og *bookmark.Book
 			return err
 		}
 
-		p := e.Console.Palette()
-		header := func() string { return p.BrightYellow.Wrap(txt.GlyphHeavyHorizontal.Prefix(" "), p.Bold) }
-		e.Console.Frame().
-			Reset().
-			CustomFunc(header, p.BrightYellow.Wrap("Diff:\n", p.Bold)).
-			Flush()
-
+		e.diffHeader()
 		fmt.Println(strategy.Diff(og, updated))
 
 		opt, err := e.Console.Choose(ctx, "save changes?", []string{"yes", "no", "edit"}, "y")
@@ -148,3 +157,100 @@ func (e *EditSession) saveRecordChanges(ctx context.Context, strategy EditStrate
 	fmt.Print(e.Console.SuccessMesg(fmt.Sprintf("bookmark [%d] changes saved\n", updated.ID)))
 	return nil
 }
+
+// processBulk handles the edit loop for all records in a single buffer.
+func (e *EditSession) processBulk(ctx context.Context, bs []*bookmark.Bookmark, strategy BulkEditStrategy) error {
+	buf, err := strategy.BuildBulkBuffer(e.meta, bs)
+	if err != nil {
+		return err
+	}
+
+	byID := make(map[int]*bookmark.Bookmark, len(bs))
+	for _, b := range bs {
+		byID[b.ID] = b
+	}
+
+	// Loop to handle the "retry" action, the buffer keeps the user changes.
+	for {
+		buf, err = e.Editor.Edit(ctx, buf, strategy.FileType())
+		if err != nil {
+			return err
+		}
+
+		updated, err := strategy.ParseBulkBuffer(ctx, buf, bs)
+		if errors.Is(err, ErrBufferUnchanged) {
+			return nil
+		}
+		if err == nil {
+			err = checkTakenURLs(ctx, e.DB, byID, updated)
+		}
+		if err != nil {
+			fmt.Println(e.Console.ErrorMesg(err))
+			// Defaults to "no", a forced session must not loop on a broken buffer.
+			opt, cerr := e.Console.Choose(ctx, "edit again?", []string{"yes", "no"}, "n")
+			if cerr != nil {
+				return cerr
+			}
+			if o := strings.ToLower(opt); o != "y" && o != "yes" {
+				return ErrBulkBuffer // details already shown
+			}
+			continue
+		}
+
+		e.diffHeader()
+		p := e.Console.Palette()
+		for _, b := range updated {
+			fmt.Println(p.Bold.With(p.Blue).Sprintf("[%d]", b.ID), txt.Shorten(b.Title, 60))
+			fmt.Println(strategy.Diff(byID[b.ID], b))
+		}
+
+		q := fmt.Sprintf("save changes to %d bookmarks?", len(updated))
+		opt, err := e.Console.Choose(ctx, q, []string{"yes", "no", "edit"}, "y")
+		if err != nil {
+			return err
+		}
+
+		switch strings.ToLower(opt) {
+		case "y", "yes":
+			return e.saveBulkChanges(ctx, strategy, byID, updated)
+		case "n", "no":
+			return nil
+		}
+	}
+}
+
+// saveBulkChanges persists all updated records at once.
+func (e *EditSession) saveBulkChanges(
+	ctx context.Context,
+	strategy BulkEditStrategy,
+	byID map[int]*bookmark.Bookmark,
+	updated []*bookmark.Bookmark,
+) error {
+	if err := strategy.SaveAll(ctx, e.DB, updated); err != nil {
+		return err
+	}
+
+	if e.postBulk != nil {
+		originals := make([]*bookmark.Bookmark, 0, len(updated))
+		for _, b := range updated {
+			originals = append(originals, byID[b.ID])
+		}
+
+		if err := e.postBulk(originals, updated); err != nil {
+			return err
+		}
+	}
+
+	fmt.Print(e.Console.SuccessMesg(fmt.Sprintf("updated %d bookmarks\n", len(updated))))
+	return nil
+}
+
+// diffHeader prints the header shown before the changes.
+func (e *EditSession) diffHeader() {
+	p := e.Console.Palette()
+	header := func() string { return p.BrightYellow.Wrap(txt.GlyphHeavyHorizontal.Prefix(" "), p.Bold) }
+	e.Console.Frame().
+		Reset().
+		CustomFunc(header, p.BrightYellow.Wrap("Diff:\n", p.Bold)).
+		Flush()
+}
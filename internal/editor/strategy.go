@@ -24,3 +24,18 @@ type EditStrategy interface {
 	// Strategy type
 	FileType() string
 }
+
+// BulkEditStrategy is an EditStrategy that edits every bookmark in a single
+// buffer, EditSession.Run uses it instead of one editor per bookmark.
+type BulkEditStrategy interface {
+	EditStrategy
+
+	// Builds one buffer holding all the bookmarks
+	BuildBulkBuffer(m *Meta, bs []*bookmark.Bookmark) ([]byte, error)
+
+	// Parses the buffer back, returns only the changed bookmarks
+	ParseBulkBuffer(ctx context.Context, buf []byte, originals []*bookmark.Bookmark) ([]*bookmark.Bookmark, error)
+
+	// Saves all changes at once
+	SaveAll(ctx context.Context, db *db.SQLite, bs []*bookmark.Bookmark) error
+}
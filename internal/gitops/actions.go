@@ -120,7 +120,7 @@ func Sync(ctx context.Context, app *application.App, msg string) error {
 		return fmt.Errorf("git sync: failed to add bookmarks: %w", err)
 	}
 
-	return m.SaveChanges(ctx, gr, msg)
+	return SaveChanges(ctx, app, m, gr, r, msg)
 }
 
 func readFiles(ctx context.Context, path string, total int) ([]*bookmark.Bookmark, error) {
@@ -133,6 +133,9 @@ func addFiles(ctx context.Context, repoPath string, bs []*bookmark.Bookmark) err
 		return err
 	}
 
+	// private bookmarks never leave the database
+	bs = bookmark.WithoutPrivate(bs)
+
 	sp := rotato.New(
 		rotato.WithMessage("starting..."),
 		rotato.WithPrefix("Git Tracker"),
@@ -165,6 +168,9 @@ func removeFiles(ctx context.Context, repoPath string, bs []*bookmark.Bookmark)
 		return err
 	}
 
+	// private bookmarks have no files in the repo
+	bs = bookmark.WithoutPrivate(bs)
+
 	c, err := bookio.NewFileRemover(repoPath, files.DefaultManager, genFullpath)
 	if err != nil {
 		return err
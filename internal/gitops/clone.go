@@ -110,6 +110,17 @@ func processRepo(ctx context.Context, d *deps.Deps, gp *GitPuller, gr *git.Repo)
 		return err
 	}
 
+	app, err := d.Application(ctx)
+	if err != nil {
+		return err
+	}
+
+	if app.Flags.Team || app.Git.Team {
+		if err := attributeFromHistory(ctx, gr); err != nil {
+			return err
+		}
+	}
+
 	if err := gp.PrintDetails(gr); err != nil {
 		if errors.Is(err, git.ErrGitRepoEmpty) {
 			return nil // move to next
@@ -188,30 +199,7 @@ func handleCreateRepoMode(ctx context.Context, d *deps.Deps, gr *git.Repo, bs []
 }
 
 func insertRecords(ctx context.Context, d *deps.Deps, bs []*bookmark.Bookmark) error {
-	r, err := d.Repository()
-	if err != nil {
-		return err
-	}
-
-	bs, err = port.DeduplicateReport(ctx, d.Console(), r, bs)
-	if err != nil {
-		return err
-	}
-
-	c := d.Console()
-	if len(bs) == 0 {
-		_ = c.Print(ctx, c.Warning("nothing to import\n").StringReset())
-		return nil
-	}
-
-	if err := r.InsertMany(ctx, bs); err != nil {
-		return err
-	}
-
-	return c.Print(
-		ctx,
-		c.SuccessMesg("inserted ", len(bs), " into ", r.Name()+"\n"),
-	)
+	return port.Save(ctx, d, bs)
 }
 
 func createRepo(ctx context.Context, d *deps.Deps, repoPath string, bs []*bookmark.Bookmark) error {
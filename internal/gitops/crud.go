@@ -2,6 +2,7 @@ package gitops
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
@@ -28,6 +29,7 @@ func NewManager(app *application.App) (*git.Mgr, error) {
 		app.Path.Git(),
 		git.WithGit(g),
 		git.WithVersion(app.Version()),
+		git.WithAuthor(app.Git.Author),
 	)
 }
 
@@ -83,11 +85,7 @@ func Add(ctx context.Context, app *application.App, r *db.SQLite, b *bookmark.Bo
 		return err
 	}
 
-	return m.SaveChanges(
-		ctx,
-		gr,
-		fmt.Sprintf("[%s] bookmark added", gr.Name()),
-	)
+	return SaveChanges(ctx, app, m, gr, r, fmt.Sprintf("[%s] bookmark added", gr.Name()))
 }
 
 func Remove(ctx context.Context, app *application.App, bs []*bookmark.Bookmark) error {
@@ -116,11 +114,17 @@ func Remove(ctx context.Context, app *application.App, bs []*bookmark.Bookmark)
 		return err
 	}
 
-	return m.SaveChanges(
-		ctx,
-		gr,
-		fmt.Sprintf("[%s] remove bookmarks", repoName),
-	)
+	return SaveChanges(ctx, app, m, gr, r, fmt.Sprintf("[%s] remove bookmarks", repoName))
+}
+
+// SaveChanges regenerates the feeds of the database and commits them with
+// the changes staged in the repo.
+func SaveChanges(ctx context.Context, app *application.App, m *git.Mgr, gr *git.Repo, r *db.SQLite, msg string) error {
+	if err := writeFeeds(ctx, app, r); err != nil {
+		return err
+	}
+
+	return m.SaveChanges(ctx, gr, msg)
 }
 
 func Drop(ctx context.Context, app *application.App, c *ui.Console) error {
@@ -166,6 +170,21 @@ func Drop(ctx context.Context, app *application.App, c *ui.Console) error {
 	return c.Print(ctx, c.SuccessMesg("database untracked\n"))
 }
 
+// IsTracked reports whether the current database is tracked in the git
+// repo.
+func IsTracked(app *application.App) bool {
+	if !app.GitEnabled() {
+		return false
+	}
+
+	m, err := NewManager(app)
+	if err != nil {
+		return false
+	}
+
+	return m.IsEnabled() && m.IsTracked(app.DBBaseName())
+}
+
 func Update(ctx context.Context, app *application.App, old, fresh *bookmark.Bookmark) error {
 	if !app.GitEnabled() {
 		return nil
@@ -191,5 +210,46 @@ func Update(ctx context.Context, app *application.App, old, fresh *bookmark.Book
 	}
 
 	gr := NewRepo(m, r.Name(), RepoStatsReader(r))
-	return m.UpdateAndSave(ctx, gr, old, fresh, files.RemoveEmptyDirs)
+	if err := m.Update(ctx, gr, old, fresh, files.RemoveEmptyDirs); err != nil {
+		return err
+	}
+
+	return SaveChanges(ctx, app, m, gr, r, fmt.Sprintf("[%s] update bookmark", gr.Name()))
+}
+
+// UpdateMany records already saved changes to several bookmarks in a single
+// commit. olds and fresh are matched by index.
+func UpdateMany(ctx context.Context, app *application.App, olds, fresh []*bookmark.Bookmark) error {
+	if !app.GitEnabled() {
+		return nil
+	}
+
+	m, err := NewManager(app)
+	if err != nil {
+		return err
+	}
+
+	if !m.IsEnabled() || !m.IsTracked(app.DBBaseName()) {
+		return nil
+	}
+
+	r, err := db.New(ctx, app.Path.DB())
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	gr := NewRepo(m, r.Name(), RepoStatsReader(r))
+	for i := range fresh {
+		if err := m.Update(ctx, gr, olds[i], fresh[i], files.RemoveEmptyDirs); err != nil {
+			return err
+		}
+	}
+
+	err = SaveChanges(ctx, app, m, gr, r, fmt.Sprintf("[%s] update %d bookmarks", gr.Name(), len(fresh)))
+	if err != nil && !errors.Is(err, git.ErrGitUpToDate) {
+		return err
+	}
+
+	return nil
 }
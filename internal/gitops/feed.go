@@ -0,0 +1,105 @@
+package gitops
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	files "github.com/mateconpizza/gofiles"
+
+	"github.com/mateconpizza/gm/internal/application"
+	"github.com/mateconpizza/gm/pkg/bookio"
+	"github.com/mateconpizza/gm/pkg/bookmark"
+	"github.com/mateconpizza/gm/pkg/db"
+)
+
+var ErrFeedName = errors.New("feed name")
+
+// writeFeeds writes the configured feeds of the synced database at the root
+// of the repository, so they are committed with the bookmarks. A feed file
+// is only rewritten when its content changes.
+func writeFeeds(ctx context.Context, app *application.App, r *db.SQLite) error {
+	for _, f := range app.Feeds {
+		if files.StripExts(cmp.Or(f.DB, application.MainDBName)) != app.DBBaseName() {
+			continue
+		}
+
+		if err := writeFeed(ctx, app.Path.Git(), f, r); err != nil {
+			return fmt.Errorf("feed %q: %w", f.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func writeFeed(ctx context.Context, root string, f *application.Feed, r *db.SQLite) error {
+	name := strings.TrimSpace(f.Name)
+	if name == "" || name != filepath.Base(name) || strings.HasPrefix(name, ".") {
+		return fmt.Errorf("%w: invalid %q", ErrFeedName, f.Name)
+	}
+
+	format, err := bookio.ValidFeedFormat(f.Format)
+	if err != nil {
+		return err
+	}
+
+	bs, err := feedBookmarks(ctx, r, f)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	opts := &bookio.FeedOptions{Title: cmp.Or(f.Title, name), Link: f.Link, Limit: f.Limit}
+	if err := bookio.ExportFeed(&buf, format, bs, opts); err != nil {
+		return err
+	}
+
+	path := filepath.Join(root, name+bookio.FeedExt(format))
+	if old, err := os.ReadFile(path); err == nil && bytes.Equal(old, buf.Bytes()) {
+		return nil
+	}
+
+	slog.DebugContext(ctx, "writing feed", "path", path, "bookmarks", len(bs))
+
+	return os.WriteFile(path, buf.Bytes(), files.FilePerm)
+}
+
+// feedBookmarks returns the bookmarks matching the feed query and having
+// all its tags.
+func feedBookmarks(ctx context.Context, r *db.SQLite, f *application.Feed) ([]*bookmark.Bookmark, error) {
+	var (
+		bs  []*bookmark.Bookmark
+		err error
+	)
+	if f.Query != "" {
+		bs, err = r.ByQuery(ctx, f.Query)
+	} else {
+		bs, err = r.All(ctx)
+	}
+	if err != nil && !errors.Is(err, db.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	return slices.DeleteFunc(bs, func(b *bookmark.Bookmark) bool {
+		return !hasTags(b, f.Tags)
+	}), nil
+}
+
+// hasTags reports whether b has all the tags.
+func hasTags(b *bookmark.Bookmark, tags []string) bool {
+	bt := strings.Split(b.Tags, ",")
+	for _, t := range tags {
+		if !slices.Contains(bt, strings.ToLower(strings.TrimSpace(t))) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,82 @@
+package gitops
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mateconpizza/gm/internal/application"
+	"github.com/mateconpizza/gm/pkg/bookmark"
+	"github.com/mateconpizza/gm/pkg/db"
+)
+
+func TestWriteFeed(t *testing.T) {
+	t.Parallel()
+
+	r, err := db.Init(t.Context(), filepath.Join(t.TempDir(), "main.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(r.Close)
+
+	bs := []*bookmark.Bookmark{
+		{URL: "https://go.dev/", Title: "Go", Tags: "go,team,"},
+		{URL: "https://example.org/", Title: "Example", Tags: "team,"},
+		{URL: "https://rust-lang.org/", Title: "Rust", Tags: "rust,"},
+	}
+	for _, b := range bs {
+		b.GenChecksum()
+		if _, err := r.InsertOne(t.Context(), b); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	root := t.TempDir()
+	f := &application.Feed{Name: "team", Format: "rss", Title: "Team links", Tags: []string{"team"}}
+	if err := writeFeed(t.Context(), root, f, r); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(root, "team.rss")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"<title>Team links</title>", "https://go.dev/", "https://example.org/"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("feed missing %q:\n%s", want, data)
+		}
+	}
+	if strings.Contains(string(data), "rust-lang.org") {
+		t.Errorf("feed includes a bookmark without the tag:\n%s", data)
+	}
+
+	// unchanged feeds are not rewritten
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeFeed(t.Context(), root, f, r); err != nil {
+		t.Fatal(err)
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !fi.ModTime().Equal(old) {
+		t.Error("unchanged feed rewritten")
+	}
+}
+
+func TestWriteFeedInvalidName(t *testing.T) {
+	t.Parallel()
+
+	for _, name := range []string{"", "../team", ".team"} {
+		f := &application.Feed{Name: name}
+		if err := writeFeed(t.Context(), t.TempDir(), f, nil); err == nil {
+			t.Errorf("expected an error for name %q", name)
+		}
+	}
+}
@@ -246,7 +246,7 @@ func promptGPGPassphrase(
 	return nil
 }
 
-func menuFingerprint(c *ui.Console, app *application.App) *menu.Menu[*gpg.Fingerprint] {
+func menuFingerprint(c *ui.Console, app *application.App) *picker.Menu[*gpg.Fingerprint] {
 	p := c.Palette()
 	trustColor := func(key *gpg.Fingerprint) string {
 		t := key.TrustLevelString()
@@ -286,7 +286,7 @@ func menuFingerprint(c *ui.Console, app *application.App) *menu.Menu[*gpg.Finger
 	return m
 }
 
-func selectFingerprint(m *menu.Menu[*gpg.Fingerprint], fps []*gpg.Fingerprint) (*gpg.Fingerprint, error) {
+func selectFingerprint(m *picker.Menu[*gpg.Fingerprint], fps []*gpg.Fingerprint) (*gpg.Fingerprint, error) {
 	keys, err := m.Select(fps)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,492 @@
+package gitops
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	files "github.com/mateconpizza/gofiles"
+
+	"github.com/mateconpizza/gm/internal/application"
+	"github.com/mateconpizza/gm/internal/deps"
+	"github.com/mateconpizza/gm/internal/locker/gpg"
+	"github.com/mateconpizza/gm/internal/ui"
+	"github.com/mateconpizza/gm/internal/ui/txt"
+	"github.com/mateconpizza/gm/pkg/bookmark"
+	"github.com/mateconpizza/gm/pkg/db"
+	"github.com/mateconpizza/gm/pkg/git"
+)
+
+// Change kinds of a bookmark between two commits.
+const (
+	ChangeAdded    = "added"
+	ChangeModified = "modified"
+	ChangeRemoved  = "removed"
+)
+
+// BookmarkChange is a bookmark version change recorded in a commit.
+type BookmarkChange struct {
+	Kind string
+	Old  *bookmark.Bookmark // nil when added
+	New  *bookmark.Bookmark // nil when removed
+}
+
+// URL returns the URL of the most recent version.
+func (bc *BookmarkChange) URL() string {
+	if bc.New != nil {
+		return bc.New.URL
+	}
+	return bc.Old.URL
+}
+
+// HistoryEntry is a commit along with the bookmark changes it holds.
+type HistoryEntry struct {
+	Commit  *git.Commit
+	Changes []*BookmarkChange
+	Skipped []error // files that could not be read
+}
+
+// revReader reads bookmark files from any revision of the repository.
+type revReader struct {
+	g     *git.Git
+	gpg   *gpg.GPG // nil for JSON repositories
+	cache map[string]*revFile
+}
+
+// revFile is the result of reading a file at a revision.
+type revFile struct {
+	b   *bookmark.Bookmark
+	err error
+}
+
+func newRevReader(app *application.App, g *git.Git) (*revReader, error) {
+	rr := &revReader{g: g, cache: make(map[string]*revFile)}
+	if !gpg.IsInitialized(app.Path.Git()) {
+		return rr, nil
+	}
+
+	// recipient is not needed for decryption
+	gp, err := gpg.New("")
+	if err != nil {
+		return nil, err
+	}
+	rr.gpg = gp
+
+	return rr, nil
+}
+
+// isBookmarkFile reports whether path holds a bookmark version.
+func (rr *revReader) isBookmarkFile(path string) bool {
+	if filepath.Base(path) == git.SummaryFileName {
+		return false
+	}
+
+	ext := ".json"
+	if rr.gpg != nil {
+		ext = gpg.Extension
+	}
+
+	return filepath.Ext(path) == ext
+}
+
+// read loads the bookmark stored at path in revision rev, each version is
+// read and decrypted once.
+func (rr *revReader) read(ctx context.Context, rev, path string) (*bookmark.Bookmark, error) {
+	key := rev + ":" + path
+	if f, ok := rr.cache[key]; ok {
+		return f.b, f.err
+	}
+
+	b, err := rr.load(ctx, rev, path)
+	rr.cache[key] = &revFile{b: b, err: err}
+
+	return b, err
+}
+
+func (rr *revReader) load(ctx context.Context, rev, path string) (*bookmark.Bookmark, error) {
+	data, err := rr.g.Show(ctx, rev, path)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s@%s: %w", path, rev, err)
+	}
+
+	if rr.gpg != nil {
+		data, err = rr.gpg.DecryptBytes(ctx, data)
+		if err != nil {
+			return nil, fmt.Errorf("%s@%s: %w", path, rev, err)
+		}
+	}
+
+	bj := bookmark.NewJSON()
+	if err := json.Unmarshal(data, bj); err != nil {
+		return nil, fmt.Errorf("error unmarshalling JSON: %w, %s@%s", err, path, rev)
+	}
+
+	return bookmark.NewFromJSON(bj), nil
+}
+
+// History returns the commits of the repository of the current database,
+// newest first, with the bookmark changes matching query.
+func History(ctx context.Context, app *application.App, query string) ([]*HistoryEntry, error) {
+	g, err := NewGit(app)
+	if err != nil {
+		return nil, err
+	}
+
+	rr, err := newRevReader(app, g)
+	if err != nil {
+		return nil, err
+	}
+
+	commits, err := g.Log(ctx, app.DBBaseName())
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []*HistoryEntry
+	for _, c := range commits {
+		// only bookmark files are read, the others are dropped beforehand
+		c.Changes = slices.DeleteFunc(c.Changes, func(fc git.FileChange) bool {
+			return !rr.isBookmarkFile(fc.Path)
+		})
+		if len(c.Changes) == 0 {
+			continue
+		}
+
+		e := &HistoryEntry{Commit: c}
+		changes, _ := readChanges(ctx, rr, c.Parent, c.Hash, c.Changes, func(err error) error {
+			e.Skipped = append(e.Skipped, err)
+			return nil
+		})
+
+		e.Changes = slices.DeleteFunc(changes, func(bc *BookmarkChange) bool {
+			return !matchQuery(bc.Old, query) && !matchQuery(bc.New, query)
+		})
+
+		if len(e.Changes) == 0 && len(e.Skipped) == 0 {
+			continue
+		}
+
+		entries = append(entries, e)
+	}
+
+	return entries, nil
+}
+
+// revChanges loads the bookmark versions of the given file changes between
+// revisions from and to.
+//
+// Files are paired by URL, since GPG repositories name files after the
+// bookmark checksum and an update shows up as a removal plus an addition.
+func revChanges(ctx context.Context, rr *revReader, from, to string, fcs []git.FileChange) ([]*BookmarkChange, error) {
+	return readChanges(ctx, rr, from, to, fcs, func(err error) error { return err })
+}
+
+// readChanges is revChanges with the read errors passed to onErr, the file
+// is skipped when it returns nil.
+func readChanges(
+	ctx context.Context,
+	rr *revReader,
+	from, to string,
+	fcs []git.FileChange,
+	onErr func(error) error,
+) ([]*BookmarkChange, error) {
+	var removed, added []*bookmark.Bookmark
+	for _, fc := range fcs {
+		if !rr.isBookmarkFile(fc.Path) {
+			continue
+		}
+
+		var (
+			old, cur *bookmark.Bookmark
+			err      error
+		)
+		if fc.Status != "A" {
+			old, err = rr.read(ctx, from, fc.Path)
+		}
+		if err == nil && fc.Status != "D" {
+			cur, err = rr.read(ctx, to, fc.Path)
+		}
+
+		if err != nil {
+			if err := onErr(err); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if old != nil {
+			removed = append(removed, old)
+		}
+		if cur != nil {
+			added = append(added, cur)
+		}
+	}
+
+	return pairChanges(removed, added), nil
+}
+
+// pairChanges matches removed and added versions by URL.
+func pairChanges(removed, added []*bookmark.Bookmark) []*BookmarkChange {
+	olds := make(map[string]*bookmark.Bookmark, len(removed))
+	for _, b := range removed {
+		olds[b.URL] = b
+	}
+
+	changes := make([]*BookmarkChange, 0, len(added)+len(removed))
+	for _, b := range added {
+		old, ok := olds[b.URL]
+		if !ok {
+			changes = append(changes, &BookmarkChange{Kind: ChangeAdded, New: b})
+			continue
+		}
+
+		delete(olds, b.URL)
+		changes = append(changes, &BookmarkChange{Kind: ChangeModified, Old: old, New: b})
+	}
+
+	for _, b := range removed {
+		if _, ok := olds[b.URL]; ok {
+			changes = append(changes, &BookmarkChange{Kind: ChangeRemoved, Old: b})
+		}
+	}
+
+	return changes
+}
+
+// matchQuery reports whether b matches query, case-insensitive.
+func matchQuery(b *bookmark.Bookmark, query string) bool {
+	if b == nil {
+		return false
+	}
+	if query == "" {
+		return true
+	}
+
+	s := strings.ToLower(b.URL + b.Title + b.Desc + b.Notes + b.Tags)
+	return strings.Contains(s, strings.ToLower(query))
+}
+
+// LogCmd prints the history of the bookmarks matching query.
+func LogCmd(ctx context.Context, d *deps.Deps, query string) error {
+	app, err := d.Application(ctx)
+	if err != nil {
+		return err
+	}
+
+	entries, err := History(ctx, app, query)
+	if err != nil {
+		return err
+	}
+
+	if len(entries) == 0 {
+		return git.ErrGitNoCommits
+	}
+
+	c := d.Console()
+	for _, e := range entries {
+		printHistoryEntry(c, e)
+	}
+
+	for _, e := range entries {
+		for _, err := range e.Skipped {
+			c.Warning(fmt.Sprintf("%s: skipped %v\n", e.Commit.Short(), err)).Flush()
+		}
+	}
+
+	return nil
+}
+
+func printHistoryEntry(c *ui.Console, e *HistoryEntry) {
+	f, p := c.Frame(), c.Palette()
+
+	f.Headerln(p.BrightYellow.Wrap(e.Commit.Short(), p.Bold) + " " + e.Commit.Subject).
+		Rowln(p.Dim.Wrap(fmt.Sprintf("%s · %s (%s)",
+			e.Commit.Author,
+			e.Commit.Date.Local().Format("2006 Jan 02 15:04"),
+			txt.RelativeTime(e.Commit.Date.Format(txt.TimeLayout)),
+		), p.Italic))
+
+	for _, bc := range e.Changes {
+		var mark string
+		switch bc.Kind {
+		case ChangeAdded:
+			mark = p.BrightGreen.Sprint("+")
+		case ChangeRemoved:
+			mark = p.BrightRed.Sprint("-")
+		default:
+			mark = p.BrightBlue.Sprint("~")
+		}
+
+		f.Midln(mark + " " + bc.URL())
+		if bc.Kind != ChangeModified {
+			continue
+		}
+
+		for _, fd := range bookmark.Diff(bc.Old, bc.New) {
+			f.Midln(fmt.Sprintf("    %s %s %s %s",
+				p.Dim.Sprint(fd.Field+":"),
+				p.BrightRed.Sprint(txt.Shorten(fd.Old, 40)),
+				p.Dim.Sprint("→"),
+				p.BrightGreen.Sprint(txt.Shorten(fd.New, 40)),
+			))
+		}
+	}
+
+	f.Rowln().Flush()
+}
+
+// Restore re-imports the bookmarks matching query as they were at revision
+// rev, inserting the missing ones and updating the existing ones.
+func Restore(ctx context.Context, d *deps.Deps, rev, query string) error {
+	app, err := d.Application(ctx)
+	if err != nil {
+		return err
+	}
+
+	r, err := d.Repository()
+	if err != nil {
+		return err
+	}
+	if err := r.CheckMatchable(ctx); err != nil {
+		return err
+	}
+
+	m, err := NewManager(app)
+	if err != nil {
+		return err
+	}
+
+	g := m.Git()
+	hash, err := g.ResolveRev(ctx, rev)
+	if err != nil {
+		return err
+	}
+
+	versions, err := versionsAt(ctx, app, g, hash, query)
+	if err != nil {
+		return err
+	}
+
+	var (
+		toInsert []*bookmark.Bookmark
+		olds     []*bookmark.Bookmark
+		toUpdate []*bookmark.Bookmark
+	)
+	for _, b := range versions {
+		current, ok := r.Has(ctx, b.URL)
+		if !ok {
+			toInsert = append(toInsert, b)
+			continue
+		}
+		if len(bookmark.Diff(current, b)) == 0 {
+			continue
+		}
+
+		olds = append(olds, current)
+		toUpdate = append(toUpdate, restoredVersion(current, b))
+	}
+
+	c := d.Console()
+	short := hash[:7]
+	if len(toInsert)+len(toUpdate) == 0 {
+		return c.Print(ctx, c.Warning("nothing to restore\n").StringReset())
+	}
+
+	q := fmt.Sprintf("restore from %s? (%d new, %d updated)", short, len(toInsert), len(toUpdate))
+	if err := c.ConfirmErr(ctx, q, "y"); err != nil {
+		return err
+	}
+
+	if err := r.InsertAndUpdate(ctx, toInsert, toUpdate); err != nil {
+		return err
+	}
+
+	if err := restoreRepoFiles(ctx, app, m, r, toInsert, olds, toUpdate, short); err != nil {
+		return err
+	}
+
+	return c.Print(ctx, c.SuccessMesg(fmt.Sprintf("restored %d bookmarks from %s\n", len(toInsert)+len(toUpdate), short)))
+}
+
+// versionsAt reads the bookmarks of the current database repository stored
+// at revision hash that match query.
+func versionsAt(ctx context.Context, app *application.App, g *git.Git, hash, query string) ([]*bookmark.Bookmark, error) {
+	rr, err := newRevReader(app, g)
+	if err != nil {
+		return nil, err
+	}
+
+	paths, err := g.ListFiles(ctx, hash, app.DBBaseName())
+	if err != nil {
+		return nil, err
+	}
+
+	var bs []*bookmark.Bookmark
+	for _, path := range paths {
+		if !rr.isBookmarkFile(path) {
+			continue
+		}
+
+		b, err := rr.read(ctx, hash, path)
+		if err != nil {
+			return nil, err
+		}
+
+		if matchQuery(b, query) {
+			bs = append(bs, b)
+		}
+	}
+
+	if len(bs) == 0 {
+		return nil, fmt.Errorf("%w at %s", db.ErrRecordNoMatch, hash[:7])
+	}
+
+	return bs, nil
+}
+
+// restoredVersion returns current with the content fields of version.
+func restoredVersion(current, version *bookmark.Bookmark) *bookmark.Bookmark {
+	b := current.Copy()
+	b.Title = version.Title
+	b.Desc = version.Desc
+	b.Tags = version.Tags
+	b.Notes = version.Notes
+	b.Favorite = version.Favorite
+	b.ArchiveURL = version.ArchiveURL
+	b.ArchiveTimestamp = version.ArchiveTimestamp
+
+	return b
+}
+
+// restoreRepoFiles writes the restored versions back to the git repository
+// and commits them.
+func restoreRepoFiles(
+	ctx context.Context,
+	app *application.App,
+	m *git.Mgr,
+	r *db.SQLite,
+	inserted, olds, updated []*bookmark.Bookmark,
+	rev string,
+) error {
+	if !app.GitEnabled() || !m.IsTracked(r.BaseName()) {
+		return nil
+	}
+
+	gr := NewRepo(m, r.Name(), RepoStatsReader(r))
+	if len(inserted) > 0 {
+		if err := gr.Add(ctx, inserted); err != nil {
+			return err
+		}
+	}
+
+	for i := range updated {
+		if err := m.Update(ctx, gr, olds[i], updated[i], files.RemoveEmptyDirs); err != nil {
+			return err
+		}
+	}
+
+	return SaveChanges(ctx, app, m, gr, r, fmt.Sprintf("[%s] restore bookmarks from %s", gr.Name(), rev))
+}
@@ -0,0 +1,63 @@
+package gitops
+
+import (
+	"testing"
+
+	"github.com/mateconpizza/gm/pkg/bookmark"
+)
+
+func TestPairChanges(t *testing.T) {
+	t.Parallel()
+
+	goOld := &bookmark.Bookmark{URL: "https://go.dev", Title: "Go"}
+	goNew := &bookmark.Bookmark{URL: "https://go.dev", Title: "Golang"}
+	zig := &bookmark.Bookmark{URL: "https://ziglang.org"}
+	rust := &bookmark.Bookmark{URL: "https://rust-lang.org"}
+
+	changes := pairChanges(
+		[]*bookmark.Bookmark{goOld, rust},
+		[]*bookmark.Bookmark{goNew, zig},
+	)
+
+	want := map[string]string{
+		"https://go.dev":        ChangeModified,
+		"https://ziglang.org":   ChangeAdded,
+		"https://rust-lang.org": ChangeRemoved,
+	}
+
+	if len(changes) != len(want) {
+		t.Fatalf("expected %d changes, got %d", len(want), len(changes))
+	}
+
+	for _, bc := range changes {
+		if got := want[bc.URL()]; got != bc.Kind {
+			t.Errorf("%s: expected %q, got %q", bc.URL(), got, bc.Kind)
+		}
+	}
+}
+
+func TestMatchQuery(t *testing.T) {
+	t.Parallel()
+
+	b := &bookmark.Bookmark{URL: "https://go.dev", Title: "Go", Tags: "lang,"}
+
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{"", true},
+		{"GO.DEV", true},
+		{"lang", true},
+		{"rust", false},
+	}
+
+	for _, tt := range tests {
+		if got := matchQuery(b, tt.query); got != tt.want {
+			t.Errorf("matchQuery(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+
+	if matchQuery(nil, "") {
+		t.Error("nil bookmark should not match")
+	}
+}
@@ -132,9 +132,10 @@ func Prune(ctx context.Context, app *application.App, r *db.SQLite) error {
 	if err != nil {
 		return err
 	}
+	bs = bookmark.WithoutPrivate(bs)
 
 	saveChanges := func(ctx context.Context, msg string) error {
-		return m.SaveChanges(ctx, gr, msg)
+		return SaveChanges(ctx, app, m, gr, r, msg)
 	}
 
 	return newRepoReconciler(gr, bs, saveChanges).Reconcile(ctx)
@@ -0,0 +1,129 @@
+package gitops
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/mateconpizza/gm/internal/application"
+	"github.com/mateconpizza/gm/pkg/bookmark"
+	"github.com/mateconpizza/gm/pkg/db"
+)
+
+// PullResult counts the bookmarks applied to the database by Pull.
+type PullResult struct {
+	Added   int
+	Updated int
+	Removed int
+}
+
+// Total returns the number of bookmarks applied.
+func (pr *PullResult) Total() int { return pr.Added + pr.Updated + pr.Removed }
+
+// Pull fetches the upstream commits of the git repository and applies the
+// bookmark changes of the current database to it.
+func Pull(ctx context.Context, app *application.App) (*PullResult, error) {
+	res := &PullResult{}
+	if !app.GitEnabled() {
+		return res, nil
+	}
+
+	m, err := NewManager(app)
+	if err != nil {
+		return nil, err
+	}
+
+	if !m.IsTracked(app.DBBaseName()) {
+		slog.Debug("git pull: database not tracked, skipping", "db", app.DBBaseName())
+		return res, nil
+	}
+
+	r, err := db.New(ctx, app.Path.DB())
+	if err != nil {
+		return nil, fmt.Errorf("git pull: failed to open database: %w", err)
+	}
+	defer r.Close()
+
+	// refuse before HEAD moves, the changes could not be applied later
+	if err := r.CheckMatchable(ctx); err != nil {
+		return nil, fmt.Errorf("git pull: %w", err)
+	}
+
+	g := m.Git()
+	before, err := g.ResolveRev(ctx, "HEAD")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := g.Pull(ctx); err != nil {
+		return nil, fmt.Errorf("git pull: %w", err)
+	}
+
+	after, err := g.ResolveRev(ctx, "HEAD")
+	if err != nil {
+		return nil, err
+	}
+
+	if before == after {
+		return res, nil
+	}
+
+	fcs, err := g.DiffNames(ctx, before, after, app.DBBaseName())
+	if err != nil {
+		return nil, err
+	}
+
+	rr, err := newRevReader(app, g)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := revChanges(ctx, rr, before, after, fcs)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := applyChanges(ctx, r, changes, res); err != nil {
+		return nil, err
+	}
+
+	slog.Info("git pull: applied changes",
+		"added", res.Added, "updated", res.Updated, "removed", res.Removed)
+
+	return res, nil
+}
+
+// applyChanges inserts, updates and deletes the database records matching
+// the given bookmark changes.
+func applyChanges(ctx context.Context, r *db.SQLite, changes []*BookmarkChange, res *PullResult) error {
+	var (
+		toInsert []*bookmark.Bookmark
+		toUpdate []*bookmark.Bookmark
+		toDelete []int
+	)
+
+	for _, bc := range changes {
+		current, ok := r.Has(ctx, bc.URL())
+		switch {
+		case bc.Kind == ChangeRemoved:
+			if ok {
+				toDelete = append(toDelete, current.ID)
+			}
+		case !ok:
+			toInsert = append(toInsert, bc.New)
+		case len(bookmark.Diff(current, bc.New)) > 0:
+			toUpdate = append(toUpdate, restoredVersion(current, bc.New))
+		}
+	}
+
+	// all or nothing, HEAD has already moved and the pull is not retried
+	if err := r.ApplyChanges(ctx, toInsert, toUpdate, toDelete); err != nil {
+		return err
+	}
+
+	res.Added = len(toInsert)
+	res.Updated = len(toUpdate)
+	res.Removed = len(toDelete)
+
+	return nil
+}
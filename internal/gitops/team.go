@@ -0,0 +1,70 @@
+package gitops
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path"
+
+	"github.com/mateconpizza/gm/internal/application"
+	"github.com/mateconpizza/gm/internal/locker/gpg"
+	"github.com/mateconpizza/gm/pkg/bookmark"
+	"github.com/mateconpizza/gm/pkg/git"
+)
+
+// Author returns the name used to attribute new bookmarks.
+//
+// It is taken from the config, the git user.name or the system user, in that
+// order.
+func Author(ctx context.Context, app *application.App) string {
+	if app.Git.Author != "" {
+		return app.Git.Author
+	}
+
+	if name := git.UserName(ctx, app.Path.Git()); name != "" {
+		return name
+	}
+
+	return os.Getenv("USER")
+}
+
+// attributeFromHistory fills the missing authors of the bookmarks in gr with
+// the author of the commit that added their file.
+func attributeFromHistory(ctx context.Context, gr *git.Repo) error {
+	g, err := git.New(gr.Root())
+	if err != nil {
+		return err
+	}
+
+	authors, err := g.FileAuthors(ctx, gr.Name())
+	if err != nil {
+		return err
+	}
+
+	encrypted := gpg.IsInitialized(gr.Root())
+	for _, b := range gr.Bookmarks() {
+		if b.AddedBy != "" {
+			continue
+		}
+
+		p, err := repoFilePath(b, encrypted)
+		if err != nil {
+			slog.Debug("team: skipping attribution", "url", b.URL, "error", err)
+			continue
+		}
+
+		b.AddedBy = authors[path.Join(gr.Name(), p)]
+	}
+
+	return nil
+}
+
+// repoFilePath returns the path of the bookmark file relative to its
+// repository.
+func repoFilePath(b *bookmark.Bookmark, encrypted bool) (string, error) {
+	if encrypted {
+		return b.GPGPath()
+	}
+
+	return b.JSONPath()
+}
@@ -22,12 +22,14 @@ import (
 	"github.com/mateconpizza/gm/internal/deps"
 	"github.com/mateconpizza/gm/internal/editor"
 	"github.com/mateconpizza/gm/internal/gitops"
+	"github.com/mateconpizza/gm/internal/picker/menucfg"
 	"github.com/mateconpizza/gm/internal/sys"
 	"github.com/mateconpizza/gm/internal/ui"
 	"github.com/mateconpizza/gm/internal/ui/formatter"
 	"github.com/mateconpizza/gm/internal/ui/printer"
 	"github.com/mateconpizza/gm/internal/ui/txt"
 	"github.com/mateconpizza/gm/pkg/bookmark"
+	"github.com/mateconpizza/gm/pkg/db"
 	"github.com/mateconpizza/gm/pkg/git"
 	"github.com/mateconpizza/gm/pkg/scraper"
 )
@@ -122,9 +124,97 @@ func Edit(ctx context.Context, es editor.EditStrategy) func(context.Context, *de
 		opt := editor.WithPostEditionRunE(func(old, fresh *bookmark.Bookmark) error {
 			return gitops.Update(ctx, app, old, fresh)
 		})
+		bulkOpt := editor.WithPostBulkEditionRunE(func(olds, fresh []*bookmark.Bookmark) error {
+			return gitops.UpdateMany(ctx, app, olds, fresh)
+		})
+
+		return runEditSession(ctx, d, bs, es, opt, bulkOpt)
+	}
+}
+
+// ToggleFavorite marks the bookmarks as favorite, or unmarks them when all
+// of them already are.
+func ToggleFavorite(ctx context.Context, d *deps.Deps, bs []*bookmark.Bookmark) error {
+	fav := false
+	for _, b := range bs {
+		if !b.Favorite {
+			fav = true
+			break
+		}
+	}
+
+	err := updateMany(ctx, d, bs, func(b *bookmark.Bookmark) { b.Favorite = fav })
+	if err != nil {
+		return err
+	}
+
+	verb := "marked "
+	if !fav {
+		verb = "unmarked "
+	}
+
+	c := d.Console()
+	return c.Print(ctx, c.SuccessMesg(verb, len(bs), " bookmarks as favorite\n"))
+}
+
+// AddTags adds the tags from the --add flag to the bookmarks, asking for
+// them when the flag is empty.
+func AddTags(ctx context.Context, d *deps.Deps, bs []*bookmark.Bookmark) error {
+	app, err := d.Application(ctx)
+	if err != nil {
+		return err
+	}
+
+	c := d.Console()
+	tags := app.Flags.TagsStr
+	if strings.TrimSpace(tags) == "" {
+		tags, err = c.Prompt(ctx, fmt.Sprintf("tags to add to %d bookmarks:", len(bs)))
+		if err != nil {
+			return err
+		}
+	}
+	if strings.TrimSpace(tags) == "" {
+		return sys.ErrActionAborted
+	}
+
+	// merging keeps the union of the tags
+	add := &bookmark.Bookmark{Tags: tags}
+	err = updateMany(ctx, d, bs, func(b *bookmark.Bookmark) { b.Tags = bookmark.Merge(b, add).Tags })
+	if err != nil {
+		return err
+	}
+
+	return c.Print(ctx, c.SuccessMesg("tagged ", len(bs), " bookmarks\n"))
+}
+
+// updateMany applies fn to the bookmarks and saves them, with a single git
+// commit.
+func updateMany(ctx context.Context, d *deps.Deps, bs []*bookmark.Bookmark, fn func(*bookmark.Bookmark)) error {
+	if len(bs) == 0 {
+		return bookmark.ErrBookmarkNotFound
+	}
 
-		return runEditSession(ctx, d, bs, es, opt)
+	app, err := d.Application(ctx)
+	if err != nil {
+		return err
+	}
+
+	r, err := d.Repository()
+	if err != nil {
+		return err
+	}
+
+	olds := make([]*bookmark.Bookmark, 0, len(bs))
+	for _, b := range bs {
+		olds = append(olds, b.Copy())
+		fn(b)
 	}
+
+	if err := r.UpdateMany(ctx, bs); err != nil {
+		return err
+	}
+
+	return gitops.UpdateMany(ctx, app, olds, bs)
 }
 
 func Yank(ctx context.Context, d *deps.Deps, bs []*bookmark.Bookmark) error {
@@ -161,6 +251,47 @@ func Yank(ctx context.Context, d *deps.Deps, bs []*bookmark.Bookmark) error {
 	)
 }
 
+// RunAction runs a user defined action on the bookmarks.
+func RunAction(a *menucfg.Action) func(context.Context, *deps.Deps, []*bookmark.Bookmark) error {
+	return func(ctx context.Context, d *deps.Deps, bs []*bookmark.Bookmark) error {
+		if len(bs) == 0 {
+			return bookmark.ErrBookmarkNotFound
+		}
+
+		app, err := d.Application(ctx)
+		if err != nil {
+			return err
+		}
+
+		// A locked private bookmark only holds its sealed URL.
+		r, err := d.Repository()
+		if err != nil {
+			return err
+		}
+		for _, b := range bs {
+			if b.Private && !r.PrivateUnlocked() {
+				return fmt.Errorf("%w: bookmark id %d", db.ErrPrivateLocked, b.ID)
+			}
+		}
+
+		c := d.Console()
+		p := c.Palette()
+		cmds := a.Commands(bs)
+		msg := fmt.Sprintf("%s %q on %d bookmarks", p.BrightGreen.Wrap("run", p.Bold), a.Name, len(bs))
+		if err := c.ConfirmLimit(ctx, len(cmds), 10, msg, app.Flags.Force); err != nil {
+			return err
+		}
+
+		for _, args := range cmds {
+			if err := sys.RunCmd(ctx, args[0], args[1:]...); err != nil {
+				return fmt.Errorf("action %q: %w", a.Name, err)
+			}
+		}
+
+		return nil
+	}
+}
+
 // HTTPStatusCheck refreshes and persists bookmark HTTP status information.
 func HTTPStatusCheck(ctx context.Context, d *deps.Deps, bs []*bookmark.Bookmark) error {
 	if len(bs) == 0 {
@@ -475,11 +606,7 @@ func saveStatusUpdates(ctx context.Context, d *deps.Deps, bs []*bookmark.Bookmar
 	}
 
 	if app.GitEnabled() {
-		err := m.SaveChanges(
-			ctx,
-			gr,
-			fmt.Sprintf("[%s] http status updated", gr.Name()),
-		)
+		err := gitops.SaveChanges(ctx, app, m, gr, r, fmt.Sprintf("[%s] http status updated", gr.Name()))
 
 		if err != nil && !errors.Is(err, git.ErrGitUpToDate) {
 			return err
@@ -0,0 +1,263 @@
+package handler
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	menu "github.com/mateconpizza/go-fzf"
+
+	"github.com/mateconpizza/gm/internal/application"
+	"github.com/mateconpizza/gm/internal/deps"
+	"github.com/mateconpizza/gm/internal/picker"
+	"github.com/mateconpizza/gm/internal/sys"
+	"github.com/mateconpizza/gm/internal/ui/printer"
+	"github.com/mateconpizza/gm/pkg/ansi"
+	"github.com/mateconpizza/gm/pkg/bookmark"
+	"github.com/mateconpizza/gm/pkg/db"
+)
+
+// TagFacet is a tag and the number of bookmarks tagged with it.
+type TagFacet struct {
+	Tag   string
+	Count int
+}
+
+// TagFacets returns the bookmarks tagged with all the selected tags, and the
+// other tags of those bookmarks, most used first.
+func TagFacets(bs []*bookmark.Bookmark, selected []string) ([]*bookmark.Bookmark, []TagFacet) {
+	counts := make(map[string]int)
+	narrowed := make([]*bookmark.Bookmark, 0, len(bs))
+	for _, b := range bs {
+		tags := strings.FieldsFunc(b.Tags, func(r rune) bool { return r == ',' })
+		if !hasAllTags(tags, selected) {
+			continue
+		}
+
+		narrowed = append(narrowed, b)
+		for _, t := range tags {
+			if !slices.Contains(selected, t) {
+				counts[t]++
+			}
+		}
+	}
+
+	return narrowed, sortFacets(counts)
+}
+
+// sortFacets sorts the tags by count, then by name.
+func sortFacets(counts map[string]int) []TagFacet {
+	facets := make([]TagFacet, 0, len(counts))
+	for t, n := range counts {
+		if n > 0 {
+			facets = append(facets, TagFacet{Tag: t, Count: n})
+		}
+	}
+
+	slices.SortFunc(facets, func(a, b TagFacet) int {
+		if c := cmp.Compare(b.Count, a.Count); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Tag, b.Tag)
+	})
+
+	return facets
+}
+
+func hasAllTags(tags, selected []string) bool {
+	for _, t := range selected {
+		if !slices.Contains(tags, t) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Browse navigates the bookmarks by tags. The tags menu narrows the
+// bookmarks to the ones tagged with all the picked tags, then lists them.
+// Leaving the list goes back to the tags of the narrowed bookmarks, to
+// drill down, and leaving the tags goes back up one level.
+func Browse(args []string) func(context.Context, *deps.Deps, []*bookmark.Bookmark) error {
+	return func(ctx context.Context, d *deps.Deps, bs []*bookmark.Bookmark) error {
+		app, err := d.Application(ctx)
+		if err != nil {
+			return err
+		}
+		// the list is the main menu, with its keymaps
+		app.Flags.Menu = true
+
+		if app.Menu.IsTUI() {
+			// the TUI browses the tags in its sidebar
+			return browseList(ctx, d, app, args, bs)
+		}
+
+		baseTags := app.Flags.Tags
+		counts, err := topLevelCounts(ctx, d, app, args, bs)
+		if err != nil {
+			return err
+		}
+
+		var levels [][]string // tags picked on each level
+		list := false
+		for {
+			selected := slices.Concat(levels...)
+			narrowed, facets := TagFacets(bs, selected)
+			if len(levels) == 0 && counts != nil {
+				facets = sortFacets(counts)
+			}
+
+			if list {
+				app.Flags.Tags = slices.Concat(baseTags, selected)
+				err := browseList(ctx, d, app, args, narrowed)
+				if !errors.Is(err, sys.ErrActionAborted) {
+					return err
+				}
+				list = false // back to the tags of the list
+
+				continue
+			}
+
+			if len(facets) == 0 {
+				if len(levels) == 0 {
+					return ErrNoItems
+				}
+				levels = levels[:len(levels)-1]
+
+				continue
+			}
+
+			picked, err := selectTags(app, selected, facets)
+			if errors.Is(err, sys.ErrActionAborted) {
+				if len(levels) == 0 {
+					return nil
+				}
+				levels = levels[:len(levels)-1]
+
+				continue
+			}
+			if err != nil {
+				return err
+			}
+
+			next, ok := descend(bs, levels, picked)
+			if !ok {
+				d.Console().Warning("no bookmarks tagged with " + strings.Join(picked, ", ") + " together\n").Flush()
+				continue
+			}
+
+			levels = next
+			list = true
+		}
+	}
+}
+
+// descend appends the picked tags as a new level, unless no bookmark is
+// tagged with all of them; the tags menu picks many tags at once, which may
+// never be used together.
+func descend(bs []*bookmark.Bookmark, levels [][]string, picked []string) ([][]string, bool) {
+	next := append(slices.Clone(levels), picked)
+	if narrowed, _ := TagFacets(bs, slices.Concat(next...)); len(narrowed) == 0 {
+		return levels, false
+	}
+
+	return next, true
+}
+
+// topLevelCounts returns the counts of the tags of the whole database, nil
+// when the bookmarks were narrowed by a query or filter.
+func topLevelCounts(ctx context.Context, d *deps.Deps, app *application.App, args []string, bs []*bookmark.Bookmark) (map[string]int, error) {
+	f := app.Flags
+	if len(args) > 0 || len(f.Tags) > 0 || f.By != "" || f.Head > 0 || f.Tail > 0 {
+		return nil, nil
+	}
+
+	r, err := d.Repository()
+	if err != nil {
+		return nil, err
+	}
+
+	// private bookmarks may be hidden
+	if r.Count(ctx, db.TableBookmarks) != len(bs) {
+		return nil, nil
+	}
+
+	return r.TagsCounter(ctx)
+}
+
+// browseList lists the bookmarks in the main menu and prints the selected
+// ones.
+func browseList(ctx context.Context, d *deps.Deps, app *application.App, args []string, bs []*bookmark.Bookmark) error {
+	m := picker.NewBrowseMenu(app, args)
+	bs, err := picker.BookmarkWithMenu(m, bs)
+	if err != nil {
+		return err
+	}
+
+	c := d.Console()
+	if app.Flags.Output == application.OutputFormat {
+		return printer.Records(ctx, c, bs)
+	}
+
+	return printer.Display(ctx, c, app.Flags.Output, bs)
+}
+
+// selectTags shows the tags menu, with the counts of the bookmarks tagged
+// with the selected tags.
+func selectTags(app *application.App, selected []string, facets []TagFacet) ([]string, error) {
+	var width int
+	for _, f := range facets {
+		width = max(width, utf8.RuneCountInString(f.Tag))
+	}
+
+	header := "select tag/s"
+	if len(selected) > 0 {
+		header = "tags: " + strings.Join(selected, " › ")
+	}
+
+	previewTags := make([]string, 0, len(selected)+1)
+	for _, t := range slices.Concat(app.Flags.Tags, selected) {
+		previewTags = append(previewTags, "--tag="+t)
+	}
+	previewTags = append(previewTags, "--tag={1}")
+
+	previewWindow := "right,60%"
+	if !app.Menu.Preview {
+		previewWindow = "hidden," + previewWindow
+	}
+
+	m := picker.New[TagFacet](
+		app,
+		menu.WithMultiSelection(),
+		menu.WithHeader(header),
+		menu.WithHeaderLabel(" browse "),
+		menu.WithFooter("esc back"),
+		menu.WithPreviewCmd(fmt.Sprintf("%s --db=%s --color=always -o oneline %s",
+			app.Command(), app.DBBaseName(), strings.Join(previewTags, " "))),
+		menu.WithPreviewWindow(previewWindow),
+		menu.WithKeybinds(menu.KeymapToggleAll(), menu.KeymapTogglePreview()),
+		menu.WithHeaderKeymaps(),
+	)
+	m.SetFormatter(func(f TagFacet) string {
+		return fmt.Sprintf("%-*s %s", width, f.Tag, ansi.Gray.Sprint(f.Count))
+	})
+
+	picked, err := m.Select(facets)
+	if err != nil {
+		if errors.Is(err, menu.ErrActionAborted) {
+			return nil, sys.ErrActionAborted
+		}
+		return nil, err
+	}
+
+	tags := make([]string, 0, len(picked))
+	for _, f := range picked {
+		tags = append(tags, f.Tag)
+	}
+
+	return tags, nil
+}
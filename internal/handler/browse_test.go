@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/mateconpizza/gm/pkg/bookmark"
+)
+
+func TestTagFacets(t *testing.T) {
+	t.Parallel()
+
+	bs := []*bookmark.Bookmark{
+		{ID: 1, Tags: "go,lang,"},
+		{ID: 2, Tags: "go,tools,"},
+		{ID: 3, Tags: "go,lang,tools,"},
+		{ID: 4, Tags: "misc,"},
+	}
+
+	tests := []struct {
+		name     string
+		selected []string
+		wantIDs  []int
+		want     []TagFacet
+	}{
+		{
+			name:    "top_level",
+			wantIDs: []int{1, 2, 3, 4},
+			want: []TagFacet{
+				{Tag: "go", Count: 3},
+				{Tag: "lang", Count: 2},
+				{Tag: "tools", Count: 2},
+				{Tag: "misc", Count: 1},
+			},
+		},
+		{
+			name:     "single_tag",
+			selected: []string{"go"},
+			wantIDs:  []int{1, 2, 3},
+			want:     []TagFacet{{Tag: "lang", Count: 2}, {Tag: "tools", Count: 2}},
+		},
+		{
+			name:     "intersection",
+			selected: []string{"go", "lang", "tools"},
+			wantIDs:  []int{3},
+			want:     []TagFacet{},
+		},
+		{
+			name:     "no_match",
+			selected: []string{"misc", "go"},
+			wantIDs:  []int{},
+			want:     []TagFacet{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			narrowed, got := TagFacets(bs, tt.selected)
+
+			ids := make([]int, 0, len(narrowed))
+			for _, b := range narrowed {
+				ids = append(ids, b.ID)
+			}
+			if !slices.Equal(ids, tt.wantIDs) {
+				t.Errorf("TagFacets() bookmarks = %v, want %v", ids, tt.wantIDs)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("TagFacets() facets = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDescend(t *testing.T) {
+	t.Parallel()
+
+	bs := []*bookmark.Bookmark{
+		{ID: 1, Tags: "go,lang,"},
+		{ID: 2, Tags: "misc,"},
+	}
+	levels := [][]string{{"go"}}
+
+	got, ok := descend(bs, levels, []string{"lang"})
+	if !ok || len(got) != 2 {
+		t.Fatalf("descend() = %v, %v, want a new level", got, ok)
+	}
+
+	// misc and lang are never used together, the level stays
+	got, ok = descend(bs, [][]string{}, []string{"misc", "lang"})
+	if ok || len(got) != 0 {
+		t.Fatalf("descend() = %v, %v, want no new level", got, ok)
+	}
+
+	got, ok = descend(bs, levels, []string{"misc"})
+	if ok || len(got) != 1 {
+		t.Fatalf("descend() = %v, %v, want the current level", got, ok)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"slices"
 	"sort"
 	"strings"
 
@@ -15,7 +16,7 @@ import (
 	"github.com/mateconpizza/gm/pkg/db"
 )
 
-var SortSupported = []string{"favorite", "newest", "visited", "popular", "reverse"}
+var SortSupported = []string{"favorite", "newest", "visited", "popular", "reverse", "<field>[:asc|:desc]"}
 
 var (
 	ErrInvalidOption = errors.New("invalid option")
@@ -124,7 +125,7 @@ func getByQuery(ctx context.Context, d *deps.Deps, args []string) ([]*bookmark.B
 	return bs, nil
 }
 
-// applyFilters applies tag and head/tail filters to the bookmark list.
+// applyFilters applies tag and contributor filters to the bookmark list.
 func applyFilters(ctx context.Context, d *deps.Deps, bs []*bookmark.Bookmark) ([]*bookmark.Bookmark, error) {
 	app, err := d.Application(ctx)
 	if err != nil {
@@ -140,9 +141,29 @@ func applyFilters(ctx context.Context, d *deps.Deps, bs []*bookmark.Bookmark) ([
 		}
 	}
 
+	// Filter by contributor
+	if f.By != "" {
+		bs = filterByAuthor(bs, f.By)
+		if len(bs) == 0 {
+			return nil, fmt.Errorf("%w by contributor: %q", db.ErrRecordNoMatch, f.By)
+		}
+	}
+
 	return bs, nil
 }
 
+// filterByAuthor returns the bookmarks added by the given teammate.
+func filterByAuthor(bs []*bookmark.Bookmark, author string) []*bookmark.Bookmark {
+	result := make([]*bookmark.Bookmark, 0, len(bs))
+	for _, b := range bs {
+		if strings.EqualFold(b.AddedBy, author) {
+			result = append(result, b)
+		}
+	}
+
+	return result
+}
+
 // filterByTags returns a slice of bookmarks that contain ALL of the provided tags.
 func filterByTags(ctx context.Context, d *deps.Deps, tags []string, bs []*bookmark.Bookmark) ([]*bookmark.Bookmark, error) {
 	n := len(bs)
@@ -293,8 +314,40 @@ func Sort(s string, bs []*bookmark.Bookmark) ([]*bookmark.Bookmark, error) {
 			return bs[i].VisitCount > bs[j].VisitCount
 		})
 	default:
+		return sortByField(s, bs)
+	}
+	return bs, nil
+}
+
+// sortByField sorts the bookmarks by a field, ascending unless the field is
+// followed by ":desc".
+//
+//	title, visits:desc, created_at:asc
+func sortByField(s string, bs []*bookmark.Bookmark) ([]*bookmark.Bookmark, error) {
+	name, order, _ := strings.Cut(s, ":")
+	key, err := bookmark.FieldKey(name)
+	if err != nil || (order != "" && order != "asc" && order != "desc") {
 		return nil, fmt.Errorf("%w %q: valid options: %s", ErrInvalidOption, s, strings.Join(SortSupported, ", "))
 	}
+
+	slices.SortStableFunc(bs, func(a, b *bookmark.Bookmark) int {
+		// encrypted values are not sorted, locked bookmarks go last
+		if db.PrivateField(key) {
+			switch la, lb := db.Locked(a), db.Locked(b); {
+			case la && lb:
+				return 0
+			case la:
+				return 1
+			case lb:
+				return -1
+			}
+		}
+		if order == "desc" {
+			return bookmark.CompareField(b, a, key)
+		}
+		return bookmark.CompareField(a, b, key)
+	})
+
 	return bs, nil
 }
 
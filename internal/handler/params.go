@@ -197,7 +197,7 @@ func paramsCleaner(u *url.URL) string {
 
 // selectParams presents a multi-select menu to choose which query params to
 // remove.
-func selectParams(m *menu.Menu[string], u *url.URL) ([]string, error) {
+func selectParams(m *picker.Menu[string], u *url.URL) ([]string, error) {
 	query := u.Query()
 	sep := "="
 	items := make([]string, 0, len(query))
@@ -227,7 +227,7 @@ func selectParams(m *menu.Menu[string], u *url.URL) ([]string, error) {
 
 // processBookmarkParams prompts for param removal and persists updates if
 // confirmed.
-func processBookmarkParams(ctx context.Context, app *application.App, c *ui.Console, m *menu.Menu[string], urlStr string) (string, error) {
+func processBookmarkParams(ctx context.Context, app *application.App, c *ui.Console, m *picker.Menu[string], urlStr string) (string, error) {
 	u, err := url.Parse(urlStr)
 	if err != nil {
 		return "", err
@@ -291,7 +291,7 @@ func promptParamRemoval(ctx context.Context, app *application.App, c *ui.Console
 
 // computeNewURL returns a new URL based on the selected option or reports
 // skip/invalid choice.
-func computeNewURL(m *menu.Menu[string], u *url.URL, opt string) (newURL string, skipped bool, err error) {
+func computeNewURL(m *picker.Menu[string], u *url.URL, opt string) (newURL string, skipped bool, err error) {
 	switch strings.ToLower(opt) {
 	case "n", "no":
 		return "", true, nil
@@ -334,6 +334,9 @@ func persistBookmarkUpdate(ctx context.Context, d *deps.Deps, b *bookmark.Bookma
 	if err != nil {
 		return err
 	}
+	if err := r.CheckMatchable(ctx); err != nil {
+		return err
+	}
 	// TODO: use bookmark.Deduplicate or port.DeduplicateReport
 	if book, has := r.Has(ctx, newB.URL); has {
 		f.Error(id(newB.ID) + p.BrightRed.Wrap("already", p.Italic) + " exists with " + id(book.ID)).
@@ -130,17 +130,26 @@ func parseNewBookmark(ctx context.Context, d *deps.Deps, b *bookmark.Bookmark, a
 
 	title := app.Flags.Title
 	tags := app.Flags.TagsStr
+	b.Private = app.Flags.Private
+	if app.Git.Team {
+		b.AddedBy = gitops.Author(ctx, app)
+	}
 
-	c := d.Console()
-	newURL, err := newURLFromArgs(ctx, c, args)
+	r, err := d.Repository()
 	if err != nil {
 		return err
 	}
+	// a locked private bookmark would not be found, and duplicated
+	if err := r.CheckMatchable(ctx); err != nil {
+		return err
+	}
 
-	r, err := d.Repository()
+	c := d.Console()
+	newURL, err := newURLFromArgs(ctx, c, args)
 	if err != nil {
 		return err
 	}
+
 	if b, exists := r.Has(ctx, newURL); exists {
 		return fmt.Errorf("%w with id=%d", bookmark.ErrBookmarkDuplicate, b.ID)
 	}
@@ -0,0 +1,36 @@
+package handler
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mateconpizza/gm/internal/testutil"
+	"github.com/mateconpizza/gm/pkg/bookmark"
+	"github.com/mateconpizza/gm/pkg/db"
+)
+
+func TestParseNewBookmark_LockedPrivate(t *testing.T) {
+	t.Parallel()
+
+	d := testutil.SetupDeps(t)
+	app, err := d.Application(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := testutil.SetupInitializedDBWithBookmarks(t, app.Path.Database, 1)
+	t.Cleanup(r.Close)
+	d.SetRepo(r)
+
+	if _, err := r.DB.ExecContext(t.Context(), "UPDATE bookmarks SET private = 1"); err != nil {
+		t.Fatal(err)
+	}
+
+	// no cipher is registered, the existing URL cannot be found and would be
+	// added again in plaintext
+	url := testutil.BookmarkSlice(1)[0].URL
+	err = parseNewBookmark(t.Context(), d, &bookmark.Bookmark{}, []string{url})
+	if !errors.Is(err, db.ErrPrivateLocked) {
+		t.Fatalf("expected ErrPrivateLocked, got %v", err)
+	}
+}
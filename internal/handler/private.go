@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mateconpizza/gm/internal/dbops"
+	"github.com/mateconpizza/gm/internal/deps"
+	"github.com/mateconpizza/gm/internal/gitops"
+	"github.com/mateconpizza/gm/pkg/bookmark"
+)
+
+// SetPrivate returns an action that marks bookmarks as private, or public
+// again when private is false.
+//
+// Private bookmarks have their URL, description and notes encrypted, and are
+// removed from the git repo. Their past versions stay in the git history in
+// plaintext, which is only warned about.
+func SetPrivate(private bool) func(context.Context, *deps.Deps, []*bookmark.Bookmark) error {
+	return func(ctx context.Context, d *deps.Deps, bs []*bookmark.Bookmark) error {
+		r, err := d.Repository()
+		if err != nil {
+			return err
+		}
+
+		app, err := d.Application(ctx)
+		if err != nil {
+			return err
+		}
+
+		c := d.Console()
+		if err := dbops.EnsurePrivate(ctx, app, r, c); err != nil {
+			return err
+		}
+
+		if private && gitops.IsTracked(app) {
+			var public int
+			for _, b := range bs {
+				if !b.Private {
+					public++
+				}
+			}
+			if public > 0 {
+				c.Warning(fmt.Sprintf("the git history keeps the plaintext of %d bookmark/s, "+
+					"only a history rewrite removes it\n", public)).Flush()
+			}
+		}
+
+		var n int
+		for _, b := range bs {
+			if b.Private == private {
+				continue
+			}
+
+			old := b.Copy()
+			b.Private = private
+			if err := r.UpdateOne(ctx, b); err != nil {
+				return fmt.Errorf("updating record: %w", err)
+			}
+			if err := gitops.Update(ctx, app, old, b); err != nil {
+				return err
+			}
+			n++
+		}
+
+		s := "public"
+		if private {
+			s = "private"
+		}
+
+		return c.Print(ctx, c.SuccessMesg(fmt.Sprintf("%d bookmark/s marked as %s\n", n, s)))
+	}
+}
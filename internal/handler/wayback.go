@@ -173,7 +173,7 @@ func printSummary(c *ui.Console, results <-chan SnapshotResult) error {
 	return nil
 }
 
-func waybackMenu[T wayback.SnapshotInfo](c *ui.Console, app *application.App, opts ...menu.Option) *menu.Menu[wayback.SnapshotInfo] {
+func waybackMenu[T wayback.SnapshotInfo](c *ui.Console, app *application.App, opts ...menu.Option) *picker.Menu[wayback.SnapshotInfo] {
 	p := c.Palette()
 	opts = append(
 		opts,
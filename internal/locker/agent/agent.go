@@ -0,0 +1,288 @@
+// Package agent implements a small unlock agent that keeps derived database
+// keys in memory, so the passphrase is typed once per session.
+//
+// The agent listens on a Unix socket readable only by the owner. Each
+// connection carries a single JSON request and response.
+package agent
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mateconpizza/gm/internal/locker"
+)
+
+// DefaultTTL is how long a cached key lives when no TTL is configured.
+const DefaultTTL = 15 * time.Minute
+
+const (
+	opGet    = "get"
+	opPut    = "put"
+	opForget = "forget"
+	opStatus = "status"
+	opStop   = "stop"
+)
+
+var (
+	ErrNotRunning     = errors.New("agent is not running")
+	ErrAlreadyRunning = errors.New("agent is already running")
+	ErrKeyNotFound    = errors.New("key not found in agent")
+	ErrInvalidRequest = errors.New("invalid agent request")
+)
+
+// wireKey is the JSON representation of a derived key.
+type wireKey struct {
+	KDF    locker.KDF       `json:"kdf"`
+	Salt   []byte           `json:"salt"`
+	Params locker.KDFParams `json:"params"`
+	Secret []byte           `json:"secret"`
+}
+
+func toWire(k *locker.Key) *wireKey {
+	return &wireKey{KDF: k.KDF, Salt: k.Salt, Params: k.Params, Secret: k.Secret()}
+}
+
+func (w *wireKey) key() *locker.Key {
+	return locker.NewKeyFromSecret(w.KDF, w.Salt, w.Params, w.Secret)
+}
+
+type request struct {
+	Op  string        `json:"op"`
+	DB  string        `json:"db,omitempty"`
+	Key *wireKey      `json:"key,omitempty"`
+	TTL time.Duration `json:"ttl,omitempty"`
+}
+
+type response struct {
+	Error   string   `json:"error,omitempty"`
+	Key     *wireKey `json:"key,omitempty"`
+	Entries []Entry  `json:"entries,omitempty"`
+}
+
+// Entry describes a cached key, without the secret.
+type Entry struct {
+	DB      string    `json:"db"`
+	Expires time.Time `json:"expires"`
+}
+
+type cached struct {
+	key     *wireKey
+	expires time.Time
+}
+
+// Server caches derived keys per database path.
+type Server struct {
+	socket string
+	ttl    time.Duration
+
+	mu       sync.Mutex
+	keys     map[string]*cached
+	lastSeen time.Time
+
+	ln       net.Listener
+	stopOnce sync.Once
+	done     chan struct{}
+}
+
+// NewServer returns a server listening on socket once started. Keys expire
+// after ttl, and the server exits once it has been empty and idle for ttl.
+func NewServer(socket string, ttl time.Duration) *Server {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+
+	return &Server{
+		socket: socket,
+		ttl:    ttl,
+		keys:   make(map[string]*cached),
+		done:   make(chan struct{}),
+	}
+}
+
+// Listen creates the socket. A stale socket left by a dead agent is removed.
+func (s *Server) Listen() error {
+	if NewClient(s.socket).Running() {
+		return fmt.Errorf("%w: %q", ErrAlreadyRunning, s.socket)
+	}
+
+	_ = os.Remove(s.socket)
+
+	ln, err := net.Listen("unix", s.socket)
+	if err != nil {
+		return fmt.Errorf("agent listen: %w", err)
+	}
+
+	if err := os.Chmod(s.socket, 0o600); err != nil {
+		_ = ln.Close()
+		return fmt.Errorf("agent socket permissions: %w", err)
+	}
+
+	s.ln = ln
+	s.lastSeen = time.Now()
+
+	return nil
+}
+
+// Serve accepts connections until Stop is called or the agent goes idle.
+func (s *Server) Serve() error {
+	if s.ln == nil {
+		if err := s.Listen(); err != nil {
+			return err
+		}
+	}
+	defer os.Remove(s.socket)
+
+	go s.janitor()
+
+	slog.Debug("agent: serving", "socket", s.socket, "ttl", s.ttl)
+
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			select {
+			case <-s.done:
+				return nil
+			default:
+				return fmt.Errorf("agent accept: %w", err)
+			}
+		}
+
+		go s.handle(conn)
+	}
+}
+
+// Stop closes the listener and wipes all cached keys.
+func (s *Server) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.done)
+
+		s.mu.Lock()
+		for db, c := range s.keys {
+			clear(c.key.Secret)
+			delete(s.keys, db)
+		}
+		s.mu.Unlock()
+
+		if s.ln != nil {
+			_ = s.ln.Close()
+		}
+	})
+}
+
+func (s *Server) handle(conn net.Conn) {
+	defer conn.Close()
+
+	_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	var req request
+	if err := json.NewDecoder(conn).Decode(&req); err != nil {
+		slog.Debug("agent: decoding request", "error", err)
+		return
+	}
+
+	resp := s.dispatch(&req)
+	if err := json.NewEncoder(conn).Encode(resp); err != nil {
+		slog.Debug("agent: encoding response", "error", err)
+	}
+
+	if req.Op == opStop {
+		s.Stop()
+	}
+}
+
+func (s *Server) dispatch(req *request) *response {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastSeen = time.Now()
+	s.evictExpired()
+
+	switch req.Op {
+	case opGet:
+		c, ok := s.keys[req.DB]
+		if !ok {
+			return &response{Error: ErrKeyNotFound.Error()}
+		}
+		k := *c.key
+		k.Secret = slices.Clone(c.key.Secret)
+		return &response{Key: &k}
+
+	case opPut:
+		if req.DB == "" || req.Key == nil || len(req.Key.Secret) == 0 {
+			return &response{Error: ErrInvalidRequest.Error()}
+		}
+		ttl := req.TTL
+		if ttl <= 0 || ttl > s.ttl {
+			ttl = s.ttl
+		}
+		s.keys[req.DB] = &cached{key: req.Key, expires: time.Now().Add(ttl)}
+		slog.Debug("agent: key cached", "db", req.DB, "ttl", ttl)
+		return &response{}
+
+	case opForget:
+		if c, ok := s.keys[req.DB]; ok {
+			clear(c.key.Secret)
+			delete(s.keys, req.DB)
+		}
+		return &response{}
+
+	case opStatus:
+		entries := make([]Entry, 0, len(s.keys))
+		for db, c := range s.keys {
+			entries = append(entries, Entry{DB: db, Expires: c.expires})
+		}
+		sort.Slice(entries, func(i, j int) bool { return entries[i].DB < entries[j].DB })
+		return &response{Entries: entries}
+
+	case opStop:
+		return &response{}
+	}
+
+	return &response{Error: fmt.Sprintf("%s: %q", ErrInvalidRequest, req.Op)}
+}
+
+// evictExpired drops expired keys. Callers must hold s.mu.
+func (s *Server) evictExpired() {
+	now := time.Now()
+	for db, c := range s.keys {
+		if now.After(c.expires) {
+			clear(c.key.Secret)
+			delete(s.keys, db)
+			slog.Debug("agent: key expired", "db", db)
+		}
+	}
+}
+
+// janitor evicts expired keys and stops the server once it is idle.
+func (s *Server) janitor() {
+	interval := min(s.ttl, time.Minute)
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-t.C:
+		}
+
+		s.mu.Lock()
+		s.evictExpired()
+		idle := len(s.keys) == 0 && time.Since(s.lastSeen) >= s.ttl
+		s.mu.Unlock()
+
+		if idle {
+			slog.Debug("agent: idle, shutting down")
+			s.Stop()
+			return
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package agent
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mateconpizza/gm/internal/locker"
+)
+
+func startServer(t *testing.T, ttl time.Duration) (*Server, *Client) {
+	t.Helper()
+
+	sock := filepath.Join(t.TempDir(), "agent.sock")
+	s := NewServer(sock, ttl)
+	if err := s.Listen(); err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- s.Serve() }()
+
+	t.Cleanup(func() {
+		s.Stop()
+		if err := <-errCh; err != nil {
+			t.Errorf("serve: %v", err)
+		}
+	})
+
+	return s, NewClient(sock)
+}
+
+func TestAgentPutGetForget(t *testing.T) {
+	t.Parallel()
+
+	_, c := startServer(t, time.Minute)
+
+	k, err := locker.NewKey("pass")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.Get("/tmp/work.db"); !errors.Is(err, ErrKeyNotFound) {
+		t.Fatalf("expected ErrKeyNotFound, got %v", err)
+	}
+
+	if err := c.Put("/tmp/work.db", k, 0); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+
+	got, err := c.Get("/tmp/work.db")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if !bytes.Equal(got.Secret(), k.Secret()) || !bytes.Equal(got.Salt, k.Salt) || got.Params != k.Params {
+		t.Error("cached key does not match")
+	}
+
+	entries, err := c.Status()
+	if err != nil {
+		t.Fatalf("status: %v", err)
+	}
+	if len(entries) != 1 || entries[0].DB != "/tmp/work.db" {
+		t.Errorf("unexpected entries: %+v", entries)
+	}
+
+	if err := c.Forget("/tmp/work.db"); err != nil {
+		t.Fatalf("forget: %v", err)
+	}
+	if _, err := c.Get("/tmp/work.db"); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("expected ErrKeyNotFound after forget, got %v", err)
+	}
+}
+
+func TestAgentKeyExpires(t *testing.T) {
+	t.Parallel()
+
+	_, c := startServer(t, time.Minute)
+
+	k, err := locker.NewKey("pass")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.Put("work", k, 10*time.Millisecond); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+
+	if _, err := c.Get("work"); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("expected expired key, got %v", err)
+	}
+}
+
+func TestAgentStop(t *testing.T) {
+	t.Parallel()
+
+	sock := filepath.Join(t.TempDir(), "agent.sock")
+	c := NewClient(sock)
+
+	if c.Running() {
+		t.Fatal("agent should not be running")
+	}
+
+	s := NewServer(sock, time.Minute)
+	if err := s.Listen(); err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- s.Serve() }()
+
+	if err := NewServer(sock, time.Minute).Listen(); !errors.Is(err, ErrAlreadyRunning) {
+		t.Errorf("expected ErrAlreadyRunning, got %v", err)
+	}
+
+	if err := c.Stop(); err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+
+	if err := <-errCh; err != nil {
+		t.Fatalf("serve: %v", err)
+	}
+
+	if c.Running() {
+		t.Error("agent should have stopped")
+	}
+}
@@ -0,0 +1,96 @@
+package agent
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/mateconpizza/gm/internal/locker"
+)
+
+const dialTimeout = time.Second
+
+// Client talks to a running agent.
+type Client struct {
+	socket string
+}
+
+func NewClient(socket string) *Client { return &Client{socket: socket} }
+
+// Running reports whether an agent answers on the socket.
+func (c *Client) Running() bool {
+	_, err := c.Status()
+	return err == nil
+}
+
+// Get returns the cached key for the database.
+func (c *Client) Get(db string) (*locker.Key, error) {
+	resp, err := c.do(&request{Op: opGet, DB: db})
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Key == nil {
+		return nil, ErrKeyNotFound
+	}
+
+	return resp.Key.key(), nil
+}
+
+// Put caches the key for the database. A zero ttl uses the agent default.
+func (c *Client) Put(db string, k *locker.Key, ttl time.Duration) error {
+	_, err := c.do(&request{Op: opPut, DB: db, Key: toWire(k), TTL: ttl})
+	return err
+}
+
+// Forget drops the cached key for the database.
+func (c *Client) Forget(db string) error {
+	_, err := c.do(&request{Op: opForget, DB: db})
+	return err
+}
+
+// Status lists the cached keys.
+func (c *Client) Status() ([]Entry, error) {
+	resp, err := c.do(&request{Op: opStatus})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Entries, nil
+}
+
+// Stop asks the agent to wipe its keys and exit.
+func (c *Client) Stop() error {
+	_, err := c.do(&request{Op: opStop})
+	return err
+}
+
+func (c *Client) do(req *request) (*response, error) {
+	conn, err := net.DialTimeout("unix", c.socket, dialTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrNotRunning, err)
+	}
+	defer conn.Close()
+
+	_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return nil, fmt.Errorf("agent request: %w", err)
+	}
+
+	var resp response
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("agent response: %w", err)
+	}
+
+	if resp.Error != "" {
+		if resp.Error == ErrKeyNotFound.Error() {
+			return nil, ErrKeyNotFound
+		}
+		return nil, errors.New(resp.Error)
+	}
+
+	return &resp, nil
+}
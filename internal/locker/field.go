@@ -0,0 +1,58 @@
+package locker
+
+import (
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+	"io"
+)
+
+// fieldAD binds sealed fields to their purpose, so a field can't be swapped
+// with a locked file payload.
+var fieldAD = []byte("gomarks-field-v1")
+
+// FieldCipher seals short values, such as bookmark fields, with AES-GCM.
+//
+// Output layout is nonce||ciphertext.
+type FieldCipher struct {
+	gcm cipher.AEAD
+}
+
+// NewFieldCipher returns a cipher for the given derived key.
+func NewFieldCipher(k *Key) (*FieldCipher, error) {
+	if k == nil || len(k.Secret()) != keySize {
+		return nil, ErrKeyMismatch
+	}
+
+	gcm, err := newGCM(k.Secret())
+	if err != nil {
+		return nil, err
+	}
+
+	return &FieldCipher{gcm: gcm}, nil
+}
+
+// Seal encrypts plaintext with a random nonce.
+func (f *FieldCipher) Seal(plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, f.gcm.NonceSize(), f.gcm.NonceSize()+len(plaintext)+f.gcm.Overhead())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("nonce generation failed: %w", err)
+	}
+
+	return f.gcm.Seal(nonce, nonce, plaintext, fieldAD), nil
+}
+
+// Open decrypts a value produced by Seal.
+func (f *FieldCipher) Open(ciphertext []byte) ([]byte, error) {
+	n := f.gcm.NonceSize()
+	if len(ciphertext) < n {
+		return nil, ErrCipherTextShort
+	}
+
+	plaintext, err := f.gcm.Open(nil, ciphertext[:n], ciphertext[n:], fieldAD)
+	if err != nil {
+		return nil, fmt.Errorf("decryption failed: %w", err)
+	}
+
+	return plaintext, nil
+}
@@ -70,6 +70,31 @@ func (g *GPG) Decrypt(ctx context.Context, encryptedPath string) ([]byte, error)
 	return output, nil
 }
 
+// DecryptBytes decrypts in-memory encrypted content, e.g. a file read from a
+// past git revision.
+func (g *GPG) DecryptBytes(ctx context.Context, content []byte) ([]byte, error) {
+	cmd := g.exec(
+		ctx,
+		flags.quiet,
+		flags.decrypt,
+	)
+
+	slog.Debug("gpg: executing GPG command", "args", cmd.Args)
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+
+	cmd.Stdin = bytes.NewReader(content)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("gpg decrypt failed: %w: %s", err, stderr.String())
+	}
+
+	return stdout.Bytes(), nil
+}
+
 // Encrypt encrypts data for the configured recipient and writes it to path.
 func (g *GPG) Encrypt(ctx context.Context, path string, content []byte) error {
 	if g.recipient == "" {
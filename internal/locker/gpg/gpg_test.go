@@ -333,6 +333,28 @@ func TestGPG_Decrypt_CommandFails(t *testing.T) {
 	}
 }
 
+func TestGPG_DecryptBytes(t *testing.T) {
+	t.Parallel()
+
+	g := &GPG{
+		binPath: "/usr/bin/gpg",
+		exec:    mockExecSuccess("decrypted text"),
+	}
+
+	out, err := g.DecryptBytes(t.Context(), []byte("ciphertext"))
+	if err != nil {
+		t.Fatalf("DecryptBytes failed unexpectedly: %v", err)
+	}
+	if string(bytes.TrimSpace(out)) != "decrypted text" {
+		t.Errorf("unexpected output: %q", out)
+	}
+
+	g.exec = mockExecFail("bad decrypt")
+	if _, err := g.DecryptBytes(t.Context(), []byte("ciphertext")); err == nil {
+		t.Fatal("expected error from DecryptBytes, got nil")
+	}
+}
+
 // Benchmark tests.
 func BenchmarkParseGPGOutput(b *testing.B) {
 	for b.Loop() {
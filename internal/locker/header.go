@@ -0,0 +1,282 @@
+package locker
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// Encrypted file layout (version 1):
+//
+//	magic    [5]byte  "GMENC"
+//	version  uint8
+//	kdf      uint8    (KDFArgon2id)
+//	saltLen  uint8
+//	salt     [saltLen]byte
+//	time     uint32   (argon2 iterations, big endian)
+//	memory   uint32   (argon2 memory in KiB, big endian)
+//	threads  uint8
+//	nonceLen uint8
+//	nonce    [nonceLen]byte
+//	payload  AES-GCM ciphertext, header bytes bound as additional data
+//
+// Files without the magic prefix are treated as legacy files, where the key
+// is sha256(passphrase) and the payload is nonce||ciphertext.
+
+const (
+	headerMagic   = "GMENC"
+	formatVersion = 1
+
+	keySize   = 32
+	saltSize  = 16
+	nonceSize = 12
+)
+
+var (
+	ErrHeaderInvalid      = errors.New("invalid encrypted file header")
+	ErrHeaderVersion      = errors.New("unsupported encrypted file version")
+	ErrKDFUnknown         = errors.New("unknown key derivation function")
+	ErrLegacyFormat       = errors.New("legacy encrypted file format")
+	ErrKeyMismatch        = errors.New("key does not match file parameters")
+	errHeaderShortPayload = errors.New("header truncated")
+)
+
+// KDF identifies the key derivation function used to derive the encryption
+// key.
+type KDF uint8
+
+const (
+	KDFLegacySHA256 KDF = 0 // unsalted sha256, only for reading legacy files
+	KDFArgon2id     KDF = 1
+)
+
+func (k KDF) String() string {
+	switch k {
+	case KDFLegacySHA256:
+		return "sha256"
+	case KDFArgon2id:
+		return "argon2id"
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(k))
+	}
+}
+
+// KDFParams holds the Argon2id cost parameters.
+type KDFParams struct {
+	Time    uint32 // number of passes
+	Memory  uint32 // memory in KiB
+	Threads uint8  // degree of parallelism
+}
+
+// DefaultKDFParams are the cost parameters used for newly encrypted files.
+var DefaultKDFParams = KDFParams{
+	Time:    3,
+	Memory:  64 * 1024,
+	Threads: 4,
+}
+
+// Bounds of the Argon2id parameters read from a file header, checked before
+// deriving a key so a crafted header can not panic or exhaust memory.
+const (
+	maxKDFTime    = 64
+	maxKDFMemory  = 4 * 1024 * 1024 // 4 GiB in KiB
+	maxKDFThreads = 64
+)
+
+// validate reports whether the parameters are usable by Argon2id.
+func (p KDFParams) validate() error {
+	switch {
+	case p.Time < 1 || p.Time > maxKDFTime:
+		return fmt.Errorf("%w: argon2 time %d", ErrHeaderInvalid, p.Time)
+	case p.Threads < 1 || p.Threads > maxKDFThreads:
+		return fmt.Errorf("%w: argon2 threads %d", ErrHeaderInvalid, p.Threads)
+	case p.Memory < 8*uint32(p.Threads) || p.Memory > maxKDFMemory:
+		return fmt.Errorf("%w: argon2 memory %d KiB", ErrHeaderInvalid, p.Memory)
+	}
+
+	return nil
+}
+
+// Key is a derived encryption key together with the parameters used to
+// derive it.
+type Key struct {
+	KDF    KDF
+	Salt   []byte
+	Params KDFParams
+
+	secret []byte
+}
+
+// NewKey derives a fresh key from the passphrase with a random salt.
+func NewKey(passphrase string) (*Key, error) {
+	if passphrase == "" {
+		return nil, ErrPassphraseEmpty
+	}
+
+	salt := make([]byte, saltSize)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, fmt.Errorf("salt generation failed: %w", err)
+	}
+
+	return DeriveKey(passphrase, KDFArgon2id, salt, DefaultKDFParams)
+}
+
+// DeriveKey derives a key from the passphrase with the given KDF, salt and
+// parameters.
+func DeriveKey(passphrase string, kdf KDF, salt []byte, p KDFParams) (*Key, error) {
+	slog.Debug("deriving key from passphrase", "kdf", kdf)
+
+	k := &Key{KDF: kdf, Salt: salt, Params: p}
+
+	switch kdf {
+	case KDFArgon2id:
+		if err := p.validate(); err != nil {
+			return nil, err
+		}
+		k.secret = argon2.IDKey([]byte(passphrase), salt, p.Time, p.Memory, p.Threads, keySize)
+	case KDFLegacySHA256:
+		hash := sha256.Sum256([]byte(passphrase))
+		k.secret = hash[:]
+	default:
+		return nil, fmt.Errorf("%w: %d", ErrKDFUnknown, kdf)
+	}
+
+	return k, nil
+}
+
+// NewKeyFromSecret rebuilds a key from an already derived secret, e.g. one
+// cached by an agent.
+func NewKeyFromSecret(kdf KDF, salt []byte, p KDFParams, secret []byte) *Key {
+	return &Key{KDF: kdf, Salt: salt, Params: p, secret: secret}
+}
+
+// Secret returns the raw derived key bytes.
+func (k *Key) Secret() []byte { return k.secret }
+
+// matches reports whether the key was derived with the header parameters.
+func (k *Key) matches(h *header) bool {
+	return k.KDF == h.kdf &&
+		bytes.Equal(k.Salt, h.salt) &&
+		k.Params == h.params
+}
+
+// header is the authenticated prefix of a versioned encrypted file.
+type header struct {
+	version uint8
+	kdf     KDF
+	salt    []byte
+	params  KDFParams
+	nonce   []byte
+}
+
+func newHeader(k *Key) (*header, error) {
+	nonce := make([]byte, nonceSize)
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("nonce generation failed: %w", err)
+	}
+
+	return &header{
+		version: formatVersion,
+		kdf:     k.KDF,
+		salt:    k.Salt,
+		params:  k.Params,
+		nonce:   nonce,
+	}, nil
+}
+
+// MarshalBinary encodes the header.
+func (h *header) MarshalBinary() ([]byte, error) {
+	if len(h.salt) > 255 || len(h.nonce) > 255 {
+		return nil, ErrHeaderInvalid
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(headerMagic)
+	buf.WriteByte(h.version)
+	buf.WriteByte(byte(h.kdf))
+	buf.WriteByte(byte(len(h.salt)))
+	buf.Write(h.salt)
+	_ = binary.Write(&buf, binary.BigEndian, h.params.Time)
+	_ = binary.Write(&buf, binary.BigEndian, h.params.Memory)
+	buf.WriteByte(h.params.Threads)
+	buf.WriteByte(byte(len(h.nonce)))
+	buf.Write(h.nonce)
+
+	return buf.Bytes(), nil
+}
+
+// hasHeader reports whether data starts with the versioned file magic.
+func hasHeader(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(headerMagic))
+}
+
+// parseHeader decodes the header and returns it together with the raw header
+// bytes (used as additional data) and the remaining ciphertext.
+func parseHeader(data []byte) (h *header, raw, rest []byte, err error) {
+	if !hasHeader(data) {
+		return nil, nil, nil, ErrLegacyFormat
+	}
+
+	r := bytes.NewReader(data[len(headerMagic):])
+	h = &header{}
+
+	readByte := func() uint8 {
+		if err != nil {
+			return 0
+		}
+		var b byte
+		b, err = r.ReadByte()
+		return b
+	}
+	readN := func(n int) []byte {
+		if err != nil {
+			return nil
+		}
+		b := make([]byte, n)
+		_, err = io.ReadFull(r, b)
+		return b
+	}
+	readUint32 := func() uint32 {
+		if err != nil {
+			return 0
+		}
+		var v uint32
+		err = binary.Read(r, binary.BigEndian, &v)
+		return v
+	}
+
+	h.version = readByte()
+	h.kdf = KDF(readByte())
+	h.salt = readN(int(readByte()))
+	h.params.Time = readUint32()
+	h.params.Memory = readUint32()
+	h.params.Threads = readByte()
+	h.nonce = readN(int(readByte()))
+
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("%w: %w", ErrHeaderInvalid, errHeaderShortPayload)
+	}
+
+	if h.version != formatVersion {
+		return nil, nil, nil, fmt.Errorf("%w: %d", ErrHeaderVersion, h.version)
+	}
+
+	if h.kdf != KDFArgon2id {
+		return nil, nil, nil, fmt.Errorf("%w: %d", ErrKDFUnknown, h.kdf)
+	}
+
+	if err := h.params.validate(); err != nil {
+		return nil, nil, nil, err
+	}
+
+	n := len(data) - r.Len()
+
+	return h, data[:n], data[n:], nil
+}
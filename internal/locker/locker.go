@@ -1,15 +1,16 @@
 // Package locker provides AES-GCM encryption and decryption utilities for
 // files, including backup and restore logic.
+//
+// Keys are derived with Argon2id; the KDF parameters are stored in a
+// versioned header that is authenticated along with the ciphertext. Legacy
+// files keyed with sha256(passphrase) are still decrypted transparently.
 package locker
 
 import (
 	"crypto/aes"
 	"crypto/cipher"
-	"crypto/rand"
-	"crypto/sha256"
 	"errors"
 	"fmt"
-	"io"
 	"log/slog"
 	"os"
 	"path/filepath"
@@ -17,6 +18,8 @@ import (
 	"time"
 
 	files "github.com/mateconpizza/gofiles"
+
+	"github.com/mateconpizza/gm/internal/sys"
 )
 
 const Extension = ".enc"
@@ -77,6 +80,138 @@ func Unlock(path, passphrase string) error {
 		return err
 	}
 
+	return unlockWith(path, func(ciphertext []byte) ([]byte, error) {
+		return decrypt(ciphertext, passphrase)
+	})
+}
+
+// LockWithKey encrypts the given file with an already derived key and adds
+// .enc extension.
+func LockWithKey(path string, k *Key) error {
+	slog.Debug("locking file with key", "path", path)
+
+	if k == nil {
+		return ErrKeyMismatch
+	}
+
+	if !files.Exists(path) {
+		return fmt.Errorf("%w: %s", os.ErrNotExist, path)
+	}
+
+	backupPath, err := backupFile(path)
+	if err != nil {
+		return fmt.Errorf("backup creation failed: %w", err)
+	}
+
+	plaintext, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read input file: %w", err)
+	}
+
+	ciphertext, err := encryptWithKey(plaintext, k)
+	if err != nil {
+		return err
+	}
+
+	if err := writeAndReplaceFile(path+Extension, ciphertext, path, backupPath); err != nil {
+		return err
+	}
+
+	_ = os.Remove(backupPath)
+
+	return nil
+}
+
+// UnlockWithKey decrypts the given .enc file with an already derived key and
+// removes the .enc extension.
+func UnlockWithKey(path string, k *Key) error {
+	slog.Debug("unlocking file with key", "path", path)
+
+	if k == nil {
+		return ErrKeyMismatch
+	}
+
+	return unlockWith(path, func(ciphertext []byte) ([]byte, error) {
+		return decryptWithKey(ciphertext, k)
+	})
+}
+
+// UnlockKey decrypts the given .enc file with the passphrase and returns a
+// key that can be used with LockWithKey to relock it.
+//
+// Legacy files get a freshly derived key, so relocking upgrades them to the
+// versioned format.
+func UnlockKey(path, passphrase string) (*Key, error) {
+	if err := validateInput(path, passphrase); err != nil {
+		return nil, err
+	}
+
+	ciphertext, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read locked file: %w", err)
+	}
+
+	var k *Key
+	if h, _, _, err := parseHeader(ciphertext); err == nil {
+		k, err = DeriveKey(passphrase, h.kdf, h.salt, h.params)
+		if err != nil {
+			return nil, err
+		}
+	} else if !errors.Is(err, ErrLegacyFormat) {
+		return nil, err
+	}
+
+	if k != nil {
+		return k, UnlockWithKey(path, k)
+	}
+
+	if err := Unlock(path, passphrase); err != nil {
+		return nil, err
+	}
+
+	return NewKey(passphrase)
+}
+
+// Rekey re-encrypts the given .enc file with a new passphrase.
+//
+// The plaintext is only kept in memory, and the new ciphertext replaces the
+// locked file atomically. Legacy files are upgraded to the versioned format.
+func Rekey(path, oldPassphrase, newPassphrase string) error {
+	slog.Debug("rekeying file", "path", path)
+
+	if err := validateInput(path, oldPassphrase); err != nil {
+		return err
+	}
+
+	if newPassphrase == "" {
+		return ErrPassphraseEmpty
+	}
+
+	if !strings.HasSuffix(path, Extension) {
+		return fmt.Errorf("%w: got %q", ErrFileExtMismatch, filepath.Ext(path))
+	}
+
+	ciphertext, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read locked file: %w", err)
+	}
+
+	plaintext, err := decrypt(ciphertext, oldPassphrase)
+	if err != nil {
+		return err
+	}
+
+	ciphertext, err = encrypt(plaintext, newPassphrase)
+	if err != nil {
+		return err
+	}
+
+	return sys.WriteFileAtomic(path, ciphertext, files.FilePerm)
+}
+
+// unlockWith decrypts the given .enc file with decryptFn and replaces it with
+// the plaintext file.
+func unlockWith(path string, decryptFn func([]byte) ([]byte, error)) error {
 	if !strings.HasSuffix(path, Extension) {
 		return fmt.Errorf("%w: got %q", ErrFileExtMismatch, filepath.Ext(path))
 	}
@@ -86,7 +221,7 @@ func Unlock(path, passphrase string) error {
 		return fmt.Errorf("failed to read locked file: %w", err)
 	}
 	// Perform decryption
-	plaintext, err := decrypt(ciphertext, passphrase)
+	plaintext, err := decryptFn(ciphertext)
 	if err != nil {
 		return err
 	}
@@ -110,44 +245,103 @@ func Unlock(path, passphrase string) error {
 	return nil
 }
 
-// encrypt encrypts data using AES-GCM with the given passphrase.
+// encrypt encrypts data using AES-GCM with a key derived from the given
+// passphrase.
 func encrypt(plaintext []byte, passphrase string) ([]byte, error) {
-	// Generate a key from the passphrase
-	key := generateKey(passphrase)
-	// Create a new AES cipher block
-	block, err := aes.NewCipher(key)
+	key, err := NewKey(passphrase)
 	if err != nil {
-		return nil, fmt.Errorf("cipher creation failed: %w", err)
+		return nil, err
 	}
-	// Create a new GCM mode
-	gcm, err := cipher.NewGCM(block)
+
+	return encryptWithKey(plaintext, key)
+}
+
+// encryptWithKey encrypts data using AES-GCM and prepends the versioned
+// header, which is authenticated as additional data.
+func encryptWithKey(plaintext []byte, key *Key) ([]byte, error) {
+	if key.KDF == KDFLegacySHA256 {
+		return nil, fmt.Errorf("%w: %s", ErrKDFUnknown, key.KDF)
+	}
+
+	gcm, err := newGCM(key.Secret())
 	if err != nil {
-		return nil, fmt.Errorf("GCM mode creation failed: %w", err)
+		return nil, err
+	}
+
+	h, err := newHeader(key)
+	if err != nil {
+		return nil, err
 	}
-	// Create a nonce (number used once)
-	nonce := make([]byte, gcm.NonceSize())
-	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
-		return nil, fmt.Errorf("nonce generation failed: %w", err)
+
+	raw, err := h.MarshalBinary()
+	if err != nil {
+		return nil, err
 	}
-	// Encrypt the data and prepend nonce
-	ciphertext := gcm.Seal(nonce, nonce, plaintext, nil)
 
-	return ciphertext, nil
+	return gcm.Seal(raw, h.nonce, plaintext, raw), nil
 }
 
-// decrypt decrypts data using AES-GCM with the given passphrase.
+// decrypt decrypts data using AES-GCM with the given passphrase, handling
+// both versioned and legacy files.
 func decrypt(ciphertext []byte, passphrase string) ([]byte, error) {
-	// Generate the key from the passphrase
-	key := generateKey(passphrase)
-	// Create a new AES cipher block
-	block, err := aes.NewCipher(key)
+	if !hasHeader(ciphertext) {
+		return decryptLegacy(ciphertext, passphrase)
+	}
+
+	h, _, _, err := parseHeader(ciphertext)
 	if err != nil {
-		return nil, fmt.Errorf("cipher creation failed: %w", err)
+		return nil, err
 	}
-	// Create a new GCM mode
-	gcm, err := cipher.NewGCM(block)
+
+	key, err := DeriveKey(passphrase, h.kdf, h.salt, h.params)
 	if err != nil {
-		return nil, fmt.Errorf("GCM mode creation failed: %w", err)
+		return nil, err
+	}
+
+	return decryptWithKey(ciphertext, key)
+}
+
+// decryptWithKey decrypts a versioned file with an already derived key.
+func decryptWithKey(ciphertext []byte, key *Key) ([]byte, error) {
+	h, raw, payload, err := parseHeader(ciphertext)
+	if err != nil {
+		return nil, err
+	}
+
+	if !key.matches(h) {
+		return nil, ErrKeyMismatch
+	}
+
+	gcm, err := newGCM(key.Secret())
+	if err != nil {
+		return nil, err
+	}
+
+	if len(h.nonce) != gcm.NonceSize() {
+		return nil, fmt.Errorf("%w: nonce size %d", ErrHeaderInvalid, len(h.nonce))
+	}
+
+	plaintext, err := gcm.Open(nil, h.nonce, payload, raw)
+	if err != nil {
+		return nil, fmt.Errorf("decryption failed: %w", err)
+	}
+
+	return plaintext, nil
+}
+
+// decryptLegacy decrypts files written before the versioned format, where
+// the key is the unsalted sha256 of the passphrase.
+func decryptLegacy(ciphertext []byte, passphrase string) ([]byte, error) {
+	slog.Debug("decrypting legacy file format")
+
+	key, err := DeriveKey(passphrase, KDFLegacySHA256, nil, KDFParams{})
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := newGCM(key.Secret())
+	if err != nil {
+		return nil, err
 	}
 	// Verify the ciphertext is long enough
 	nonceSize := gcm.NonceSize()
@@ -165,6 +359,21 @@ func decrypt(ciphertext []byte, passphrase string) ([]byte, error) {
 	return plaintext, nil
 }
 
+// newGCM creates an AES-GCM cipher from the given key.
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("cipher creation failed: %w", err)
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("GCM mode creation failed: %w", err)
+	}
+
+	return gcm, nil
+}
+
 // IsLocked checks if the given file has .enc extension.
 func IsLocked(s string) error {
 	slog.Debug("checking if file is locked")
@@ -179,15 +388,6 @@ func IsLocked(s string) error {
 	return nil
 }
 
-// generateKey creates a 32-byte key from the passphrase using SHA-256.
-func generateKey(passphrase string) []byte {
-	slog.Debug("generating key from passphrase")
-
-	hash := sha256.Sum256([]byte(passphrase))
-
-	return hash[:]
-}
-
 // validateInput validates input parameters for Lock function.
 func validateInput(path, passphrase string) error {
 	if passphrase == "" {
@@ -266,3 +266,210 @@ func TestWriteAndReplaceFile(t *testing.T) {
 		}
 	})
 }
+
+func TestDecryptLegacyFormat(t *testing.T) {
+	t.Parallel()
+
+	pp := "123456"
+	b := []byte("legacy content")
+
+	// build a legacy payload: sha256(passphrase) key, nonce||ciphertext
+	key, err := DeriveKey(pp, KDFLegacySHA256, nil, KDFParams{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	gcm, err := newGCM(key.Secret())
+	if err != nil {
+		t.Fatal(err)
+	}
+	nonce := bytes.Repeat([]byte{1}, gcm.NonceSize())
+	legacy := gcm.Seal(nonce, nonce, b, nil)
+
+	plaintext, err := decrypt(legacy, pp)
+	if err != nil {
+		t.Fatalf("Decryption of legacy file failed: %v", err)
+	}
+
+	if !bytes.Equal(plaintext, b) {
+		t.Errorf("Expected %q, got %q", string(b), string(plaintext))
+	}
+}
+
+func TestEncryptedHeader(t *testing.T) {
+	t.Parallel()
+
+	pp := "123456"
+	ciphertext, err := encrypt([]byte("content"), pp)
+	if err != nil {
+		t.Fatalf("Encryption failed: %v", err)
+	}
+
+	if !hasHeader(ciphertext) {
+		t.Fatal("Expected versioned header")
+	}
+
+	h, raw, _, err := parseHeader(ciphertext)
+	if err != nil {
+		t.Fatalf("Parsing header failed: %v", err)
+	}
+	if h.version != formatVersion {
+		t.Errorf("Expected version %d, got %d", formatVersion, h.version)
+	}
+	if h.kdf != KDFArgon2id {
+		t.Errorf("Expected kdf %s, got %s", KDFArgon2id, h.kdf)
+	}
+	if len(h.salt) != saltSize {
+		t.Errorf("Expected salt size %d, got %d", saltSize, len(h.salt))
+	}
+	if h.params != DefaultKDFParams {
+		t.Errorf("Expected params %+v, got %+v", DefaultKDFParams, h.params)
+	}
+
+	t.Run("tampered header", func(t *testing.T) {
+		t.Parallel()
+
+		tampered := bytes.Clone(ciphertext)
+		// flip a salt byte, key derivation changes and auth must fail
+		tampered[len(headerMagic)+3] ^= 0xff
+		if _, err := decrypt(tampered, pp); err == nil {
+			t.Error("Expected error for tampered header, got nil")
+		}
+	})
+
+	t.Run("unsupported version", func(t *testing.T) {
+		t.Parallel()
+
+		tampered := bytes.Clone(ciphertext)
+		tampered[len(headerMagic)] = formatVersion + 1
+		_, _, _, err := parseHeader(tampered)
+		if !errors.Is(err, ErrHeaderVersion) {
+			t.Errorf("Expected ErrHeaderVersion, got %v", err)
+		}
+	})
+
+	t.Run("truncated header", func(t *testing.T) {
+		t.Parallel()
+
+		_, _, _, err := parseHeader(raw[:len(raw)-4])
+		if !errors.Is(err, ErrHeaderInvalid) {
+			t.Errorf("Expected ErrHeaderInvalid, got %v", err)
+		}
+	})
+
+	t.Run("kdf params out of range", func(t *testing.T) {
+		t.Parallel()
+
+		for _, p := range []KDFParams{
+			{Time: 0, Memory: 64 * 1024, Threads: 4},
+			{Time: 3, Memory: 64 * 1024, Threads: 0},
+			{Time: 3, Memory: 0, Threads: 4},
+			{Time: 1 << 31, Memory: 64 * 1024, Threads: 4},
+			{Time: 3, Memory: 1<<32 - 1, Threads: 4},
+			{Time: 3, Memory: 64 * 1024, Threads: 255},
+		} {
+			hh := *h
+			hh.params = p
+			data, err := hh.MarshalBinary()
+			if err != nil {
+				t.Fatalf("Marshal header failed: %v", err)
+			}
+
+			_, _, _, err = parseHeader(data)
+			if !errors.Is(err, ErrHeaderInvalid) {
+				t.Errorf("%+v: expected ErrHeaderInvalid, got %v", p, err)
+			}
+			if _, err := decrypt(data, pp); !errors.Is(err, ErrHeaderInvalid) {
+				t.Errorf("%+v: expected decrypt to fail with ErrHeaderInvalid, got %v", p, err)
+			}
+		}
+	})
+}
+
+func TestRekey(t *testing.T) {
+	t.Parallel()
+
+	content := []byte("database content")
+	path := filepath.Join(t.TempDir(), "test.db")
+	if err := os.WriteFile(path, content, files.FilePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Lock(path, "old"); err != nil {
+		t.Fatalf("Lock failed: %v", err)
+	}
+
+	locked := path + Extension
+	if err := Rekey(locked, "wrong", "new"); err == nil {
+		t.Error("Expected error with wrong passphrase, got nil")
+	}
+
+	if err := Rekey(locked, "old", "new"); err != nil {
+		t.Fatalf("Rekey failed: %v", err)
+	}
+
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Error("Plaintext file should not exist after rekey")
+	}
+
+	if err := Unlock(locked, "old"); err == nil {
+		t.Error("Expected error unlocking with old passphrase, got nil")
+	}
+
+	if err := Unlock(locked, "new"); err != nil {
+		t.Fatalf("Unlock with new passphrase failed: %v", err)
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, content) {
+		t.Errorf("Expected %q, got %q", string(content), string(got))
+	}
+}
+
+func TestFieldCipher(t *testing.T) {
+	t.Parallel()
+
+	k, err := NewKey("pass")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fc, err := NewFieldCipher(k)
+	if err != nil {
+		t.Fatalf("NewFieldCipher failed: %v", err)
+	}
+
+	plain := []byte("https://intranet.example.com/?token=abc")
+	sealed, err := fc.Seal(plain)
+	if err != nil {
+		t.Fatalf("Seal failed: %v", err)
+	}
+	if bytes.Contains(sealed, plain) {
+		t.Error("sealed value contains plaintext")
+	}
+
+	again, _ := fc.Seal(plain)
+	if bytes.Equal(sealed, again) {
+		t.Error("expected a fresh nonce per seal")
+	}
+
+	got, err := fc.Open(sealed)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	if !bytes.Equal(got, plain) {
+		t.Errorf("Expected %q, got %q", plain, got)
+	}
+
+	other, _ := NewKey("other")
+	ofc, _ := NewFieldCipher(other)
+	if _, err := ofc.Open(sealed); err == nil {
+		t.Error("Expected error opening with another key, got nil")
+	}
+
+	if _, err := fc.Open(sealed[:4]); !errors.Is(err, ErrCipherTextShort) {
+		t.Errorf("Expected ErrCipherTextShort, got %v", err)
+	}
+}
@@ -0,0 +1,135 @@
+// Package secret resolves database passphrases without prompting, from an
+// environment variable or an external command such as `pass`.
+package secret
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+var (
+	ErrNoSecret       = errors.New("no secret available")
+	ErrCommandFailed  = errors.New("passphrase command failed")
+	ErrCommandNoInput = errors.New("passphrase command returned empty output")
+)
+
+// Provider returns a passphrase for a database.
+type Provider interface {
+	// Passphrase returns the passphrase for the named database, or ErrNoSecret
+	// if the provider has nothing to offer.
+	Passphrase(ctx context.Context, dbName string) (string, error)
+	String() string
+}
+
+// Env reads the passphrase from an environment variable.
+type Env struct {
+	Var string
+}
+
+func NewEnv(name string) *Env { return &Env{Var: name} }
+
+func (e *Env) String() string { return "env:" + e.Var }
+
+func (e *Env) Passphrase(_ context.Context, _ string) (string, error) {
+	if e.Var == "" {
+		return "", ErrNoSecret
+	}
+
+	s := os.Getenv(e.Var)
+	if s == "" {
+		return "", ErrNoSecret
+	}
+
+	slog.Debug("secret: passphrase from env", "var", e.Var)
+
+	return s, nil
+}
+
+// Command runs a shell command and uses the first line of its output as
+// passphrase, `pass`-style.
+//
+// The placeholder {db} is replaced with the database name, quoted for the
+// shell.
+type Command struct {
+	Cmd string
+}
+
+func NewCommand(cmd string) *Command { return &Command{Cmd: cmd} }
+
+func (c *Command) String() string { return "cmd:" + c.Cmd }
+
+func (c *Command) Passphrase(ctx context.Context, dbName string) (string, error) {
+	if strings.TrimSpace(c.Cmd) == "" {
+		return "", ErrNoSecret
+	}
+
+	line := strings.ReplaceAll(c.Cmd, "{db}", shellQuote(dbName))
+	slog.Debug("secret: running passphrase command", "cmd", line)
+
+	var stdout, stderr bytes.Buffer
+	cmd := shellCommand(ctx, line)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.Stdin = os.Stdin
+
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return "", fmt.Errorf("%w: %s", ErrCommandFailed, msg)
+	}
+
+	s, _, _ := strings.Cut(stdout.String(), "\n")
+	s = strings.TrimRight(s, "\r")
+	if s == "" {
+		return "", ErrCommandNoInput
+	}
+
+	return s, nil
+}
+
+// Resolve returns the passphrase from the first provider that has one.
+func Resolve(ctx context.Context, dbName string, ps ...Provider) (string, error) {
+	for _, p := range ps {
+		if p == nil {
+			continue
+		}
+
+		s, err := p.Passphrase(ctx, dbName)
+		if err == nil {
+			return s, nil
+		}
+
+		if !errors.Is(err, ErrNoSecret) {
+			return "", fmt.Errorf("%s: %w", p, err)
+		}
+	}
+
+	return "", ErrNoSecret
+}
+
+func shellCommand(ctx context.Context, line string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", line)
+	}
+
+	return exec.CommandContext(ctx, "sh", "-c", line)
+}
+
+// shellQuote quotes s as a single argument of the shell running the command.
+func shellQuote(s string) string {
+	if runtime.GOOS == "windows" {
+		// cmd has no escape inside quotes, file names cannot hold one
+		return `"` + s + `"`
+	}
+
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,96 @@
+package secret
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestEnvProvider(t *testing.T) {
+	t.Setenv("GM_TEST_PASSPHRASE", "s3cret")
+
+	p := NewEnv("GM_TEST_PASSPHRASE")
+	got, err := p.Passphrase(context.Background(), "work")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "s3cret" {
+		t.Errorf("expected %q, got %q", "s3cret", got)
+	}
+
+	_, err = NewEnv("GM_TEST_PASSPHRASE_UNSET").Passphrase(context.Background(), "work")
+	if !errors.Is(err, ErrNoSecret) {
+		t.Errorf("expected ErrNoSecret, got %v", err)
+	}
+}
+
+func TestCommandProvider(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		cmd     string
+		want    string
+		wantErr error
+	}{
+		{name: "first line", cmd: "printf 'pass-{db}\\nuser: me\\n'", want: "pass-work"},
+		{name: "empty command", cmd: " ", wantErr: ErrNoSecret},
+		{name: "empty output", cmd: "true", wantErr: ErrCommandNoInput},
+		{name: "failing command", cmd: "exit 3", wantErr: ErrCommandFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := NewCommand(tt.cmd).Passphrase(context.Background(), "work")
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCommandProviderQuotesDB(t *testing.T) {
+	t.Parallel()
+
+	const name = "work'; echo injected; '"
+	got, err := NewCommand("printf '%s\\n' {db}").Passphrase(context.Background(), name)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != name {
+		t.Errorf("expected %q, got %q", name, got)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	t.Setenv("GM_TEST_PASSPHRASE", "")
+
+	ps := []Provider{
+		NewEnv("GM_TEST_PASSPHRASE"),
+		NewCommand("echo from-cmd"),
+	}
+
+	got, err := Resolve(context.Background(), "work", ps...)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "from-cmd" {
+		t.Errorf("expected %q, got %q", "from-cmd", got)
+	}
+
+	_, err = Resolve(context.Background(), "work", NewEnv(""), nil)
+	if !errors.Is(err, ErrNoSecret) {
+		t.Errorf("expected ErrNoSecret, got %v", err)
+	}
+}
@@ -0,0 +1,271 @@
+package menucfg
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"runtime"
+	"strings"
+
+	menu "github.com/mateconpizza/go-fzf"
+
+	"github.com/mateconpizza/gm/pkg/bookmark"
+)
+
+var (
+	ErrInvalidAction  = errors.New("invalid action")
+	ErrActionNotFound = errors.New("action not found")
+)
+
+// actionNameRe restricts action names to what can be passed as a single
+// argument to the run subcommand.
+var actionNameRe = regexp.MustCompile(`^[\w.-]+$`)
+
+// Action is a user defined command bound to a menu key and exposed as
+// `run <name>`.
+//
+// The command is run by the shell, with the placeholders {url}, {title} and
+// {tags} replaced by the quoted values of the bookmark. On Windows it runs
+// without a shell, as a program and its arguments. In multi mode the command
+// runs once with the values of all the bookmarks, otherwise once per bookmark.
+type Action struct {
+	Name  string       `json:"name"           yaml:"name"`
+	Desc  string       `json:"desc,omitempty" yaml:"desc,omitempty"`
+	Cmd   string       `json:"cmd"            yaml:"cmd"`
+	Bind  menu.Keybind `json:"bind,omitempty" yaml:"bind,omitempty"`
+	Multi bool         `json:"multi"          yaml:"multi"`
+}
+
+// Description returns the description of the action, its name by default.
+func (a *Action) Description() string {
+	if a.Desc != "" {
+		return a.Desc
+	}
+
+	return a.Name
+}
+
+// Validate checks the action.
+func (a *Action) Validate() error {
+	if !actionNameRe.MatchString(a.Name) {
+		return fmt.Errorf("%w: name %q: use letters, digits, '.', '-' or '_'", ErrInvalidAction, a.Name)
+	}
+	if strings.TrimSpace(a.Cmd) == "" {
+		return fmt.Errorf("%w: %q: missing cmd", ErrInvalidAction, a.Name)
+	}
+	if quotedPlaceholder(a.Cmd) {
+		return fmt.Errorf("%w: %q: placeholders are already quoted, remove the quotes around them",
+			ErrInvalidAction, a.Name)
+	}
+
+	return nil
+}
+
+// Commands returns the commands to run on the bookmarks, each one as a
+// program and its arguments.
+func (a *Action) Commands(bs []*bookmark.Bookmark) [][]string {
+	return a.commands(runtime.GOOS, bs)
+}
+
+func (a *Action) commands(goos string, bs []*bookmark.Bookmark) [][]string {
+	groups := [][]*bookmark.Bookmark{bs}
+	if !a.Multi {
+		groups = groups[:0]
+		for _, b := range bs {
+			groups = append(groups, []*bookmark.Bookmark{b})
+		}
+	}
+
+	cmds := make([][]string, 0, len(groups))
+	for _, g := range groups {
+		if goos == "windows" {
+			cmds = append(cmds, expandArgs(a.Cmd, g))
+			continue
+		}
+		cmds = append(cmds, []string{"sh", "-c", expandPlaceholders(a.Cmd, g...)})
+	}
+
+	return cmds
+}
+
+// Action returns the action with the given name.
+func (c *Config) Action(name string) (*Action, error) {
+	for _, a := range c.Actions {
+		if a.Name == name {
+			return a, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %q", ErrActionNotFound, name)
+}
+
+// ValidateActions checks the actions, their names must be unique and their
+// binds must not shadow the enabled keymaps.
+func (c *Config) ValidateActions() error {
+	binds := make(map[menu.Keybind]string)
+	if k := c.Keymaps(); k != nil {
+		for _, km := range k.List() {
+			if km != nil && km.IsEnabled() && km.Bind != "" {
+				binds[km.Bind] = km.Desc
+			}
+		}
+	}
+
+	names := make(map[string]bool, len(c.Actions))
+	for _, a := range c.Actions {
+		if err := a.Validate(); err != nil {
+			return err
+		}
+		if names[a.Name] {
+			return fmt.Errorf("%w: %q: duplicated name", ErrInvalidAction, a.Name)
+		}
+		names[a.Name] = true
+
+		if a.Bind == "" {
+			continue
+		}
+		if used, ok := binds[a.Bind]; ok {
+			return fmt.Errorf("%w: %q: bind %q already used by %q", ErrInvalidAction, a.Name, a.Bind, used)
+		}
+		binds[a.Bind] = a.Name
+	}
+
+	return nil
+}
+
+// actionKeymaps returns the keymaps of the actions with a bind, running
+// `run <name>` on the selected records, or the current one when the action
+// is not multi.
+func (c *Config) actionKeymaps(kb *KeymapBuilder) []*menu.Keymap {
+	keymaps := make([]*menu.Keymap, 0, len(c.Actions))
+	for _, a := range c.Actions {
+		if a.Bind == "" {
+			continue
+		}
+
+		kc := kb.New(a.Bind, a.Description())
+		if !a.Multi && kb.single != "" {
+			kc.WithPlaceholder(kb.single)
+		}
+		keymaps = append(keymaps, kc.WithExecute("run "+a.Name))
+	}
+
+	return keymaps
+}
+
+// placeholders maps each placeholder to the bookmark value it stands for.
+var placeholders = map[string]func(b *bookmark.Bookmark) string{
+	"{url}":   func(b *bookmark.Bookmark) string { return b.URL },
+	"{title}": func(b *bookmark.Bookmark) string { return b.Title },
+	"{tags}":  func(b *bookmark.Bookmark) string { return strings.Trim(b.Tags, ",") },
+}
+
+// expandPlaceholders replaces the placeholders with the quoted values of
+// the bookmarks, separated by spaces.
+func expandPlaceholders(s string, bs ...*bookmark.Bookmark) string {
+	oldnew := make([]string, 0, len(placeholders)*2)
+	for p, f := range placeholders {
+		v := make([]string, 0, len(bs))
+		for _, b := range bs {
+			v = append(v, shellQuote(f(b)))
+		}
+		oldnew = append(oldnew, p, strings.Join(v, " "))
+	}
+
+	return strings.NewReplacer(oldnew...).Replace(s)
+}
+
+// expandArgs splits s into arguments and replaces the placeholders, without
+// any shell involved. A placeholder that is a whole argument becomes one
+// argument per bookmark.
+func expandArgs(s string, bs []*bookmark.Bookmark) []string {
+	var args []string
+	for _, word := range splitWords(s) {
+		if f, ok := placeholders[word]; ok {
+			for _, b := range bs {
+				args = append(args, f(b))
+			}
+			continue
+		}
+
+		for p, f := range placeholders {
+			v := make([]string, 0, len(bs))
+			for _, b := range bs {
+				v = append(v, f(b))
+			}
+			word = strings.ReplaceAll(word, p, strings.Join(v, " "))
+		}
+		args = append(args, word)
+	}
+
+	return args
+}
+
+// splitWords splits s on spaces, keeping double quoted text in one word.
+func splitWords(s string) []string {
+	var (
+		words  []string
+		word   strings.Builder
+		quoted bool
+		inWord bool
+	)
+
+	for _, r := range s {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			inWord = true
+		case (r == ' ' || r == '\t') && !quoted:
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+
+	return words
+}
+
+// quotedPlaceholder reports whether a placeholder is written inside shell
+// quotes, where its quoting would be undone and the value run as code.
+func quotedPlaceholder(s string) bool {
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		if quote != 0 {
+			for p := range placeholders {
+				if strings.HasPrefix(s[i:], p) {
+					return true
+				}
+			}
+		}
+
+		switch c := s[i]; {
+		case quote == '\'':
+			if c == '\'' {
+				quote = 0
+			}
+		case c == '\\':
+			i++
+		case quote == '"':
+			if c == '"' {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		}
+	}
+
+	return false
+}
+
+// shellQuote quotes s as a single POSIX shell word.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
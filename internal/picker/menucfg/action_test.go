@@ -0,0 +1,215 @@
+package menucfg
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	menu "github.com/mateconpizza/go-fzf"
+
+	"github.com/mateconpizza/gm/pkg/bookmark"
+)
+
+func TestAction_Commands(t *testing.T) {
+	t.Parallel()
+
+	bs := []*bookmark.Bookmark{
+		{ID: 1, URL: "https://go.dev", Title: "Go", Tags: "go,lang,"},
+		{ID: 2, URL: "https://example.com/?q=it's", Title: "It's $HOME", Tags: "misc,"},
+	}
+
+	tests := []struct {
+		name   string
+		action *Action
+		want   []string
+	}{
+		{
+			name:   "single",
+			action: &Action{Name: "md", Cmd: "echo [{title}]({url})"},
+			want: []string{
+				`echo ['Go']('https://go.dev')`,
+				`echo ['It'\''s $HOME']('https://example.com/?q=it'\''s')`,
+			},
+		},
+		{
+			name:   "multi",
+			action: &Action{Name: "mpv", Cmd: "mpv {url}", Multi: true},
+			want:   []string{`mpv 'https://go.dev' 'https://example.com/?q=it'\''s'`},
+		},
+		{
+			name:   "tags",
+			action: &Action{Name: "tags", Cmd: "echo {tags}"},
+			want:   []string{`echo 'go,lang'`, `echo 'misc'`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := tt.action.commands("linux", bs)
+			if len(got) != len(tt.want) {
+				t.Fatalf("expected %d commands, got %q", len(tt.want), got)
+			}
+			for i, want := range tt.want {
+				if !slices.Equal(got[i], []string{"sh", "-c", want}) {
+					t.Errorf("commands() = %q, want %q", got[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestAction_CommandsWindows(t *testing.T) {
+	t.Parallel()
+
+	bs := []*bookmark.Bookmark{
+		{ID: 1, URL: "https://go.dev", Title: `Go" & calc`, Tags: "go,lang,"},
+		{ID: 2, URL: "https://example.com", Title: "Example", Tags: "misc,"},
+	}
+
+	tests := []struct {
+		name   string
+		action *Action
+		want   [][]string
+	}{
+		{
+			name:   "single",
+			action: &Action{Name: "note", Cmd: `notify "gm link" --title={title} {url}`},
+			want: [][]string{
+				{"notify", "gm link", `--title=Go" & calc`, "https://go.dev"},
+				{"notify", "gm link", "--title=Example", "https://example.com"},
+			},
+		},
+		{
+			name:   "multi",
+			action: &Action{Name: "mpv", Cmd: "mpv {url}", Multi: true},
+			want:   [][]string{{"mpv", "https://go.dev", "https://example.com"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := tt.action.commands("windows", bs)
+			if len(got) != len(tt.want) {
+				t.Fatalf("expected %d commands, got %q", len(tt.want), got)
+			}
+			for i := range tt.want {
+				if !slices.Equal(got[i], tt.want[i]) {
+					t.Errorf("commands() = %q, want %q", got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestConfig_ValidateActions(t *testing.T) {
+	t.Parallel()
+
+	keymaps := func() *Keymaps {
+		return &Keymaps{Edit: &menu.Keymap{Enabled: true, Bind: menu.KeyCtrlE, Desc: "edit"}}
+	}
+
+	tests := []struct {
+		name    string
+		actions []*Action
+		wantErr error
+	}{
+		{
+			name: "valid",
+			actions: []*Action{
+				{Name: "mpv", Cmd: "mpv {url}", Bind: "alt-m", Multi: true},
+				{Name: "md-link", Cmd: "echo {url}"},
+			},
+		},
+		{
+			name:    "invalid_name",
+			actions: []*Action{{Name: "open in mpv", Cmd: "mpv {url}"}},
+			wantErr: ErrInvalidAction,
+		},
+		{
+			name:    "quoted_placeholder",
+			actions: []*Action{{Name: "echo", Cmd: `echo "title: {title}"`}},
+			wantErr: ErrInvalidAction,
+		},
+		{
+			name:    "escaped_quote",
+			actions: []*Action{{Name: "echo", Cmd: `echo \" {title} \"`}},
+		},
+		{
+			name:    "missing_cmd",
+			actions: []*Action{{Name: "mpv"}},
+			wantErr: ErrInvalidAction,
+		},
+		{
+			name: "duplicated_name",
+			actions: []*Action{
+				{Name: "mpv", Cmd: "mpv {url}"},
+				{Name: "mpv", Cmd: "vlc {url}"},
+			},
+			wantErr: ErrInvalidAction,
+		},
+		{
+			name:    "bind_used_by_keymap",
+			actions: []*Action{{Name: "mpv", Cmd: "mpv {url}", Bind: menu.KeyCtrlE}},
+			wantErr: ErrInvalidAction,
+		},
+		{
+			name: "bind_used_by_action",
+			actions: []*Action{
+				{Name: "mpv", Cmd: "mpv {url}", Bind: "alt-m"},
+				{Name: "vlc", Cmd: "vlc {url}", Bind: "alt-m"},
+			},
+			wantErr: ErrInvalidAction,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			c := &Config{DefaultKeymaps: keymaps(), Actions: tt.actions}
+			if err := c.ValidateActions(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("ValidateActions() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfig_ActionKeymaps(t *testing.T) {
+	t.Parallel()
+
+	c := &Config{Actions: []*Action{
+		{Name: "mpv", Cmd: "mpv {url}", Bind: "alt-m", Multi: true},
+		{Name: "md-link", Desc: "markdown", Cmd: "echo {url}", Bind: "alt-l"},
+		{Name: "unbound", Cmd: "echo {url}"},
+	}}
+
+	kb := NewBindBuilder().
+		WithCommand("gm").
+		WithDBName("main.db").
+		WithPlaceholder("{+1}").
+		WithSinglePlaceholder("{1}")
+
+	km := c.actionKeymaps(kb)
+	if len(km) != 2 {
+		t.Fatalf("expected 2 keymaps, got %d", len(km))
+	}
+
+	if got := string(km[0].Action); !strings.Contains(got, "gm --db=main.db run mpv {+1}") {
+		t.Errorf("unexpected multi action: %q", got)
+	}
+	if got := string(km[1].Action); !strings.Contains(got, "gm --db=main.db run md-link {1}") {
+		t.Errorf("unexpected single action: %q", got)
+	}
+	if km[1].Desc != "markdown" {
+		t.Errorf("expected desc %q, got %q", "markdown", km[1].Desc)
+	}
+
+	if _, err := c.Action("unbound"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := c.Action("missing"); !errors.Is(err, ErrActionNotFound) {
+		t.Errorf("expected ErrActionNotFound, got %v", err)
+	}
+}
@@ -19,6 +19,10 @@ type Keymaps struct {
 	ToggleAll *menu.Keymap `json:"toggle_all" yaml:"toggle_all"`
 	Yank      *menu.Keymap `json:"yank"       yaml:"yank"`
 	Repos     *menu.Keymap `json:"repos"      yaml:"repos"`
+	Favorite  *menu.Keymap `json:"favorite"   yaml:"favorite"`
+	Delete    *menu.Keymap `json:"delete"     yaml:"delete"`
+	AddTag    *menu.Keymap `json:"add_tag"    yaml:"add_tag"`
+	ChangeDB  *menu.Keymap `json:"change_db"  yaml:"change_db"`
 }
 
 func (k *Keymaps) List() []*menu.Keymap {
@@ -34,6 +38,31 @@ func (k *Keymaps) List() []*menu.Keymap {
 	return keymaps
 }
 
+// setDefaults sets the default keymaps missing from the config, e.g. the
+// ones added after the config file was written.
+func (k *Keymaps) setDefaults() {
+	def := NewDefault().DefaultKeymaps
+	set := func(dst **menu.Keymap, src *menu.Keymap) {
+		if *dst == nil {
+			*dst = src
+		}
+	}
+
+	set(&k.Edit, def.Edit)
+	set(&k.EditNotes, def.EditNotes)
+	set(&k.Open, def.Open)
+	set(&k.Preview, def.Preview)
+	set(&k.QR, def.QR)
+	set(&k.OpenQR, def.OpenQR)
+	set(&k.ToggleAll, def.ToggleAll)
+	set(&k.Yank, def.Yank)
+	set(&k.Repos, def.Repos)
+	set(&k.Favorite, def.Favorite)
+	set(&k.Delete, def.Delete)
+	set(&k.AddTag, def.AddTag)
+	set(&k.ChangeDB, def.ChangeDB)
+}
+
 func (k *Keymaps) Validate() error {
 	check := func(name string, km *menu.Keymap) error {
 		if km == nil || !km.IsEnabled() {
@@ -59,6 +88,10 @@ func (k *Keymaps) Validate() error {
 		{"toggle-preview", k.ToggleAll},
 		{"yank", k.Yank},
 		{"repos", k.Repos},
+		{"favorite", k.Favorite},
+		{"delete", k.Delete},
+		{"add_tag", k.AddTag},
+		{"change_db", k.ChangeDB},
 	} {
 		if err := check(entry.name, entry.km); err != nil {
 			return err
@@ -73,6 +106,8 @@ type KeymapBuilder struct {
 	cmd         string
 	dbName      string
 	placeholder string
+	single      string
+	reload      string
 }
 
 // NewBindBuilder creates a new keybind builder for the given command and
@@ -87,6 +122,24 @@ func (bb *KeymapBuilder) WithPlaceholder(p string) *KeymapBuilder {
 	return bb
 }
 
+// WithSinglePlaceholder sets the FZF placeholder of the current record only
+// (e.g. "{1}"), used by the keymaps that act on a single record.
+func (bb *KeymapBuilder) WithSinglePlaceholder(p string) *KeymapBuilder {
+	bb.single = p
+	return bb
+}
+
+// WithReload sets the arguments of the command that prints the menu items
+// again, run after the keymaps marked with Reload.
+func (bb *KeymapBuilder) WithReload(args ...string) *KeymapBuilder {
+	quoted := make([]string, 0, len(args))
+	for _, a := range args {
+		quoted = append(quoted, shellQuote(a))
+	}
+	bb.reload = bb.baseCmd(strings.Join(quoted, " "))
+	return bb
+}
+
 // WithCommand sets the CLI command to be executed.
 func (bb *KeymapBuilder) WithCommand(cmd string) *KeymapBuilder {
 	bb.cmd = cmd
@@ -142,6 +195,14 @@ type KeymapConfig struct {
 	base        *menu.Keymap
 	builder     *KeymapBuilder
 	placeholder string
+	reload      bool
+}
+
+// Reload reloads the menu items after the action, so the changes it made
+// show up in the menu.
+func (kc *KeymapConfig) Reload() *KeymapConfig {
+	kc.reload = true
+	return kc
 }
 
 // WithPlaceholder overrides the builder-level placeholder for this keymap
@@ -165,13 +226,13 @@ func (kc *KeymapConfig) WithBind(bind menu.Keybind) *KeymapConfig {
 // WithExecute sets an execute action with the given CLI subcommand.
 func (kc *KeymapConfig) WithExecute(action string) *menu.Keymap {
 	kc.base.WithExecute(kc.builder.baseCmd(kc.applyPlaceholder(action)))
-	return kc.base
+	return kc.withReload()
 }
 
 // WithExecuteSilent sets an execute-silent action with the given CLI subcommand.
 func (kc *KeymapConfig) WithExecuteSilent(action string) *menu.Keymap {
 	kc.base.WithSilentExecute(kc.builder.baseCmd(kc.applyPlaceholder(action)))
-	return kc.base
+	return kc.withReload()
 }
 
 func (kc *KeymapConfig) WithBecome(cmd string) *menu.Keymap {
@@ -179,6 +240,16 @@ func (kc *KeymapConfig) WithBecome(cmd string) *menu.Keymap {
 	return kc.base
 }
 
+// withReload chains the reload of the menu items to the action. fzf ends
+// "reload(...)" at the first ")", so the reload goes last as "reload:", which
+// takes the rest of the binding whatever the query or tags hold.
+func (kc *KeymapConfig) withReload() *menu.Keymap {
+	if kc.reload && kc.builder.reload != "" {
+		kc.base.Action += menu.KeybindAction("+reload:" + kc.builder.reload)
+	}
+	return kc.base
+}
+
 func (kc *KeymapConfig) resolvePlaceholder() string {
 	if kc.placeholder != "" {
 		return kc.placeholder
@@ -2,6 +2,7 @@ package menucfg
 
 import (
 	"errors"
+	"strings"
 	"testing"
 
 	menu "github.com/mateconpizza/go-fzf"
@@ -110,3 +111,45 @@ func TestBuiltinKeymaps_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestKeymapBuilder_Reload(t *testing.T) {
+	t.Parallel()
+
+	kb := NewBindBuilder().
+		WithCommand("gm").
+		WithDBName("main.db").
+		WithPlaceholder("{+1}").
+		WithReload("--menu-source", "--tag=go", "it's (draft)")
+
+	k := kb.New(menu.KeyCtrlF, "favorite").Reload().WithExecuteSilent("edit favorite")
+	want := `execute-silent(gm --db=main.db edit favorite {+1})+reload:gm --db=main.db '--menu-source' '--tag=go' 'it'\''s (draft)'`
+	if got := string(k.Action); got != want {
+		t.Errorf("unexpected action:\n got: %s\nwant: %s", got, want)
+	}
+
+	k = kb.New(menu.KeyCtrlY, "yank").WithExecute("yank")
+	if got := string(k.Action); strings.Contains(got, "reload") {
+		t.Errorf("expected no reload, got %s", got)
+	}
+}
+
+func TestConfig_LoadKeymapsDefaults(t *testing.T) {
+	t.Parallel()
+
+	// config written before the favorite, delete, add_tag and change_db
+	// keymaps existed
+	c := NewDefault()
+	c.DefaultKeymaps.Favorite = nil
+	c.DefaultKeymaps.Delete = nil
+
+	kb := NewBindBuilder().WithCommand("gm").WithDBName("main.db").WithPlaceholder("{+1}")
+	for _, k := range c.LoadKeymaps(kb) {
+		if k == nil {
+			t.Fatal("unexpected nil keymap")
+		}
+	}
+
+	if k := c.Keymaps().Delete; k == nil || k.Bind != menu.KeyCtrlD {
+		t.Errorf("expected default delete keymap, got %v", k)
+	}
+}
@@ -2,12 +2,23 @@ package menucfg
 
 import (
 	"errors"
+	"fmt"
 	"log/slog"
+	"runtime"
 
 	menu "github.com/mateconpizza/go-fzf"
 )
 
-var ErrInvalidConfigKeymap = errors.New("invalid keymap")
+var (
+	ErrInvalidConfigKeymap = errors.New("invalid keymap")
+	ErrInvalidBackend      = errors.New("invalid menu backend")
+)
+
+// Menu backends.
+const (
+	BackendFzf = "fzf" // External fzf binary, the default
+	BackendTUI = "tui" // Built-in terminal menu
+)
 
 const (
 	defaultFormatter = "oneline"
@@ -17,6 +28,9 @@ const (
 
 // Config holds the menu configuration.
 type Config struct {
+	// Menu backend, fzf or tui, defaults to fzf
+	Backend string `json:"backend,omitempty" yaml:"backend,omitempty"`
+
 	// Use $FZF_DEFAULT_OPTS_FILE n $FZF_DEFAULT_OPTS
 	Defaults bool `json:"defaults" yaml:"defaults"`
 
@@ -29,6 +43,9 @@ type Config struct {
 	// Fzf enable preview
 	Preview bool `json:"preview" yaml:"preview"`
 
+	// Fzf preview format, defaults to frame
+	PreviewFormat string `json:"preview_format,omitempty" yaml:"preview_format,omitempty"`
+
 	// Fzf header
 	Header Header `json:"header" yaml:"header"`
 
@@ -37,6 +54,9 @@ type Config struct {
 
 	// Fzf arguments
 	Arguments menu.Args `json:"arguments,omitempty" yaml:"arguments,omitempty"`
+
+	// User defined actions
+	Actions []*Action `json:"actions,omitempty" yaml:"actions,omitempty"`
 }
 
 // Header holds the header configuration for FZF.
@@ -65,6 +85,10 @@ func NewDefault() *Config {
 			ToggleAll: menu.NewKeymap().WithBind(menu.KeyCtrlA).WithDesc("toggle-all").Hide(),
 			Preview:   menu.NewKeymap().WithBind(menu.KeyCtrlSlash).WithDesc("toggle-preview").Hide(),
 			Repos:     menu.NewKeymap().WithBind(menu.KeyCtrlO).WithDesc("repos").Hide(),
+			Favorite:  menu.NewKeymap().WithBind(menu.KeyCtrlF).WithDesc("favorite"),
+			Delete:    menu.NewKeymap().WithBind(menu.KeyCtrlD).WithDesc("delete"),
+			AddTag:    menu.NewKeymap().WithBind("alt-t").WithDesc("add-tag"),
+			ChangeDB:  menu.NewKeymap().WithBind(menu.KeyCtrlB).WithDesc("change-db").Hide(),
 		},
 		Arguments: menu.NewArgsBuilder().
 			WithAnsi().
@@ -85,12 +109,46 @@ func (c *Config) Keymaps() *Keymaps {
 	return c.DefaultKeymaps
 }
 
+// IsTUI reports whether menus use the built-in terminal menu.
+func (c *Config) IsTUI() bool {
+	return c.Backend == BackendTUI
+}
+
+// ValidateBackend checks the menu backend. The built-in menu reads from
+// /dev/tty, on Windows it falls back to fzf.
+func (c *Config) ValidateBackend() error {
+	return c.validateBackend(runtime.GOOS)
+}
+
+func (c *Config) validateBackend(goos string) error {
+	switch c.Backend {
+	case "", BackendFzf:
+		return nil
+	case BackendTUI:
+		if goos == "windows" {
+			slog.Warn("menu: the tui backend is not supported on windows, using fzf")
+			c.Backend = BackendFzf
+		}
+		return nil
+	}
+
+	return fmt.Errorf("%w: %q (use %s or %s)", ErrInvalidBackend, c.Backend, BackendFzf, BackendTUI)
+}
+
 // Validate validates the menu configuration.
 func (c *Config) Validate() error {
+	if err := c.ValidateBackend(); err != nil {
+		return err
+	}
+
 	if err := c.Keymaps().Validate(); err != nil {
 		return err
 	}
 
+	if err := c.ValidateActions(); err != nil {
+		return err
+	}
+
 	// set default prompt
 	if c.Prompt == "" {
 		slog.Debug("empty prompt, loading default prompt")
@@ -111,10 +169,13 @@ func (c *Config) Validate() error {
 	return nil
 }
 
+// LoadKeymaps builds the keymaps of the main menu. The ones that change the
+// records reload the menu items when the builder has a reload command.
 func (c *Config) LoadKeymaps(kb *KeymapBuilder) []*menu.Keymap {
 	k := c.Keymaps()
-	k.Edit = kb.From(k.Edit).WithExecute("edit")
-	k.EditNotes = kb.From(k.EditNotes).WithExecute("notes edit")
+	k.setDefaults()
+	k.Edit = kb.From(k.Edit).Reload().WithExecute("edit")
+	k.EditNotes = kb.From(k.EditNotes).Reload().WithExecute("notes edit")
 	k.Open = kb.From(k.Open).WithExecute("open")
 	k.QR = kb.From(k.QR).WithExecute("qr")
 	k.OpenQR = kb.From(k.OpenQR).WithExecute("qr open")
@@ -122,6 +183,10 @@ func (c *Config) LoadKeymaps(kb *KeymapBuilder) []*menu.Keymap {
 	k.ToggleAll = kb.Builtin(k.ToggleAll, menu.KeybindActionToggleAll)
 	k.Preview = kb.Builtin(k.Preview, menu.KeybindActionTogglePreview)
 	k.Repos = kb.From(k.Repos).WithExecute("db select")
+	k.Favorite = kb.From(k.Favorite).Reload().WithExecuteSilent("edit favorite")
+	k.Delete = kb.From(k.Delete).Reload().WithExecute("rm")
+	k.AddTag = kb.From(k.AddTag).Reload().WithExecute("edit tag")
+	k.ChangeDB = kb.From(k.ChangeDB).WithBecome("db select")
 
-	return k.List()
+	return append(k.List(), c.actionKeymaps(kb)...)
 }
@@ -0,0 +1,39 @@
+package menucfg
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateBackend(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		backend string
+		goos    string
+		want    string
+		wantErr error
+	}{
+		{name: "default", backend: "", goos: "linux", want: ""},
+		{name: "fzf", backend: BackendFzf, goos: "linux", want: BackendFzf},
+		{name: "tui", backend: BackendTUI, goos: "linux", want: BackendTUI},
+		{name: "tui_on_windows", backend: BackendTUI, goos: "windows", want: BackendFzf},
+		{name: "invalid", backend: "dmenu", goos: "linux", wantErr: ErrInvalidBackend},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := &Config{Backend: tt.backend}
+			err := c.validateBackend(tt.goos)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("validateBackend() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && c.Backend != tt.want {
+				t.Errorf("validateBackend() backend = %q, want %q", c.Backend, tt.want)
+			}
+		})
+	}
+}
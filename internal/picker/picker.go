@@ -3,6 +3,8 @@ package picker
 import (
 	"errors"
 	"fmt"
+	"io"
+	"strconv"
 	"strings"
 
 	menu "github.com/mateconpizza/go-fzf"
@@ -12,12 +14,41 @@ import (
 	"github.com/mateconpizza/gm/internal/sys"
 	"github.com/mateconpizza/gm/internal/ui"
 	"github.com/mateconpizza/gm/internal/ui/formatter"
+	"github.com/mateconpizza/gm/internal/ui/preview"
 	"github.com/mateconpizza/gm/pkg/ansi"
 	"github.com/mateconpizza/gm/pkg/bookmark"
 )
 
 var ErrNoItems = errors.New("no items")
 
+// Menu is a menu run by the configured backend, fzf or the built-in TUI.
+type Menu[T comparable] struct {
+	*menu.Menu[T]
+
+	// app is set when the built-in TUI runs the menu.
+	app *application.App
+
+	actions   bool // in-place actions of the main menu
+	focusTags bool // start on the tag sidebar
+}
+
+// Select shows the menu and returns the selected items.
+func (m *Menu[T]) Select(items []T) ([]T, error) {
+	if m.app == nil {
+		return m.Menu.Select(items)
+	}
+
+	return selectItemsWithTUI(m, items)
+}
+
+// previewFormat is the output format of the bookmark preview, the rich
+// preview by default.
+var previewFormat = preview.Format
+
+// SetPreviewFormat sets the output format used by the bookmark preview
+// command.
+func SetPreviewFormat(name string) { previewFormat = name }
+
 var (
 	HeaderKeymapFmt = func(bind, sep, desc string) string {
 		return fmt.Sprintf(
@@ -33,8 +64,9 @@ var (
 	}
 )
 
-// NewMainMenu builds the interactive FZF menu for selecting records.
-func NewMainMenu(app *application.App) *menu.Menu[bookmark.Bookmark] {
+// NewMainMenu builds the interactive FZF menu for selecting records. The
+// keymaps that change the records reload the items listed for args.
+func NewMainMenu(app *application.App, args []string) *Menu[bookmark.Bookmark] {
 	if !app.Flags.Menu {
 		return nil
 	}
@@ -46,7 +78,9 @@ func NewMainMenu(app *application.App) *menu.Menu[bookmark.Bookmark] {
 	kb := menucfg.NewBindBuilder().
 		WithCommand(app.Command()).
 		WithDBName(app.DBBaseName()).
-		WithPlaceholder(p.Multi())
+		WithPlaceholder(p.Multi()).
+		WithSinglePlaceholder(p.Single()).
+		WithReload(sourceArgs(app, args)...)
 
 	builtinKeymaps := app.Menu.LoadKeymaps(kb)
 
@@ -70,11 +104,71 @@ func NewMainMenu(app *application.App) *menu.Menu[bookmark.Bookmark] {
 	m.SetFormatter(func(b bookmark.Bookmark) string {
 		return fm.Render(ui.NewConsole(), &b)
 	})
+	m.actions = true
 
 	return m
 }
 
-func NewWithFormatter(app *application.App, fm formatter.Formatter, opts ...menu.Option) *menu.Menu[bookmark.Bookmark] {
+// WriteItems writes the bookmarks as the items of the main menu.
+func WriteItems(w io.Writer, app *application.App, bs []*bookmark.Bookmark) error {
+	fm := app.Formatter()
+	sep := "\n"
+	if fm.Menu.IsMultiline() {
+		sep = "\x00"
+	}
+
+	c := ui.NewConsole()
+	for _, b := range bs {
+		if _, err := io.WriteString(w, fm.Render(c, b)+sep); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// sourceArgs returns the arguments of the menu source, which lists the
+// items of the main menu again with the same query and filters.
+func sourceArgs(app *application.App, args []string) []string {
+	f := app.Flags
+	s := []string{"--menu-source", "--output=" + app.Menu.Format}
+	if f.Color {
+		s = append(s, "--color=always")
+	}
+	if f.Private {
+		s = append(s, "--private")
+	}
+	for _, t := range f.Tags {
+		s = append(s, "--tag="+t)
+	}
+	if f.Sort != "" {
+		s = append(s, "--sort="+f.Sort)
+	}
+	if f.Head > 0 {
+		s = append(s, "--head="+strconv.Itoa(f.Head))
+	}
+	if f.Tail > 0 {
+		s = append(s, "--tail="+strconv.Itoa(f.Tail))
+	}
+	if f.By != "" {
+		s = append(s, "--by="+f.By)
+	}
+
+	return append(s, args...)
+}
+
+// NewBrowseMenu builds the main menu of the tag browsing mode. The built-in
+// TUI browses the tags in its sidebar instead.
+func NewBrowseMenu(app *application.App, args []string) *Menu[bookmark.Bookmark] {
+	m := NewMainMenu(app, args)
+	if m != nil {
+		m.focusTags = true
+	}
+
+	return m
+}
+
+func NewWithFormatter(app *application.App, fm formatter.Formatter, opts ...menu.Option) *Menu[bookmark.Bookmark] {
 	opts = append(opts, fm.Menu.Opts...)
 	m := New[bookmark.Bookmark](app, opts...)
 	m.SetFormatter(func(b bookmark.Bookmark) string {
@@ -85,7 +179,7 @@ func NewWithFormatter(app *application.App, fm formatter.Formatter, opts ...menu
 }
 
 // New builds a simpler menu without all keybindings.
-func New[T comparable](app *application.App, opts ...menu.Option) *menu.Menu[T] {
+func New[T comparable](app *application.App, opts ...menu.Option) *Menu[T] {
 	opts = append(
 		opts,
 		// appearance
@@ -117,7 +211,12 @@ func New[T comparable](app *application.App, opts ...menu.Option) *menu.Menu[T]
 		menu.WithHeaderSeparatorFmt(HeaderSeparatorFmt),
 	)
 
-	return menu.New[T](opts...)
+	m := &Menu[T]{Menu: menu.New[T](opts...)}
+	if app.Menu.IsTUI() {
+		m.app = app
+	}
+
+	return m
 }
 
 func Select[T comparable](items []T, opts ...menu.Option) ([]T, error) {
@@ -139,13 +238,7 @@ func Select[T comparable](items []T, opts ...menu.Option) ([]T, error) {
 }
 
 // BookmarkWithMenu applies menu selection to bookmarks.
-func BookmarkWithMenu(m *menu.Menu[bookmark.Bookmark], bs []*bookmark.Bookmark) ([]*bookmark.Bookmark, error) {
-	// Create copy for menu selection
-	bsCopy := make([]bookmark.Bookmark, 0, len(bs))
-	for _, b := range bs {
-		bsCopy = append(bsCopy, *b)
-	}
-
+func BookmarkWithMenu(m *Menu[bookmark.Bookmark], bs []*bookmark.Bookmark) ([]*bookmark.Bookmark, error) {
 	defFormatter := func(b bookmark.Bookmark) string {
 		return formatter.Default().Render(ui.NewConsole(), &b)
 	}
@@ -153,8 +246,18 @@ func BookmarkWithMenu(m *menu.Menu[bookmark.Bookmark], bs []*bookmark.Bookmark)
 		m.SetFormatter(defFormatter)
 	}
 
+	if m.app != nil {
+		return selectWithTUI(m, bs)
+	}
+
+	// Create copy for menu selection
+	bsCopy := make([]bookmark.Bookmark, 0, len(bs))
+	for _, b := range bs {
+		bsCopy = append(bsCopy, *b)
+	}
+
 	// Select with menu
-	items, err := selectionWithMenu(m, bsCopy, m.Formatter)
+	items, err := selectionWithMenu(m.Menu, bsCopy, m.Formatter)
 	if err != nil {
 		return nil, err
 	}
@@ -201,8 +304,9 @@ func PreviewCmd(command, dbName string, args ...string) string {
 	// color is disable, FZF will handle the color strip but keeps text styles
 	// (dim, bold, italic, etc)
 	return fmt.Sprintf(
-		"%s --preview=frame --color=always --db=%s %s",
+		"%s --preview=%s --color=always --db=%s %s",
 		command,
+		previewFormat,
 		dbName,
 		strings.Join(args, " "),
 	)
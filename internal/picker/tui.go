@@ -0,0 +1,268 @@
+package picker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	menu "github.com/mateconpizza/go-fzf"
+
+	"github.com/mateconpizza/gm/internal/application"
+	"github.com/mateconpizza/gm/internal/picker/tui"
+	"github.com/mateconpizza/gm/internal/sys"
+	"github.com/mateconpizza/gm/internal/ui"
+	"github.com/mateconpizza/gm/internal/ui/formatter"
+	"github.com/mateconpizza/gm/internal/ui/preview"
+	"github.com/mateconpizza/gm/pkg/ansi"
+	"github.com/mateconpizza/gm/pkg/bookmark"
+	"github.com/mateconpizza/gm/pkg/db"
+)
+
+// selectWithTUI selects bookmarks with the built-in TUI, the menu only
+// provides the formatter.
+func selectWithTUI(m *Menu[bookmark.Bookmark], bs []*bookmark.Bookmark) ([]*bookmark.Bookmark, error) {
+	app := m.app
+	opts := tuiOptions(app)
+	opts.Render = func(b *bookmark.Bookmark) string { return m.Formatter(*b) }
+	opts.Preview = tuiPreview(bs)
+	opts.ShowPreview = app.Menu.Preview
+	opts.Reload = tuiReload(app)
+
+	if m.actions {
+		opts.Actions = tuiActions(app)
+		opts.FocusTags = m.focusTags
+	} else {
+		opts.Header = "select record/s"
+	}
+
+	items, err := tui.Run(context.Background(), bs, opts)
+	switch {
+	case errors.Is(err, tui.ErrAborted):
+		return nil, sys.ErrActionAborted
+	case errors.Is(err, tui.ErrNoItems):
+		return nil, menu.ErrNoItems
+	case err != nil:
+		return nil, err
+	case len(items) == 0:
+		return nil, ErrNoItems
+	}
+
+	return items, nil
+}
+
+// selectItemsWithTUI selects any kind of items with the built-in TUI. Each
+// item is listed as a bookmark holding its index, imported bookmarks keep
+// their URL, title and tags, so they can be filtered by them.
+func selectItemsWithTUI[T comparable](m *Menu[T], items []T) ([]T, error) {
+	format := func(item T) string { return fmt.Sprint(item) }
+	if m.Formatter != nil {
+		format = m.Formatter
+	}
+
+	bs := make([]*bookmark.Bookmark, 0, len(items))
+	for i, item := range items {
+		b := &bookmark.Bookmark{}
+		if ib, ok := any(item).(*bookmark.Bookmark); ok && ib != nil {
+			b = ib.Copy()
+		}
+		b.ID = i + 1
+		bs = append(bs, b)
+	}
+
+	opts := tuiOptions(m.app)
+	opts.Render = func(b *bookmark.Bookmark) string { return format(items[b.ID-1]) }
+
+	selected, err := tui.Run(context.Background(), bs, opts)
+	switch {
+	case errors.Is(err, tui.ErrAborted):
+		return nil, menu.ErrActionAborted
+	case errors.Is(err, tui.ErrNoItems):
+		return nil, menu.ErrNoItems
+	case err != nil:
+		return nil, err
+	}
+
+	result := make([]T, 0, len(selected))
+	for _, b := range selected {
+		result = append(result, items[b.ID-1])
+	}
+
+	return result, nil
+}
+
+// tuiOptions returns the options shared by every menu of the built-in TUI.
+func tuiOptions(app *application.App) *tui.Options {
+	k := app.Menu.Keymaps()
+	opts := &tui.Options{
+		Prompt: app.Menu.Prompt,
+		Multi:  true,
+	}
+	if k.ToggleAll != nil && k.ToggleAll.IsEnabled() {
+		opts.ToggleAll = tui.Key(k.ToggleAll.Bind)
+	}
+	if k.Preview != nil && k.Preview.IsEnabled() {
+		opts.TogglePreview = tui.Key(k.Preview.Bind)
+	}
+
+	return opts
+}
+
+// tuiActions returns the in-place actions of the main menu, bound to the
+// keys of the menu config, user defined actions included.
+func tuiActions(app *application.App) []*tui.Action {
+	k := app.Menu.Keymaps()
+
+	var actions []*tui.Action
+	add := func(km *menu.Keymap, args ...string) {
+		if km == nil || !km.IsEnabled() || km.Bind == "" {
+			return
+		}
+		actions = append(actions, &tui.Action{
+			Key:  tui.Key(km.Bind),
+			Desc: km.Desc,
+			Exec: true,
+			Run:  tuiExec(app, args...),
+		})
+	}
+
+	add(k.Open, "open")
+	add(k.Edit, "edit")
+	add(k.EditNotes, "notes", "edit")
+	add(k.Yank, "yank")
+	add(k.QR, "qr")
+	add(k.OpenQR, "qr", "open")
+	add(k.Delete, "rm")
+	add(k.AddTag, "edit", "tag")
+
+	// toggled without suspending the menu
+	if km := k.Favorite; km != nil && km.IsEnabled() && km.Bind != "" {
+		actions = append(actions, &tui.Action{
+			Key:  tui.Key(km.Bind),
+			Desc: km.Desc,
+			Run:  tuiExecSilent(app, "edit", "favorite"),
+		})
+	}
+
+	for _, a := range app.Menu.Actions {
+		if a.Bind == "" {
+			continue
+		}
+		actions = append(actions, &tui.Action{
+			Key:    tui.Key(a.Bind),
+			Desc:   a.Description(),
+			Exec:   true,
+			Single: !a.Multi,
+			Run:    tuiExec(app, "run", a.Name),
+		})
+	}
+
+	return actions
+}
+
+// tuiExec runs a subcommand on the bookmarks, the same way the fzf
+// keybinds do, with the running executable.
+func tuiExec(app *application.App, args ...string) func(context.Context, []*bookmark.Bookmark) error {
+	return func(ctx context.Context, bs []*bookmark.Bookmark) error {
+		cmd := tuiCommand(ctx, app, bs, args...)
+		cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+		if tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0); err == nil {
+			defer tty.Close()
+			cmd.Stdin, cmd.Stdout, cmd.Stderr = tty, tty, tty
+		}
+
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("%s: %w", args[0], err)
+		}
+
+		return nil
+	}
+}
+
+// tuiExecSilent runs a subcommand on the bookmarks without the terminal,
+// its output is only shown when it fails.
+func tuiExecSilent(app *application.App, args ...string) func(context.Context, []*bookmark.Bookmark) error {
+	return func(ctx context.Context, bs []*bookmark.Bookmark) error {
+		out, err := tuiCommand(ctx, app, bs, args...).CombinedOutput()
+		if err != nil {
+			if msg := strings.TrimSpace(ansi.Remover(string(out))); msg != "" {
+				return fmt.Errorf("%s: %s", args[0], msg)
+			}
+			return fmt.Errorf("%s: %w", args[0], err)
+		}
+
+		return nil
+	}
+}
+
+// tuiCommand returns the command running args on the bookmarks.
+func tuiCommand(ctx context.Context, app *application.App, bs []*bookmark.Bookmark, args ...string) *exec.Cmd {
+	argv := append([]string{"--db=" + app.DBBaseName()}, args...)
+	for _, b := range bs {
+		argv = append(argv, strconv.Itoa(b.ID))
+	}
+
+	bin, err := os.Executable()
+	if err != nil {
+		bin = app.Command()
+	}
+
+	return exec.CommandContext(ctx, bin, argv...)
+}
+
+// tuiReload fetches the bookmarks again, dropping the deleted ones.
+func tuiReload(app *application.App) func(context.Context, []*bookmark.Bookmark) ([]*bookmark.Bookmark, error) {
+	return func(ctx context.Context, bs []*bookmark.Bookmark) ([]*bookmark.Bookmark, error) {
+		r, err := db.New(ctx, app.Path.DB())
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+
+		fresh := make([]*bookmark.Bookmark, 0, len(bs))
+		for _, b := range bs {
+			nb, err := r.ByID(ctx, b.ID)
+			if errors.Is(err, db.ErrRecordNotFound) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			fresh = append(fresh, nb)
+		}
+
+		return fresh, nil
+	}
+}
+
+// tuiPreview renders the preview pane with the preview format, the rich
+// preview relates the bookmark to the listed ones and draws no images.
+func tuiPreview(bs []*bookmark.Bookmark) func(*bookmark.Bookmark, int) string {
+	c := ui.NewConsole()
+	if previewFormat == preview.Format {
+		return func(b *bookmark.Bookmark, width int) string {
+			return preview.Render(&paneConsole{Console: c, width: width}, b, bs, preview.None)
+		}
+	}
+
+	fm, err := formatter.New(formatter.Format(previewFormat))
+	if err != nil {
+		fm = formatter.Default()
+	}
+
+	return func(b *bookmark.Bookmark, width int) string {
+		return fm.Render(&paneConsole{Console: c, width: width}, b)
+	}
+}
+
+// paneConsole renders to the width of the preview pane.
+type paneConsole struct {
+	*ui.Console
+	width int
+}
+
+func (c *paneConsole) MaxWidth() int { return c.width }
+func (c *paneConsole) MinWidth() int { return min(c.Console.MinWidth(), c.width) }
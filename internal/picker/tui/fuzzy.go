@@ -0,0 +1,73 @@
+package tui
+
+import (
+	"strings"
+	"unicode"
+)
+
+// match scores text against the space separated terms of query, every
+// term must match. A term matches as a substring or else as a fuzzy
+// subsequence, substrings always rank higher. Terms are case-insensitive
+// unless they hold an uppercase letter.
+func match(query, text string) (int, bool) {
+	lower := strings.ToLower(text)
+
+	score := 0
+	for _, term := range strings.Fields(query) {
+		s := lower
+		if hasUpper(term) {
+			s = text
+		}
+
+		n, ok := matchTerm(term, s)
+		if !ok {
+			return 0, false
+		}
+		score += n
+	}
+
+	return score, true
+}
+
+// matchTerm scores a single term against s.
+func matchTerm(term, s string) (int, bool) {
+	if i := strings.Index(s, term); i >= 0 {
+		return 100*len(term) - min(i, 99), true
+	}
+
+	tr := []rune(term)
+	score, ti := 0, 0
+	prev, matched := ' ', false
+	for _, r := range s {
+		if ti == len(tr) {
+			break
+		}
+		if r != tr[ti] {
+			prev, matched = r, false
+			continue
+		}
+
+		score++
+		if matched {
+			score += 5
+		}
+		if !unicode.IsLetter(prev) && !unicode.IsDigit(prev) {
+			score += 3
+		}
+
+		ti++
+		prev, matched = r, true
+	}
+
+	return score, ti == len(tr)
+}
+
+func hasUpper(s string) bool {
+	for _, r := range s {
+		if unicode.IsUpper(r) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,126 @@
+package tui
+
+import (
+	"unicode/utf8"
+)
+
+// Key is a key press. Named keys use the fzf names, e.g. "ctrl-e",
+// "enter" or "alt-d", so keymaps from the menu config apply as is. A
+// printable character is the character itself.
+type Key string
+
+const (
+	KeyEnter     Key = "enter"
+	KeyEsc       Key = "esc"
+	KeyTab       Key = "tab"
+	KeyBTab      Key = "btab"
+	KeyBackspace Key = "bspace"
+	KeyUp        Key = "up"
+	KeyDown      Key = "down"
+	KeyLeft      Key = "left"
+	KeyRight     Key = "right"
+	KeyHome      Key = "home"
+	KeyEnd       Key = "end"
+	KeyPageUp    Key = "page-up"
+	KeyPageDown  Key = "page-down"
+	KeyDelete    Key = "del"
+	KeyCtrlC     Key = "ctrl-c"
+	KeyCtrlN     Key = "ctrl-n"
+	KeyCtrlP     Key = "ctrl-p"
+	KeyCtrlT     Key = "ctrl-t"
+	KeyCtrlU     Key = "ctrl-u"
+	KeyCtrlSlash Key = "ctrl-/"
+)
+
+// escapes maps the escape sequences sent by terminals to keys.
+var escapes = map[string]Key{
+	"[A": KeyUp, "[B": KeyDown, "[C": KeyRight, "[D": KeyLeft,
+	"OA": KeyUp, "OB": KeyDown, "OC": KeyRight, "OD": KeyLeft,
+	"[H": KeyHome, "[F": KeyEnd, "OH": KeyHome, "OF": KeyEnd,
+	"[1~": KeyHome, "[4~": KeyEnd, "[7~": KeyHome, "[8~": KeyEnd,
+	"[3~": KeyDelete, "[5~": KeyPageUp, "[6~": KeyPageDown,
+	"[Z": KeyBTab,
+}
+
+// IsPrintable reports whether the key is a character to insert in the
+// query.
+func (k Key) IsPrintable() bool {
+	return utf8.RuneCountInString(string(k)) == 1
+}
+
+// parseKeys splits the bytes read from the terminal into keys. A read
+// holds a single key most of the time, several when text is pasted.
+func parseKeys(b []byte) []Key {
+	var keys []Key
+	for len(b) > 0 {
+		k, n := parseKey(b)
+		if k != "" {
+			keys = append(keys, k)
+		}
+		b = b[n:]
+	}
+
+	return keys
+}
+
+// parseKey returns the first key in b and the number of bytes it takes.
+func parseKey(b []byte) (Key, int) {
+	c := b[0]
+	switch {
+	case c == 0x1b:
+		return parseEscape(b)
+	case c == '\r' || c == '\n':
+		return KeyEnter, 1
+	case c == '\t':
+		return KeyTab, 1
+	case c == 0x7f || c == 0x08:
+		return KeyBackspace, 1
+	case c == 0x1f:
+		return KeyCtrlSlash, 1
+	case c == 0x00:
+		return "ctrl-space", 1
+	case c < 0x1b:
+		return Key("ctrl-" + string(rune('a'+c-1))), 1
+	case c < 0x20:
+		return "", 1
+	}
+
+	r, n := utf8.DecodeRune(b)
+	if r == utf8.RuneError {
+		return "", n
+	}
+
+	return Key(string(r)), n
+}
+
+// parseEscape parses a key starting with ESC: an arrow or function key, an
+// alt combination, or ESC itself.
+func parseEscape(b []byte) (Key, int) {
+	if len(b) == 1 {
+		return KeyEsc, 1
+	}
+
+	if b[1] == '[' || b[1] == 'O' {
+		// CSI/SS3: parameters, then a final byte in 0x40..0x7e.
+		for i := 2; i < len(b); i++ {
+			if b[i] >= 0x40 && b[i] <= 0x7e {
+				if k, ok := escapes[string(b[1:i+1])]; ok {
+					return k, i + 1
+				}
+				return "", i + 1
+			}
+		}
+		return "", len(b)
+	}
+
+	if b[1] == 0x1b {
+		return KeyEsc, 1
+	}
+
+	k, n := parseKey(b[1:])
+	if k == "" || k == KeyEsc {
+		return KeyEsc, 1
+	}
+
+	return "alt-" + k, n + 1
+}
@@ -0,0 +1,71 @@
+package tui
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseKeys(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		in   string
+		want []Key
+	}{
+		{"printable", "ab", []Key{"a", "b"}},
+		{"unicode", "ñ", []Key{"ñ"}},
+		{"enter", "\r", []Key{KeyEnter}},
+		{"tab", "\t", []Key{KeyTab}},
+		{"backspace", "\x7f", []Key{KeyBackspace}},
+		{"ctrl", "\x05\x19", []Key{"ctrl-e", "ctrl-y"}},
+		{"ctrl slash", "\x1f", []Key{KeyCtrlSlash}},
+		{"esc", "\x1b", []Key{KeyEsc}},
+		{"arrows", "\x1b[A\x1b[B", []Key{KeyUp, KeyDown}},
+		{"ss3 arrows", "\x1bOA", []Key{KeyUp}},
+		{"pages", "\x1b[5~\x1b[6~", []Key{KeyPageUp, KeyPageDown}},
+		{"shift tab", "\x1b[Z", []Key{KeyBTab}},
+		{"alt", "\x1bd", []Key{"alt-d"}},
+		{"unknown csi", "\x1b[99z", nil},
+		{"mixed", "go\x1b[Bx", []Key{"g", "o", KeyDown, "x"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := parseKeys([]byte(tt.in)); !slices.Equal(got, tt.want) {
+				t.Errorf("parseKeys(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatch(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		query, text string
+		want        bool
+	}{
+		{"", "anything", true},
+		{"go", "Golang docs", true},
+		{"gdc", "golang docs", true},
+		{"go docs", "golang docs", true},
+		{"go rust", "golang docs", false},
+		{"Go", "golang docs", false},
+		{"Go", "Golang docs", true},
+		{"xyz", "golang docs", false},
+	}
+
+	for _, tt := range tests {
+		if _, got := match(tt.query, tt.text); got != tt.want {
+			t.Errorf("match(%q, %q) = %v, want %v", tt.query, tt.text, got, tt.want)
+		}
+	}
+
+	sub, _ := match("doc", "golang docs")
+	fuzzy, _ := match("doc", "download cache")
+	if sub <= fuzzy {
+		t.Errorf("expected substring to score higher: %d <= %d", sub, fuzzy)
+	}
+}
@@ -0,0 +1,385 @@
+package tui
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/mateconpizza/gm/pkg/ansi"
+	"github.com/mateconpizza/gm/pkg/bookmark"
+)
+
+// item is a bookmark in the list.
+type item struct {
+	b     *bookmark.Bookmark
+	line  string // rendered list line
+	text  string // plain text the query is matched against
+	tags  []string
+	score int
+}
+
+// tagCount is a tag in the sidebar.
+type tagCount struct {
+	name  string
+	count int
+}
+
+// outcome tells the loop what to do after a key.
+type outcome int
+
+const (
+	stay outcome = iota
+	accept
+	abort
+	runAction
+)
+
+// model holds the state of the TUI, it knows nothing about the terminal.
+type model struct {
+	opts *Options
+
+	items   []*item
+	matches []*item
+	query   []rune
+
+	cursor int // index in matches
+	offset int // first visible match
+	height int // visible list rows
+
+	selected map[int]bool // bookmark IDs
+
+	tags       []tagCount
+	activeTags map[string]bool
+	tagCursor  int
+	showTags   bool
+	focusTags  bool
+
+	showPreview bool
+	preview     *preview
+	status      string
+}
+
+func newModel(bs []*bookmark.Bookmark, opts *Options) *model {
+	m := &model{
+		opts:        opts,
+		selected:    make(map[int]bool),
+		activeTags:  make(map[string]bool),
+		showPreview: opts.Preview != nil && opts.ShowPreview,
+		showTags:    opts.ShowTags || opts.FocusTags,
+		focusTags:   opts.FocusTags,
+		height:      1,
+	}
+	m.setItems(bs)
+
+	return m
+}
+
+// setItems replaces the bookmarks, keeping the cursor on the same one
+// when it is still there.
+func (m *model) setItems(bs []*bookmark.Bookmark) {
+	current := m.current()
+
+	m.items = make([]*item, 0, len(bs))
+	ids := make(map[int]bool, len(bs))
+	for _, b := range bs {
+		it := &item{b: b, line: strings.ReplaceAll(m.opts.Render(b), "\n", " ")}
+		for t := range strings.SplitSeq(b.Tags, ",") {
+			if t != "" {
+				it.tags = append(it.tags, t)
+			}
+		}
+		it.text = strings.Join([]string{ansi.Remover(it.line), b.URL, b.Title, b.Tags}, " ")
+		m.items = append(m.items, it)
+		ids[b.ID] = true
+	}
+
+	for id := range m.selected {
+		if !ids[id] {
+			delete(m.selected, id)
+		}
+	}
+
+	m.preview = nil
+	m.countTags()
+	m.filter()
+
+	if current != nil {
+		for i, it := range m.matches {
+			if it.b.ID == current.b.ID {
+				m.cursor = i
+				break
+			}
+		}
+	}
+	m.clampCursor()
+}
+
+// countTags builds the sidebar, most used tags first.
+func (m *model) countTags() {
+	counts := make(map[string]int)
+	for _, it := range m.items {
+		for _, t := range it.tags {
+			counts[t]++
+		}
+	}
+
+	m.tags = m.tags[:0]
+	for name, n := range counts {
+		m.tags = append(m.tags, tagCount{name: name, count: n})
+	}
+	slices.SortFunc(m.tags, func(a, b tagCount) int {
+		if c := cmp.Compare(b.count, a.count); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.name, b.name)
+	})
+
+	for t := range m.activeTags {
+		if counts[t] == 0 {
+			delete(m.activeTags, t)
+		}
+	}
+	m.tagCursor = min(m.tagCursor, max(len(m.tags)-1, 0))
+}
+
+// filter applies the query and the active tags. With a query the matches
+// are sorted by score, otherwise they keep the input order.
+func (m *model) filter() {
+	q := string(m.query)
+	m.matches = m.matches[:0]
+	for _, it := range m.items {
+		if !m.hasActiveTags(it) {
+			continue
+		}
+
+		score, ok := match(q, it.text)
+		if !ok {
+			continue
+		}
+		it.score = score
+		m.matches = append(m.matches, it)
+	}
+
+	if strings.TrimSpace(q) != "" {
+		slices.SortStableFunc(m.matches, func(a, b *item) int {
+			return cmp.Compare(b.score, a.score)
+		})
+	}
+
+	m.cursor, m.offset = 0, 0
+}
+
+func (m *model) hasActiveTags(it *item) bool {
+	for t := range m.activeTags {
+		if !slices.Contains(it.tags, t) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// current returns the item under the cursor.
+func (m *model) current() *item {
+	if m.cursor < 0 || m.cursor >= len(m.matches) {
+		return nil
+	}
+
+	return m.matches[m.cursor]
+}
+
+// targets returns the selected bookmarks in list order, or the one under
+// the cursor when none is selected.
+func (m *model) targets() []*bookmark.Bookmark {
+	var bs []*bookmark.Bookmark
+	for _, it := range m.items {
+		if m.selected[it.b.ID] {
+			bs = append(bs, it.b)
+		}
+	}
+
+	if len(bs) == 0 {
+		if it := m.current(); it != nil {
+			bs = append(bs, it.b)
+		}
+	}
+
+	return bs
+}
+
+// actionTargets returns the bookmarks the action runs on.
+func (m *model) actionTargets(a *Action) []*bookmark.Bookmark {
+	if !a.Single {
+		return m.targets()
+	}
+
+	if it := m.current(); it != nil {
+		return []*bookmark.Bookmark{it.b}
+	}
+
+	return nil
+}
+
+func (m *model) moveCursor(n int) {
+	m.cursor += n
+	m.clampCursor()
+}
+
+func (m *model) clampCursor() {
+	m.cursor = max(min(m.cursor, len(m.matches)-1), 0)
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+m.height {
+		m.offset = m.cursor - m.height + 1
+	}
+	m.offset = max(m.offset, 0)
+}
+
+func (m *model) toggleSelected(it *item) {
+	if it == nil || !m.opts.Multi {
+		return
+	}
+
+	if m.selected[it.b.ID] {
+		delete(m.selected, it.b.ID)
+		return
+	}
+	m.selected[it.b.ID] = true
+}
+
+// toggleAll selects every match, or clears the selection when all of
+// them are already selected.
+func (m *model) toggleAll() {
+	if !m.opts.Multi {
+		return
+	}
+
+	all := true
+	for _, it := range m.matches {
+		if !m.selected[it.b.ID] {
+			all = false
+			break
+		}
+	}
+
+	for _, it := range m.matches {
+		if all {
+			delete(m.selected, it.b.ID)
+		} else {
+			m.selected[it.b.ID] = true
+		}
+	}
+}
+
+func (m *model) toggleTag() {
+	if len(m.tags) == 0 {
+		return
+	}
+
+	t := m.tags[m.tagCursor].name
+	if m.activeTags[t] {
+		delete(m.activeTags, t)
+	} else {
+		m.activeTags[t] = true
+	}
+	m.filter()
+}
+
+// action returns the action bound to k.
+func (m *model) action(k Key) *Action {
+	for _, a := range m.opts.Actions {
+		if a.Key == k {
+			return a
+		}
+	}
+
+	return nil
+}
+
+// handle updates the model with a key press.
+func (m *model) handle(k Key) (outcome, *Action) {
+	m.status = ""
+
+	if m.focusTags {
+		if m.handleTags(k) {
+			return stay, nil
+		}
+	}
+
+	if a := m.action(k); a != nil {
+		if len(m.actionTargets(a)) == 0 {
+			return stay, nil
+		}
+		return runAction, a
+	}
+
+	switch k {
+	case KeyEnter:
+		if len(m.targets()) == 0 {
+			return stay, nil
+		}
+		return accept, nil
+	case KeyEsc, KeyCtrlC, "ctrl-g", "ctrl-q":
+		return abort, nil
+	case KeyUp, KeyCtrlP, "ctrl-k":
+		m.moveCursor(-1)
+	case KeyDown, KeyCtrlN, "ctrl-j":
+		m.moveCursor(1)
+	case KeyPageUp:
+		m.moveCursor(-m.height)
+	case KeyPageDown:
+		m.moveCursor(m.height)
+	case KeyHome:
+		m.moveCursor(-len(m.matches))
+	case KeyEnd:
+		m.moveCursor(len(m.matches))
+	case KeyTab:
+		m.toggleSelected(m.current())
+		m.moveCursor(1)
+	case KeyBTab:
+		m.toggleSelected(m.current())
+		m.moveCursor(-1)
+	case m.opts.ToggleAll:
+		m.toggleAll()
+	case m.opts.TogglePreview:
+		m.showPreview = m.opts.Preview != nil && !m.showPreview
+	case KeyCtrlT:
+		m.focusTags = !m.focusTags
+		m.showTags = m.showTags || m.focusTags
+	case KeyBackspace:
+		if len(m.query) > 0 {
+			m.query = m.query[:len(m.query)-1]
+			m.filter()
+		}
+	case KeyCtrlU:
+		m.query = m.query[:0]
+		m.filter()
+	default:
+		if k.IsPrintable() {
+			m.query = append(m.query, []rune(string(k))...)
+			m.filter()
+		}
+	}
+
+	return stay, nil
+}
+
+// handleTags handles the keys of the focused sidebar, reports false for
+// the keys it leaves to the list.
+func (m *model) handleTags(k Key) bool {
+	switch k {
+	case KeyUp, KeyCtrlP, "ctrl-k":
+		m.tagCursor = max(m.tagCursor-1, 0)
+	case KeyDown, KeyCtrlN, "ctrl-j":
+		m.tagCursor = max(min(m.tagCursor+1, len(m.tags)-1), 0)
+	case KeyEnter, KeyTab, " ":
+		m.toggleTag()
+	case KeyEsc, KeyCtrlT, KeyRight:
+		m.focusTags = false
+	default:
+		return false
+	}
+
+	return true
+}
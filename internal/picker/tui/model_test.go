@@ -0,0 +1,253 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	runewidth "github.com/mattn/go-runewidth"
+
+	"github.com/mateconpizza/gm/pkg/ansi"
+	"github.com/mateconpizza/gm/pkg/bookmark"
+)
+
+func testBookmarks() []*bookmark.Bookmark {
+	return []*bookmark.Bookmark{
+		{ID: 1, URL: "https://go.dev", Title: "Go", Tags: "go,lang,"},
+		{ID: 2, URL: "https://rust-lang.org", Title: "Rust", Tags: "lang,rust,"},
+		{ID: 3, URL: "https://pkg.go.dev", Title: "Go packages", Tags: "docs,go,"},
+	}
+}
+
+func testModel(actions ...*Action) *model {
+	return newModel(testBookmarks(), &Options{
+		Prompt:        "> ",
+		Render:        func(b *bookmark.Bookmark) string { return fmt.Sprintf("%d %s", b.ID, b.URL) },
+		Preview:       func(b *bookmark.Bookmark, _ int) string { return "preview " + b.Title },
+		ShowPreview:   true,
+		Multi:         true,
+		ToggleAll:     "ctrl-a",
+		TogglePreview: KeyCtrlSlash,
+		Actions:       actions,
+	})
+}
+
+func ids(bs []*bookmark.Bookmark) []int {
+	out := make([]int, 0, len(bs))
+	for _, b := range bs {
+		out = append(out, b.ID)
+	}
+
+	return out
+}
+
+func matchIDs(m *model) []int {
+	out := make([]int, 0, len(m.matches))
+	for _, it := range m.matches {
+		out = append(out, it.b.ID)
+	}
+
+	return out
+}
+
+func typeKeys(m *model, keys ...Key) {
+	for _, k := range keys {
+		m.handle(k)
+	}
+}
+
+func TestModelFilter(t *testing.T) {
+	t.Parallel()
+
+	m := testModel()
+	if got := matchIDs(m); !slices.Equal(got, []int{1, 2, 3}) {
+		t.Fatalf("expected all items, got %v", got)
+	}
+
+	typeKeys(m, "r", "u", "s", "t")
+	if got := matchIDs(m); !slices.Equal(got, []int{2}) {
+		t.Errorf("expected [2], got %v", got)
+	}
+
+	typeKeys(m, KeyCtrlU, "p", "k", "g")
+	if got := matchIDs(m); !slices.Equal(got, []int{3}) {
+		t.Errorf("expected [3], got %v", got)
+	}
+
+	typeKeys(m, KeyBackspace, KeyBackspace, KeyBackspace)
+	if got := matchIDs(m); len(got) != 3 {
+		t.Errorf("expected all items after clearing the query, got %v", got)
+	}
+}
+
+func TestModelTags(t *testing.T) {
+	t.Parallel()
+
+	m := testModel()
+	if m.tags[0].name != "go" && m.tags[0].name != "lang" {
+		t.Fatalf("expected most used tag first, got %v", m.tags)
+	}
+
+	// focus the sidebar and activate "go"
+	typeKeys(m, KeyCtrlT)
+	for m.tags[m.tagCursor].name != "go" {
+		typeKeys(m, KeyDown)
+	}
+	typeKeys(m, KeyEnter)
+	if got := matchIDs(m); !slices.Equal(got, []int{1, 3}) {
+		t.Errorf("expected bookmarks tagged go, got %v", got)
+	}
+
+	// leave the sidebar, typed keys filter the list again
+	typeKeys(m, KeyEsc, "p", "k", "g")
+	if m.focusTags {
+		t.Error("expected sidebar to lose focus")
+	}
+	if got := matchIDs(m); !slices.Equal(got, []int{3}) {
+		t.Errorf("expected [3], got %v", got)
+	}
+}
+
+func TestModelSelection(t *testing.T) {
+	t.Parallel()
+
+	m := testModel()
+	if got := ids(m.targets()); !slices.Equal(got, []int{1}) {
+		t.Errorf("expected item under cursor, got %v", got)
+	}
+
+	typeKeys(m, KeyTab, KeyDown)
+	typeKeys(m, KeyTab)
+	if got := ids(m.targets()); !slices.Equal(got, []int{1, 3}) {
+		t.Errorf("expected selected items, got %v", got)
+	}
+
+	out, _ := m.handle(KeyEnter)
+	if out != accept {
+		t.Errorf("expected enter to accept, got %v", out)
+	}
+
+	typeKeys(m, "ctrl-a")
+	if len(m.selected) != 3 {
+		t.Errorf("expected all selected, got %v", m.selected)
+	}
+	typeKeys(m, "ctrl-a")
+	if len(m.selected) != 0 {
+		t.Errorf("expected none selected, got %v", m.selected)
+	}
+
+	if out, _ := m.handle(KeyEsc); out != abort {
+		t.Errorf("expected esc to abort, got %v", out)
+	}
+}
+
+func TestModelActions(t *testing.T) {
+	t.Parallel()
+
+	var got []int
+	a := &Action{
+		Key:  "ctrl-e",
+		Desc: "edit",
+		Run: func(_ context.Context, bs []*bookmark.Bookmark) error {
+			got = ids(bs)
+			return nil
+		},
+	}
+
+	m := testModel(a)
+	m.opts.Reload = func(_ context.Context, bs []*bookmark.Bookmark) ([]*bookmark.Bookmark, error) {
+		return bs[1:], nil // the first one was deleted
+	}
+
+	typeKeys(m, KeyDown)
+	out, act := m.handle("ctrl-e")
+	if out != runAction || act != a {
+		t.Fatalf("expected action, got %v %v", out, act)
+	}
+
+	tm := &terminal{}
+	if err := tm.run(t.Context(), m, act); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(got, []int{2}) {
+		t.Errorf("expected action on [2], got %v", got)
+	}
+	if got := matchIDs(m); !slices.Equal(got, []int{2, 3}) {
+		t.Errorf("expected reloaded items, got %v", got)
+	}
+	if cur := m.current(); cur == nil || cur.b.ID != 2 {
+		t.Errorf("expected cursor to stay on [2], got %v", cur)
+	}
+}
+
+func TestModelActionSingle(t *testing.T) {
+	t.Parallel()
+
+	single := &Action{Key: "alt-m", Single: true}
+	multi := &Action{Key: "alt-n"}
+	m := testModel(single, multi)
+
+	typeKeys(m, KeyTab, KeyTab)
+	if got := ids(m.actionTargets(multi)); !slices.Equal(got, []int{1, 2}) {
+		t.Errorf("expected selected items, got %v", got)
+	}
+	if got := ids(m.actionTargets(single)); !slices.Equal(got, []int{3}) {
+		t.Errorf("expected item under cursor, got %v", got)
+	}
+}
+
+func TestModelView(t *testing.T) {
+	t.Parallel()
+
+	const w, h = 100, 10
+	m := testModel()
+	m.showTags = true
+
+	rows := m.view(w, h)
+	if len(rows) != h {
+		t.Fatalf("expected %d rows, got %d", h, len(rows))
+	}
+
+	for i, r := range rows {
+		if got := runewidth.StringWidth(ansi.Remover(r)); got != w {
+			t.Errorf("row %d: expected width %d, got %d: %q", i, w, got, r)
+		}
+	}
+
+	screen := ansi.Remover(strings.Join(rows, "\n"))
+	for _, want := range []string{"3/3", "https://go.dev", "preview Go", "tags", "esc quit"} {
+		if !strings.Contains(screen, want) {
+			t.Errorf("expected %q on screen:\n%s", want, screen)
+		}
+	}
+
+	// narrow terminals show the preview below the list
+	rows = m.view(60, h)
+	if len(rows) != h {
+		t.Fatalf("expected %d rows, got %d", h, len(rows))
+	}
+}
+
+func TestFit(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in   string
+		w    int
+		want string
+	}{
+		{"abc", 5, "abc  "},
+		{"abcdef", 3, "abc"},
+		{"日本語", 4, "日本"},
+		{"\x1b[1mbold\x1b[0m", 2, "bo"},
+	}
+
+	for _, tt := range tests {
+		got := fit(tt.in, tt.w)
+		if plain := ansi.Remover(got); plain != tt.want {
+			t.Errorf("fit(%q, %d) = %q, want %q", tt.in, tt.w, plain, tt.want)
+		}
+	}
+}
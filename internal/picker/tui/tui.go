@@ -0,0 +1,261 @@
+// Package tui is a built-in terminal menu to select bookmarks, an
+// alternative to fzf. It lists the bookmarks with a fuzzy filter, a
+// preview pane and a tag sidebar, and runs actions on them in place.
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/term"
+
+	"github.com/mateconpizza/gm/pkg/bookmark"
+)
+
+var (
+	ErrAborted = errors.New("selection aborted")
+	ErrNoTTY   = errors.New("no terminal available")
+	ErrNoItems = errors.New("no items")
+)
+
+// Terminal control sequences.
+const (
+	altScreenOn  = "\x1b[?1049h"
+	altScreenOff = "\x1b[?1049l"
+	cursorHide   = "\x1b[?25l"
+	cursorShow   = "\x1b[?25h"
+	cursorHome   = "\x1b[H"
+)
+
+// pollInterval is how often the terminal size is checked while waiting
+// for keys.
+const pollInterval = 200 * time.Millisecond
+
+// Action runs on the selected bookmarks, or the one under the cursor,
+// without leaving the menu.
+type Action struct {
+	Key  Key
+	Desc string
+
+	// Exec suspends the menu while Run uses the terminal.
+	Exec bool
+
+	// Single runs the action on the bookmark under the cursor only.
+	Single bool
+
+	Run func(ctx context.Context, bs []*bookmark.Bookmark) error
+}
+
+// Options configure the menu.
+type Options struct {
+	Prompt string
+	Header string
+
+	// Render returns the list line of a bookmark.
+	Render func(b *bookmark.Bookmark) string
+
+	// Preview returns the preview of a bookmark for the given width, nil
+	// disables the preview pane.
+	Preview     func(b *bookmark.Bookmark, width int) string
+	ShowPreview bool
+
+	ShowTags  bool
+	FocusTags bool // start with the tag sidebar focused
+	Multi     bool
+
+	ToggleAll     Key
+	TogglePreview Key
+
+	Actions []*Action
+
+	// Reload fetches the bookmarks again after an action ran.
+	Reload func(ctx context.Context, bs []*bookmark.Bookmark) ([]*bookmark.Bookmark, error)
+}
+
+// Run shows the menu and returns the selected bookmarks, or the one under
+// the cursor when none was selected. ErrAborted is returned when the user
+// quits.
+func Run(ctx context.Context, bs []*bookmark.Bookmark, opts *Options) ([]*bookmark.Bookmark, error) {
+	if len(bs) == 0 {
+		return nil, ErrNoItems
+	}
+
+	t, err := openTerminal()
+	if err != nil {
+		return nil, err
+	}
+	defer t.close()
+
+	m := newModel(bs, opts)
+
+	return loop(ctx, t, m)
+}
+
+func loop(ctx context.Context, t *terminal, m *model) ([]*bookmark.Bookmark, error) {
+	buf := make([]byte, 4096)
+	t.draw(m)
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		n, err := t.read(buf)
+		if errors.Is(err, os.ErrDeadlineExceeded) {
+			if t.resized() {
+				t.draw(m)
+			}
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		for _, k := range parseKeys(buf[:n]) {
+			out, a := m.handle(k)
+			switch out {
+			case accept:
+				return m.targets(), nil
+			case abort:
+				return nil, ErrAborted
+			case runAction:
+				if err := t.run(ctx, m, a); err != nil {
+					m.status = err.Error()
+				}
+			}
+		}
+
+		t.draw(m)
+	}
+}
+
+// terminal is the controlling terminal in raw mode, drawn on the
+// alternate screen so the previous content comes back on exit.
+type terminal struct {
+	tty    *os.File
+	state  *term.State
+	width  int
+	height int
+}
+
+func openTerminal() (*terminal, error) {
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrNoTTY, err)
+	}
+
+	t := &terminal{tty: tty}
+	if err := t.start(); err != nil {
+		tty.Close()
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// control runs fn with the file descriptor of the terminal. Unlike Fd, it
+// keeps the file in non-blocking mode, so reads can time out.
+func (t *terminal) control(fn func(fd int)) error {
+	rc, err := t.tty.SyscallConn()
+	if err != nil {
+		return err
+	}
+
+	return rc.Control(func(fd uintptr) { fn(int(fd)) })
+}
+
+// start switches to raw mode and the alternate screen.
+func (t *terminal) start() error {
+	var err error
+	if cerr := t.control(func(fd int) { t.state, err = term.MakeRaw(fd) }); cerr != nil {
+		return fmt.Errorf("%w: %w", ErrNoTTY, cerr)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrNoTTY, err)
+	}
+
+	t.resized()
+	_, err = io.WriteString(t.tty, altScreenOn+cursorHide)
+
+	return err
+}
+
+// stop restores the terminal as it was before start.
+func (t *terminal) stop() {
+	_, _ = io.WriteString(t.tty, cursorShow+altScreenOff)
+	if t.state != nil {
+		_ = t.control(func(fd int) { _ = term.Restore(fd, t.state) })
+	}
+}
+
+func (t *terminal) close() {
+	t.stop()
+	t.tty.Close()
+}
+
+// read waits for keys until the poll interval expires. Terminals that do
+// not support deadlines block until a key is pressed.
+func (t *terminal) read(buf []byte) (int, error) {
+	_ = t.tty.SetReadDeadline(time.Now().Add(pollInterval))
+	return t.tty.Read(buf)
+}
+
+// resized updates the terminal size, reports whether it changed.
+func (t *terminal) resized() bool {
+	w, h := t.width, t.height
+	_ = t.control(func(fd int) {
+		if nw, nh, err := term.GetSize(fd); err == nil {
+			t.width, t.height = nw, nh
+		}
+	})
+	if t.width <= 0 || t.height <= 0 {
+		t.width, t.height = 80, 24
+	}
+
+	return w != t.width || h != t.height
+}
+
+// draw renders the model over the whole screen.
+func (t *terminal) draw(m *model) {
+	rows := m.view(t.width, t.height)
+	_, _ = io.WriteString(t.tty, cursorHome+strings.Join(rows, "\r\n"))
+}
+
+// run executes an action, suspending the menu when it needs the terminal,
+// then reloads the bookmarks.
+func (t *terminal) run(ctx context.Context, m *model, a *Action) error {
+	bs := m.actionTargets(a)
+	if a.Exec {
+		t.stop()
+		defer func() {
+			if err := t.start(); err != nil {
+				m.status = err.Error()
+			}
+		}()
+	}
+
+	if err := a.Run(ctx, bs); err != nil {
+		return err
+	}
+
+	if m.opts.Reload == nil {
+		return nil
+	}
+
+	items := make([]*bookmark.Bookmark, 0, len(m.items))
+	for _, it := range m.items {
+		items = append(items, it.b)
+	}
+
+	fresh, err := m.opts.Reload(ctx, items)
+	if err != nil {
+		return err
+	}
+	m.setItems(fresh)
+
+	return nil
+}
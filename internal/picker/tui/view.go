@@ -0,0 +1,267 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	runewidth "github.com/mattn/go-runewidth"
+
+	"github.com/mateconpizza/gm/pkg/ansi"
+)
+
+const (
+	sidebarMaxWidth = 24
+	sideBySideMin   = 80 // narrower terminals show the preview below the list
+	vbar            = "│"
+	hbar            = "─"
+)
+
+// preview caches the preview of the bookmark under the cursor.
+type preview struct {
+	id    int
+	width int
+	lines []string
+}
+
+// view renders the whole screen, one string per row.
+func (m *model) view(w, h int) []string {
+	rows := make([]string, 0, h)
+	rows = append(rows, m.promptLine(w))
+	if m.opts.Header != "" {
+		rows = append(rows, fit(ansi.Dim.Sprint(m.opts.Header), w))
+	}
+
+	body := max(h-len(rows)-1, 1)
+
+	var side []string
+	sw := 0
+	if m.showTags {
+		sw = min(sidebarMaxWidth, w/4)
+		side = m.sidebar(sw, body)
+		sw++
+	}
+
+	rw := w - sw
+	listW, listH := rw, body
+	var pv []string
+	switch {
+	case !m.showPreview:
+	case rw >= sideBySideMin:
+		listW = rw / 2
+		pv = m.previewLines(rw-listW-1, body)
+	default:
+		listH = max(body/2, 1)
+		pv = m.previewLines(rw, body-listH-1)
+	}
+
+	m.height = listH
+	m.clampCursor()
+	list := m.listLines(listW, listH)
+
+	for i := range body {
+		var sb strings.Builder
+		if sw > 0 {
+			sb.WriteString(side[i])
+			sb.WriteString(ansi.Dim.Sprint(vbar))
+		}
+
+		switch {
+		case pv == nil:
+			sb.WriteString(list[i])
+		case rw >= sideBySideMin:
+			sb.WriteString(list[i])
+			sb.WriteString(ansi.Dim.Sprint(vbar))
+			sb.WriteString(pv[i])
+		case i < listH:
+			sb.WriteString(list[i])
+		case i == listH:
+			sb.WriteString(ansi.Dim.Sprint(strings.Repeat(hbar, rw)))
+		default:
+			sb.WriteString(pv[i-listH-1])
+		}
+
+		rows = append(rows, sb.String())
+	}
+
+	return append(rows, m.footer(w))
+}
+
+// promptLine renders the prompt, the query and the match counter.
+func (m *model) promptLine(w int) string {
+	counter := fmt.Sprintf("%d/%d", len(m.matches), len(m.items))
+	if n := len(m.selected); n > 0 {
+		counter += fmt.Sprintf(" (%d)", n)
+	}
+	for t := range m.activeTags {
+		counter = "#" + t + " " + counter
+	}
+
+	left := ansi.Bold.Sprint(m.opts.Prompt) + string(m.query) + ansi.Inverse.Sprint(" ")
+	pad := max(w-runewidth.StringWidth(ansi.Remover(left))-runewidth.StringWidth(counter)-1, 1)
+
+	return fit(left+strings.Repeat(" ", pad)+ansi.BrightBlue.Sprint(counter), w)
+}
+
+// listLines renders the visible matches.
+func (m *model) listLines(w, h int) []string {
+	lines := make([]string, h)
+	for i := range h {
+		idx := m.offset + i
+		if idx >= len(m.matches) {
+			lines[i] = strings.Repeat(" ", w)
+			continue
+		}
+
+		it := m.matches[idx]
+		cur, sel := " ", " "
+		if idx == m.cursor {
+			cur = ansi.BrightMagenta.Wrap(">", ansi.Bold)
+		}
+		if m.selected[it.b.ID] {
+			sel = ansi.BrightGreen.Sprint("+")
+		}
+
+		lines[i] = fit(cur+sel+it.line, w)
+	}
+
+	return lines
+}
+
+// sidebar renders the tags with their count, active ones marked.
+func (m *model) sidebar(w, h int) []string {
+	lines := make([]string, h)
+	title := "tags"
+	if m.focusTags {
+		title = ansi.BrightYellow.Wrap(title, ansi.Bold)
+	}
+	lines[0] = fit(" "+title, w)
+
+	// keep the tag cursor visible
+	start := max(m.tagCursor-(h-2), 0)
+	for i := 1; i < h; i++ {
+		idx := start + i - 1
+		if idx >= len(m.tags) {
+			lines[i] = strings.Repeat(" ", w)
+			continue
+		}
+
+		t := m.tags[idx]
+		mark := " "
+		if m.activeTags[t.name] {
+			mark = ansi.BrightGreen.Sprint("*")
+		}
+		name := t.name
+		if m.focusTags && idx == m.tagCursor {
+			name = ansi.Inverse.Sprint(name)
+		}
+		count := ansi.Dim.Sprint(strconv.Itoa(t.count))
+
+		lines[i] = fit(mark+name+" "+count, w)
+	}
+
+	return lines
+}
+
+// previewLines renders the preview of the current bookmark.
+func (m *model) previewLines(w, h int) []string {
+	lines := make([]string, max(h, 0))
+	for i := range lines {
+		lines[i] = strings.Repeat(" ", w)
+	}
+
+	it := m.current()
+	if it == nil || h <= 0 {
+		return lines
+	}
+
+	if m.preview == nil || m.preview.id != it.b.ID || m.preview.width != w {
+		text := strings.TrimRight(m.opts.Preview(it.b, w), "\n")
+		m.preview = &preview{id: it.b.ID, width: w, lines: strings.Split(text, "\n")}
+	}
+
+	for i, l := range m.preview.lines {
+		if i >= h {
+			break
+		}
+		lines[i] = fit(" "+l, w)
+	}
+
+	return lines
+}
+
+// footer renders the status message or the keys help.
+func (m *model) footer(w int) string {
+	if m.status != "" {
+		return fit(ansi.BrightRed.Sprint(m.status), w)
+	}
+
+	var help []string
+	add := func(k Key, desc string) {
+		if k != "" && desc != "" {
+			help = append(help, ansi.BrightYellow.Sprint(string(k))+" "+ansi.BrightBlue.Sprint(desc))
+		}
+	}
+
+	for _, a := range m.opts.Actions {
+		add(a.Key, a.Desc)
+	}
+	if m.opts.Multi {
+		add(KeyTab, "select")
+	}
+	add(KeyCtrlT, "tags")
+	if m.opts.Preview != nil {
+		add(m.opts.TogglePreview, "preview")
+	}
+	add(KeyEsc, "quit")
+
+	return fit(strings.Join(help, ansi.Dim.Sprint(" · ")), w)
+}
+
+// fit cuts s to w visible columns and pads it, escape sequences are kept
+// and do not count.
+func fit(s string, w int) string {
+	if w <= 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	width, styled := 0, false
+	for i := 0; i < len(s); {
+		if s[i] == 0x1b {
+			j := i + 1
+			if j < len(s) && s[j] == '[' {
+				for j++; j < len(s) && (s[j] < 0x40 || s[j] > 0x7e); j++ {
+				}
+			}
+			j = min(j+1, len(s))
+			sb.WriteString(s[i:j])
+			styled = true
+			i = j
+			continue
+		}
+
+		r, n := utf8.DecodeRuneInString(s[i:])
+		i += n
+		if r == '\t' {
+			r = ' '
+		}
+		if r < ' ' {
+			continue
+		}
+
+		rw := runewidth.RuneWidth(r)
+		if width+rw > w {
+			break
+		}
+		sb.WriteRune(r)
+		width += rw
+	}
+
+	if styled {
+		sb.WriteString(string(ansi.Reset))
+	}
+
+	return sb.String() + strings.Repeat(" ", w-width)
+}
@@ -7,10 +7,15 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/jmoiron/sqlx"
 	files "github.com/mateconpizza/gofiles"
 	"github.com/mateconpizza/rotato"
 
@@ -19,6 +24,7 @@ import (
 	"github.com/mateconpizza/gm/internal/ui"
 	"github.com/mateconpizza/gm/pkg/ansi"
 	"github.com/mateconpizza/gm/pkg/bookmark"
+	"github.com/mateconpizza/gm/pkg/db"
 )
 
 var (
@@ -85,8 +91,33 @@ func (b *BlinkBrowser) LoadPaths() error {
 	return nil
 }
 
+// historyFile is the history database, next to the bookmarks file.
+const historyFile = "History"
+
+// loadFunc reads the bookmark candidates of a profile file.
+type loadFunc func(ctx context.Context, path string, opts browser.Options) ([]blinkBookmark, error)
+
 // Import extracts profile system names and user names.
-func (b *BlinkBrowser) Import(ctx context.Context, c *ui.Console, force bool) ([]*bookmark.Bookmark, error) {
+func (b *BlinkBrowser) Import(ctx context.Context, c *ui.Console, opts browser.Options) ([]*bookmark.Bookmark, error) {
+	return b.collect(ctx, c, opts, "", func(ctx context.Context, path string, _ browser.Options) ([]blinkBookmark, error) {
+		return loadChromeDatabase(ctx, path)
+	})
+}
+
+// History returns the frequently visited pages of every profile.
+func (b *BlinkBrowser) History(ctx context.Context, c *ui.Console, opts browser.Options) ([]*bookmark.Bookmark, error) {
+	return b.collect(ctx, c, opts, historyFile, loadHistory)
+}
+
+// collect loads the candidates of every profile. file replaces the
+// bookmarks file name when not empty.
+func (b *BlinkBrowser) collect(
+	ctx context.Context,
+	c *ui.Console,
+	opts browser.Options,
+	file string,
+	load loadFunc,
+) ([]*bookmark.Bookmark, error) {
 	if b.paths.bookmarks == "" || b.paths.profiles == "" {
 		return nil, ErrBrowserConfigPathNotSet
 	}
@@ -119,8 +150,12 @@ func (b *BlinkBrowser) Import(ctx context.Context, c *ui.Console, force bool) ([
 			return nil, err
 		}
 
-		bookmarksPath := fmt.Sprintf(b.paths.bookmarks, profile)
-		if err := processProfile(ctx, c, &bs, v, files.ExpandHomeDir(bookmarksPath), force); err != nil {
+		path := files.ExpandHomeDir(fmt.Sprintf(b.paths.bookmarks, profile))
+		if file != "" {
+			path = filepath.Join(filepath.Dir(path), file)
+		}
+
+		if err := processProfile(ctx, c, &bs, v, path, opts, load); err != nil {
 			return nil, err
 		}
 	}
@@ -160,46 +195,41 @@ type JSONData struct {
 }
 
 type blinkBookmark struct {
-	title string
-	url   string
-	tags  []string
+	title    string
+	url      string
+	folders  []string // folder path below the root
+	added    time.Time
+	lastUsed time.Time
+	visits   int
 }
 
-// Define a function to traverse the bookmark folder.
-func traverseBmFolder(
-	children []any,
-	uniqueTag string,
-	parentName string,
-	addParentFolderAsTag bool,
-) [][]string {
-	var results [][]string
+// traverseBmFolder walks a bookmark folder, recording the folder path of
+// every bookmark.
+func traverseBmFolder(children []any, folders []string) []blinkBookmark {
+	var results []blinkBookmark
 
 	for _, child := range children {
 		childMap, ok := child.(map[string]any)
 		if !ok {
 			continue
 		}
-		// Get the name and URL of the bookmark
-		name, ok := childMap["name"].(string)
-		if !ok {
-			name = ""
-		}
 
-		url, ok := childMap["url"].(string)
-		if !ok {
-			url = ""
-		}
+		name, _ := childMap["name"].(string)
 
 		// Check if the bookmark is a folder
 		typeStr, ok := childMap["type"].(string)
 		if !ok || typeStr != "folder" {
-			tags := []string{uniqueTag}
-			if addParentFolderAsTag {
-				tags = append(tags, parentName)
-			}
-
-			item := append([]string{name, url}, tags...)
-			results = append(results, item)
+			url, _ := childMap["url"].(string)
+			added, _ := childMap["date_added"].(string)
+			lastUsed, _ := childMap["date_last_used"].(string)
+
+			results = append(results, blinkBookmark{
+				title:    name,
+				url:      url,
+				folders:  folders,
+				added:    chromeTime(added),
+				lastUsed: chromeTime(lastUsed),
+			})
 
 			continue
 		}
@@ -210,21 +240,37 @@ func traverseBmFolder(
 			continue
 		}
 
-		// Add the unique tag to the folder
-		results = append(
-			results,
-			traverseBmFolder(
-				childrenVal,
-				uniqueTag,
-				name,
-				addParentFolderAsTag,
-			)...,
-		)
+		path := append(slices.Clone(folders), name)
+		results = append(results, traverseBmFolder(childrenVal, path)...)
 	}
 
 	return results
 }
 
+// epochDelta is the number of microseconds between the Chromium epoch,
+// 1601-01-01 UTC, and the Unix epoch.
+const epochDelta = 11644473600 * 1e6
+
+// chromeTime converts a Chromium timestamp, microseconds since 1601-01-01
+// UTC. It returns the zero time for unset values.
+func chromeTime(s string) time.Time {
+	us, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+
+	return chromeMicro(us)
+}
+
+// chromeMicro converts microseconds since the Chromium epoch.
+func chromeMicro(us int64) time.Time {
+	if us <= 0 {
+		return time.Time{}
+	}
+
+	return time.UnixMicro(us - epochDelta)
+}
+
 // Function to extract profile system names and user names.
 func processChromiumProfiles(jsonData []byte) (map[string]string, error) {
 	var data JSONData
@@ -241,7 +287,14 @@ func processChromiumProfiles(jsonData []byte) (map[string]string, error) {
 }
 
 // processProfile extracts profile system names and user names.
-func processProfile(ctx context.Context, c *ui.Console, bs *[]*bookmark.Bookmark, profile, path string, force bool) error {
+func processProfile(
+	ctx context.Context,
+	c *ui.Console,
+	bs *[]*bookmark.Bookmark,
+	profile, path string,
+	opts browser.Options,
+	load loadFunc,
+) error {
 	if err := ctx.Err(); err != nil {
 		return err
 	}
@@ -251,11 +304,11 @@ func processProfile(ctx context.Context, c *ui.Console, bs *[]*bookmark.Bookmark
 	pf := p.Italic.Wrap(profile, p.Bold)
 	if !files.Exists(path) {
 		f.Reset()
-		f.Headerln(skip + " profile " + pf + p.Italic.Sprint(": bookmarks file not found")).Flush()
+		f.Headerln(skip + " profile " + pf + p.Italic.Sprint(": "+filepath.Base(path)+" file not found")).Flush()
 		return nil
 	}
 
-	if !force {
+	if !opts.Force {
 		if err := c.ConfirmErr(ctx, fmt.Sprintf("import bookmarks from %q profile?", profile), "y"); err != nil {
 			reason := p.Italic.Sprint(": skipped by user")
 			c.ReplaceLine(f.Warning(skip + " profile " + pf + reason).String())
@@ -265,10 +318,7 @@ func processProfile(ctx context.Context, c *ui.Console, bs *[]*bookmark.Bookmark
 		c.Warning("force import bookmarks from '" + profile + "' profile\n").Flush()
 	}
 
-	uniqueTag := getTodayFormatted()
-	addParentFolderAsTag := true
-
-	result, err := loadChromeDatabase(ctx, path, uniqueTag, addParentFolderAsTag)
+	result, err := load(ctx, path, opts)
 	if err != nil {
 		fmt.Fprintln(c.Writer(), "Error loading Chrome database:", err)
 	}
@@ -277,10 +327,21 @@ func processProfile(ctx context.Context, c *ui.Console, bs *[]*bookmark.Bookmark
 	ogSize := len(*bs)
 
 	for _, c := range result {
+		var tags []string
+		if t := browser.FolderTag(c.folders); t != "" {
+			tags = append(tags, t)
+		}
+		if opts.TodayTag {
+			tags = append(tags, browser.TodayTag())
+		}
+
 		b := bookmark.New()
 		b.Title = c.title
 		b.URL = c.url
-		b.Tags = bookmark.ParseTags(strings.Join(c.tags, ","))
+		b.Tags = bookmark.ParseTags(strings.Join(tags, ","))
+		b.CreatedAt = browser.FormatTime(c.added)
+		b.LastVisit = browser.FormatTime(c.lastUsed)
+		b.VisitCount = c.visits
 
 		// deduplicate by URL
 		duplicate := false
@@ -304,7 +365,7 @@ func processProfile(ctx context.Context, c *ui.Console, bs *[]*bookmark.Bookmark
 }
 
 // Define the main function to load the Chrome database.
-func loadChromeDatabase(ctx context.Context, path, uniqueTag string, addParentFolderAsTag bool) ([]blinkBookmark, error) {
+func loadChromeDatabase(ctx context.Context, path string) ([]blinkBookmark, error) {
 	byteValue, _ := os.ReadFile(path)
 
 	s := rotato.New(
@@ -339,25 +400,120 @@ func loadChromeDatabase(ctx context.Context, path, uniqueTag string, addParentFo
 			continue
 		}
 
-		parentName, ok := valueMap["name"].(string)
-		if !ok {
+		// the roots (bookmark bar, other, synced) are not part of the path
+		results = append(results, traverseBmFolder(children, nil)...)
+	}
+
+	return results, nil
+}
+
+// historyRow is a row of the Chromium urls table with its visits.
+type historyRow struct {
+	URL        string `db:"url"`
+	Title      string `db:"title"`
+	VisitCount int    `db:"visit_count"`
+	LastVisit  int64  `db:"last_visit"`
+	FirstVisit int64  `db:"first_visit"`
+}
+
+// loadHistory reads the pages visited at least opts.MinVisits times since
+// opts.Since from a copy of the history database, the browser keeps it
+// locked while running.
+func loadHistory(ctx context.Context, path string, opts browser.Options) ([]blinkBookmark, error) {
+	tmp, err := copyToTemp(path)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp)
+
+	cfg, err := db.NewSQLiteCfg(tmp)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := db.OpenDatabase(ctx, tmp, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("opening history: %w", err)
+	}
+	defer r.Close()
+
+	rows, err := queryHistory(r, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]blinkBookmark, 0, len(rows))
+	for _, h := range rows {
+		if !strings.HasPrefix(h.URL, "http://") && !strings.HasPrefix(h.URL, "https://") {
 			continue
 		}
 
-		for _, item := range traverseBmFolder(children, uniqueTag, parentName, addParentFolderAsTag) {
-			c := &blinkBookmark{
-				title: item[0],
-				url:   item[1],
-				tags:  item[2:],
-			}
-			results = append(results, *c)
-		}
+		results = append(results, blinkBookmark{
+			title:    h.Title,
+			url:      h.URL,
+			added:    chromeMicro(h.FirstVisit),
+			lastUsed: chromeMicro(h.LastVisit),
+			visits:   h.VisitCount,
+		})
 	}
 
 	return results, nil
 }
 
-func getTodayFormatted() string {
-	today := time.Now()
-	return today.Format("2006Jan02")
+// queryHistory queries the visible pages visited at least opts.MinVisits
+// times since opts.Since, most visited first.
+func queryHistory(r *sqlx.DB, opts browser.Options) ([]historyRow, error) {
+	const q = `
+	SELECT
+		u.url,
+		COALESCE(u.title, '') AS title,
+		u.visit_count,
+		u.last_visit_time AS last_visit,
+		MIN(v.visit_time) AS first_visit
+	FROM urls u
+	JOIN visits v ON v.url = u.id
+	WHERE v.visit_time >= ? AND u.hidden = 0
+	GROUP BY u.id
+	HAVING COUNT(v.id) >= ?
+	ORDER BY COUNT(v.id) DESC`
+
+	var since int64
+	if !opts.Since.IsZero() {
+		since = opts.Since.UnixMicro() + epochDelta
+	}
+
+	var rows []historyRow
+	if err := r.Select(&rows, q, since, max(opts.MinVisits, 1)); err != nil {
+		return nil, fmt.Errorf("failed to query history: %w", err)
+	}
+
+	return rows, nil
+}
+
+// copyToTemp copies the file at path to a temporary file and returns its
+// path.
+func copyToTemp(path string) (string, error) {
+	src, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer src.Close()
+
+	dst, err := os.CreateTemp("", "gomarks-history-*.sqlite")
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := io.Copy(dst, src); err != nil {
+		_ = dst.Close()
+		_ = os.Remove(dst.Name())
+		return "", fmt.Errorf("copying %q: %w", path, err)
+	}
+
+	if err := dst.Close(); err != nil {
+		_ = os.Remove(dst.Name())
+		return "", err
+	}
+
+	return dst.Name(), nil
 }
@@ -1,8 +1,14 @@
 package blink
 
 import (
+	"path/filepath"
 	"reflect"
 	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/mateconpizza/gm/internal/sys/browser"
 )
 
 // generateChildren generates children for testing based in the JSON file.
@@ -56,57 +62,51 @@ func generateChildren() []any {
 	}
 }
 
-var testBasicBookmarks = [][]string{
+var testBasicBookmarks = []blinkBookmark{
 	{
-		"Pass: The Standard Unix Password Manager",
-		"https://www.passwordstore.org/",
-		"testTag",
-		"root",
+		title:   "Pass: The Standard Unix Password Manager",
+		url:     "https://www.passwordstore.org/",
+		folders: []string{"root"},
+		added:   time.Date(2024, 12, 21, 12, 17, 23, 306561000, time.UTC),
 	},
-	{"ExampleChad.net", "https://examplechad.net/", "testTag", "root"},
+	{title: "ExampleChad.net", url: "https://examplechad.net/", folders: []string{"root"}},
 	{
-		"How to Check if a File or Directory Exists in Bash",
-		"https://example.com/post/bash-check-if-file-exists/",
-		"testTag",
-		"bash",
+		title:   "How to Check if a File or Directory Exists in Bash",
+		url:     "https://example.com/post/bash-check-if-file-exists/",
+		folders: []string{"root", "bash"},
+		added:   time.Date(2024, 12, 21, 12, 18, 15, 727946000, time.UTC),
 	},
 }
 
-var testNoParentFolderBookmarks = [][]string{
+var testNoParentFolderBookmarks = []blinkBookmark{
 	{
-		"Pass: The Standard Unix Password Manager",
-		"https://www.passwordstore.org/",
-		"testTag2",
+		title: "Pass: The Standard Unix Password Manager",
+		url:   "https://www.passwordstore.org/",
+		added: time.Date(2024, 12, 21, 12, 17, 23, 306561000, time.UTC),
 	},
-	{"ExampleChad.net", "https://examplechad.net/", "testTag2"},
+	{title: "ExampleChad.net", url: "https://examplechad.net/"},
 	{
-		"How to Check if a File or Directory Exists in Bash",
-		"https://example.com/post/bash-check-if-file-exists/",
-		"testTag2",
+		title:   "How to Check if a File or Directory Exists in Bash",
+		url:     "https://example.com/post/bash-check-if-file-exists/",
+		folders: []string{"bash"},
+		added:   time.Date(2024, 12, 21, 12, 18, 15, 727946000, time.UTC),
 	},
 }
 
-var testMissingFields = [][]string{
-	{
-		"",
-		"",
-		"testTag",
-		"root",
-	},
+var testMissingFields = []blinkBookmark{
+	{folders: []string{"root"}},
 }
 
-var testDuplicateNames = [][]string{
+var testDuplicateNames = []blinkBookmark{
 	{
-		"Duplicate Name",
-		"https://duplicate.example.com/",
-		"testTag",
-		"root",
+		title:   "Duplicate Name",
+		url:     "https://duplicate.example.com/",
+		folders: []string{"root"},
 	},
 	{
-		"Duplicate Name",
-		"https://another-duplicate.example.com/",
-		"testTag",
-		"root",
+		title:   "Duplicate Name",
+		url:     "https://another-duplicate.example.com/",
+		folders: []string{"root"},
 	},
 }
 
@@ -141,44 +141,33 @@ func TestTraverseBmFolder(t *testing.T) {
 	t.Parallel()
 
 	tests := []struct {
-		name                 string
-		children             []any
-		uniqueTag            string
-		parentName           string
-		addParentFolderAsTag bool
-		expected             [][]string
+		name     string
+		children []any
+		folders  []string
+		expected []blinkBookmark
 	}{
 		{
-			name:                 "Basic structure with URLs and a folder",
-			children:             generateChildren(),
-			uniqueTag:            "testTag",
-			parentName:           "root",
-			addParentFolderAsTag: true,
-			expected:             testBasicBookmarks,
+			name:     "Basic structure with URLs and a folder",
+			children: generateChildren(),
+			folders:  []string{"root"},
+			expected: testBasicBookmarks,
 		},
 		{
-			name:                 "No parent folder tag",
-			children:             generateChildren(),
-			uniqueTag:            "testTag2",
-			parentName:           "root",
-			addParentFolderAsTag: false,
-			expected:             testNoParentFolderBookmarks,
+			name:     "No parent folder",
+			children: generateChildren(),
+			expected: testNoParentFolderBookmarks,
 		},
 		{
-			name:                 "Missing fields",
-			children:             generateMissingFields(),
-			uniqueTag:            "testTag",
-			parentName:           "root",
-			addParentFolderAsTag: true,
-			expected:             testMissingFields,
+			name:     "Missing fields",
+			children: generateMissingFields(),
+			folders:  []string{"root"},
+			expected: testMissingFields,
 		},
 		{
-			name:                 "Duplicate names",
-			children:             generateDuplicateNames(),
-			uniqueTag:            "testTag",
-			parentName:           "root",
-			addParentFolderAsTag: true,
-			expected:             testDuplicateNames,
+			name:     "Duplicate names",
+			children: generateDuplicateNames(),
+			folders:  []string{"root"},
+			expected: testDuplicateNames,
 		},
 	}
 
@@ -186,16 +175,97 @@ func TestTraverseBmFolder(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			result := traverseBmFolder(
-				tt.children,
-				tt.uniqueTag,
-				tt.parentName,
-				tt.addParentFolderAsTag,
-			)
+			result := traverseBmFolder(tt.children, tt.folders)
+			if len(result) != len(tt.expected) {
+				t.Fatalf("Expected %d bookmarks, got %d", len(tt.expected), len(result))
+			}
 
-			if !reflect.DeepEqual(result, tt.expected) {
-				t.Errorf("Expected: %v, got: %v", tt.expected, result)
+			for i, got := range result {
+				want := tt.expected[i]
+				if got.title != want.title || got.url != want.url ||
+					!reflect.DeepEqual(got.folders, want.folders) ||
+					!got.added.Equal(want.added) || !got.lastUsed.Equal(want.lastUsed) {
+					t.Errorf("Expected: %+v, got: %+v", want, got)
+				}
 			}
 		})
 	}
 }
+
+func TestChromeTime(t *testing.T) {
+	t.Parallel()
+
+	if got := chromeTime("0"); !got.IsZero() {
+		t.Errorf("expected zero time for unset value, got %v", got)
+	}
+	if got := chromeTime("not a number"); !got.IsZero() {
+		t.Errorf("expected zero time for invalid value, got %v", got)
+	}
+
+	want := time.Date(2024, 12, 21, 12, 17, 23, 306561000, time.UTC)
+	if got := chromeTime("13379257043306561"); !got.Equal(want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestLoadHistory(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "History")
+	r, err := sqlx.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stmts := []string{
+		`CREATE TABLE urls (id INTEGER PRIMARY KEY, url TEXT, title TEXT, visit_count INTEGER,
+			last_visit_time INTEGER, hidden INTEGER DEFAULT 0)`,
+		`CREATE TABLE visits (id INTEGER PRIMARY KEY, url INTEGER, visit_time INTEGER)`,
+		`INSERT INTO urls (id, url, title, visit_count, last_visit_time, hidden) VALUES
+			(1, 'https://go.dev/', 'Go', 12, 13379257095727946, 0),
+			(2, 'chrome://settings/', 'Settings', 9, 13379257095727946, 0),
+			(3, 'https://hidden.example/', 'Hidden', 9, 13379257095727946, 1),
+			(4, 'https://rare.example/', 'Rare', 1, 13379257095727946, 0)`,
+		`INSERT INTO visits (url, visit_time) VALUES
+			(1, 13379257043306561), (1, 13379257095727946),
+			(2, 13379257043306561), (2, 13379257095727946),
+			(3, 13379257043306561), (3, 13379257095727946),
+			(4, 13379257095727946)`,
+	}
+	for _, s := range stmts {
+		if _, err := r.Exec(s); err != nil {
+			t.Fatalf("%v: %s", err, s)
+		}
+	}
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := loadHistory(t.Context(), path, browser.Options{MinVisits: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("expected 1 page, got %d: %+v", len(got), got)
+	}
+
+	b := got[0]
+	if b.url != "https://go.dev/" || b.title != "Go" || b.visits != 12 {
+		t.Errorf("unexpected page: %+v", b)
+	}
+	if want := time.Date(2024, 12, 21, 12, 17, 23, 306561000, time.UTC); !b.added.Equal(want) {
+		t.Errorf("first visit: expected %v, got %v", want, b.added)
+	}
+	if want := time.Date(2024, 12, 21, 12, 18, 15, 727946000, time.UTC); !b.lastUsed.Equal(want) {
+		t.Errorf("last visit: expected %v, got %v", want, b.lastUsed)
+	}
+
+	since := time.Date(2024, 12, 21, 12, 18, 0, 0, time.UTC)
+	got, err = loadHistory(t.Context(), path, browser.Options{MinVisits: 2, Since: since})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 0 {
+		t.Errorf("expected no page with 2 visits since %v, got %+v", since, got)
+	}
+}
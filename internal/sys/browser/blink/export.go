@@ -0,0 +1,252 @@
+package blink
+
+import (
+	"context"
+	"crypto/md5" //nolint:gosec // checksum format used by Chromium
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+	"unicode/utf16"
+
+	files "github.com/mateconpizza/gofiles"
+
+	"github.com/mateconpizza/gm/internal/sys"
+	"github.com/mateconpizza/gm/internal/sys/browser"
+	"github.com/mateconpizza/gm/pkg/bookmark"
+)
+
+var ErrInvalidBookmarksFile = errors.New("invalid bookmarks file")
+
+// checksumRoots are the root folders, in the order Chromium hashes them.
+var checksumRoots = []string{"bookmark_bar", "other", "synced"}
+
+// Export writes bs into the "gm" folder of the profile "Other bookmarks",
+// replacing its previous content. The browser overwrites the file on exit,
+// so it should be closed.
+func (b *BlinkBrowser) Export(_ context.Context, profile string, bs []*bookmark.Bookmark) error {
+	if b.paths.bookmarks == "" || b.paths.profiles == "" {
+		return ErrBrowserConfigPathNotSet
+	}
+
+	data, err := os.ReadFile(b.paths.profiles)
+	if err != nil {
+		return fmt.Errorf("error reading JSON file: %w", err)
+	}
+
+	profiles, err := processChromiumProfiles(data)
+	if err != nil {
+		return err
+	}
+
+	key, err := browser.FindProfile(profiles, profile)
+	if err != nil {
+		return err
+	}
+
+	return exportToFile(files.ExpandHomeDir(fmt.Sprintf(b.paths.bookmarks, key)), bs, time.Now())
+}
+
+// exportToFile writes bs into the "gm" folder of the bookmarks file at path
+// and updates its checksum.
+func exportToFile(path string, bs []*bookmark.Bookmark, now time.Time) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var root map[string]any
+	if err := json.Unmarshal(data, &root); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidBookmarksFile, err)
+	}
+
+	roots, ok := root["roots"].(map[string]any)
+	if !ok {
+		return fmt.Errorf("%w: missing roots", ErrInvalidBookmarksFile)
+	}
+
+	other, ok := roots["other"].(map[string]any)
+	if !ok {
+		return fmt.Errorf("%w: missing other bookmarks folder", ErrInvalidBookmarksFile)
+	}
+
+	nextID := maxID(roots) + 1
+	id := func() string {
+		nextID++
+		return strconv.Itoa(nextID - 1)
+	}
+
+	stamp := chromeStamp(now)
+	folder := findFolder(other, browser.ExportFolder)
+	if folder == nil {
+		folder = map[string]any{
+			"date_added":     stamp,
+			"date_last_used": "0",
+			"guid":           newGUID(),
+			"id":             id(),
+			"name":           browser.ExportFolder,
+			"type":           "folder",
+		}
+		children, _ := other["children"].([]any)
+		other["children"] = append(children, folder)
+	}
+
+	// Chrome Sync tracks nodes by GUID, the previous entries keep theirs so
+	// an export is not seen as every bookmark removed and added again
+	prev := urlNodes(folder)
+
+	nodes := make([]any, 0, len(bs))
+	for _, b := range bookmark.WithoutPrivate(bs) {
+		added := stamp
+		if t, err := time.Parse(time.RFC3339, b.CreatedAt); err == nil {
+			added = chromeStamp(t)
+		}
+
+		n := map[string]any{
+			"date_added":     added,
+			"date_last_used": "0",
+			"name":           b.Title,
+			"type":           "url",
+			"url":            b.URL,
+		}
+		if old, ok := prev[b.URL]; ok {
+			delete(prev, b.URL)
+			n["guid"], n["id"] = old["guid"], old["id"]
+			if used, ok := old["date_last_used"]; ok {
+				n["date_last_used"] = used
+			}
+		} else {
+			n["guid"], n["id"] = newGUID(), id()
+		}
+		nodes = append(nodes, n)
+	}
+	folder["children"] = nodes
+	folder["date_modified"] = stamp
+
+	root["checksum"] = checksum(roots)
+
+	out, err := json.MarshalIndent(root, "", "   ")
+	if err != nil {
+		return fmt.Errorf("encoding bookmarks: %w", err)
+	}
+
+	// the profile keeps the permissions Chromium gave to the file
+	return sys.WriteFileAtomic(path, out, sys.FileMode(path, 0o600))
+}
+
+// findFolder returns the direct child folder of parent named name.
+func findFolder(parent map[string]any, name string) map[string]any {
+	children, _ := parent["children"].([]any)
+	for _, c := range children {
+		n, ok := c.(map[string]any)
+		if ok && n["type"] == "folder" && n["name"] == name {
+			return n
+		}
+	}
+
+	return nil
+}
+
+// urlNodes returns the bookmarks of folder by URL, the first one when a URL
+// is repeated.
+func urlNodes(folder map[string]any) map[string]map[string]any {
+	nodes := make(map[string]map[string]any)
+	children, _ := folder["children"].([]any)
+	for _, c := range children {
+		n, ok := c.(map[string]any)
+		if !ok || n["type"] != "url" {
+			continue
+		}
+		if u, ok := n["url"].(string); ok {
+			if _, seen := nodes[u]; !seen {
+				nodes[u] = n
+			}
+		}
+	}
+
+	return nodes
+}
+
+// maxID returns the highest node id in the tree.
+func maxID(n any) int {
+	highest := 0
+
+	switch v := n.(type) {
+	case map[string]any:
+		if s, ok := v["id"].(string); ok {
+			if id, err := strconv.Atoi(s); err == nil {
+				highest = id
+			}
+		}
+		for _, c := range v {
+			highest = max(highest, maxID(c))
+		}
+	case []any:
+		for _, c := range v {
+			highest = max(highest, maxID(c))
+		}
+	}
+
+	return highest
+}
+
+// checksum returns the MD5 checksum Chromium stores in the bookmarks file,
+// computed over the id, title and type of every node and the URL of the
+// bookmarks.
+func checksum(roots map[string]any) string {
+	h := md5.New() //nolint:gosec // checksum format used by Chromium
+
+	var walk func(n map[string]any)
+	walk = func(n map[string]any) {
+		id, _ := n["id"].(string)
+		name, _ := n["name"].(string)
+		typ, _ := n["type"].(string)
+
+		h.Write([]byte(id))
+		for _, r := range utf16.Encode([]rune(name)) {
+			_ = binary.Write(h, binary.LittleEndian, r)
+		}
+		h.Write([]byte(typ))
+
+		if typ == "url" {
+			u, _ := n["url"].(string)
+			h.Write([]byte(u))
+			return
+		}
+
+		children, _ := n["children"].([]any)
+		for _, c := range children {
+			if child, ok := c.(map[string]any); ok {
+				walk(child)
+			}
+		}
+	}
+
+	for _, name := range checksumRoots {
+		if n, ok := roots[name].(map[string]any); ok {
+			walk(n)
+		}
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// chromeStamp formats t as a Chromium timestamp.
+func chromeStamp(t time.Time) string {
+	return strconv.FormatInt(t.UnixMicro()+epochDelta, 10)
+}
+
+// newGUID returns a random version 4 UUID.
+func newGUID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
@@ -0,0 +1,152 @@
+package blink
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mateconpizza/gm/internal/sys/browser"
+	"github.com/mateconpizza/gm/pkg/bookmark"
+)
+
+const testBookmarksFile = `{
+   "checksum": "0",
+   "roots": {
+      "bookmark_bar": {
+         "children": [ {
+            "date_added": "13379257043306561",
+            "guid": "a1b2",
+            "id": "4",
+            "name": "Gö",
+            "type": "url",
+            "url": "https://go.dev/"
+         } ],
+         "id": "1",
+         "name": "Bookmarks bar",
+         "type": "folder"
+      },
+      "other": { "children": [], "id": "2", "name": "Other bookmarks", "type": "folder" },
+      "synced": { "children": [], "id": "3", "name": "Mobile bookmarks", "type": "folder" }
+   },
+   "sync_metadata": "keep-me",
+   "version": 1
+}`
+
+func TestChecksum(t *testing.T) {
+	t.Parallel()
+
+	var root map[string]any
+	if err := json.Unmarshal([]byte(testBookmarksFile), &root); err != nil {
+		t.Fatal(err)
+	}
+
+	// computed with Python hashlib following the Chromium bookmark codec
+	const want = "ba57cd9caa055e53d46b0d7c82ccb4ff"
+	if got := checksum(root["roots"].(map[string]any)); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestExportToFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "Bookmarks")
+	if err := os.WriteFile(path, []byte(testBookmarksFile), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	bs := []*bookmark.Bookmark{
+		{URL: "https://a.example/", Title: "A", CreatedAt: "2024-12-21T12:17:23Z"},
+		{URL: "https://b.example/", Title: "B"},
+		{URL: "https://private.example/", Title: "P", Private: true},
+	}
+
+	// a second export replaces the folder content
+	var guids []any
+	for range 2 {
+		if err := exportToFile(path, bs, now); err != nil {
+			t.Fatal(err)
+		}
+		guids = append(guids, exportedGUIDs(t, path)...)
+	}
+	if len(guids) != 4 || guids[0] != guids[2] || guids[1] != guids[3] {
+		t.Errorf("expected the GUIDs kept between exports, got %v", guids)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var root map[string]any
+	if err := json.Unmarshal(data, &root); err != nil {
+		t.Fatal(err)
+	}
+
+	if root["sync_metadata"] != "keep-me" {
+		t.Error("unknown fields not kept")
+	}
+
+	roots := root["roots"].(map[string]any)
+	if got := root["checksum"]; got != checksum(roots) {
+		t.Errorf("checksum not updated: %v", got)
+	}
+
+	other := roots["other"].(map[string]any)
+	if n := len(other["children"].([]any)); n != 1 {
+		t.Fatalf("expected a single %q folder, got %d children", browser.ExportFolder, n)
+	}
+
+	folder := findFolder(other, browser.ExportFolder)
+	if folder == nil {
+		t.Fatalf("folder %q not found", browser.ExportFolder)
+	}
+
+	children := folder["children"].([]any)
+	if len(children) != 2 {
+		t.Fatalf("expected 2 bookmarks, got %d", len(children))
+	}
+
+	first := children[0].(map[string]any)
+	if first["url"] != "https://a.example/" || first["name"] != "A" {
+		t.Errorf("unexpected bookmark: %v", first)
+	}
+	if first["date_added"] != "13379257043000000" {
+		t.Errorf("creation date not kept: %v", first["date_added"])
+	}
+
+	seen := map[string]bool{}
+	for _, c := range append(children, folder, roots["bookmark_bar"]) {
+		id := c.(map[string]any)["id"].(string)
+		if seen[id] {
+			t.Errorf("duplicated id %q", id)
+		}
+		seen[id] = true
+	}
+}
+
+// exportedGUIDs returns the GUIDs of the exported bookmarks in the file.
+func exportedGUIDs(t *testing.T, path string) []any {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var root map[string]any
+	if err := json.Unmarshal(data, &root); err != nil {
+		t.Fatal(err)
+	}
+
+	other := root["roots"].(map[string]any)["other"].(map[string]any)
+	var guids []any
+	for _, c := range findFolder(other, browser.ExportFolder)["children"].([]any) {
+		guids = append(guids, c.(map[string]any)["guid"])
+	}
+
+	return guids
+}
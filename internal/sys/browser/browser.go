@@ -5,12 +5,22 @@ package browser
 import (
 	"context"
 	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/mateconpizza/gm/internal/ui"
 	"github.com/mateconpizza/gm/pkg/bookmark"
 )
 
-var ErrBrowserUnsupported = errors.New("browser unsupported")
+var (
+	ErrBrowserUnsupported = errors.New("browser unsupported")
+	ErrProfileNotFound    = errors.New("profile not found")
+)
+
+// ExportFolder is the folder holding the bookmarks exported to a browser.
+const ExportFolder = "gm"
 
 type Supported struct {
 	Browser Browser
@@ -23,6 +33,74 @@ type Browser interface {
 	Name() string
 	Short() string
 	LoadPaths() error
-	Import(ctx context.Context, c *ui.Console, force bool) ([]*bookmark.Bookmark, error)
+	Import(ctx context.Context, c *ui.Console, opts Options) ([]*bookmark.Bookmark, error)
+	History(ctx context.Context, c *ui.Console, opts Options) ([]*bookmark.Bookmark, error)
+	Export(ctx context.Context, profile string, bs []*bookmark.Bookmark) error
 	String() string
 }
+
+// Options controls how bookmarks are imported from a browser.
+type Options struct {
+	Force    bool // Import every profile without confirmation
+	TodayTag bool // Tag the bookmarks with the import date
+
+	// history
+	MinVisits int       // Minimum visits since Since
+	Since     time.Time // Only count the visits after, zero for all
+}
+
+// TodayTag returns the tag added to the bookmarks imported today.
+func TodayTag() string {
+	return time.Now().Format("2006Jan02")
+}
+
+// FolderTag returns the hierarchical tag of a folder path, e.g.
+// "dev/go-tools" for ["Dev", "Go Tools"]. It is empty for the root folders.
+func FolderTag(folders []string) string {
+	parts := make([]string, 0, len(folders))
+	for _, f := range folders {
+		f = strings.Map(func(r rune) rune {
+			if r == ',' || r == '/' {
+				return ' '
+			}
+			return r
+		}, strings.ToLower(f))
+
+		if f = strings.Join(strings.Fields(f), "-"); f != "" {
+			parts = append(parts, f)
+		}
+	}
+
+	return strings.Join(parts, "/")
+}
+
+// FindProfile returns the key of the profile whose key or value matches
+// name, ignoring case. An empty name matches the only profile.
+func FindProfile(profiles map[string]string, name string) (string, error) {
+	if name == "" && len(profiles) == 1 {
+		for k := range profiles {
+			return k, nil
+		}
+	}
+
+	names := make([]string, 0, len(profiles))
+	for k, v := range profiles {
+		if name != "" && (strings.EqualFold(k, name) || strings.EqualFold(v, name)) {
+			return k, nil
+		}
+		names = append(names, k)
+	}
+	slices.Sort(names)
+
+	return "", fmt.Errorf("%w: %q (available: %s)", ErrProfileNotFound, name, strings.Join(names, ", "))
+}
+
+// FormatTime returns t as stored in the database, or an empty string for
+// the zero time.
+func FormatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.UTC().Format(time.RFC3339)
+}
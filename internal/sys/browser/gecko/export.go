@@ -0,0 +1,383 @@
+package gecko
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/bits"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	files "github.com/mateconpizza/gofiles"
+
+	"github.com/mateconpizza/gm/internal/sys/browser"
+	"github.com/mateconpizza/gm/pkg/bookmark"
+	"github.com/mateconpizza/gm/pkg/db"
+)
+
+// Root folders GUIDs.
+const (
+	guidUnfiled = "unfiled_____" // "Other Bookmarks"
+	guidTags    = "tags________"
+)
+
+const (
+	typeBookmark = 1
+	typeFolder   = 2
+)
+
+// Export writes bs into the "gm" folder of the profile "Other Bookmarks",
+// replacing its previous content, and tags them. It refuses while the
+// browser is running.
+func (b *GeckoBrowser) Export(ctx context.Context, profile string, bs []*bookmark.Bookmark) error {
+	profilesPath, bookmarksPath := b.processPaths()
+	if profilesPath == "" || bookmarksPath == "" {
+		return fmt.Errorf("%w: profiles filepath: empty", ErrBrowserConfigPathNotSet)
+	}
+
+	profiles, err := allProfiles(profilesPath)
+	if err != nil {
+		return err
+	}
+
+	key, err := browser.FindProfile(profiles, profile)
+	if err != nil {
+		return err
+	}
+
+	return exportToPlaces(ctx, files.ExpandHomeDir(fmt.Sprintf(bookmarksPath, profiles[key])), bs, time.Now())
+}
+
+// exportToPlaces writes bs into the places database at path.
+func exportToPlaces(ctx context.Context, path string, bs []*bookmark.Bookmark, now time.Time) error {
+	if err := files.ExistsErr(path); err != nil {
+		return fmt.Errorf("%w: %q", err, path)
+	}
+
+	cfg, err := db.NewSQLiteCfg(path)
+	if err != nil {
+		return err
+	}
+
+	r, err := db.OpenDatabase(ctx, path, cfg)
+	if err != nil {
+		return lockErr(err)
+	}
+	defer r.Close()
+
+	tx, err := r.BeginTxx(ctx, nil)
+	if err != nil {
+		return lockErr(err)
+	}
+
+	p := &placesWriter{tx: tx, now: now.UnixMicro()}
+	if err := p.lock(ctx); err != nil {
+		_ = tx.Rollback()
+		return lockErr(err)
+	}
+	if err := p.write(ctx, bookmark.WithoutPrivate(bs)); err != nil {
+		_ = tx.Rollback()
+		return lockErr(err)
+	}
+
+	return lockErr(tx.Commit())
+}
+
+// lockErr reports a locked database as the browser being open.
+func lockErr(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	msg := err.Error()
+	if strings.Contains(msg, ErrDatabaseLocked.Error()) || strings.Contains(msg, "SQLITE_BUSY") {
+		return fmt.Errorf("%w: %w", ErrBrowserIsOpen, err)
+	}
+
+	return err
+}
+
+// placesWriter inserts bookmarks in a places database transaction.
+type placesWriter struct {
+	tx  *sqlx.Tx
+	now int64 // PRTime
+}
+
+// lock takes the write lock of the database. The browser holds it while
+// running, so this fails at once instead of waiting for it.
+func (p *placesWriter) lock(ctx context.Context) error {
+	if _, err := p.tx.ExecContext(ctx, "PRAGMA busy_timeout = 0"); err != nil {
+		return err
+	}
+
+	_, err := p.tx.ExecContext(ctx, "DELETE FROM moz_bookmarks WHERE 0")
+
+	return err
+}
+
+func (p *placesWriter) write(ctx context.Context, bs []*bookmark.Bookmark) error {
+	unfiled, err := p.rootID(ctx, guidUnfiled)
+	if err != nil {
+		return err
+	}
+
+	tagsRoot, err := p.rootID(ctx, guidTags)
+	if err != nil {
+		return err
+	}
+
+	folder, err := p.folder(ctx, unfiled, browser.ExportFolder)
+	if err != nil {
+		return err
+	}
+
+	if err := p.clear(ctx, folder, tagsRoot); err != nil {
+		return err
+	}
+
+	for i, b := range bs {
+		placeID, err := p.place(ctx, b)
+		if err != nil {
+			return err
+		}
+
+		added := p.now
+		if t, err := time.Parse(time.RFC3339, b.CreatedAt); err == nil {
+			added = t.UnixMicro()
+		}
+
+		if err := p.insertBookmark(ctx, placeID, folder, i, b.Title, added); err != nil {
+			return err
+		}
+
+		for tag := range strings.SplitSeq(bookmark.ParseTags(b.Tags), ",") {
+			if tag == "" || tag == bookmark.DefaultTag {
+				continue
+			}
+			if err := p.tag(ctx, tagsRoot, placeID, tag); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := p.pruneTags(ctx, tagsRoot); err != nil {
+		return err
+	}
+
+	_, err = p.tx.ExecContext(ctx, "UPDATE moz_bookmarks SET lastModified = ? WHERE id = ?", p.now, folder)
+
+	return err
+}
+
+// rootID returns the id of the root folder with guid.
+func (p *placesWriter) rootID(ctx context.Context, guid string) (int64, error) {
+	var id int64
+	if err := p.tx.GetContext(ctx, &id, "SELECT id FROM moz_bookmarks WHERE guid = ?", guid); err != nil {
+		return 0, fmt.Errorf("root folder %q: %w", guid, err)
+	}
+
+	return id, nil
+}
+
+// folder returns the id of the folder named title in parent, creating it.
+func (p *placesWriter) folder(ctx context.Context, parent int64, title string) (int64, error) {
+	var id int64
+	err := p.tx.GetContext(ctx, &id,
+		"SELECT id FROM moz_bookmarks WHERE parent = ? AND type = ? AND title = ? LIMIT 1", parent, typeFolder, title)
+	if err == nil {
+		return id, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return 0, fmt.Errorf("folder %q: %w", title, err)
+	}
+
+	res, err := p.tx.ExecContext(ctx, `
+		INSERT INTO moz_bookmarks (type, parent, position, title, dateAdded, lastModified, guid)
+		VALUES (?, ?, (SELECT COALESCE(MAX(position) + 1, 0) FROM moz_bookmarks WHERE parent = ?), ?, ?, ?, ?)`,
+		typeFolder, parent, parent, title, p.now, p.now, newGUID())
+	if err != nil {
+		return 0, fmt.Errorf("creating folder %q: %w", title, err)
+	}
+
+	return res.LastInsertId()
+}
+
+// clear removes the bookmarks of folder, and the tags of the places that are
+// no longer bookmarked anywhere else. The tags of places kept in other
+// folders are shared with them and left alone.
+func (p *placesWriter) clear(ctx context.Context, folder, tagsRoot int64) error {
+	q := `
+		UPDATE moz_places SET foreign_count = MAX(foreign_count - 1, 0)
+		WHERE id IN (SELECT fk FROM moz_bookmarks WHERE parent = ? AND type = ?)`
+	if _, err := p.tx.ExecContext(ctx, q, folder, typeBookmark); err != nil {
+		return fmt.Errorf("clearing folder: %w", err)
+	}
+
+	if _, err := p.tx.ExecContext(ctx, "DELETE FROM moz_bookmarks WHERE parent = ? AND type = ?", folder, typeBookmark); err != nil {
+		return fmt.Errorf("clearing folder: %w", err)
+	}
+
+	// tag entries of places without any bookmark left
+	orphans := `
+		SELECT fk FROM moz_bookmarks
+		WHERE parent IN (SELECT id FROM moz_bookmarks WHERE parent = :root)
+		EXCEPT
+		SELECT fk FROM moz_bookmarks
+		WHERE type = :type AND fk IS NOT NULL
+		  AND parent NOT IN (SELECT id FROM moz_bookmarks WHERE parent = :root)`
+	args := []any{sql.Named("root", tagsRoot), sql.Named("type", typeBookmark)}
+
+	q = `
+		UPDATE moz_places SET foreign_count = MAX(foreign_count - (
+			SELECT COUNT(*) FROM moz_bookmarks
+			WHERE fk = moz_places.id AND parent IN (SELECT id FROM moz_bookmarks WHERE parent = :root)
+		), 0)
+		WHERE id IN (` + orphans + `)`
+	if _, err := p.tx.ExecContext(ctx, q, args...); err != nil {
+		return fmt.Errorf("clearing tags: %w", err)
+	}
+
+	q = `
+		DELETE FROM moz_bookmarks
+		WHERE parent IN (SELECT id FROM moz_bookmarks WHERE parent = :root)
+		  AND fk IN (` + orphans + `)`
+	if _, err := p.tx.ExecContext(ctx, q, args...); err != nil {
+		return fmt.Errorf("clearing tags: %w", err)
+	}
+
+	return nil
+}
+
+// pruneTags removes the tag folders left empty.
+func (p *placesWriter) pruneTags(ctx context.Context, tagsRoot int64) error {
+	q := `
+		DELETE FROM moz_bookmarks
+		WHERE parent = ? AND type = ?
+		  AND id NOT IN (SELECT parent FROM moz_bookmarks WHERE parent IS NOT NULL)`
+	if _, err := p.tx.ExecContext(ctx, q, tagsRoot, typeFolder); err != nil {
+		return fmt.Errorf("removing empty tags: %w", err)
+	}
+
+	return nil
+}
+
+// place returns the id of the place of b, creating it.
+func (p *placesWriter) place(ctx context.Context, b *bookmark.Bookmark) (int64, error) {
+	var id int64
+	err := p.tx.GetContext(ctx, &id, "SELECT id FROM moz_places WHERE url = ?", b.URL)
+	if err == nil {
+		return id, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return 0, fmt.Errorf("place %q: %w", b.URL, err)
+	}
+
+	res, err := p.tx.ExecContext(ctx, `
+		INSERT INTO moz_places (url, title, rev_host, guid, url_hash, frecency, foreign_count)
+		VALUES (?, ?, ?, ?, ?, -1, 0)`,
+		b.URL, b.Title, revHost(b.URL), newGUID(), hashURL(b.URL))
+	if err != nil {
+		return 0, fmt.Errorf("inserting place %q: %w", b.URL, err)
+	}
+
+	return res.LastInsertId()
+}
+
+// insertBookmark adds a bookmark for the place in parent.
+func (p *placesWriter) insertBookmark(ctx context.Context, placeID, parent int64, pos int, title string, added int64) error {
+	_, err := p.tx.ExecContext(ctx, `
+		INSERT INTO moz_bookmarks (type, fk, parent, position, title, dateAdded, lastModified, guid)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		typeBookmark, placeID, parent, pos, title, added, p.now, newGUID())
+	if err != nil {
+		return fmt.Errorf("inserting bookmark: %w", err)
+	}
+
+	_, err = p.tx.ExecContext(ctx, "UPDATE moz_places SET foreign_count = foreign_count + 1 WHERE id = ?", placeID)
+
+	return err
+}
+
+// tag adds the tag to the place, tags are folders of the tags root holding
+// an untitled entry per place.
+func (p *placesWriter) tag(ctx context.Context, tagsRoot, placeID int64, tag string) error {
+	folder, err := p.folder(ctx, tagsRoot, tag)
+	if err != nil {
+		return err
+	}
+
+	var n int
+	err = p.tx.GetContext(ctx, &n, "SELECT COUNT(*) FROM moz_bookmarks WHERE parent = ? AND fk = ?", folder, placeID)
+	if err != nil || n > 0 {
+		return err
+	}
+
+	_, err = p.tx.ExecContext(ctx, `
+		INSERT INTO moz_bookmarks (type, fk, parent, position, title, dateAdded, lastModified, guid)
+		VALUES (?, ?, ?, (SELECT COALESCE(MAX(position) + 1, 0) FROM moz_bookmarks WHERE parent = ?), NULL, ?, ?, ?)`,
+		typeBookmark, placeID, folder, folder, p.now, p.now, newGUID())
+	if err != nil {
+		return fmt.Errorf("tagging %q: %w", tag, err)
+	}
+
+	_, err = p.tx.ExecContext(ctx, "UPDATE moz_places SET foreign_count = foreign_count + 1 WHERE id = ?", placeID)
+
+	return err
+}
+
+// newGUID returns a random places GUID, 12 URL-safe base64 characters.
+func newGUID() string {
+	var b [9]byte
+	_, _ = rand.Read(b[:])
+
+	return base64.RawURLEncoding.EncodeToString(b[:])
+}
+
+// revHost returns the reversed host of rawURL followed by a dot, as stored
+// in moz_places.rev_host.
+func revHost(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+
+	host := []rune(strings.ToLower(u.Hostname()))
+	for i, j := 0, len(host)-1; i < j; i, j = i+1, j-1 {
+		host[i], host[j] = host[j], host[i]
+	}
+
+	return string(host) + "."
+}
+
+// hashURL returns the moz_places.url_hash of spec, the 16 bit hash of the
+// scheme followed by the 32 bit hash of the first 1500 characters.
+func hashURL(spec string) uint64 {
+	const maxCharsToHash = 1500
+
+	strHash := uint64(hashString(spec[:min(len(spec), maxCharsToHash)]))
+
+	head := spec[:min(len(spec), 50)]
+	if i := strings.IndexByte(head, ':'); i >= 0 {
+		prefixHash := uint64(hashString(head[:i]) & 0x0000FFFF)
+		return prefixHash<<32 + strHash
+	}
+
+	return strHash
+}
+
+// hashString is the golden ratio string hash used by Firefox.
+func hashString(s string) uint32 {
+	const goldenRatio = 0x9E3779B9
+
+	var h uint32
+	for i := range len(s) {
+		h = goldenRatio * (bits.RotateLeft32(h, 5) ^ uint32(s[i]))
+	}
+
+	return h
+}
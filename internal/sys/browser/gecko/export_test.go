@@ -0,0 +1,196 @@
+package gecko
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/mateconpizza/gm/internal/sys/browser"
+	"github.com/mateconpizza/gm/pkg/bookmark"
+)
+
+// testPlacesFile creates a places database file with the root folders and
+// the columns written by the export.
+func testPlacesFile(t *testing.T) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "places.sqlite")
+	r, err := sqlx.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stmts := []string{
+		`CREATE TABLE moz_places (id INTEGER PRIMARY KEY, url TEXT UNIQUE, title TEXT, rev_host TEXT,
+			visit_count INTEGER DEFAULT 0, frecency INTEGER DEFAULT -1, guid TEXT UNIQUE,
+			foreign_count INTEGER DEFAULT 0 NOT NULL, url_hash INTEGER DEFAULT 0 NOT NULL)`,
+		`CREATE TABLE moz_bookmarks (id INTEGER PRIMARY KEY, type INTEGER, fk INTEGER DEFAULT NULL,
+			parent INTEGER, position INTEGER, title TEXT, dateAdded INTEGER, lastModified INTEGER,
+			guid TEXT UNIQUE)`,
+		`INSERT INTO moz_bookmarks (id, type, parent, position, title, guid) VALUES
+			(1, 2, 0, 0, '', 'root________'), (2, 2, 1, 0, 'menu', 'menu________'),
+			(4, 2, 1, 2, 'tags', 'tags________'), (5, 2, 1, 3, 'unfiled', 'unfiled_____')`,
+		`INSERT INTO moz_places (id, url, title, guid, foreign_count) VALUES
+			(100, 'https://go.dev/', 'Go', 'placeguid100', 1)`,
+		`INSERT INTO moz_bookmarks (type, fk, parent, position, title, guid) VALUES
+			(1, 100, 2, 0, 'Go', 'bookmark0001')`,
+	}
+	for _, s := range stmts {
+		if _, err := r.Exec(s); err != nil {
+			t.Fatalf("%v: %s", err, s)
+		}
+	}
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestExportToPlaces(t *testing.T) {
+	t.Parallel()
+
+	path := testPlacesFile(t)
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	bs := []*bookmark.Bookmark{
+		{URL: "https://go.dev/", Title: "Go site", Tags: "go,dev,", CreatedAt: "2024-12-21T12:17:23Z"},
+		{URL: "https://a.example/", Title: "A", Tags: "notag,"},
+		{URL: "https://private.example/", Title: "P", Private: true},
+	}
+
+	// a second export replaces the folder content
+	for range 2 {
+		if err := exportToPlaces(t.Context(), path, bs, now); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r, err := sqlx.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	var folders []int64
+	q := `SELECT id FROM moz_bookmarks WHERE type = 2 AND title = ? AND parent = 5`
+	if err := r.Select(&folders, q, browser.ExportFolder); err != nil {
+		t.Fatal(err)
+	}
+	if len(folders) != 1 {
+		t.Fatalf("expected a single %q folder, got %d", browser.ExportFolder, len(folders))
+	}
+
+	var got []struct {
+		URL       string `db:"url"`
+		Title     string `db:"title"`
+		DateAdded int64  `db:"dateAdded"`
+		Count     int    `db:"foreign_count"`
+	}
+	q = `SELECT p.url, b.title, b.dateAdded, p.foreign_count FROM moz_bookmarks b
+		JOIN moz_places p ON p.id = b.fk WHERE b.parent = ? ORDER BY b.position`
+	if err := r.Select(&got, q, folders[0]); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 bookmarks, got %+v", got)
+	}
+
+	goDev := got[0]
+	if goDev.URL != "https://go.dev/" || goDev.Title != "Go site" {
+		t.Errorf("unexpected bookmark: %+v", goDev)
+	}
+	if want := time.Date(2024, 12, 21, 12, 17, 23, 0, time.UTC).UnixMicro(); goDev.DateAdded != want {
+		t.Errorf("creation date: expected %d, got %d", want, goDev.DateAdded)
+	}
+	// menu bookmark, gm bookmark and two tags
+	if goDev.Count != 4 {
+		t.Errorf("foreign count: expected 4, got %d", goDev.Count)
+	}
+
+	var tags []string
+	q = `SELECT t.title FROM moz_bookmarks b JOIN moz_bookmarks t ON t.id = b.parent
+		WHERE t.parent = 4 AND b.fk = 100 ORDER BY t.title`
+	if err := r.Select(&tags, q); err != nil {
+		t.Fatal(err)
+	}
+	if len(tags) != 2 || tags[0] != "dev" || tags[1] != "go" {
+		t.Errorf("expected tags [dev go], got %v", tags)
+	}
+
+	var revHost string
+	if err := r.Get(&revHost, "SELECT rev_host FROM moz_places WHERE url = 'https://a.example/'"); err != nil {
+		t.Fatal(err)
+	}
+	if revHost != "elpmaxe.a." {
+		t.Errorf("rev_host: expected %q, got %q", "elpmaxe.a.", revHost)
+	}
+}
+
+func TestExportToPlacesRemovesTags(t *testing.T) {
+	t.Parallel()
+
+	path := testPlacesFile(t)
+	now := time.Now()
+	bs := []*bookmark.Bookmark{
+		{URL: "https://go.dev/", Title: "Go", Tags: "go,"},
+		{URL: "https://a.example/", Title: "A", Tags: "misc,"},
+	}
+	if err := exportToPlaces(t.Context(), path, bs, now); err != nil {
+		t.Fatal(err)
+	}
+	if err := exportToPlaces(t.Context(), path, nil, now); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := sqlx.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	// the menu bookmark keeps the tag of go.dev
+	var tags []string
+	if err := r.Select(&tags, "SELECT title FROM moz_bookmarks WHERE parent = 4 ORDER BY title"); err != nil {
+		t.Fatal(err)
+	}
+	if len(tags) != 1 || tags[0] != "go" {
+		t.Errorf("expected tags [go], got %v", tags)
+	}
+
+	var count int
+	if err := r.Get(&count, "SELECT foreign_count FROM moz_places WHERE url = 'https://a.example/'"); err != nil {
+		t.Fatal(err)
+	}
+	if count != 0 {
+		t.Errorf("foreign count: expected 0, got %d", count)
+	}
+}
+
+func TestExportToPlacesLocked(t *testing.T) {
+	t.Parallel()
+
+	path := testPlacesFile(t)
+	r, err := sqlx.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	// the browser holds the database in exclusive locking mode
+	conn, err := r.Conn(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if _, err := conn.ExecContext(t.Context(), "BEGIN EXCLUSIVE"); err != nil {
+		t.Fatal(err)
+	}
+
+	err = exportToPlaces(t.Context(), path, []*bookmark.Bookmark{{URL: "https://a.example/"}}, time.Now())
+	if !errors.Is(err, ErrBrowserIsOpen) {
+		t.Errorf("expected %v, got %v", ErrBrowserIsOpen, err)
+	}
+}
@@ -6,8 +6,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
+	"path/filepath"
 	"slices"
 	"strings"
 	"time"
@@ -50,11 +52,6 @@ var Supported = []browser.Supported{
 	{Browser: New("Waterfox")},
 }
 
-func getTodayFormatted() string {
-	today := time.Now()
-	return today.Format("2006Jan02")
-}
-
 var geckoBrowserPaths = map[string]Paths{
 	"Firefox": {
 		profiles: []string{
@@ -121,7 +118,28 @@ func (b *GeckoBrowser) LoadPaths() error {
 	return nil
 }
 
-func (b *GeckoBrowser) Import(ctx context.Context, c *ui.Console, force bool) ([]*bookmark.Bookmark, error) {
+// queryFunc reads the bookmark candidates from the places database.
+type queryFunc func(r *sqlx.DB, opts browser.Options) ([]*geckoBookmark, error)
+
+func (b *GeckoBrowser) Import(ctx context.Context, c *ui.Console, opts browser.Options) ([]*bookmark.Bookmark, error) {
+	return b.collect(ctx, c, opts, queryBookmarks, false)
+}
+
+// History returns the frequently visited pages of every profile. It reads a
+// copy of the places database, so it works while the browser is running.
+func (b *GeckoBrowser) History(ctx context.Context, c *ui.Console, opts browser.Options) ([]*bookmark.Bookmark, error) {
+	return b.collect(ctx, c, opts, queryHistory, true)
+}
+
+// collect runs query on the places database of every profile, or on a copy
+// of it when snapshot is set.
+func (b *GeckoBrowser) collect(
+	ctx context.Context,
+	c *ui.Console,
+	opts browser.Options,
+	query queryFunc,
+	snapshot bool,
+) ([]*bookmark.Bookmark, error) {
 	profilesPath, bookmarksPath := b.processPaths()
 	if profilesPath == "" {
 		return nil, fmt.Errorf("%w: profiles filepath: empty", ErrBrowserConfigPathNotSet)
@@ -154,9 +172,9 @@ func (b *GeckoBrowser) Import(ctx context.Context, c *ui.Console, force bool) ([
 		if err := ctx.Err(); err != nil {
 			return nil, err
 		}
-		bookmarksPath = fmt.Sprintf(bookmarksPath, v)
+		path := fmt.Sprintf(bookmarksPath, v)
 
-		if err := processProfile(ctx, c, &bs, profileName, bookmarksPath, force); err != nil {
+		if err := processProfile(ctx, c, &bs, profileName, path, opts, query, snapshot); err != nil {
 			if !errors.Is(err, os.ErrNotExist) {
 				return nil, err
 			}
@@ -188,12 +206,26 @@ func (b *GeckoBrowser) processPaths() (profilePath, bookmarksPath string) {
 	return profilePath, bookmarksPath
 }
 
+// geckoBookmark is a row of moz_bookmarks joined with its place. Times are
+// PRTime, microseconds since the Unix epoch.
 type geckoBookmark struct {
-	FK     int    `db:"fk"`
+	FK           int    `db:"fk"`
+	Parent       int    `db:"parent"`
+	Title        string `db:"title"`
+	URL          string `db:"url"`
+	DateAdded    int64  `db:"date_added"`
+	LastModified int64  `db:"last_modified"`
+	LastVisit    int64  `db:"last_visit"`
+	VisitCount   int    `db:"visit_count"`
+	Keyword      string `db:"keyword"`
+	Tags         string `db:"tags"`
+}
+
+// geckoFolder is a folder of moz_bookmarks.
+type geckoFolder struct {
+	ID     int    `db:"id"`
 	Parent int    `db:"parent"`
 	Title  string `db:"title"`
-	URL    string `db:"url"`
-	Tags   string `db:"tags"`
 }
 
 // openSQLite opens the SQLite database and returns a *sql.DB object.
@@ -231,46 +263,149 @@ func isNonGenericURL(url string) bool {
 	})
 }
 
-// queryBookmarks queries the bookmarks table to retrieve some sample data.
-func queryBookmarks(r *sqlx.DB) ([]*geckoBookmark, error) {
-	q := "SELECT DISTINCT fk, parent, title FROM moz_bookmarks WHERE type=1 AND title IS NOT NULL"
+// queryBookmarks queries the bookmarks with their places, keywords, folder
+// and tags.
+func queryBookmarks(r *sqlx.DB, opts browser.Options) ([]*geckoBookmark, error) {
+	const q = `
+	SELECT DISTINCT
+		b.fk,
+		b.parent,
+		b.title,
+		p.url,
+		COALESCE(b.dateAdded, 0) AS date_added,
+		COALESCE(b.lastModified, 0) AS last_modified,
+		COALESCE(p.last_visit_date, 0) AS last_visit,
+		COALESCE(p.visit_count, 0) AS visit_count,
+		COALESCE((SELECT keyword FROM moz_keywords k WHERE k.place_id = b.fk LIMIT 1), '') AS keyword
+	FROM moz_bookmarks b
+	JOIN moz_places p ON p.id = b.fk
+	WHERE b.type = 1 AND b.title IS NOT NULL`
+
 	var bs []*geckoBookmark
+	if err := r.Select(&bs, q); err != nil {
+		return nil, fmt.Errorf("failed to query bookmarks: %w", err)
+	}
 
-	err := r.Select(&bs, q)
+	folders, err := queryFolders(r)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query bookmarks: %w", err)
+		return nil, err
 	}
 
-	parseGeckoBookmark := func(gb *geckoBookmark) error {
-		gb.URL, err = processURLs(r, gb.FK)
-		if err != nil {
-			return err
+	for _, gb := range bs {
+		if isNonGenericURL(gb.URL) {
+			gb.URL = ""
+			continue
 		}
 
-		if gb.URL == "" {
-			return nil
+		t, err := processTags(r, gb.FK)
+		if err != nil {
+			return nil, err
 		}
 
-		gb.Tags, err = processTags(r, gb.FK)
-		if err != nil {
-			return err
+		var tags []string
+		for _, s := range []string{t, browser.FolderTag(folders.path(gb.Parent))} {
+			if s != "" {
+				tags = append(tags, s)
+			}
+		}
+		if gb.Keyword != "" {
+			tags = append(tags, "keyword:"+gb.Keyword)
+		}
+		if opts.TodayTag {
+			tags = append(tags, browser.TodayTag())
 		}
 
-		gb.Tags += " " + getTodayFormatted()
-		gb.Tags = bookmark.ParseTags(gb.Tags)
+		gb.Tags = bookmark.ParseTags(strings.Join(tags, ","))
+	}
 
-		return nil
+	return bs, nil
+}
+
+// queryHistory queries the visible pages visited at least opts.MinVisits
+// times since opts.Since, most visited first. Only the visits made by
+// following a link, typing the URL or opening a bookmark are counted,
+// embedded content, redirects, framed links, downloads and reloads are not.
+func queryHistory(r *sqlx.DB, opts browser.Options) ([]*geckoBookmark, error) {
+	const q = `
+	SELECT
+		p.url,
+		COALESCE(p.title, '') AS title,
+		MIN(v.visit_date) AS date_added,
+		COALESCE(p.last_visit_date, 0) AS last_visit,
+		COALESCE(p.visit_count, 0) AS visit_count
+	FROM moz_places p
+	JOIN moz_historyvisits v ON v.place_id = p.id
+	WHERE v.visit_date >= ? AND p.hidden = 0 AND v.visit_type IN (1, 2, 3)
+	GROUP BY p.id
+	HAVING COUNT(v.id) >= ?
+	ORDER BY COUNT(v.id) DESC`
+
+	var since int64
+	if !opts.Since.IsZero() {
+		since = opts.Since.UnixMicro()
 	}
 
-	for _, b := range bs {
-		if err := parseGeckoBookmark(b); err != nil {
-			return nil, err
+	var bs []*geckoBookmark
+	if err := r.Select(&bs, q, since, max(opts.MinVisits, 1)); err != nil {
+		return nil, fmt.Errorf("failed to query history: %w", err)
+	}
+
+	for _, gb := range bs {
+		if isNonGenericURL(gb.URL) {
+			gb.URL = ""
+			continue
+		}
+
+		var tags string
+		if opts.TodayTag {
+			tags = browser.TodayTag()
 		}
+		gb.Tags = bookmark.ParseTags(tags)
 	}
 
 	return bs, nil
 }
 
+// folderTree maps the folder IDs to their folder.
+type folderTree map[int]*geckoFolder
+
+// queryFolders returns all the bookmark folders.
+func queryFolders(r *sqlx.DB) (folderTree, error) {
+	var fs []*geckoFolder
+	if err := r.Select(&fs, "SELECT id, parent, COALESCE(title, '') AS title FROM moz_bookmarks WHERE type = 2"); err != nil {
+		return nil, fmt.Errorf("failed to query folders: %w", err)
+	}
+
+	t := make(folderTree, len(fs))
+	for _, f := range fs {
+		t[f.ID] = f
+	}
+
+	return t, nil
+}
+
+// path returns the folder names from the top folder down to id, without the
+// built-in roots (menu, toolbar, other, mobile).
+func (t folderTree) path(id int) []string {
+	var names []string
+	for f, ok := t[id]; ok && !t.isRoot(f); f, ok = t[f.Parent] {
+		names = append([]string{f.Title}, names...)
+	}
+
+	return names
+}
+
+// isRoot reports whether f is the places root or one of its children.
+func (t folderTree) isRoot(f *geckoFolder) bool {
+	if f.Parent == 0 {
+		return true
+	}
+
+	parent, ok := t[f.Parent]
+
+	return !ok || parent.Parent == 0
+}
+
 func allProfiles(p string) (map[string]string, error) {
 	inidata, err := ini.Load(p)
 	if err != nil {
@@ -294,8 +429,16 @@ func allProfiles(p string) (map[string]string, error) {
 }
 
 // processProfile processes a single profile and extracts bookmarks.
-func processProfile(ctx context.Context, c *ui.Console, bs *[]*bookmark.Bookmark, profile, path string, force bool) error {
-	if !confirmImport(ctx, c, profile, force) {
+func processProfile(
+	ctx context.Context,
+	c *ui.Console,
+	bs *[]*bookmark.Bookmark,
+	profile, path string,
+	opts browser.Options,
+	query queryFunc,
+	snapshot bool,
+) error {
+	if !confirmImport(ctx, c, profile, opts.Force) {
 		return nil
 	}
 
@@ -305,6 +448,15 @@ func processProfile(ctx context.Context, c *ui.Console, bs *[]*bookmark.Bookmark
 		return fmt.Errorf("%w: %q", err, path)
 	}
 
+	if snapshot {
+		tmp, err := copyToTemp(path)
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(filepath.Dir(tmp))
+		path = tmp
+	}
+
 	r, err := openSQLite(ctx, c, path)
 	if err != nil {
 		handleDBError(c, p, profile, err)
@@ -319,7 +471,7 @@ func processProfile(ctx context.Context, c *ui.Console, bs *[]*bookmark.Bookmark
 		slog.Debug("database for profile closed", "profile", profile)
 	}()
 
-	gmarks, err := queryBookmarks(r)
+	gmarks, err := query(r, opts)
 	if err != nil {
 		fmt.Fprintf(c.Writer(), "err querying bookmarks for profile %q: %v\n", profile, err)
 		return err
@@ -374,6 +526,10 @@ func importBookmarks(bs *[]*bookmark.Bookmark, gmarks []*geckoBookmark) int {
 		b.Title = gb.Title
 		b.URL = gb.URL
 		b.Tags = gb.Tags
+		b.CreatedAt = browser.FormatTime(prTime(gb.DateAdded))
+		b.UpdatedAt = browser.FormatTime(prTime(gb.LastModified))
+		b.LastVisit = browser.FormatTime(prTime(gb.LastVisit))
+		b.VisitCount = gb.VisitCount
 
 		if isDuplicate(*bs, b.URL) {
 			skipped++
@@ -428,33 +584,54 @@ func processTags(r *sqlx.DB, fk int) (string, error) {
 	return strings.Join(tags, ","), nil
 }
 
-func processURLs(r *sqlx.DB, fk int) (string, error) {
-	var url string
+// prTime converts a PRTime, microseconds since the Unix epoch.
+func prTime(us int64) time.Time {
+	if us <= 0 {
+		return time.Time{}
+	}
+
+	return time.UnixMicro(us)
+}
 
-	rows, err := r.Query("SELECT url FROM moz_places where id=?", fk)
+// copyToTemp copies the places database at path, with its write-ahead log
+// holding the changes not checkpointed yet, to a temporary directory and
+// returns the path of the copy.
+func copyToTemp(path string) (string, error) {
+	dir, err := os.MkdirTemp("", "gomarks-places-*")
 	if err != nil {
-		return url, fmt.Errorf("failed to query places: %w", err)
+		return "", err
 	}
 
-	defer func() {
-		if err := rows.Close(); err != nil {
-			slog.Error("closing rows", "err", err)
+	dst := filepath.Join(dir, filepath.Base(path))
+	for _, suffix := range []string{"", "-wal"} {
+		if err := copyFile(path+suffix, dst+suffix); err != nil {
+			if suffix != "" && errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			_ = os.RemoveAll(dir)
+			return "", fmt.Errorf("copying %q: %w", path+suffix, err)
 		}
-	}()
+	}
 
-	for rows.Next() {
-		if err := rows.Scan(&url); err != nil {
-			return url, fmt.Errorf("failed to scan url row: %w", err)
-		}
+	return dst, nil
+}
 
-		if isNonGenericURL(url) {
-			return "", nil
-		}
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
 	}
+	defer in.Close()
 
-	if err := rows.Err(); err != nil {
-		return url, fmt.Errorf("rows iteration error: %w", err)
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
 	}
 
-	return url, nil
+	return out.Close()
 }
@@ -1,6 +1,17 @@
 package gecko
 
-import "testing"
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/mateconpizza/gm/internal/sys/browser"
+	"github.com/mateconpizza/gm/pkg/bookmark"
+)
 
 func TestIsNonGenericURL(t *testing.T) {
 	t.Parallel()
@@ -34,3 +45,213 @@ func TestIsNonGenericURL(t *testing.T) {
 		}
 	}
 }
+
+// testPlaces returns an in-memory database with a subset of the Firefox
+// places schema.
+func testPlaces(t *testing.T) *sqlx.DB {
+	t.Helper()
+
+	r, err := sqlx.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = r.Close() })
+
+	r.SetMaxOpenConns(1)
+
+	stmts := []string{
+		`CREATE TABLE moz_places (id INTEGER PRIMARY KEY, url TEXT, title TEXT, visit_count INTEGER, last_visit_date INTEGER,
+			hidden INTEGER DEFAULT 0 NOT NULL)`,
+		`CREATE TABLE moz_bookmarks (id INTEGER PRIMARY KEY, type INTEGER, fk INTEGER, parent INTEGER,
+			title TEXT, dateAdded INTEGER, lastModified INTEGER)`,
+		`CREATE TABLE moz_keywords (id INTEGER PRIMARY KEY, keyword TEXT, place_id INTEGER)`,
+
+		`INSERT INTO moz_bookmarks (id, type, parent, title) VALUES
+			(1, 2, 0, ''), (2, 2, 1, 'menu'), (3, 2, 1, 'toolbar'), (4, 2, 1, 'tags'),
+			(10, 2, 3, 'Dev'), (11, 2, 10, 'Go Tools'), (20, 2, 4, 'golang')`,
+		`INSERT INTO moz_places (id, url, title, visit_count, last_visit_date) VALUES
+			(100, 'https://go.dev/', 'The Go Programming Language', 7, 1700000000000000),
+			(101, 'https://example.org/', NULL, 0, NULL),
+			(102, 'place:sort=8', NULL, 0, NULL)`,
+		`INSERT INTO moz_places (id, url, title, visit_count, last_visit_date, hidden) VALUES
+			(103, 'https://ads.example/frame', NULL, 0, NULL, 1)`,
+		`INSERT INTO moz_bookmarks (id, type, fk, parent, title, dateAdded, lastModified) VALUES
+			(30, 1, 100, 11, 'Go', 1600000000000000, 1650000000000000),
+			(31, 1, 100, 20, NULL, 1600000000000000, 1600000000000000),
+			(32, 1, 101, 2, 'Example', 1500000000000000, 1500000000000000),
+			(33, 1, 102, 3, 'Recent', 0, 0)`,
+		`INSERT INTO moz_keywords VALUES (1, 'go', 100)`,
+
+		`CREATE TABLE moz_historyvisits (id INTEGER PRIMARY KEY, place_id INTEGER, visit_date INTEGER,
+			visit_type INTEGER DEFAULT 1)`,
+		`INSERT INTO moz_historyvisits (place_id, visit_date) VALUES
+			(100, 1690000000000000), (100, 1695000000000000), (100, 1700000000000000),
+			(101, 1000000000000000), (101, 1000000000000001), (101, 1000000000000002),
+			(102, 1700000000000000), (102, 1700000000000001), (102, 1700000000000002),
+			(103, 1700000000000000), (103, 1700000000000001), (103, 1700000000000002)`,
+		// embedded and redirected visits are not counted
+		`INSERT INTO moz_historyvisits (place_id, visit_date, visit_type) VALUES
+			(100, 1700000000000003, 4), (101, 1700000000000003, 5), (101, 1700000000000004, 6),
+			(101, 1700000000000005, 4)`,
+	}
+	for _, s := range stmts {
+		if _, err := r.Exec(s); err != nil {
+			t.Fatalf("%v: %s", err, s)
+		}
+	}
+
+	return r
+}
+
+func TestQueryBookmarks(t *testing.T) {
+	t.Parallel()
+
+	r := testPlaces(t)
+
+	gbs, err := queryBookmarks(r, browser.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var bs []*bookmark.Bookmark
+	importBookmarks(&bs, gbs)
+	if len(bs) != 2 {
+		t.Fatalf("expected 2 bookmarks, got %d", len(bs))
+	}
+
+	byURL := map[string]*bookmark.Bookmark{}
+	for _, b := range bs {
+		byURL[b.URL] = b
+	}
+
+	goDev := byURL["https://go.dev/"]
+	if goDev == nil {
+		t.Fatal("https://go.dev/ not imported")
+	}
+	if want := "dev/go-tools,golang,keyword:go,"; goDev.Tags != want {
+		t.Errorf("tags: expected %q, got %q", want, goDev.Tags)
+	}
+	if want := "2020-09-13T12:26:40Z"; goDev.CreatedAt != want {
+		t.Errorf("created_at: expected %q, got %q", want, goDev.CreatedAt)
+	}
+	if want := "2022-04-15T05:20:00Z"; goDev.UpdatedAt != want {
+		t.Errorf("updated_at: expected %q, got %q", want, goDev.UpdatedAt)
+	}
+	if want := "2023-11-14T22:13:20Z"; goDev.LastVisit != want || goDev.VisitCount != 7 {
+		t.Errorf("visits: expected %q and 7, got %q and %d", want, goDev.LastVisit, goDev.VisitCount)
+	}
+
+	example := byURL["https://example.org/"]
+	if example == nil {
+		t.Fatal("https://example.org/ not imported")
+	}
+	if example.Tags != bookmark.DefaultTag || example.LastVisit != "" {
+		t.Errorf("expected no tags nor visit, got %q and %q", example.Tags, example.LastVisit)
+	}
+
+	gbs, err = queryBookmarks(r, browser.Options{TodayTag: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, gb := range gbs {
+		if gb.URL != "" && !strings.Contains(gb.Tags, browser.TodayTag()) {
+			t.Errorf("expected today tag in %q", gb.Tags)
+		}
+	}
+}
+
+func TestQueryHistory(t *testing.T) {
+	t.Parallel()
+
+	r := testPlaces(t)
+
+	tests := []struct {
+		name  string
+		opts  browser.Options
+		wantN int
+	}{
+		{name: "all time", opts: browser.Options{MinVisits: 3}, wantN: 2},
+		{name: "since filters old visits", opts: browser.Options{MinVisits: 3, Since: time.UnixMicro(1600000000000000)}, wantN: 1},
+		{name: "min visits", opts: browser.Options{MinVisits: 4}, wantN: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gbs, err := queryHistory(r, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var bs []*bookmark.Bookmark
+			importBookmarks(&bs, gbs)
+			if len(bs) != tt.wantN {
+				t.Fatalf("expected %d pages, got %d", tt.wantN, len(bs))
+			}
+		})
+	}
+
+	gbs, err := queryHistory(r, browser.Options{MinVisits: 3, Since: time.UnixMicro(1600000000000000)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var bs []*bookmark.Bookmark
+	importBookmarks(&bs, gbs)
+	b := bs[0]
+	if b.URL != "https://go.dev/" || b.Title != "The Go Programming Language" ||
+		b.VisitCount != 7 || b.Tags != bookmark.DefaultTag {
+		t.Errorf("unexpected page: %+v", b)
+	}
+	if want := "2023-11-14T22:13:20Z"; b.LastVisit != want {
+		t.Errorf("last visit: expected %q, got %q", want, b.LastVisit)
+	}
+	if want := "2023-07-22T04:26:40Z"; b.CreatedAt != want {
+		t.Errorf("first visit: expected %q, got %q", want, b.CreatedAt)
+	}
+}
+
+func TestCopyToTemp(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "places.sqlite")
+	r, err := sqlx.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	// the open connection keeps the rows in the write-ahead log
+	r.SetMaxOpenConns(1)
+	for _, s := range []string{
+		"PRAGMA journal_mode = WAL",
+		"PRAGMA wal_autocheckpoint = 0",
+		"CREATE TABLE moz_places (id INTEGER PRIMARY KEY, url TEXT)",
+		"INSERT INTO moz_places (url) VALUES ('https://go.dev/')",
+	} {
+		if _, err := r.Exec(s); err != nil {
+			t.Fatalf("%v: %s", err, s)
+		}
+	}
+
+	tmp, err := copyToTemp(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(filepath.Dir(tmp))
+
+	cp, err := sqlx.Open("sqlite", tmp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cp.Close()
+
+	var n int
+	if err := cp.Get(&n, "SELECT COUNT(*) FROM moz_places"); err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Errorf("expected 1 place in the copy, got %d", n)
+	}
+}
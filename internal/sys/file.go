@@ -0,0 +1,81 @@
+package sys
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// WriteFileAtomic writes data to a temporary file next to path and renames
+// it over path, readers see either the old or the new content. Both the file
+// and its directory are synced, so a crash cannot leave path empty or
+// truncated.
+func WriteFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+
+	tmpPath := tmp.Name()
+	defer func() { _ = os.Remove(tmpPath) }()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write temp file: %w", err)
+	}
+
+	if err := tmp.Chmod(perm); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to set permissions: %w", err)
+	}
+
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to sync temp file: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close temp file: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to replace file: %w", err)
+	}
+
+	if err := syncDir(filepath.Dir(path)); err != nil {
+		return fmt.Errorf("failed to sync directory: %w", err)
+	}
+
+	slog.Debug("replaced file", "path", path)
+
+	return nil
+}
+
+// syncDir flushes the entries of a directory to disk, e.g. a rename. Windows
+// cannot sync directories, renames are durable there once they return.
+func syncDir(dir string) error {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = d.Close() }()
+
+	return d.Sync()
+}
+
+// FileMode returns the permissions of the file at path, or def when it does
+// not exist yet, so a replaced file keeps the mode it had.
+func FileMode(path string, def os.FileMode) os.FileMode {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return def
+	}
+
+	return fi.Mode().Perm()
+}
@@ -79,10 +79,17 @@ type MenuConfig struct {
 	// the FZF menu. These typically control rendering behavior
 	// (e.g., column projection, layout, preview settings).
 	Opts []menu.Option
+
+	// multiline reports whether items span several lines, the menu reads
+	// them separated by NUL instead of newlines.
+	multiline bool
 }
 
 func (m MenuConfig) Placeholder() Placeholder { return m.placeholder }
 
+// IsMultiline reports whether the menu items span several lines.
+func (m MenuConfig) IsMultiline() bool { return m.multiline }
+
 var Formatters = map[Format]Formatter{
 	Brief: {
 		Name:   Brief,
@@ -116,6 +123,7 @@ var Formatters = map[Format]Formatter{
 		Menu: MenuConfig{
 			placeholder: "{1}",
 			Opts:        []menu.Option{menu.WithMultilineView()},
+			multiline:   true,
 		},
 	},
 
@@ -134,6 +142,7 @@ var Formatters = map[Format]Formatter{
 		Menu: MenuConfig{
 			placeholder: "{2}",
 			Opts:        []menu.Option{menu.WithNth("3.."), menu.WithMultilineView()},
+			multiline:   true,
 		},
 		Hidden: true,
 	},
@@ -154,6 +163,7 @@ var Formatters = map[Format]Formatter{
 		Menu: MenuConfig{
 			placeholder: "{1}",
 			Opts:        []menu.Option{menu.WithNth("2.."), menu.WithMultilineView()},
+			multiline:   true,
 		},
 	},
 
@@ -197,6 +207,7 @@ var Formatters = map[Format]Formatter{
 		Menu: MenuConfig{
 			placeholder: "{1}",
 			Opts:        []menu.Option{menu.WithMultilineView()},
+			multiline:   true,
 		},
 		Hidden: true,
 	},
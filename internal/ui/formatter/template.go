@@ -0,0 +1,185 @@
+package formatter
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	runewidth "github.com/mattn/go-runewidth"
+
+	menu "github.com/mateconpizza/go-fzf"
+
+	"github.com/mateconpizza/gm/internal/ui/txt"
+	"github.com/mateconpizza/gm/pkg/ansi"
+	"github.com/mateconpizza/gm/pkg/bookmark"
+)
+
+var ErrTemplate = errors.New("invalid format template")
+
+// TemplateSpec describes an output format rendered by a text/template.
+type TemplateSpec struct {
+	Template    string // text/template executed with the bookmark
+	Placeholder string // fzf field holding the bookmark ID, defaults to {1}
+	WithNth     string // fzf fields shown in the menu, all when empty
+	Multiline   bool   // Items span several lines in the menu
+}
+
+// NewTemplate returns a formatter rendering bookmarks with the template in
+// spec. Besides the bookmark fields, the template can use:
+//
+//	color "bright-yellow bold" .Title   colors and styles, space separated
+//	truncate 40 .Title                  shortens to a visual width
+//	pad 40 .Title                       fills to a visual width
+//	ago .CreatedAt                      relative time, e.g. "3 days ago"
+//	domain .URL                         host of a URL
+//	status .                            colored HTTP status badge
+//	tags " " .Tags                      tags joined by a separator
+//	width                               terminal width
+func NewTemplate(name string, spec *TemplateSpec) (Formatter, error) {
+	t, err := template.New(name).Funcs(templateFuncs(nil)).Parse(spec.Template)
+	if err != nil {
+		return Formatter{}, fmt.Errorf("%w %q: %w", ErrTemplate, name, err)
+	}
+
+	var opts []menu.Option
+	if spec.WithNth != "" {
+		opts = append(opts, menu.WithNth(spec.WithNth))
+	}
+	if spec.Multiline {
+		opts = append(opts, menu.WithMultilineView())
+	}
+
+	placeholder := spec.Placeholder
+	if placeholder == "" {
+		placeholder = "{1}"
+	}
+
+	return Formatter{
+		Name:   Format(name),
+		Render: templateFunc(t),
+		Menu:   MenuConfig{placeholder: Placeholder(placeholder), Opts: opts, multiline: spec.Multiline},
+	}, nil
+}
+
+// templateFunc returns the render function of a template. The helpers are
+// bound to the console once, when it changes. Errors are rendered in place
+// of the bookmark, so a broken format shows up where it is used.
+func templateFunc(t *template.Template) Func {
+	var (
+		mu    sync.Mutex
+		bound Console
+		ct    *template.Template
+	)
+
+	bind := func(c Console) (*template.Template, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if ct != nil && c == bound {
+			return ct, nil
+		}
+
+		clone, err := t.Clone()
+		if err != nil {
+			return nil, err
+		}
+		bound, ct = c, clone.Funcs(templateFuncs(c))
+
+		return ct, nil
+	}
+
+	return func(c Console, b *bookmark.Bookmark) string {
+		ct, err := bind(c)
+		if err != nil {
+			return err.Error()
+		}
+
+		var sb strings.Builder
+		if err := ct.Execute(&sb, b); err != nil {
+			return fmt.Sprintf("%d %v", b.ID, err)
+		}
+
+		return strings.TrimRight(sb.String(), "\n")
+	}
+}
+
+// templateFuncs returns the template helpers, colors come from the console
+// palette.
+func templateFuncs(c Console) template.FuncMap {
+	var p *ansi.Palette
+	width := 80
+	if c != nil {
+		p, width = c.Palette(), c.MaxWidth()
+	} else {
+		p = ansi.NewPalette()
+	}
+
+	return template.FuncMap{
+		"color": func(styles string, a ...any) string {
+			return paletteStyle(p, styles).Sprint(a...)
+		},
+		"truncate": func(n int, s string) string { return txt.Shorten(s, n) },
+		"pad":      func(n int, s string) string { return runewidth.FillRight(s, n) },
+		"ago":      relativeTime,
+		"domain": func(s string) string {
+			u, err := url.Parse(s)
+			if err != nil {
+				return ""
+			}
+			return u.Host
+		},
+		"status": func(b *bookmark.Bookmark) string {
+			label := "---"
+			if b.HTTPStatusCode != 0 {
+				label = strconv.Itoa(b.HTTPStatusCode)
+			}
+			return txt.HTTPStatusCodeColor(b.HTTPStatusCode, p).Sprint(label)
+		},
+		"tags":  func(sep, s string) string { return txt.TagsWith(s, sep) },
+		"width": func() int { return width },
+	}
+}
+
+// paletteStyle returns the combined SGR of space separated color and style
+// names, e.g. "bright-yellow bold". Unknown names are ignored.
+func paletteStyle(p *ansi.Palette, names string) ansi.SGR {
+	styles := map[string]ansi.SGR{
+		"black": p.Black, "red": p.Red, "green": p.Green, "yellow": p.Yellow,
+		"blue": p.Blue, "magenta": p.Magenta, "cyan": p.Cyan, "white": p.White,
+		"gray": p.Gray, "orange": p.Orange,
+		"bright-black": p.BrightBlack, "bright-red": p.BrightRed, "bright-green": p.BrightGreen,
+		"bright-yellow": p.BrightYellow, "bright-blue": p.BrightBlue, "bright-magenta": p.BrightMagenta,
+		"bright-cyan": p.BrightCyan, "bright-white": p.BrightWhite,
+		"bold": p.Bold, "dim": p.Dim, "italic": p.Italic, "underline": p.Underline,
+		"inverse": p.Inverse, "strikethrough": p.Strikethrough,
+	}
+
+	var out []ansi.SGR
+	for name := range strings.FieldsSeq(strings.ToLower(names)) {
+		if s, ok := styles[name]; ok {
+			out = append(out, s)
+		}
+	}
+
+	if len(out) == 0 {
+		return p.Normal
+	}
+
+	return out[0].With(out[1:]...)
+}
+
+// relativeTime describes a bookmark timestamp relative to now.
+func relativeTime(ts string) string {
+	for _, layout := range []string{time.DateTime, time.RFC3339} {
+		if t, err := time.Parse(layout, ts); err == nil {
+			return txt.RelativeISOTime(t.UTC().Format(time.RFC3339))
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,112 @@
+package formatter
+
+import (
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/mateconpizza/gm/internal/ui/frame"
+	"github.com/mateconpizza/gm/pkg/ansi"
+	"github.com/mateconpizza/gm/pkg/bookmark"
+)
+
+type testConsole struct{}
+
+func (testConsole) Frame() *frame.Frame    { return frame.New() }
+func (testConsole) MaxWidth() int          { return 80 }
+func (testConsole) MinWidth() int          { return 40 }
+func (testConsole) Palette() *ansi.Palette { return ansi.NewPalette() }
+func (testConsole) Writer() io.Writer      { return io.Discard }
+
+func TestNewTemplate(t *testing.T) {
+	t.Parallel()
+
+	b := &bookmark.Bookmark{
+		ID: 7, URL: "https://go.dev/doc/", Title: "The Go Programming Language",
+		Tags: "go,lang,", HTTPStatusCode: 200, CreatedAt: "2001-01-01 00:00:00",
+	}
+
+	tests := []struct {
+		tmpl string
+		want string
+	}{
+		{`{{.ID}} {{truncate 10 .Title}}`, "7 The Go Pr…"},
+		{`{{domain .URL}} [{{tags "|" .Tags}}]`, "go.dev [go|lang]"},
+		{`[{{pad 5 "go"}}]`, "[go   ]"},
+		{`{{ago .CreatedAt}}`, "years ago"},
+		{"{{.ID}}\n{{.URL}}\n", "7\nhttps://go.dev/doc/"},
+		{`{{.Missing}}`, "7 template: "},
+	}
+
+	for _, tt := range tests {
+		fm, err := NewTemplate("test", &TemplateSpec{Template: tt.tmpl})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got := ansi.NewPalette().Remover(fm.Render(testConsole{}, b))
+		if !strings.Contains(got, tt.want) {
+			t.Errorf("%q: got %q, want %q", tt.tmpl, got, tt.want)
+		}
+	}
+}
+
+// widthConsole counts the reads of its width.
+type widthConsole struct {
+	testConsole
+	reads int
+}
+
+func (c *widthConsole) MaxWidth() int { c.reads++; return 80 }
+
+func TestNewTemplateBindsOnce(t *testing.T) {
+	t.Parallel()
+
+	fm, err := NewTemplate("test", &TemplateSpec{Template: "{{.ID}} {{width}}"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := &widthConsole{}
+	for i := range 3 {
+		if got := fm.Render(c, &bookmark.Bookmark{ID: i}); got != strconv.Itoa(i)+" 80" {
+			t.Errorf("unexpected render %q", got)
+		}
+	}
+	if c.reads != 1 {
+		t.Errorf("expected the helpers bound once, width read %d times", c.reads)
+	}
+}
+
+func TestNewTemplateMenu(t *testing.T) {
+	t.Parallel()
+
+	fm, err := NewTemplate("test", &TemplateSpec{Template: "{{.ID}}", WithNth: "2..", Multiline: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fm.Name != "test" || fm.Menu.Placeholder() != "{1}" || len(fm.Menu.Opts) != 2 {
+		t.Errorf("unexpected formatter: %+v", fm)
+	}
+
+	if _, err := NewTemplate("bad", &TemplateSpec{Template: "{{.ID"}); !errors.Is(err, ErrTemplate) {
+		t.Errorf("expected ErrTemplate, got %v", err)
+	}
+	if _, err := NewTemplate("bad", &TemplateSpec{Template: `{{nope .ID}}`}); !errors.Is(err, ErrTemplate) {
+		t.Errorf("expected ErrTemplate for an unknown func, got %v", err)
+	}
+}
+
+func TestPaletteStyle(t *testing.T) {
+	t.Parallel()
+
+	p := ansi.NewPalette()
+	if got, want := paletteStyle(p, "Bright-Yellow bold"), p.BrightYellow.With(p.Bold); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got := paletteStyle(p, "unknown"); got != p.Normal {
+		t.Errorf("unknown style: got %q", got)
+	}
+}
@@ -0,0 +1,254 @@
+package preview
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif"  // favicon decoder
+	_ "image/jpeg" // favicon decoder
+	"image/png"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/image/draw"
+
+	"github.com/mateconpizza/gm/pkg/bookmark"
+)
+
+// Protocol is a terminal graphics protocol.
+type Protocol int
+
+const (
+	None Protocol = iota
+	Kitty
+	Sixel
+)
+
+// iconSize is the size in pixels the favicon is drawn at.
+const iconSize = 32
+
+var ErrUnsupportedIcon = errors.New("unsupported icon")
+
+// Detect returns the graphics protocol supported by the terminal, guessed
+// from the environment, None when images are not supported.
+func Detect() Protocol {
+	term, program := os.Getenv("TERM"), os.Getenv("TERM_PROGRAM")
+
+	switch {
+	case os.Getenv("KITTY_WINDOW_ID") != "", term == "xterm-kitty",
+		term == "xterm-ghostty", program == "ghostty", program == "WezTerm":
+		return Kitty
+	case strings.HasPrefix(term, "foot"), strings.HasPrefix(term, "mlterm"),
+		strings.Contains(term, "sixel"), program == "iTerm.app", os.Getenv("WT_SESSION") != "":
+		return Sixel
+	}
+
+	return None
+}
+
+// Favicon returns the escape sequence drawing the cached favicon of the
+// bookmark, empty when there is none or it can not be decoded.
+func Favicon(b *bookmark.Bookmark, proto Protocol) string {
+	if proto == None || b.FaviconLocal == "" {
+		return ""
+	}
+
+	img, err := loadIcon(b.FaviconLocal)
+	if err != nil {
+		return ""
+	}
+
+	dst := image.NewNRGBA(image.Rect(0, 0, iconSize, iconSize))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, img.Bounds(), draw.Over, nil)
+
+	var buf bytes.Buffer
+	switch proto {
+	case Kitty:
+		err = encodeKitty(&buf, dst)
+	case Sixel:
+		err = encodeSixel(&buf, dst)
+	}
+	if err != nil {
+		return ""
+	}
+
+	return buf.String()
+}
+
+// loadIcon decodes a PNG, GIF or JPEG image, or an ICO file holding PNG
+// images.
+func loadIcon(path string) (image.Image, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if isICO(data) {
+		data, err = icoLargestPNG(data)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnsupportedIcon, err)
+	}
+
+	return img, nil
+}
+
+func isICO(data []byte) bool {
+	return len(data) >= 6 && binary.LittleEndian.Uint16(data[0:]) == 0 &&
+		binary.LittleEndian.Uint16(data[2:]) == 1
+}
+
+// icoLargestPNG returns the largest image of an ICO file, which must be
+// stored as PNG.
+func icoLargestPNG(data []byte) ([]byte, error) {
+	const (
+		headerSize = 6
+		entrySize  = 16
+	)
+
+	n := int(binary.LittleEndian.Uint16(data[4:]))
+	var best []byte
+	bestSize := -1
+	for i := range n {
+		e := headerSize + i*entrySize
+		if e+entrySize > len(data) {
+			break
+		}
+
+		// a width of 0 means 256 pixels
+		w := int(data[e])
+		if w == 0 {
+			w = 256
+		}
+		size := int(binary.LittleEndian.Uint32(data[e+8:]))
+		offset := int(binary.LittleEndian.Uint32(data[e+12:]))
+		if offset+size > len(data) || w <= bestSize {
+			continue
+		}
+
+		best, bestSize = data[offset:offset+size], w
+	}
+
+	if !bytes.HasPrefix(best, []byte("\x89PNG")) {
+		return nil, fmt.Errorf("%w: ico without PNG images", ErrUnsupportedIcon)
+	}
+
+	return best, nil
+}
+
+// encodeKitty writes the image with the kitty graphics protocol, sent as PNG
+// in chunks.
+func encodeKitty(w io.Writer, img image.Image) error {
+	const chunkSize = 4096
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return err
+	}
+
+	data := base64.StdEncoding.EncodeToString(buf.Bytes())
+	for first := true; first || data != ""; first = false {
+		chunk := data[:min(chunkSize, len(data))]
+		data = data[len(chunk):]
+
+		more := 0
+		if data != "" {
+			more = 1
+		}
+
+		ctrl := fmt.Sprintf("m=%d", more)
+		if first {
+			ctrl = "a=T,f=100,c=4," + ctrl
+		}
+		fmt.Fprintf(w, "\x1b_G%s;%s\x1b\\", ctrl, chunk)
+	}
+
+	return nil
+}
+
+// encodeSixel writes the image as sixels, quantized to a 6x6x6 color cube.
+// Transparent pixels are left blank.
+func encodeSixel(w io.Writer, img image.Image) error {
+	const levels = 6
+
+	b := img.Bounds()
+	width, height := b.Dx(), b.Dy()
+
+	index := func(x, y int) int {
+		c := color.NRGBAModel.Convert(img.At(b.Min.X+x, b.Min.Y+y)).(color.NRGBA)
+		if c.A < 128 {
+			return -1
+		}
+		q := func(v uint8) int { return int(v) * (levels - 1) / 255 }
+		return q(c.R)*levels*levels + q(c.G)*levels + q(c.B)
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "\x1bP0;1q\"1;1;%d;%d", width, height)
+	for i := range levels * levels * levels {
+		r, g, bl := i/(levels*levels), i/levels%levels, i%levels
+		pct := func(v int) int { return v * 100 / (levels - 1) }
+		fmt.Fprintf(&sb, "#%d;2;%d;%d;%d", i, pct(r), pct(g), pct(bl))
+	}
+
+	for y0 := 0; y0 < height; y0 += 6 {
+		// sixel bits of each color used in the band
+		bands := make(map[int][]byte)
+		var order []int
+		for x := range width {
+			for dy := 0; dy < 6 && y0+dy < height; dy++ {
+				i := index(x, y0+dy)
+				if i < 0 {
+					continue
+				}
+				if _, ok := bands[i]; !ok {
+					bands[i] = make([]byte, width)
+					order = append(order, i)
+				}
+				bands[i][x] |= 1 << dy
+			}
+		}
+
+		for n, i := range order {
+			if n > 0 {
+				sb.WriteByte('$')
+			}
+			fmt.Fprintf(&sb, "#%d", i)
+			writeSixelRow(&sb, bands[i])
+		}
+		sb.WriteByte('-')
+	}
+	sb.WriteString("\x1b\\")
+
+	_, err := io.WriteString(w, sb.String())
+
+	return err
+}
+
+// writeSixelRow writes the sixels of a row, run length encoded.
+func writeSixelRow(sb *strings.Builder, row []byte) {
+	for x := 0; x < len(row); {
+		run := 1
+		for x+run < len(row) && row[x+run] == row[x] {
+			run++
+		}
+
+		c := rune(63 + row[x])
+		if run > 3 {
+			fmt.Fprintf(sb, "!%d%c", run, c)
+		} else {
+			sb.WriteString(strings.Repeat(string(c), run))
+		}
+		x += run
+	}
+}
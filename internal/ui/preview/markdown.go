@@ -0,0 +1,111 @@
+package preview
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/mateconpizza/gm/internal/ui/txt"
+	"github.com/mateconpizza/gm/pkg/ansi"
+)
+
+var (
+	mdCode   = regexp.MustCompile("`([^`]+)`")
+	mdBold   = regexp.MustCompile(`\*\*([^*]+)\*\*|__([^_]+)__`)
+	mdItalic = regexp.MustCompile(`\*([^*\s][^*]*)\*|\b_([^_\s][^_]*)_\b`)
+	mdLink   = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+	mdHead   = regexp.MustCompile(`^(#{1,6})\s+(.*)$`)
+	mdBullet = regexp.MustCompile(`^(\s*)[-*+]\s+(.*)$`)
+)
+
+// Markdown renders the first maxLines lines of the Markdown text s, wrapped
+// to width. Only the common syntax is styled: headings, lists, quotes, code,
+// emphasis and links.
+func Markdown(p *ansi.Palette, s string, width, maxLines int) []string {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil
+	}
+
+	var (
+		lines []string
+		code  bool
+	)
+
+	for l := range strings.SplitSeq(s, "\n") {
+		l = strings.TrimRight(l, " \t\r")
+		if strings.HasPrefix(strings.TrimSpace(l), "```") {
+			code = !code
+			continue
+		}
+
+		if code {
+			lines = append(lines, p.Dim.Sprint("  "+txt.Shorten(l, width-2)))
+			continue
+		}
+
+		lines = append(lines, markdownLine(p, l, width)...)
+	}
+
+	if len(lines) > maxLines {
+		lines = append(lines[:maxLines], p.Dim.Sprint(txt.GlyphEllipsis.String()))
+	}
+
+	return lines
+}
+
+// markdownLine renders a line of text, wrapped to width.
+func markdownLine(p *ansi.Palette, l string, width int) []string {
+	if m := mdHead.FindStringSubmatch(l); m != nil {
+		style := p.Bold
+		if len(m[1]) == 1 {
+			style = p.BrightCyan.With(p.Bold)
+		}
+
+		return []string{style.Sprint(txt.Shorten(m[2], width))}
+	}
+
+	prefix := ""
+	switch {
+	case mdBullet.MatchString(l):
+		m := mdBullet.FindStringSubmatch(l)
+		prefix, l = m[1]+"• ", m[2]
+	case strings.HasPrefix(l, ">"):
+		prefix, l = p.Dim.Sprint("│ "), strings.TrimSpace(strings.TrimPrefix(l, ">"))
+	}
+
+	indent := len(ansi.Remover(prefix))
+	if l == "" {
+		return []string{prefix}
+	}
+
+	chunks := txt.SplitIntoChunks(l, max(width-indent, 1))
+	lines := make([]string, 0, len(chunks))
+	for i, c := range chunks {
+		lead := strings.Repeat(" ", indent)
+		if i == 0 {
+			lead = prefix
+		}
+		lines = append(lines, lead+markdownInline(p, c))
+	}
+
+	return lines
+}
+
+// markdownInline styles the inline syntax of s.
+func markdownInline(p *ansi.Palette, s string) string {
+	s = mdCode.ReplaceAllStringFunc(s, func(m string) string {
+		return p.Yellow.Sprint(strings.Trim(m, "`"))
+	})
+	s = mdLink.ReplaceAllStringFunc(s, func(m string) string {
+		sub := mdLink.FindStringSubmatch(m)
+		return p.Underline.Sprint(sub[1]) + p.Dim.Sprintf(" (%s)", sub[2])
+	})
+	s = mdBold.ReplaceAllStringFunc(s, func(m string) string {
+		return p.Bold.Sprint(m[2 : len(m)-2])
+	})
+	s = mdItalic.ReplaceAllStringFunc(s, func(m string) string {
+		return p.Italic.Sprint(m[1 : len(m)-1])
+	})
+
+	return s
+}
@@ -0,0 +1,158 @@
+// Package preview renders the detailed view of a bookmark shown in the menu
+// preview pane.
+package preview
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mateconpizza/gm/internal/ui/formatter"
+	"github.com/mateconpizza/gm/internal/ui/txt"
+	"github.com/mateconpizza/gm/pkg/ansi"
+	"github.com/mateconpizza/gm/pkg/bookmark"
+)
+
+// Format is the name of the preview renderer, usable as
+// `menu.preview_format` next to the output formats.
+const Format = "rich"
+
+const (
+	// maxNotesLines is the number of lines of notes shown.
+	maxNotesLines = 8
+
+	// maxRelated is the number of related bookmarks shown.
+	maxRelated = 5
+
+	// timestampLayout is the layout of the status check and Wayback
+	// timestamps.
+	timestampLayout = "20060102150405"
+)
+
+// Render returns the preview of the bookmark: its card, the link status, the
+// Wayback snapshot, the notes, the related bookmarks found in all and the
+// favicon, drawn with the image protocol when it is not None.
+func Render(c formatter.Console, b *bookmark.Bookmark, all []*bookmark.Bookmark, proto Protocol) string {
+	p, w := c.Palette(), c.MaxWidth()
+
+	var sb strings.Builder
+	img := Favicon(b, proto)
+	if img != "" {
+		sb.WriteString(img)
+		sb.WriteByte('\n')
+	}
+
+	sb.WriteString(formatter.FrameFunc(c, b))
+
+	section := func(title string, lines ...string) {
+		if len(lines) == 0 {
+			return
+		}
+		sb.WriteString("\n" + p.Bold.Sprint(title) + "\n")
+		for _, l := range lines {
+			sb.WriteString(l + "\n")
+		}
+	}
+
+	section("Status", statusLines(p, b, img == "")...)
+	section("Notes", Markdown(p, b.Notes, w, maxNotesLines)...)
+	section("Related", relatedLines(p, Related(b, all, maxRelated), w)...)
+
+	return sb.String()
+}
+
+// statusLines returns the HTTP status with the age of the last check, the
+// Wayback snapshot date and, when the favicon was not drawn, its URL.
+func statusLines(p *ansi.Palette, b *bookmark.Bookmark, iconURL bool) []string {
+	field := func(label, value string) string {
+		return txt.PaddedLineWithPad(p.Dim.Sprint(label+":"), value, 9)
+	}
+
+	status := p.Dim.Sprint("not checked")
+	if b.LastStatusChecked != "" {
+		text := cmp.Or(b.HTTPStatusText, "Unassigned")
+		status = txt.HTTPStatusCodeColor(b.HTTPStatusCode, p).Sprintf("%d %s", b.HTTPStatusCode, text)
+		if t, err := time.Parse(timestampLayout, b.LastStatusChecked); err == nil {
+			status += p.Dim.Sprintf(" (checked %s)", txt.RelativeISOTime(t.Format(time.RFC3339)))
+		}
+	}
+
+	lines := []string{field("HTTP", status)}
+
+	if b.ArchiveURL != "" {
+		snapshot := p.Dim.Sprint("snapshot")
+		if t, err := time.Parse(timestampLayout, b.ArchiveTimestamp); err == nil {
+			snapshot = t.Format("2006 Jan 02") + p.Dim.Sprintf(" (%s)", txt.RelativeISOTime(t.Format(time.RFC3339)))
+		}
+		lines = append(lines, field("Wayback", snapshot))
+	}
+
+	if iconURL && b.FaviconURL != "" {
+		lines = append(lines, field("Favicon", p.Dim.Sprint(b.FaviconURL)))
+	}
+
+	return lines
+}
+
+// Related returns up to n bookmarks sharing tags or the domain with b, the
+// ones sharing more tags first.
+func Related(b *bookmark.Bookmark, all []*bookmark.Bookmark, n int) []*bookmark.Bookmark {
+	type scored struct {
+		b     *bookmark.Bookmark
+		score int
+	}
+
+	tags := splitTags(b.Tags)
+	domain, _ := bookmark.Domain(b.URL)
+
+	var related []scored
+	for _, o := range all {
+		if o.ID == b.ID {
+			continue
+		}
+
+		score := 0
+		for _, t := range splitTags(o.Tags) {
+			if slices.Contains(tags, t) {
+				score++
+			}
+		}
+		if d, err := bookmark.Domain(o.URL); err == nil && domain != "" && d == domain {
+			score++
+		}
+
+		if score > 0 {
+			related = append(related, scored{b: o, score: score})
+		}
+	}
+
+	slices.SortStableFunc(related, func(a, b scored) int {
+		return cmp.Compare(b.score, a.score)
+	})
+
+	bs := make([]*bookmark.Bookmark, 0, min(n, len(related)))
+	for _, r := range related[:min(n, len(related))] {
+		bs = append(bs, r.b)
+	}
+
+	return bs
+}
+
+func relatedLines(p *ansi.Palette, bs []*bookmark.Bookmark, w int) []string {
+	lines := make([]string, 0, len(bs))
+	for _, b := range bs {
+		id := strconv.Itoa(b.ID)
+		title := cmp.Or(b.Title, b.URL)
+		lines = append(lines, fmt.Sprintf("%s %s",
+			p.BrightYellow.Sprint(id), txt.Shorten(title, w-len(id)-1)))
+	}
+
+	return lines
+}
+
+func splitTags(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool { return r == ',' })
+}
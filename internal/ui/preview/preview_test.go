@@ -0,0 +1,97 @@
+package preview
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/mateconpizza/gm/pkg/ansi"
+	"github.com/mateconpizza/gm/pkg/bookmark"
+)
+
+func TestRelated(t *testing.T) {
+	t.Parallel()
+
+	b := &bookmark.Bookmark{ID: 1, URL: "https://go.dev/doc", Tags: "go,docs,"}
+	all := []*bookmark.Bookmark{
+		b,
+		{ID: 2, URL: "https://example.com", Tags: "go,"},
+		{ID: 3, URL: "https://go.dev/blog", Tags: "blog,"},
+		{ID: 4, URL: "https://pkg.go.dev", Tags: "go,docs,"},
+		{ID: 5, URL: "https://example.org", Tags: "misc,"},
+	}
+
+	ids := func(bs []*bookmark.Bookmark) []int {
+		r := make([]int, 0, len(bs))
+		for _, b := range bs {
+			r = append(r, b.ID)
+		}
+		return r
+	}
+
+	if got, want := ids(Related(b, all, 5)), []int{4, 2, 3}; !slices.Equal(got, want) {
+		t.Errorf("Related() = %v, want %v", got, want)
+	}
+	if got, want := ids(Related(b, all, 1)), []int{4}; !slices.Equal(got, want) {
+		t.Errorf("Related() limited = %v, want %v", got, want)
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	t.Parallel()
+
+	p := ansi.NewPalette()
+	notes := "# Title\nsome **bold** and `code`\n\n- item\n```\nraw *text*\n```\n> quote"
+
+	got := make([]string, 0)
+	for _, l := range Markdown(p, notes, 80, 10) {
+		got = append(got, ansi.Remover(l))
+	}
+
+	want := []string{"Title", "some bold and code", "", "• item", "  raw *text*", "│ quote"}
+	if !slices.Equal(got, want) {
+		t.Errorf("Markdown() = %q, want %q", got, want)
+	}
+
+	if lines := Markdown(p, notes, 80, 2); len(lines) != 3 {
+		t.Errorf("expected 2 lines and an ellipsis, got %q", lines)
+	}
+	if lines := Markdown(p, "  \n", 80, 2); lines != nil {
+		t.Errorf("expected no lines, got %q", lines)
+	}
+}
+
+func TestEncodeImage(t *testing.T) {
+	t.Parallel()
+
+	img := image.NewNRGBA(image.Rect(0, 0, 8, 8))
+	for x := range 8 {
+		for y := range 8 {
+			img.Set(x, y, color.NRGBA{R: 255, A: 255})
+		}
+	}
+
+	var kitty bytes.Buffer
+	if err := encodeKitty(&kitty, img); err != nil {
+		t.Fatal(err)
+	}
+	if s := kitty.String(); !strings.HasPrefix(s, "\x1b_Ga=T,f=100,") || !strings.HasSuffix(s, "\x1b\\") {
+		t.Errorf("unexpected kitty sequence: %q", s)
+	}
+
+	var sixel bytes.Buffer
+	if err := encodeSixel(&sixel, img); err != nil {
+		t.Fatal(err)
+	}
+	s := sixel.String()
+	if !strings.HasPrefix(s, "\x1bP0;1q\"1;1;8;8") || !strings.HasSuffix(s, "-\x1b\\") {
+		t.Errorf("unexpected sixel sequence: %q", s)
+	}
+	// red is the last color of the red axis, two bands of 8 full columns
+	if !strings.Contains(s, "#180!8~-#180!8B-") {
+		t.Errorf("unexpected sixel data: %q", s[strings.LastIndex(s, "#215;"):])
+	}
+}
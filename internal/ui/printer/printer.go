@@ -11,7 +11,6 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
-	"text/tabwriter"
 
 	menu "github.com/mateconpizza/go-fzf"
 	files "github.com/mateconpizza/gofiles"
@@ -26,6 +25,8 @@ import (
 	"github.com/mateconpizza/gm/internal/ui"
 	"github.com/mateconpizza/gm/internal/ui/formatter"
 	"github.com/mateconpizza/gm/internal/ui/frame"
+	"github.com/mateconpizza/gm/internal/ui/preview"
+	"github.com/mateconpizza/gm/internal/ui/table"
 	"github.com/mateconpizza/gm/internal/ui/txt"
 	"github.com/mateconpizza/gm/pkg/ansi"
 	"github.com/mateconpizza/gm/pkg/bookmark"
@@ -50,6 +51,26 @@ func MenuPreview(c *ui.Console, bs []*bookmark.Bookmark, f string) error {
 	return nil
 }
 
+// RichPreview prints the detailed preview of the bookmarks, with their
+// related bookmarks in the database.
+func RichPreview(ctx context.Context, d *deps.Deps, bs []*bookmark.Bookmark) error {
+	r, err := d.Repository()
+	if err != nil {
+		return err
+	}
+
+	c, proto := d.Console(), preview.Detect()
+	for _, b := range bs {
+		related, err := r.RelatedTo(ctx, b)
+		if err != nil {
+			return fmt.Errorf("preview: %w", err)
+		}
+		fmt.Fprint(c.Writer(), preview.Render(c, b, related, proto))
+	}
+
+	return nil
+}
+
 // Records prints the bookmarks in a frame format with the given colorscheme.
 func Records(ctx context.Context, c *ui.Console, bs []*bookmark.Bookmark) error {
 	var buf strings.Builder
@@ -148,44 +169,23 @@ func Notes(ctx context.Context, c *ui.Console, bs []*bookmark.Bookmark) error {
 	return c.Print(ctx, f.String())
 }
 
-type fieldSpec struct {
-	name  string
-	limit int // 0: no limit
-}
+// ByField prints the selected fields of the bookmarks as a table, aligned to
+// the terminal width or as TSV/CSV.
+func ByField(ctx context.Context, c *ui.Console, fields string, mode table.Mode, header bool, bs []*bookmark.Bookmark) error {
+	cols, err := table.ParseColumns(fields)
+	if err != nil {
+		return err
+	}
 
-func ByField(ctx context.Context, c *ui.Console, fields string, bs []*bookmark.Bookmark) error {
-	parts := strings.Split(fields, ",")
-	specs := make([]fieldSpec, len(parts))
-	for i, p := range parts {
-		p = strings.TrimSpace(p)
-		if strings.Contains(p, ":") {
-			sub := strings.Split(p, ":")
-			specs[i].name = sub[0]
-			specs[i].limit, _ = strconv.Atoi(sub[1])
-		} else {
-			specs[i].name = p
-		}
+	t := &table.Table{Columns: cols, Mode: mode, Header: header, Palette: c.Palette()}
+	if mode != table.Text {
+		return t.Write(c.Writer(), bs)
 	}
 
+	t.Width = c.Width()
+
 	var buf strings.Builder
-	w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
-	for _, b := range bs {
-		var row []string
-		for _, spec := range specs {
-			val, err := b.Field(spec.name)
-			if err != nil {
-				return err
-			}
-			if spec.limit > 0 {
-				val = txt.Shorten(val, spec.limit)
-			} else {
-				val = txt.Shorten(val, c.MaxWidth()/len(specs))
-			}
-			row = append(row, val)
-		}
-		fmt.Fprintln(w, strings.Join(row, "\t"))
-	}
-	if err := w.Flush(); err != nil {
+	if err := t.Write(&buf, bs); err != nil {
 		return err
 	}
 
@@ -0,0 +1,323 @@
+// Package table prints bookmarks as columns of their fields, aligned for the
+// terminal or as TSV and CSV for other programs.
+package table
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	runewidth "github.com/mattn/go-runewidth"
+
+	"github.com/mateconpizza/gm/internal/ui/txt"
+	"github.com/mateconpizza/gm/pkg/ansi"
+	"github.com/mateconpizza/gm/pkg/bookmark"
+	"github.com/mateconpizza/gm/pkg/db"
+)
+
+var ErrInvalidColumn = errors.New("invalid column")
+
+// DefaultColumns are the columns printed when none are selected.
+const DefaultColumns = "id,title,url,tags"
+
+const (
+	// gap is the space between columns.
+	gap = 2
+
+	// minWidth is the width columns are not shrunk below to fit the
+	// terminal.
+	minWidth = 8
+)
+
+// Mode is how the table is written.
+type Mode int
+
+const (
+	Text Mode = iota // aligned columns
+	TSV              // tab separated values
+	CSV              // comma separated values
+)
+
+// Truncate is how values wider than their column are cut.
+type Truncate int
+
+const (
+	TruncEnd    Truncate = iota // cut the end
+	TruncMiddle                 // cut the middle, keeping both ends
+	TruncNone                   // never cut, the column is not shrunk
+)
+
+var truncatePolicies = map[string]Truncate{
+	"end":    TruncEnd,
+	"middle": TruncMiddle,
+	"none":   TruncNone,
+}
+
+// Column is a field of the bookmarks.
+type Column struct {
+	Name  string   // field as selected, used as header
+	Key   string   // column name of the field, see bookmark.FieldKey
+	Width int      // maximum width, 0 to fit the terminal
+	Trunc Truncate // how values are cut
+}
+
+// ParseColumns parses a comma separated list of columns, each one given as
+// name[:width[:end|middle|none]].
+//
+//	id,url:40:middle,title,visits
+func ParseColumns(s string) ([]Column, error) {
+	if strings.TrimSpace(s) == "" {
+		s = DefaultColumns
+	}
+
+	parts := strings.Split(s, ",")
+	cols := make([]Column, 0, len(parts))
+	for _, p := range parts {
+		name, rest, _ := strings.Cut(strings.TrimSpace(p), ":")
+		key, err := bookmark.FieldKey(name)
+		if err != nil {
+			return nil, err
+		}
+
+		col := Column{Name: name, Key: key}
+		if key == "url" || key == "archive_url" || key == "favicon_url" {
+			col.Trunc = TruncMiddle
+		}
+
+		width, policy, _ := strings.Cut(rest, ":")
+		if width != "" {
+			if col.Width, err = strconv.Atoi(width); err != nil || col.Width < 0 {
+				return nil, fmt.Errorf("%w: %q: invalid width %q", ErrInvalidColumn, name, width)
+			}
+		}
+		if policy != "" {
+			t, ok := truncatePolicies[policy]
+			if !ok {
+				return nil, fmt.Errorf("%w: %q: truncate with end, middle or none", ErrInvalidColumn, name)
+			}
+			col.Trunc = t
+		}
+
+		cols = append(cols, col)
+	}
+
+	return cols, nil
+}
+
+// Table writes bookmarks as rows of columns.
+type Table struct {
+	Columns []Column
+	Mode    Mode
+	Header  bool          // print the column names first
+	Width   int           // width the text mode fits in, 0 for no limit
+	Palette *ansi.Palette // styles the header of the text mode
+}
+
+// Write writes the bookmarks to w.
+func (t *Table) Write(w io.Writer, bs []*bookmark.Bookmark) error {
+	rows, err := t.rows(bs)
+	if err != nil {
+		return err
+	}
+
+	switch t.Mode {
+	case CSV:
+		cw := csv.NewWriter(w)
+		if err := cw.WriteAll(rows); err != nil {
+			return fmt.Errorf("csv: %w", err)
+		}
+		return nil
+	case TSV:
+		for _, row := range rows {
+			for i := range row {
+				row[i] = flatten(row[i], " ")
+			}
+			if _, err := fmt.Fprintln(w, strings.Join(row, "\t")); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		_, err := io.WriteString(w, t.text(rows))
+		return err
+	}
+}
+
+// rows returns the values of the columns, the header first.
+func (t *Table) rows(bs []*bookmark.Bookmark) ([][]string, error) {
+	rows := make([][]string, 0, len(bs)+1)
+	if t.Header {
+		header := make([]string, 0, len(t.Columns))
+		for _, c := range t.Columns {
+			if t.Mode == Text {
+				header = append(header, strings.ToUpper(c.Name))
+			} else {
+				header = append(header, c.Key)
+			}
+		}
+		rows = append(rows, header)
+	}
+
+	for _, b := range bs {
+		row := make([]string, 0, len(t.Columns))
+		for _, c := range t.Columns {
+			if db.Locked(b) && db.PrivateField(c.Key) {
+				row = append(row, t.locked())
+				continue
+			}
+
+			v, err := b.Field(c.Key)
+			if err != nil && c.Key != bookmark.FieldDomain {
+				return nil, err
+			}
+			if c.Key == "tags" {
+				v = strings.Trim(v, ",")
+			}
+			if t.Mode == CSV {
+				v = escapeFormula(v)
+			}
+			row = append(row, v)
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// locked returns the value of the encrypted fields of a locked private
+// bookmark, blank for other programs.
+func (t *Table) locked() string {
+	if t.Mode != Text {
+		return ""
+	}
+
+	return "locked"
+}
+
+// escapeFormula prefixes a quote to the values spreadsheets would run as a
+// formula.
+func escapeFormula(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+
+	return s
+}
+
+// text aligns the rows in columns fitted to the width of the table.
+func (t *Table) text(rows [][]string) string {
+	for _, row := range rows {
+		for i := range row {
+			row[i] = flatten(row[i], " ")
+		}
+	}
+
+	widths := t.fit(rows)
+	last := len(t.Columns) - 1
+
+	var sb strings.Builder
+	for n, row := range rows {
+		for i, v := range row {
+			c := t.Columns[i]
+			v = truncate(v, widths[i], c.Trunc)
+			if n == 0 && t.Header && t.Palette != nil {
+				v = t.Palette.Bold.Sprint(v)
+			}
+			sb.WriteString(v)
+
+			if i < last {
+				pad := widths[i] - runewidth.StringWidth(ansi.Remover(v)) + gap
+				sb.WriteString(strings.Repeat(" ", max(pad, gap)))
+			}
+		}
+		sb.WriteByte('\n')
+	}
+
+	return sb.String()
+}
+
+// fit returns the width of each column, the widest columns that can be cut
+// are shrunk until the table fits its width.
+func (t *Table) fit(rows [][]string) []int {
+	widths := make([]int, len(t.Columns))
+	for _, row := range rows {
+		for i, v := range row {
+			widths[i] = max(widths[i], runewidth.StringWidth(v))
+		}
+	}
+	for i, c := range t.Columns {
+		if c.Width > 0 && c.Trunc != TruncNone {
+			widths[i] = min(widths[i], c.Width)
+		}
+	}
+
+	if t.Width <= 0 {
+		return widths
+	}
+
+	total := gap * (len(widths) - 1)
+	for _, w := range widths {
+		total += w
+	}
+
+	for total > t.Width {
+		widest := -1
+		for i, c := range t.Columns {
+			if c.Trunc == TruncNone || widths[i] <= minWidth {
+				continue
+			}
+			if widest < 0 || widths[i] > widths[widest] {
+				widest = i
+			}
+		}
+		if widest < 0 {
+			break
+		}
+
+		widths[widest]--
+		total--
+	}
+
+	return widths
+}
+
+// truncate cuts s to width w with the policy p.
+func truncate(s string, w int, p Truncate) string {
+	if p == TruncNone || runewidth.StringWidth(s) <= w {
+		return s
+	}
+
+	ellipsis := txt.GlyphEllipsis.String()
+	if p == TruncMiddle && w > 2 {
+		head := (w - 1) / 2
+		tail := w - 1 - head
+		rs := []rune(s)
+		end := string(rs[len(rs)-tailRunes(rs, tail):])
+
+		return runewidth.Truncate(s, head, "") + ellipsis + end
+	}
+
+	return txt.Shorten(s, w)
+}
+
+// tailRunes returns the number of runes at the end of rs fitting in width w.
+func tailRunes(rs []rune, w int) int {
+	n, width := 0, 0
+	for i := len(rs) - 1; i >= 0; i-- {
+		width += runewidth.RuneWidth(rs[i])
+		if width > w {
+			break
+		}
+		n++
+	}
+
+	return n
+}
+
+// flatten replaces the line breaks and tabs of s with sep.
+func flatten(s, sep string) string {
+	return strings.NewReplacer("\r\n", sep, "\n", sep, "\t", sep).Replace(s)
+}
@@ -0,0 +1,151 @@
+package table
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	runewidth "github.com/mattn/go-runewidth"
+
+	"github.com/mateconpizza/gm/pkg/bookmark"
+)
+
+func testBookmarks() []*bookmark.Bookmark {
+	return []*bookmark.Bookmark{
+		{ID: 1, URL: "https://go.dev/doc/effective_go", Title: "Effective Go", Tags: "go,docs,", VisitCount: 3},
+		{ID: 2, URL: "https://example.com", Title: "Example, with comma", Notes: "line\nbreak", Favorite: true},
+	}
+}
+
+func TestParseColumns(t *testing.T) {
+	t.Parallel()
+
+	cols, err := ParseColumns("id, url:30, title:10:none,visits")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Column{
+		{Name: "id", Key: "id"},
+		{Name: "url", Key: "url", Width: 30, Trunc: TruncMiddle},
+		{Name: "title", Key: "title", Width: 10, Trunc: TruncNone},
+		{Name: "visits", Key: "visit_count"},
+	}
+	if len(cols) != len(want) {
+		t.Fatalf("expected %d columns, got %d", len(want), len(cols))
+	}
+	for i := range want {
+		if cols[i] != want[i] {
+			t.Errorf("column %d = %+v, want %+v", i, cols[i], want[i])
+		}
+	}
+
+	for _, s := range []string{"bogus", "url:x", "url:10:left"} {
+		if _, err := ParseColumns(s); err == nil {
+			t.Errorf("ParseColumns(%q): expected error", s)
+		}
+	}
+	if _, err := ParseColumns("url:-1"); !errors.Is(err, ErrInvalidColumn) {
+		t.Errorf("expected ErrInvalidColumn, got %v", err)
+	}
+}
+
+func TestTable_Machine(t *testing.T) {
+	t.Parallel()
+
+	cols, err := ParseColumns("id,title,notes,favorite")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		mode   Mode
+		header bool
+		want   string
+	}{
+		{
+			name:   "tsv",
+			mode:   TSV,
+			header: true,
+			want:   "id\ttitle\tnotes\tfavorite\n1\tEffective Go\t\tfalse\n2\tExample, with comma\tline break\ttrue\n",
+		},
+		{
+			name: "csv_without_header",
+			mode: CSV,
+			want: "1,Effective Go,,false\n2,\"Example, with comma\",\"line\nbreak\",true\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var sb strings.Builder
+			tb := &Table{Columns: cols, Mode: tt.mode, Header: tt.header}
+			if err := tb.Write(&sb, testBookmarks()); err != nil {
+				t.Fatal(err)
+			}
+			if got := sb.String(); got != tt.want {
+				t.Errorf("Write() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTable_TextFitsWidth(t *testing.T) {
+	t.Parallel()
+
+	cols, err := ParseColumns("id,url,title,tags")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const width = 50
+	var sb strings.Builder
+	tb := &Table{Columns: cols, Header: true, Width: width}
+	if err := tb.Write(&sb, testBookmarks()); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(sb.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 lines, got %q", lines)
+	}
+	if !strings.HasPrefix(lines[0], "ID  URL") {
+		t.Errorf("unexpected header: %q", lines[0])
+	}
+	for _, l := range lines {
+		if w := runewidth.StringWidth(l); w > width {
+			t.Errorf("line wider than %d: %q (%d)", width, l, w)
+		}
+	}
+	// URLs keep both ends
+	if !strings.Contains(lines[1], "https://") || !strings.Contains(lines[1], "ve_go") {
+		t.Errorf("expected the URL cut in the middle: %q", lines[1])
+	}
+}
+
+func TestTable_LockedAndFormulas(t *testing.T) {
+	t.Parallel()
+
+	cols, err := ParseColumns("id,url,title")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bs := []*bookmark.Bookmark{
+		{ID: 1, URL: "gmenc:c2VhbGVk", Title: "secret", Private: true},
+		{ID: 2, URL: "https://example.com", Title: "=HYPERLINK(\"x\")"},
+	}
+
+	var sb strings.Builder
+	tb := &Table{Columns: cols, Mode: CSV}
+	if err := tb.Write(&sb, bs); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "1,,secret\n2,https://example.com,\"'=HYPERLINK(\"\"x\"\")\"\n"
+	if got := sb.String(); got != want {
+		t.Errorf("Write() = %q, want %q", got, want)
+	}
+}
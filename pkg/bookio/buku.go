@@ -0,0 +1,130 @@
+package bookio
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	gap "github.com/muesli/go-app-paths"
+	_ "modernc.org/sqlite"
+
+	"github.com/mateconpizza/gm/pkg/bookmark"
+)
+
+var ErrNotBukuDatabase = errors.New("not a buku database")
+
+// BukuImmutableTag marks the bookmarks buku keeps from refreshing the title.
+const BukuImmutableTag = "immutable"
+
+// bukuFlagImmutable is the buku flag for an immutable title.
+const bukuFlagImmutable = 1
+
+const sqliteMagic = "SQLite format 3\x00"
+
+// bukuRecord is a row of the buku bookmarks table.
+type bukuRecord struct {
+	URL   string `db:"URL"`
+	Title string `db:"metadata"`
+	Tags  string `db:"tags"` // ",tag1,tag2,"
+	Desc  string `db:"desc"`
+	Flags int    `db:"flags"`
+}
+
+func (r *bukuRecord) bookmark() *bookmark.Bookmark {
+	var tags []string
+	for t := range strings.SplitSeq(r.Tags, ",") {
+		// buku tags may hold spaces
+		if t = strings.Join(strings.Fields(t), "-"); t != "" {
+			tags = append(tags, t)
+		}
+	}
+	if r.Flags&bukuFlagImmutable != 0 {
+		tags = append(tags, BukuImmutableTag)
+	}
+
+	return &bookmark.Bookmark{
+		URL:   r.URL,
+		Title: r.Title,
+		Desc:  r.Desc,
+		Tags:  importTags(tags, false),
+	}
+}
+
+// BukuPath returns the default path of the buku database.
+func BukuPath() (string, error) {
+	return gap.NewScope(gap.User, "buku").DataPath("bookmarks.db")
+}
+
+// openBuku opens the buku database at path read-only.
+func openBuku(ctx context.Context, path string) (*sqlx.DB, error) {
+	dsn := "file:" + (&url.URL{Path: path}).EscapedPath() + "?mode=ro"
+
+	r, err := sqlx.Open("sqlite", dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := r.ExecContext(ctx, `SELECT URL, metadata, tags, "desc", flags FROM bookmarks LIMIT 0`); err != nil {
+		_ = r.Close()
+		return nil, fmt.Errorf("%w: %w", ErrNotBukuDatabase, err)
+	}
+
+	return r, nil
+}
+
+// IsBukuDatabase reports whether path is a buku database.
+func IsBukuDatabase(ctx context.Context, path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+
+	head := make([]byte, len(sqliteMagic))
+	_, err = io.ReadFull(f, head)
+	_ = f.Close()
+	if err != nil || !bytes.Equal(head, []byte(sqliteMagic)) {
+		return false
+	}
+
+	r, err := openBuku(ctx, path)
+	if err != nil {
+		return false
+	}
+	_ = r.Close()
+
+	return true
+}
+
+// ImportFromBuku reads the bookmarks of a buku database. The tags lose
+// their surrounding commas and the immutable title flag becomes a tag.
+func ImportFromBuku(ctx context.Context, path string) ([]*bookmark.Bookmark, error) {
+	r, err := openBuku(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	var records []*bukuRecord
+	q := `
+		SELECT URL, COALESCE(metadata, '') AS metadata, COALESCE(tags, '') AS tags,
+			COALESCE("desc", '') AS "desc", COALESCE(flags, 0) AS flags
+		FROM bookmarks ORDER BY id`
+	if err := r.SelectContext(ctx, &records, q); err != nil {
+		return nil, fmt.Errorf("reading buku bookmarks: %w", err)
+	}
+
+	bs := make([]*bookmark.Bookmark, 0, len(records))
+	for _, rec := range records {
+		b := rec.bookmark()
+		b.GenChecksum()
+		bs = append(bs, b)
+	}
+
+	return bs, nil
+}
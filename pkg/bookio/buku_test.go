@@ -0,0 +1,94 @@
+package bookio
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// testBukuDatabase creates a database with the buku schema.
+func testBukuDatabase(t *testing.T) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "bookmarks.db")
+	r, err := sqlx.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stmts := []string{
+		`CREATE TABLE bookmarks (id integer PRIMARY KEY, URL text NOT NULL UNIQUE, metadata text default '',
+			tags text default ',', desc text default '', flags integer default 0)`,
+		`INSERT INTO bookmarks (URL, metadata, tags, desc, flags) VALUES
+			('https://go.dev/', 'The Go Programming Language', ',go,programming,', 'Go home', 1),
+			('https://example.org/', 'Example', ',', '', 0),
+			('https://buku.example/', NULL, ',web dev,cli,', NULL, NULL)`,
+	}
+	for _, s := range stmts {
+		if _, err := r.Exec(s); err != nil {
+			t.Fatalf("%v: %s", err, s)
+		}
+	}
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestImportFromBuku(t *testing.T) {
+	t.Parallel()
+
+	path := testBukuDatabase(t)
+	if !IsBukuDatabase(t.Context(), path) {
+		t.Fatal("buku database not detected")
+	}
+
+	bs, err := ImportFromBuku(t.Context(), path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []serviceFields{
+		{URL: "https://go.dev/", Title: "The Go Programming Language", Desc: "Go home", Tags: "go,immutable,programming,"},
+		{URL: "https://example.org/", Title: "Example", Tags: "notag"},
+		{URL: "https://buku.example/", Tags: "cli,web-dev,"},
+	}
+
+	got := toServiceFields(bs)
+	if len(got) != len(want) {
+		t.Fatalf("expected %d bookmarks, got %d: %+v", len(want), len(got), got)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Errorf("bookmark %d:\n got %+v\nwant %+v", i, got[i], want[i])
+		}
+		if bs[i].Checksum == "" {
+			t.Errorf("bookmark %d: empty checksum", i)
+		}
+	}
+}
+
+func TestIsBukuDatabase(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "other.db")
+	r, err := sqlx.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Exec(`CREATE TABLE bookmarks (id integer PRIMARY KEY, url text, title text)`); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if IsBukuDatabase(t.Context(), path) {
+		t.Error("expected a non buku database")
+	}
+	if IsBukuDatabase(t.Context(), filepath.Join("testdata", "pinboard.json")) {
+		t.Error("expected a non database file")
+	}
+}
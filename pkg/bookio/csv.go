@@ -1,6 +1,7 @@
 package bookio
 
 import (
+	"bytes"
 	"encoding/csv"
 	"errors"
 	"fmt"
@@ -20,7 +21,9 @@ var (
 
 var CSVDefaultHeader = []string{"id", "url", "title", "desc", "created_at", "favorite", "notes"}
 
+// ExportToCSV writes the bookmarks as CSV, private bookmarks are skipped.
 func ExportToCSV(bs []*bookmark.Bookmark, writer io.Writer, fields []string) error {
+	bs = bookmark.WithoutPrivate(bs)
 	if len(fields) == 0 {
 		fields = CSVDefaultHeader
 	}
@@ -84,6 +87,38 @@ func ExportToCSV(bs []*bookmark.Bookmark, writer io.Writer, fields []string) err
 	return w.Error()
 }
 
+// CSVExporter writes bookmarks as CSV, Fields selects the columns.
+type CSVExporter struct {
+	Fields []string
+}
+
+func (e *CSVExporter) Export(w io.Writer, bs []*bookmark.Bookmark) error {
+	return ExportToCSV(bs, w, e.Fields)
+}
+
+func (e *CSVExporter) WithFields(fields []string) Exporter {
+	return &CSVExporter{Fields: fields}
+}
+
+// sniffCSV reports whether the first line of head is a CSV header holding a
+// url column.
+func sniffCSV(head []byte) bool {
+	line, _, _ := bytes.Cut(trimHead(head), []byte("\n"))
+	if !bytes.ContainsRune(line, ',') {
+		return false
+	}
+
+	for col := range bytes.SplitSeq(line, []byte(",")) {
+		col = bytes.Trim(bytes.TrimSpace(col), `"`)
+		if strings.EqualFold(string(col), "url") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ImportFromCSV reads bookmarks from CSV, the header must hold a url column.
 func ImportFromCSV(r io.Reader) ([]*bookmark.Bookmark, error) {
 	cr := csv.NewReader(r)
 
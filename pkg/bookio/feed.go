@@ -0,0 +1,400 @@
+package bookio
+
+import (
+	"cmp"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mateconpizza/gm/pkg/bookmark"
+)
+
+// Feed formats.
+const (
+	FeedAtom     = "atom"
+	FeedRSS      = "rss"
+	FeedJSONFeed = "jsonfeed"
+)
+
+var ErrFeedFormat = errors.New("invalid feed format")
+
+// FeedOptions describe the feed itself.
+type FeedOptions struct {
+	Title    string // Feed title
+	Link     string // Self link, the URL the feed is served from
+	HomePage string // Site the feed belongs to, optional
+	Author   string // Feed author, defaults to the title
+	Limit    int    // Newest bookmarks to include, zero for all
+}
+
+// FeedFormats returns the supported feed formats.
+func FeedFormats() []string {
+	return []string{FeedAtom, FeedRSS, FeedJSONFeed}
+}
+
+// FeedExt returns the file extension of a feed format.
+func FeedExt(format string) string {
+	switch format {
+	case FeedRSS:
+		return ".rss"
+	case FeedJSONFeed:
+		return jsonExt
+	default:
+		return ".atom"
+	}
+}
+
+// ValidFeedFormat returns the validated feed format, defaults to Atom.
+func ValidFeedFormat(format string) (string, error) {
+	format = strings.ToLower(strings.TrimSpace(format))
+	switch format {
+	case "":
+		return FeedAtom, nil
+	case FeedAtom, FeedRSS, FeedJSONFeed:
+		return format, nil
+	case "json":
+		return FeedJSONFeed, nil
+	}
+
+	return "", fmt.Errorf("%w: %q (use %s)", ErrFeedFormat, format, strings.Join(FeedFormats(), ", "))
+}
+
+// ExportFeed writes the bookmarks as a feed, newest first by creation date.
+// Titles, descriptions and notes make the content and tags the categories.
+// Private bookmarks are skipped. The feed date is the one of its newest
+// entry, so the output only changes along with the bookmarks.
+func ExportFeed(w io.Writer, format string, bs []*bookmark.Bookmark, opts *FeedOptions) error {
+	format, err := ValidFeedFormat(format)
+	if err != nil {
+		return err
+	}
+
+	items := feedItems(bs, opts.Limit)
+
+	switch format {
+	case FeedRSS:
+		return writeRSS(w, items, opts)
+	case FeedJSONFeed:
+		return writeJSONFeed(w, items, opts)
+	default:
+		return writeAtom(w, items, opts)
+	}
+}
+
+// feedItem is a bookmark ready to be written in a feed.
+type feedItem struct {
+	b         *bookmark.Bookmark
+	published time.Time
+	updated   time.Time
+	tags      []string
+}
+
+func (it *feedItem) title() string {
+	if it.b.Title != "" {
+		return it.b.Title
+	}
+
+	return it.b.URL
+}
+
+// content returns the description and the notes.
+func (it *feedItem) content() string {
+	var parts []string
+	for _, s := range []string{it.b.Desc, it.b.Notes} {
+		if s = strings.TrimSpace(s); s != "" {
+			parts = append(parts, s)
+		}
+	}
+
+	return strings.Join(parts, "\n\n")
+}
+
+// feedItems returns the public bookmarks sorted newest first, at most limit
+// when positive.
+func feedItems(bs []*bookmark.Bookmark, limit int) []*feedItem {
+	items := make([]*feedItem, 0, len(bs))
+	for _, b := range bookmark.WithoutPrivate(bs) {
+		it := &feedItem{b: b}
+		it.published, _ = bookmarkTime(b.CreatedAt)
+		it.updated = it.published
+		if t, ok := bookmarkTime(b.UpdatedAt); ok && t.After(it.published) {
+			it.updated = t
+		}
+
+		for t := range strings.SplitSeq(b.Tags, ",") {
+			if t != "" && t != bookmark.DefaultTag {
+				it.tags = append(it.tags, t)
+			}
+		}
+
+		items = append(items, it)
+	}
+
+	slices.SortStableFunc(items, func(a, b *feedItem) int {
+		if c := b.published.Compare(a.published); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.b.ID, a.b.ID)
+	})
+
+	if limit > 0 && len(items) > limit {
+		items = items[:limit]
+	}
+
+	return items
+}
+
+// lastUpdate returns the newest date of the items.
+func lastUpdate(items []*feedItem) time.Time {
+	var last time.Time
+	for _, it := range items {
+		if it.updated.After(last) {
+			last = it.updated
+		}
+	}
+
+	if last.IsZero() {
+		return time.Unix(0, 0).UTC()
+	}
+
+	return last
+}
+
+const atomNS = "http://www.w3.org/2005/Atom"
+
+type atomFeed struct {
+	XMLName xml.Name     `xml:"feed"`
+	NS      string       `xml:"xmlns,attr"`
+	Title   string       `xml:"title"`
+	ID      string       `xml:"id"`
+	Updated string       `xml:"updated"`
+	Links   []atomLink   `xml:"link"`
+	Author  atomAuthor   `xml:"author"`
+	Entries []*atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomEntry struct {
+	Title      string         `xml:"title"`
+	ID         string         `xml:"id"`
+	Link       atomLink       `xml:"link"`
+	Published  string         `xml:"published,omitempty"`
+	Updated    string         `xml:"updated"`
+	Summary    string         `xml:"summary,omitempty"`
+	Content    *atomContent   `xml:"content"`
+	Categories []atomCategory `xml:"category"`
+}
+
+type atomContent struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+func writeAtom(w io.Writer, items []*feedItem, opts *FeedOptions) error {
+	f := &atomFeed{
+		NS:      atomNS,
+		Title:   opts.Title,
+		ID:      feedID(opts),
+		Updated: lastUpdate(items).Format(time.RFC3339),
+		Author:  atomAuthor{Name: cmp.Or(opts.Author, opts.Title)},
+	}
+	if opts.Link != "" {
+		f.Links = append(f.Links, atomLink{Href: opts.Link, Rel: "self", Type: "application/atom+xml"})
+	}
+	if opts.HomePage != "" {
+		f.Links = append(f.Links, atomLink{Href: opts.HomePage, Rel: "alternate"})
+	}
+
+	for _, it := range items {
+		e := &atomEntry{
+			Title:   it.title(),
+			ID:      it.b.URL,
+			Link:    atomLink{Href: it.b.URL},
+			Updated: lastUpdate([]*feedItem{it}).Format(time.RFC3339),
+			Summary: it.b.Desc,
+		}
+		if !it.published.IsZero() {
+			e.Published = it.published.Format(time.RFC3339)
+		}
+		if c := it.content(); c != "" {
+			e.Content = &atomContent{Type: "text", Body: c}
+		}
+		for _, t := range it.tags {
+			e.Categories = append(e.Categories, atomCategory{Term: t})
+		}
+
+		f.Entries = append(f.Entries, e)
+	}
+
+	return writeXML(w, f)
+}
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	AtomNS  string     `xml:"xmlns:atom,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string     `xml:"title"`
+	Link          string     `xml:"link"`
+	Description   string     `xml:"description"`
+	Self          *atomLink  `xml:"atom:link"`
+	LastBuildDate string     `xml:"lastBuildDate"`
+	Items         []*rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	Description string   `xml:"description,omitempty"`
+	GUID        rssGUID  `xml:"guid"`
+	PubDate     string   `xml:"pubDate,omitempty"`
+	Categories  []string `xml:"category"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+func writeRSS(w io.Writer, items []*feedItem, opts *FeedOptions) error {
+	f := &rssFeed{
+		Version: "2.0",
+		AtomNS:  atomNS,
+		Channel: rssChannel{
+			Title:         opts.Title,
+			Link:          cmp.Or(opts.HomePage, opts.Link),
+			Description:   opts.Title,
+			LastBuildDate: lastUpdate(items).Format(time.RFC1123Z),
+		},
+	}
+	if opts.Link != "" {
+		f.Channel.Self = &atomLink{Href: opts.Link, Rel: "self", Type: "application/rss+xml"}
+	}
+
+	for _, it := range items {
+		item := &rssItem{
+			Title:       it.title(),
+			Link:        it.b.URL,
+			Description: it.content(),
+			GUID:        rssGUID{IsPermaLink: true, Value: it.b.URL},
+			Categories:  it.tags,
+		}
+		if !it.published.IsZero() {
+			item.PubDate = it.published.Format(time.RFC1123Z)
+		}
+
+		f.Channel.Items = append(f.Channel.Items, item)
+	}
+
+	return writeXML(w, f)
+}
+
+func writeXML(w io.Writer, v any) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+
+	return err
+}
+
+const jsonFeedVersion = "https://jsonfeed.org/version/1.1"
+
+type jsonFeed struct {
+	Version     string          `json:"version"`
+	Title       string          `json:"title"`
+	HomePageURL string          `json:"home_page_url,omitempty"`
+	FeedURL     string          `json:"feed_url,omitempty"`
+	Authors     []jsonAuthor    `json:"authors,omitempty"`
+	Items       []*jsonFeedItem `json:"items"`
+}
+
+type jsonAuthor struct {
+	Name string `json:"name"`
+}
+
+type jsonFeedItem struct {
+	ID            string   `json:"id"`
+	URL           string   `json:"url"`
+	Title         string   `json:"title"`
+	ContentText   string   `json:"content_text"`
+	Summary       string   `json:"summary,omitempty"`
+	DatePublished string   `json:"date_published,omitempty"`
+	DateModified  string   `json:"date_modified,omitempty"`
+	Tags          []string `json:"tags,omitempty"`
+}
+
+func writeJSONFeed(w io.Writer, items []*feedItem, opts *FeedOptions) error {
+	f := &jsonFeed{
+		Version:     jsonFeedVersion,
+		Title:       opts.Title,
+		HomePageURL: opts.HomePage,
+		FeedURL:     opts.Link,
+		Items:       make([]*jsonFeedItem, 0, len(items)),
+	}
+	if a := cmp.Or(opts.Author, opts.Title); a != "" {
+		f.Authors = []jsonAuthor{{Name: a}}
+	}
+
+	for _, it := range items {
+		item := &jsonFeedItem{
+			ID:          it.b.URL,
+			URL:         it.b.URL,
+			Title:       it.title(),
+			ContentText: it.content(),
+			Summary:     it.b.Desc,
+			Tags:        it.tags,
+		}
+		if !it.published.IsZero() {
+			item.DatePublished = it.published.Format(time.RFC3339)
+		}
+		if it.updated.After(it.published) {
+			item.DateModified = it.updated.Format(time.RFC3339)
+		}
+
+		f.Items = append(f.Items, item)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+
+	return enc.Encode(f)
+}
+
+// feedID returns the permanent identifier of the feed.
+func feedID(opts *FeedOptions) string {
+	if id := cmp.Or(opts.Link, opts.HomePage); id != "" {
+		return id
+	}
+
+	return "urn:gomarks:feed:" + strings.Join(strings.Fields(strings.ToLower(opts.Title)), "-")
+}
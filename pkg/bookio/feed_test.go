@@ -0,0 +1,145 @@
+package bookio
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/mateconpizza/gm/pkg/bookmark"
+)
+
+func testFeedBookmarks() []*bookmark.Bookmark {
+	return []*bookmark.Bookmark{
+		{
+			ID: 1, URL: "https://go.dev/", Title: "Go", Desc: "Go home", Notes: "Start with the tour",
+			Tags: "go,programming,", CreatedAt: "2023-01-15 10:30:00", UpdatedAt: "2023-02-01 09:00:00",
+		},
+		{ID: 2, URL: "https://example.org/?a=1&b=2", Tags: bookmark.DefaultTag, CreatedAt: "2024-06-01T08:00:00Z"},
+		{ID: 3, URL: "https://private.example/", Title: "Private", CreatedAt: "2025-01-01 00:00:00", Private: true},
+		{ID: 4, URL: "https://old.example/", Title: "Old", CreatedAt: "2020-01-01 00:00:00"},
+	}
+}
+
+func TestExportFeedAtom(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	opts := &FeedOptions{Title: "Team links", Link: "https://example.org/team.atom"}
+	if err := ExportFeed(&buf, FeedAtom, testFeedBookmarks(), opts); err != nil {
+		t.Fatal(err)
+	}
+
+	var f atomFeed
+	if err := xml.Unmarshal(buf.Bytes(), &f); err != nil {
+		t.Fatalf("invalid XML: %v\n%s", err, buf.String())
+	}
+
+	if f.Title != opts.Title || f.ID != opts.Link || f.Updated != "2024-06-01T08:00:00Z" {
+		t.Errorf("unexpected feed header: %+v", f)
+	}
+	if len(f.Entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(f.Entries))
+	}
+
+	// newest first, untitled entries use the URL
+	if f.Entries[0].Title != "https://example.org/?a=1&b=2" || f.Entries[2].Title != "Old" {
+		t.Errorf("unexpected order: %q, %q", f.Entries[0].Title, f.Entries[2].Title)
+	}
+	if len(f.Entries[0].Categories) != 0 {
+		t.Errorf("default tag used as category: %+v", f.Entries[0].Categories)
+	}
+
+	e := f.Entries[1]
+	if e.Content == nil || e.Content.Body != "Go home\n\nStart with the tour" {
+		t.Errorf("unexpected content: %+v", e.Content)
+	}
+	if e.Updated != "2023-02-01T09:00:00Z" || len(e.Categories) != 2 {
+		t.Errorf("unexpected entry: %+v", e)
+	}
+}
+
+func TestExportFeedRSS(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	opts := &FeedOptions{Title: "Team links", Link: "https://example.org/team.rss", Limit: 2}
+	if err := ExportFeed(&buf, FeedRSS, testFeedBookmarks(), opts); err != nil {
+		t.Fatal(err)
+	}
+
+	var f rssFeed
+	if err := xml.Unmarshal(buf.Bytes(), &f); err != nil {
+		t.Fatalf("invalid XML: %v\n%s", err, buf.String())
+	}
+
+	if len(f.Channel.Items) != 2 {
+		t.Fatalf("expected the limit of 2 items, got %d", len(f.Channel.Items))
+	}
+	if got := f.Channel.Items[1]; got.PubDate != "Sun, 15 Jan 2023 10:30:00 +0000" || got.GUID.Value != "https://go.dev/" {
+		t.Errorf("unexpected item: %+v", got)
+	}
+	if !strings.Contains(buf.String(), `<atom:link href="https://example.org/team.rss" rel="self"`) {
+		t.Errorf("missing self link:\n%s", buf.String())
+	}
+}
+
+func TestExportFeedJSON(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	if err := ExportFeed(&buf, FeedJSONFeed, testFeedBookmarks(), &FeedOptions{Title: "Team links"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var f jsonFeed
+	if err := json.Unmarshal(buf.Bytes(), &f); err != nil {
+		t.Fatal(err)
+	}
+
+	if f.Version != jsonFeedVersion || len(f.Items) != 3 {
+		t.Fatalf("unexpected feed: %+v", f)
+	}
+	if got := f.Items[1]; got.DatePublished != "2023-01-15T10:30:00Z" || strings.Join(got.Tags, ",") != "go,programming" {
+		t.Errorf("unexpected item: %+v", got)
+	}
+	if strings.Contains(buf.String(), "private.example") {
+		t.Error("private bookmark exported")
+	}
+}
+
+func TestExportFeedDeterministic(t *testing.T) {
+	t.Parallel()
+
+	for _, format := range FeedFormats() {
+		var a, b bytes.Buffer
+		opts := &FeedOptions{Title: "Team links"}
+		if err := ExportFeed(&a, format, testFeedBookmarks(), opts); err != nil {
+			t.Fatal(err)
+		}
+		if err := ExportFeed(&b, format, testFeedBookmarks(), opts); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(a.Bytes(), b.Bytes()) {
+			t.Errorf("%s: output changed between runs", format)
+		}
+	}
+}
+
+func TestValidFeedFormat(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{"": FeedAtom, "RSS": FeedRSS, "json": FeedJSONFeed, "jsonfeed": FeedJSONFeed}
+	for in, want := range tests {
+		got, err := ValidFeedFormat(in)
+		if err != nil || got != want {
+			t.Errorf("ValidFeedFormat(%q) = %q, %v, want %q", in, got, err, want)
+		}
+	}
+
+	if _, err := ValidFeedFormat("html"); !errors.Is(err, ErrFeedFormat) {
+		t.Errorf("expected ErrFeedFormat, got %v", err)
+	}
+}
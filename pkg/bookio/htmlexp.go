@@ -12,8 +12,11 @@ import (
 	"github.com/mateconpizza/gm/pkg/bookmark"
 )
 
-// ExportToNetscapeHTML exports bookmarks to Netscape HTML format.
+// ExportToNetscapeHTML exports bookmarks to Netscape HTML format, private
+// bookmarks are skipped.
 func ExportToNetscapeHTML(bs []*bookmark.Bookmark, writer io.Writer) error {
+	bs = bookmark.WithoutPrivate(bs)
+
 	// Write HTML header
 	_, err := writer.Write([]byte(`<!DOCTYPE NETSCAPE-Bookmark-file-1>
 <!-- This is an automatically generated file.
@@ -2,6 +2,7 @@ package bookio
 
 import (
 	"bufio"
+	"bytes"
 	"errors"
 	"fmt"
 	"io"
@@ -170,6 +171,26 @@ func FromNetscape(nb *BookmarkNetscape) *bookmark.Bookmark {
 	}
 }
 
+// ImportFromNetscapeHTML reads the bookmarks of a Netscape HTML file.
+func ImportFromNetscapeHTML(r io.Reader) ([]*bookmark.Bookmark, error) {
+	nbs, err := NewHTMLParser().ParseHTML(r)
+	if err != nil {
+		return nil, err
+	}
+
+	bs := make([]*bookmark.Bookmark, 0, len(nbs))
+	for i := range nbs {
+		bs = append(bs, FromNetscape(&nbs[i]))
+	}
+
+	return bs, nil
+}
+
+// sniffNetscape reports whether head starts a Netscape bookmark file.
+func sniffNetscape(head []byte) bool {
+	return bytes.Contains(bytes.ToUpper(head), []byte("<!DOCTYPE NETSCAPE-BOOKMARK-FILE-1>"))
+}
+
 // IsValidNetscapeFile checks if the file is a valid Netscape bookmark file
 // by looking for the specific DOCTYPE declaration.
 func IsValidNetscapeFile(file io.ReadSeeker) error {
@@ -2,8 +2,10 @@ package bookio
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 
@@ -18,6 +20,10 @@ const (
 	trackerFilepath = ".tracked.json" // Tracked databases in Git
 )
 
+// ErrPrivateRecord is returned when a private bookmark is about to be
+// written in plaintext.
+var ErrPrivateRecord = errors.New("private bookmarks cannot be written")
+
 var JSONStrategy = &RepositoryLoader{
 	Func:   jsonLoader,
 	Prefix: "Loading JSON bookmarks",
@@ -37,12 +43,55 @@ func jsonLoader(ctx context.Context, path string) (*bookmark.Bookmark, error) {
 	return bookmark.NewFromJSON(bj), nil
 }
 
+// ImportFromJSON reads a JSON array of bookmarks.
+func ImportFromJSON(r io.Reader) ([]*bookmark.Bookmark, error) {
+	var jbs []*bookmark.BookmarkJSON
+	if err := json.NewDecoder(r).Decode(&jbs); err != nil {
+		return nil, fmt.Errorf("decoding JSON: %w", err)
+	}
+
+	bs := make([]*bookmark.Bookmark, 0, len(jbs))
+	for _, bj := range jbs {
+		if bj.URL == "" {
+			return nil, ErrURLMissing
+		}
+		bs = append(bs, bookmark.NewFromJSON(bj))
+	}
+
+	return bs, nil
+}
+
+// ExportToJSON writes the bookmarks as an indented JSON array, private
+// bookmarks are skipped.
+func ExportToJSON(w io.Writer, bs []*bookmark.Bookmark) error {
+	bs = bookmark.WithoutPrivate(bs)
+	jbs := make([]*bookmark.BookmarkJSON, 0, len(bs))
+	for _, b := range bs {
+		jbs = append(jbs, b.JSON())
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(jbs)
+}
+
+// sniffJSON reports whether head starts a JSON array.
+func sniffJSON(head []byte) bool {
+	head = trimHead(head)
+	return len(head) > 0 && head[0] == '['
+}
+
 // SaveAsJSON creates files structure.
 //
 //	root -> dbName -> domain -> urlHash.json
 //
 // Returns true if the file was created or updated, false if no changes were made.
 func SaveAsJSON(rootPath string, b *bookmark.Bookmark, force bool) (bool, error) {
+	if b.Private {
+		return false, fmt.Errorf("%w: id %d", ErrPrivateRecord, b.ID)
+	}
+
 	domain, err := b.Domain()
 	if err != nil {
 		return false, fmt.Errorf("%w", err)
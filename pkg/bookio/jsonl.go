@@ -0,0 +1,112 @@
+package bookio
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/mateconpizza/gm/pkg/bookmark"
+)
+
+// jsonlMaxLine is the longest line read from a JSON Lines stream.
+const jsonlMaxLine = 16 << 20
+
+// JSONLWriter writes bookmarks as JSON Lines, one object per line, as they
+// come. Private bookmarks are skipped.
+type JSONLWriter struct {
+	enc *json.Encoder
+	n   int
+}
+
+// NewJSONLWriter returns a writer of JSON Lines to w.
+func NewJSONLWriter(w io.Writer) *JSONLWriter {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+
+	return &JSONLWriter{enc: enc}
+}
+
+// Write writes b on its own line.
+func (jw *JSONLWriter) Write(b *bookmark.Bookmark) error {
+	if b.Private {
+		return nil
+	}
+
+	if err := jw.enc.Encode(b.JSON()); err != nil {
+		return err
+	}
+	jw.n++
+
+	return nil
+}
+
+// Count returns the number of bookmarks written.
+func (jw *JSONLWriter) Count() int { return jw.n }
+
+// ExportToJSONL writes the bookmarks as JSON Lines, private bookmarks are
+// skipped.
+func ExportToJSONL(w io.Writer, bs []*bookmark.Bookmark) error {
+	jw := NewJSONLWriter(w)
+	for _, b := range bs {
+		if err := jw.Write(b); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ImportFromJSONL reads a JSON Lines stream of bookmarks, blank lines are
+// ignored.
+func ImportFromJSONL(r io.Reader) ([]*bookmark.Bookmark, error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), jsonlMaxLine)
+
+	var (
+		bs   []*bookmark.Bookmark
+		line int
+	)
+	for sc.Scan() {
+		line++
+		data := bytes.TrimSpace(sc.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+
+		bj := &bookmark.BookmarkJSON{}
+		if err := json.Unmarshal(data, bj); err != nil {
+			return nil, fmt.Errorf("line %d: decoding JSON: %w", line, err)
+		}
+		if bj.URL == "" {
+			return nil, fmt.Errorf("line %d: %w", line, ErrURLMissing)
+		}
+
+		// a tag added to an untagged bookmark replaces the default one
+		if len(bj.Tags) > 1 {
+			bj.Tags = slices.DeleteFunc(bj.Tags, func(t string) bool { return t == bookmark.DefaultTag })
+		}
+
+		bs = append(bs, bookmark.NewFromJSON(bj))
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("line %d: %w", line+1, err)
+	}
+
+	return bs, nil
+}
+
+// sniffJSONL reports whether head starts with a JSON object on its own
+// line.
+func sniffJSONL(head []byte) bool {
+	head = trimHead(head)
+	if len(head) == 0 || head[0] != '{' {
+		return false
+	}
+
+	first, _, _ := bytes.Cut(head, []byte("\n"))
+
+	return json.Valid(first) && bytes.Contains(first, []byte(`"url"`))
+}
@@ -0,0 +1,107 @@
+package bookio
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/mateconpizza/gm/pkg/bookmark"
+)
+
+func TestJSONLWriter(t *testing.T) {
+	t.Parallel()
+
+	bs := testSliceBookmarks(2)
+	bs[0].Notes = "a <b> & c\nsecond line"
+	private := testSingleBookmark()
+	private.Private = true
+	bs = append(bs, private)
+
+	var buf bytes.Buffer
+	jw := NewJSONLWriter(&buf)
+	for _, b := range bs {
+		if err := jw.Write(b); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if jw.Count() != 2 {
+		t.Errorf("expected 2 bookmarks written, got %d", jw.Count())
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected one line per bookmark, got:\n%s", buf.String())
+	}
+	if !strings.Contains(lines[0], `a <b> & c\nsecond line`) {
+		t.Errorf("notes not kept on one line: %s", lines[0])
+	}
+
+	got, err := ImportFromJSONL(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, b := range got {
+		if b.URL != bs[i].URL || b.Notes != bs[i].Notes || b.Tags != bookmark.ParseTags(bs[i].Tags) {
+			t.Errorf("bookmark %d: got %+v, want %+v", i, b, bs[i])
+		}
+	}
+}
+
+func TestImportFromJSONL(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		in      string
+		want    string
+		wantErr error
+	}{
+		{
+			name: "blank lines",
+			in:   "\n{\"url\":\"https://go.dev\"}\n\n  \n{\"url\":\"https://example.org\"}",
+		},
+		{
+			name: "default tag",
+			in:   "{\"url\":\"https://go.dev\",\"tags\":[\"notag\",\"later\"]}\n{\"url\":\"https://example.org\",\"tags\":[\"notag\"]}",
+		},
+		{
+			name: "invalid line",
+			in:   "{\"url\":\"https://go.dev\"}\n{\"url\":\n",
+			want: "line 2",
+		},
+		{
+			name:    "missing url",
+			in:      "{\"url\":\"https://go.dev\"}\n\n{\"title\":\"no url\"}\n",
+			want:    "line 3",
+			wantErr: ErrURLMissing,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			bs, err := ImportFromJSONL(strings.NewReader(tt.in))
+			if tt.want == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if len(bs) != 2 {
+					t.Fatalf("expected 2 bookmarks, got %d", len(bs))
+				}
+				if tt.name == "default tag" && (bs[0].Tags != "later," || bs[1].Tags != bookmark.DefaultTag+",") {
+					t.Errorf("default tag: got %q, %q", bs[0].Tags, bs[1].Tags)
+				}
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("expected an error at %s, got %v", tt.want, err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package bookio
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/mateconpizza/gm/pkg/bookmark"
+)
+
+// linkdingBookmark is a bookmark of the Linkding API.
+type linkdingBookmark struct {
+	URL          string   `json:"url"`
+	Title        string   `json:"title"`
+	Description  string   `json:"description"`
+	Notes        string   `json:"notes"`
+	Unread       bool     `json:"unread"`
+	Shared       bool     `json:"shared"`
+	TagNames     []string `json:"tag_names"`
+	DateAdded    string   `json:"date_added"`
+	DateModified string   `json:"date_modified"`
+}
+
+// linkdingPage is a page of the Linkding bookmarks API.
+type linkdingPage struct {
+	Count    int                 `json:"count"`
+	Next     *string             `json:"next"`
+	Previous *string             `json:"previous"`
+	Results  []*linkdingBookmark `json:"results"`
+}
+
+// ImportFromLinkding reads Linkding bookmarks, either an API page or a JSON
+// array of its results. Unread bookmarks get the unread tag.
+func ImportFromLinkding(r io.Reader) ([]*bookmark.Bookmark, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var lbs []*linkdingBookmark
+	if head := trimHead(data); len(head) > 0 && head[0] == '[' {
+		err = json.Unmarshal(head, &lbs)
+	} else {
+		var page linkdingPage
+		err = json.Unmarshal(head, &page)
+		lbs = page.Results
+	}
+	if err != nil {
+		return nil, fmt.Errorf("decoding JSON: %w", err)
+	}
+
+	bs := make([]*bookmark.Bookmark, 0, len(lbs))
+	for _, lb := range lbs {
+		if lb.URL == "" {
+			return nil, ErrURLMissing
+		}
+
+		bs = append(bs, &bookmark.Bookmark{
+			URL:       lb.URL,
+			Title:     lb.Title,
+			Desc:      lb.Description,
+			Notes:     lb.Notes,
+			Tags:      importTags(lb.TagNames, lb.Unread),
+			CreatedAt: parseServiceTime(lb.DateAdded),
+			UpdatedAt: parseServiceTime(lb.DateModified),
+		})
+	}
+
+	return bs, nil
+}
+
+// ExportToLinkding writes the bookmarks as a Linkding API page, private
+// bookmarks are skipped.
+func ExportToLinkding(w io.Writer, bs []*bookmark.Bookmark) error {
+	bs = bookmark.WithoutPrivate(bs)
+	page := &linkdingPage{Results: make([]*linkdingBookmark, 0, len(bs))}
+	for _, b := range bs {
+		tags, unread := serviceTags(b)
+		if tags == nil {
+			tags = []string{}
+		}
+
+		lb := &linkdingBookmark{
+			URL:         b.URL,
+			Title:       b.Title,
+			Description: b.Desc,
+			Notes:       b.Notes,
+			Unread:      unread,
+			TagNames:    tags,
+		}
+		if t, ok := bookmarkTime(b.CreatedAt); ok {
+			lb.DateAdded = t.Format(time.RFC3339)
+		}
+		lb.DateModified = lb.DateAdded
+		if t, ok := bookmarkTime(b.UpdatedAt); ok {
+			lb.DateModified = t.Format(time.RFC3339)
+		}
+
+		page.Results = append(page.Results, lb)
+	}
+	page.Count = len(page.Results)
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+
+	return enc.Encode(page)
+}
+
+// sniffLinkding reports whether head holds Linkding bookmarks.
+func sniffLinkding(head []byte) bool {
+	head = trimHead(head)
+	return len(head) > 0 && (head[0] == '[' || head[0] == '{') && bytes.Contains(head, []byte(`"tag_names"`))
+}
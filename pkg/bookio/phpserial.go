@@ -0,0 +1,229 @@
+package bookio
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var ErrPHPSerialized = errors.New("invalid PHP serialized data")
+
+// phpArray is a decoded PHP array or object, keeping the key order.
+// Object property names are stored without their visibility prefix.
+type phpArray struct {
+	class  string // empty for arrays
+	keys   []string
+	values map[string]any
+}
+
+func (a *phpArray) get(key string) any { return a.values[key] }
+
+func (a *phpArray) str(key string) string {
+	switch v := a.values[key].(type) {
+	case string:
+		return v
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+
+	return ""
+}
+
+func (a *phpArray) bool(key string) bool {
+	switch v := a.values[key].(type) {
+	case bool:
+		return v
+	case int64:
+		return v != 0
+	case string:
+		return v != "" && v != "0"
+	}
+
+	return false
+}
+
+// list returns the values in order.
+func (a *phpArray) list() []any {
+	out := make([]any, 0, len(a.keys))
+	for _, k := range a.keys {
+		out = append(out, a.values[k])
+	}
+
+	return out
+}
+
+// phpDecoder decodes the output of the PHP serialize function. Strings are
+// byte counted, so the input is walked as bytes.
+type phpDecoder struct {
+	data string
+	pos  int
+}
+
+// phpUnserialize decodes a PHP serialized value into strings, int64,
+// float64, bool, nil and *phpArray.
+func phpUnserialize(data string) (any, error) {
+	d := &phpDecoder{data: data}
+	v, err := d.value()
+	if err != nil {
+		return nil, fmt.Errorf("%w: offset %d: %w", ErrPHPSerialized, d.pos, err)
+	}
+
+	return v, nil
+}
+
+func (d *phpDecoder) value() (any, error) {
+	if d.pos+1 >= len(d.data) {
+		return nil, errors.New("unexpected end")
+	}
+
+	kind := d.data[d.pos]
+	if kind == 'N' {
+		d.pos++
+		return nil, d.expect(';')
+	}
+
+	d.pos++
+	if err := d.expect(':'); err != nil {
+		return nil, err
+	}
+
+	switch kind {
+	case 'b':
+		s, err := d.until(';')
+		return s == "1", err
+	case 'i':
+		s, err := d.until(';')
+		if err != nil {
+			return nil, err
+		}
+		return strconv.ParseInt(s, 10, 64)
+	case 'd':
+		s, err := d.until(';')
+		if err != nil {
+			return nil, err
+		}
+		return strconv.ParseFloat(s, 64)
+	case 's':
+		s, err := d.str()
+		if err != nil {
+			return nil, err
+		}
+		return s, d.expect(';')
+	case 'a':
+		return d.array("")
+	case 'O':
+		class, err := d.str()
+		if err != nil {
+			return nil, err
+		}
+		if err := d.expect(':'); err != nil {
+			return nil, err
+		}
+		return d.array(class)
+	}
+
+	return nil, fmt.Errorf("unsupported type %q", kind)
+}
+
+// str reads a `<len>:"<bytes>"` string.
+func (d *phpDecoder) str() (string, error) {
+	s, err := d.until(':')
+	if err != nil {
+		return "", err
+	}
+
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 {
+		return "", fmt.Errorf("invalid string length %q", s)
+	}
+
+	if err := d.expect('"'); err != nil {
+		return "", err
+	}
+	if n > len(d.data)-d.pos {
+		return "", errors.New("string out of range")
+	}
+
+	v := d.data[d.pos : d.pos+n]
+	d.pos += n
+
+	return v, d.expect('"')
+}
+
+// array reads a `<n>:{key;value;...}` array, object properties included.
+func (d *phpDecoder) array(class string) (*phpArray, error) {
+	s, err := d.until(':')
+	if err != nil {
+		return nil, err
+	}
+
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 {
+		return nil, fmt.Errorf("invalid array length %q", s)
+	}
+
+	if err := d.expect('{'); err != nil {
+		return nil, err
+	}
+	// every entry takes a few bytes, a larger count is corrupt
+	if n > len(d.data)-d.pos {
+		return nil, errors.New("array out of range")
+	}
+
+	a := &phpArray{class: class, values: make(map[string]any, n)}
+	for range n {
+		k, err := d.value()
+		if err != nil {
+			return nil, err
+		}
+
+		var key string
+		switch k := k.(type) {
+		case string:
+			// protected "\0*\0name" and private "\0Class\0name" properties
+			if i := strings.LastIndexByte(k, 0); i >= 0 {
+				k = k[i+1:]
+			}
+			key = k
+		case int64:
+			key = strconv.FormatInt(k, 10)
+		default:
+			return nil, fmt.Errorf("invalid array key %v", k)
+		}
+
+		v, err := d.value()
+		if err != nil {
+			return nil, err
+		}
+
+		a.keys = append(a.keys, key)
+		a.values[key] = v
+	}
+
+	return a, d.expect('}')
+}
+
+func (d *phpDecoder) expect(c byte) error {
+	if d.pos >= len(d.data) || d.data[d.pos] != c {
+		return fmt.Errorf("expected %q", c)
+	}
+	d.pos++
+
+	return nil
+}
+
+// until returns the bytes up to sep and skips it.
+func (d *phpDecoder) until(sep byte) (string, error) {
+	i := strings.IndexByte(d.data[d.pos:], sep)
+	if i < 0 {
+		return "", fmt.Errorf("expected %q", sep)
+	}
+
+	s := d.data[d.pos : d.pos+i]
+	d.pos += i + 1
+
+	return s, nil
+}
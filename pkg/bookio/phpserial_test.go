@@ -0,0 +1,31 @@
+package bookio
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPHPUnserializeMalformed(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		in   string
+	}{
+		{"huge_string", `s:9223372036854775807:"abc";`},
+		{"long_string", `s:10:"abc";`},
+		{"huge_array", `a:9223372036854775807:{i:0;N;}`},
+		{"long_array", `a:3:{i:0;N;}`},
+		{"negative_length", `s:-1:"";`},
+		{"truncated", `a:1:{s:3:"url"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if _, err := phpUnserialize(tt.in); !errors.Is(err, ErrPHPSerialized) {
+				t.Errorf("phpUnserialize(%q): expected %v, got %v", tt.in, ErrPHPSerialized, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package bookio
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/mateconpizza/gm/pkg/bookmark"
+)
+
+// pinboardPost is an entry of the Pinboard JSON export.
+type pinboardPost struct {
+	Href        string `json:"href"`
+	Description string `json:"description"` // title
+	Extended    string `json:"extended"`    // description
+	Time        string `json:"time"`
+	Shared      string `json:"shared"` // "yes" or "no"
+	ToRead      string `json:"toread"` // "yes" or "no"
+	Tags        string `json:"tags"`   // space separated
+}
+
+// ImportFromPinboard reads a Pinboard JSON export. Posts not shared are
+// imported as private and unread posts get the unread tag.
+func ImportFromPinboard(r io.Reader) ([]*bookmark.Bookmark, error) {
+	var posts []*pinboardPost
+	if err := json.NewDecoder(r).Decode(&posts); err != nil {
+		return nil, fmt.Errorf("decoding JSON: %w", err)
+	}
+
+	bs := make([]*bookmark.Bookmark, 0, len(posts))
+	for _, p := range posts {
+		if p.Href == "" {
+			return nil, ErrURLMissing
+		}
+
+		bs = append(bs, &bookmark.Bookmark{
+			URL:       p.Href,
+			Title:     p.Description,
+			Desc:      p.Extended,
+			Tags:      importTags(strings.Fields(p.Tags), p.ToRead == "yes"),
+			CreatedAt: parseServiceTime(p.Time),
+			Private:   p.Shared == "no",
+		})
+	}
+
+	return bs, nil
+}
+
+// ExportToPinboard writes the bookmarks as a Pinboard JSON export, private
+// bookmarks are skipped.
+func ExportToPinboard(w io.Writer, bs []*bookmark.Bookmark) error {
+	bs = bookmark.WithoutPrivate(bs)
+	posts := make([]*pinboardPost, 0, len(bs))
+	for _, b := range bs {
+		tags, unread := serviceTags(b)
+
+		p := &pinboardPost{
+			Href:        b.URL,
+			Description: b.Title,
+			Extended:    b.Desc,
+			Shared:      "yes",
+			ToRead:      "no",
+			Tags:        strings.Join(tags, " "),
+		}
+		if unread {
+			p.ToRead = "yes"
+		}
+		if t, ok := bookmarkTime(b.CreatedAt); ok {
+			p.Time = t.Format(time.RFC3339)
+		}
+
+		posts = append(posts, p)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+
+	return enc.Encode(posts)
+}
+
+// sniffPinboard reports whether head starts a JSON array of Pinboard posts.
+func sniffPinboard(head []byte) bool {
+	head = trimHead(head)
+	return len(head) > 0 && head[0] == '[' && bytes.Contains(head, []byte(`"href"`))
+}
@@ -0,0 +1,177 @@
+package bookio
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+
+	nethtml "golang.org/x/net/html"
+
+	"github.com/mateconpizza/gm/pkg/bookmark"
+)
+
+// Pocket export sections, the first one holds the unread items.
+const (
+	pocketUnread  = "Unread"
+	pocketArchive = "Read Archive"
+)
+
+// pocketItem is an item of the Pocket export.
+type pocketItem struct {
+	url, title string
+	added      string // unix seconds
+	tags       []string
+	unread     bool
+}
+
+func (p *pocketItem) bookmark() *bookmark.Bookmark {
+	return &bookmark.Bookmark{
+		URL:       p.url,
+		Title:     p.title,
+		Tags:      importTags(p.tags, p.unread),
+		CreatedAt: parseServiceTime(p.added),
+	}
+}
+
+// ImportFromPocket reads a Pocket export, either the HTML file or the CSV
+// one. Unread items get the unread tag.
+func ImportFromPocket(r io.Reader) ([]*bookmark.Bookmark, error) {
+	br := bufio.NewReader(r)
+
+	head, _ := br.Peek(sniffLen)
+	if h := trimHead(head); len(h) > 0 && h[0] == '<' {
+		return importPocketHTML(br)
+	}
+
+	return importPocketCSV(br)
+}
+
+// importPocketHTML reads the Pocket HTML export, a list of links per section.
+func importPocketHTML(r io.Reader) ([]*bookmark.Bookmark, error) {
+	doc, err := nethtml.Parse(r)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing HTML: %w", err)
+	}
+
+	var (
+		bs      []*bookmark.Bookmark
+		section string
+		walk    func(n *nethtml.Node)
+	)
+
+	walk = func(n *nethtml.Node) {
+		if n.Type == nethtml.ElementNode {
+			switch n.Data {
+			case "h1":
+				if n.FirstChild != nil {
+					section = strings.TrimSpace(n.FirstChild.Data)
+				}
+			case "a":
+				item := &pocketItem{unread: section != pocketArchive}
+				for _, a := range n.Attr {
+					switch a.Key {
+					case "href":
+						item.url = a.Val
+					case "time_added":
+						item.added = a.Val
+					case "tags":
+						item.tags = strings.Split(a.Val, ",")
+					}
+				}
+				if n.FirstChild != nil {
+					item.title = strings.TrimSpace(n.FirstChild.Data)
+				}
+				if item.url != "" {
+					bs = append(bs, item.bookmark())
+				}
+			}
+		}
+
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+
+	return bs, nil
+}
+
+// importPocketCSV reads the Pocket CSV export, tags are separated by "|".
+func importPocketCSV(r io.Reader) ([]*bookmark.Bookmark, error) {
+	rows, err := csvRecords(r)
+	if err != nil {
+		return nil, err
+	}
+
+	bs := make([]*bookmark.Bookmark, 0, len(rows))
+	for _, row := range rows {
+		item := &pocketItem{
+			url:    row["url"],
+			title:  row["title"],
+			added:  row["time_added"],
+			tags:   strings.Split(row["tags"], "|"),
+			unread: row["status"] != "archive",
+		}
+		bs = append(bs, item.bookmark())
+	}
+
+	return bs, nil
+}
+
+// ExportToPocket writes the bookmarks as a Pocket HTML export, the ones
+// tagged unread in the first section. Private bookmarks are skipped.
+func ExportToPocket(w io.Writer, bs []*bookmark.Bookmark) error {
+	var unread, archive []*bookmark.Bookmark
+	for _, b := range bookmark.WithoutPrivate(bs) {
+		if _, ok := serviceTags(b); ok {
+			unread = append(unread, b)
+		} else {
+			archive = append(archive, b)
+		}
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(`<!DOCTYPE html>
+<html>
+	<!--So long and thanks for all the fish-->
+	<head>
+		<meta http-equiv="Content-Type" content="text/html; charset=UTF-8" />
+		<title>Pocket Export</title>
+	</head>
+	<body>
+`)
+
+	section := func(name string, bs []*bookmark.Bookmark) {
+		buf.WriteString("\t\t<h1>" + name + "</h1>\n\t\t<ul>\n")
+		for _, b := range bs {
+			tags, _ := serviceTags(b)
+
+			var added int64
+			if t, ok := bookmarkTime(b.CreatedAt); ok {
+				added = t.Unix()
+			}
+
+			fmt.Fprintf(&buf, "\t\t\t<li><a href=\"%s\" time_added=\"%d\" tags=\"%s\">%s</a></li>\n",
+				html.EscapeString(b.URL), added,
+				html.EscapeString(strings.Join(tags, ",")), html.EscapeString(b.Title))
+		}
+		buf.WriteString("\t\t</ul>\n")
+	}
+	section(pocketUnread, unread)
+	section(pocketArchive, archive)
+
+	buf.WriteString("\t</body>\n</html>\n")
+
+	_, err := buf.WriteTo(w)
+
+	return err
+}
+
+// sniffPocket reports whether head starts a Pocket HTML or CSV export.
+func sniffPocket(head []byte) bool {
+	return bytes.Contains(head, []byte("<title>Pocket Export</title>")) ||
+		hasColumns(head, "url", "time_added", "status")
+}
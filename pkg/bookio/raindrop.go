@@ -0,0 +1,89 @@
+package bookio
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mateconpizza/gm/pkg/bookmark"
+)
+
+// raindropUnsorted is the Raindrop folder of bookmarks without collection.
+const raindropUnsorted = "Unsorted"
+
+var raindropHeader = []string{
+	"id", "title", "note", "excerpt", "url", "folder", "tags", "created", "cover", "highlights", "favorite",
+}
+
+// ImportFromRaindrop reads a Raindrop CSV export. The folder becomes a tag.
+func ImportFromRaindrop(r io.Reader) ([]*bookmark.Bookmark, error) {
+	rows, err := csvRecords(r)
+	if err != nil {
+		return nil, err
+	}
+
+	bs := make([]*bookmark.Bookmark, 0, len(rows))
+	for _, row := range rows {
+		tags := strings.Split(row["tags"], ",")
+		if f := row["folder"]; f != "" && f != raindropUnsorted {
+			tags = append(tags, folderTag(f))
+		}
+		for i := range tags {
+			tags[i] = strings.TrimSpace(tags[i])
+		}
+
+		favorite, _ := strconv.ParseBool(row["favorite"])
+
+		bs = append(bs, &bookmark.Bookmark{
+			URL:       row["url"],
+			Title:     row["title"],
+			Desc:      row["excerpt"],
+			Notes:     row["note"],
+			Tags:      importTags(tags, false),
+			CreatedAt: parseServiceTime(row["created"]),
+			Favorite:  favorite,
+		})
+	}
+
+	return bs, nil
+}
+
+// ExportToRaindrop writes the bookmarks as a Raindrop CSV export, private
+// bookmarks are skipped.
+func ExportToRaindrop(w io.Writer, bs []*bookmark.Bookmark) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(raindropHeader); err != nil {
+		return err
+	}
+
+	for _, b := range bookmark.WithoutPrivate(bs) {
+		tags, unread := serviceTags(b)
+		if unread {
+			tags = append(tags, UnreadTag)
+		}
+
+		var created string
+		if t, ok := bookmarkTime(b.CreatedAt); ok {
+			created = t.Format(time.RFC3339)
+		}
+
+		row := []string{
+			strconv.Itoa(b.ID), b.Title, b.Notes, b.Desc, b.URL, raindropUnsorted,
+			strings.Join(tags, ", "), created, "", "", strconv.FormatBool(b.Favorite),
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
+
+// sniffRaindrop reports whether head starts with the Raindrop CSV header.
+func sniffRaindrop(head []byte) bool {
+	return hasColumns(head, "url", "excerpt", "folder", "highlights")
+}
@@ -0,0 +1,300 @@
+package bookio
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/mateconpizza/gm/pkg/bookmark"
+)
+
+var (
+	ErrFormatExists     = errors.New("format already registered")
+	ErrFormatUnknown    = errors.New("unknown format")
+	ErrFormatUndetected = errors.New("could not detect format")
+	ErrFormatNoImport   = errors.New("format does not support import")
+	ErrFormatNoExport   = errors.New("format does not support export")
+)
+
+// sniffLen is the number of bytes inspected to detect a format.
+const sniffLen = 4096
+
+// Importer reads bookmarks from a serialized format.
+type Importer interface {
+	Import(r io.Reader) ([]*bookmark.Bookmark, error)
+}
+
+// Exporter writes bookmarks in a serialized format. Private bookmarks must be
+// skipped.
+type Exporter interface {
+	Export(w io.Writer, bs []*bookmark.Bookmark) error
+}
+
+// FieldSelector is implemented by exporters that can write a subset of the
+// bookmark fields.
+type FieldSelector interface {
+	WithFields(fields []string) Exporter
+}
+
+// ImporterFunc adapts a function to the Importer interface.
+type ImporterFunc func(r io.Reader) ([]*bookmark.Bookmark, error)
+
+func (f ImporterFunc) Import(r io.Reader) ([]*bookmark.Bookmark, error) { return f(r) }
+
+// ExporterFunc adapts a function to the Exporter interface.
+type ExporterFunc func(w io.Writer, bs []*bookmark.Bookmark) error
+
+func (f ExporterFunc) Export(w io.Writer, bs []*bookmark.Bookmark) error { return f(w, bs) }
+
+// Format describes a bookmark file format. Importer or Exporter may be nil
+// for one-way formats.
+type Format struct {
+	Name       string                 // Unique name, e.g. "html"
+	Desc       string                 // Short description
+	Extensions []string               // File extensions, e.g. ".html"
+	Sniff      func(head []byte) bool // Reports whether the content head looks like the format
+	Importer   Importer
+	Exporter   Exporter
+}
+
+// CanImport reports whether the format supports import.
+func (f *Format) CanImport() bool { return f.Importer != nil }
+
+// CanExport reports whether the format supports export.
+func (f *Format) CanExport() bool { return f.Exporter != nil }
+
+// hasExtension reports whether name ends with one of the format extensions.
+func (f *Format) hasExtension(name string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	return ext != "" && slices.Contains(f.Extensions, ext)
+}
+
+var registry = struct {
+	sync.RWMutex
+	formats []*Format // registration order, used when sniffing
+}{}
+
+// Register adds a format to the registry.
+func Register(f *Format) error {
+	registry.Lock()
+	defer registry.Unlock()
+
+	name := strings.ToLower(f.Name)
+	for _, r := range registry.formats {
+		if r.Name == name {
+			return fmt.Errorf("%w: %q", ErrFormatExists, name)
+		}
+	}
+
+	f.Name = name
+	registry.formats = append(registry.formats, f)
+
+	return nil
+}
+
+// MustRegister is like Register but panics on error.
+func MustRegister(f *Format) {
+	if err := Register(f); err != nil {
+		panic(err)
+	}
+}
+
+// Lookup returns the registered format with the given name.
+func Lookup(name string) (*Format, error) {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	name = strings.ToLower(strings.TrimSpace(name))
+	for _, f := range registry.formats {
+		if f.Name == name {
+			return f, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %q", ErrFormatUnknown, name)
+}
+
+// Formats returns the registered formats sorted by name.
+func Formats() []*Format {
+	registry.RLock()
+	fs := slices.Clone(registry.formats)
+	registry.RUnlock()
+
+	slices.SortFunc(fs, func(a, b *Format) int { return strings.Compare(a.Name, b.Name) })
+
+	return fs
+}
+
+// FormatNames returns the names of the formats supporting import or export.
+func FormatNames(export bool) []string {
+	var names []string
+	for _, f := range Formats() {
+		if (export && f.CanExport()) || (!export && f.CanImport()) {
+			names = append(names, f.Name)
+		}
+	}
+
+	return names
+}
+
+// Detect returns the importable format of a file, sniffing its content head
+// first and falling back to the file name extension.
+func Detect(name string, head []byte) (*Format, error) {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	for _, f := range registry.formats {
+		if f.CanImport() && f.Sniff != nil && f.Sniff(head) {
+			return f, nil
+		}
+	}
+
+	for _, f := range registry.formats {
+		if f.CanImport() && f.hasExtension(name) {
+			return f, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %q", ErrFormatUndetected, name)
+}
+
+// Import reads the bookmarks from r using the named format, or the detected
+// one when format is empty.
+func Import(r io.Reader, name, format string) (*Format, []*bookmark.Bookmark, error) {
+	br := bufio.NewReaderSize(r, sniffLen)
+
+	var (
+		f   *Format
+		err error
+	)
+	if format != "" {
+		f, err = Lookup(format)
+	} else {
+		head, _ := br.Peek(sniffLen)
+		f, err = Detect(name, head)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !f.CanImport() {
+		return nil, nil, fmt.Errorf("%w: %q", ErrFormatNoImport, f.Name)
+	}
+
+	bs, err := f.Importer.Import(br)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", f.Name, err)
+	}
+
+	for _, b := range bs {
+		if b.Checksum == "" {
+			b.GenChecksum()
+		}
+	}
+
+	return f, bs, nil
+}
+
+// Export writes the bookmarks to w using the named format. fields is only
+// used by exporters implementing FieldSelector.
+func Export(w io.Writer, format string, bs []*bookmark.Bookmark, fields []string) error {
+	f, err := Lookup(format)
+	if err != nil {
+		return err
+	}
+
+	if !f.CanExport() {
+		return fmt.Errorf("%w: %q", ErrFormatNoExport, f.Name)
+	}
+
+	e := f.Exporter
+	if fs, ok := e.(FieldSelector); ok && len(fields) > 0 {
+		e = fs.WithFields(fields)
+	}
+
+	return e.Export(w, bookmark.WithoutPrivate(bs))
+}
+
+// trimHead returns head without a leading BOM and whitespace.
+func trimHead(head []byte) []byte {
+	return bytes.TrimSpace(bytes.TrimPrefix(head, []byte("\xef\xbb\xbf")))
+}
+
+func init() {
+	// services first, their content is a more specific match than the
+	// generic formats
+	MustRegister(&Format{
+		Name:     "pinboard",
+		Desc:     "Pinboard JSON export",
+		Sniff:    sniffPinboard,
+		Importer: ImporterFunc(ImportFromPinboard),
+		Exporter: ExporterFunc(ExportToPinboard),
+	})
+	MustRegister(&Format{
+		Name:     "linkding",
+		Desc:     "Linkding JSON bookmarks",
+		Sniff:    sniffLinkding,
+		Importer: ImporterFunc(ImportFromLinkding),
+		Exporter: ExporterFunc(ExportToLinkding),
+	})
+	MustRegister(&Format{
+		Name:       "shaarli",
+		Desc:       "Shaarli datastore.php or API JSON export",
+		Extensions: []string{".php"},
+		Sniff:      sniffShaarli,
+		Importer:   ImporterFunc(ImportFromShaarli),
+		Exporter:   ExporterFunc(ExportToShaarli),
+	})
+	MustRegister(&Format{
+		Name:     "pocket",
+		Desc:     "Pocket HTML or CSV export",
+		Sniff:    sniffPocket,
+		Importer: ImporterFunc(ImportFromPocket),
+		Exporter: ExporterFunc(ExportToPocket),
+	})
+	MustRegister(&Format{
+		Name:     "raindrop",
+		Desc:     "Raindrop CSV export",
+		Sniff:    sniffRaindrop,
+		Importer: ImporterFunc(ImportFromRaindrop),
+		Exporter: ExporterFunc(ExportToRaindrop),
+	})
+	MustRegister(&Format{
+		Name:       "html",
+		Desc:       "Netscape bookmark file",
+		Extensions: []string{".html", ".htm"},
+		Sniff:      sniffNetscape,
+		Importer:   ImporterFunc(ImportFromNetscapeHTML),
+		Exporter:   ExporterFunc(func(w io.Writer, bs []*bookmark.Bookmark) error { return ExportToNetscapeHTML(bs, w) }),
+	})
+	MustRegister(&Format{
+		Name:       "json",
+		Desc:       "JSON array of bookmarks",
+		Extensions: []string{jsonExt},
+		Sniff:      sniffJSON,
+		Importer:   ImporterFunc(ImportFromJSON),
+		Exporter:   ExporterFunc(ExportToJSON),
+	})
+	MustRegister(&Format{
+		Name:       "jsonl",
+		Desc:       "JSON Lines, one bookmark per line",
+		Extensions: []string{".jsonl", ".ndjson"},
+		Sniff:      sniffJSONL,
+		Importer:   ImporterFunc(ImportFromJSONL),
+		Exporter:   ExporterFunc(ExportToJSONL),
+	})
+	MustRegister(&Format{
+		Name:       "csv",
+		Desc:       "comma-separated values with a header",
+		Extensions: []string{".csv"},
+		Sniff:      sniffCSV,
+		Importer:   ImporterFunc(ImportFromCSV),
+		Exporter:   &CSVExporter{},
+	})
+}
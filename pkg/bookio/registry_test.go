@@ -0,0 +1,162 @@
+package bookio
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/mateconpizza/gm/pkg/bookmark"
+)
+
+func TestDetect(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		file    string
+		head    string
+		want    string
+		wantErr error
+	}{
+		{
+			name: "netscape doctype",
+			file: "bookmarks.txt",
+			head: "\n<!DOCTYPE NETSCAPE-Bookmark-file-1>\n<DL><p>",
+			want: "html",
+		},
+		{
+			name: "json array with BOM",
+			file: "export",
+			head: "\xef\xbb\xbf  [\n  {\"url\": \"https://go.dev\"}]",
+			want: "json",
+		},
+		{
+			name: "json lines",
+			file: "-",
+			head: "{\"id\":1,\"url\":\"https://go.dev\",\"tags\":[\"go\"]}\n{\"url\":\"https://example.org\"}\n",
+			want: "jsonl",
+		},
+		{
+			name: "jsonl extension fallback",
+			file: "export.ndjson",
+			head: "",
+			want: "jsonl",
+		},
+		{
+			name: "csv header",
+			file: "data",
+			head: "id,\"URL\",title\n1,https://go.dev,Go\n",
+			want: "csv",
+		},
+		{
+			name: "extension fallback",
+			file: "Bookmarks.HTM",
+			head: "<html><body></body></html>",
+			want: "html",
+		},
+		{
+			name:    "unknown",
+			file:    "notes.txt",
+			head:    "just some text",
+			wantErr: ErrFormatUndetected,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			f, err := Detect(tt.file, []byte(tt.head))
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if f.Name != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, f.Name)
+			}
+		})
+	}
+}
+
+func TestRegistryRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	bs := testSliceBookmarks(3)
+	private := testSingleBookmark()
+	private.URL = "https://private.example.com"
+	private.Private = true
+	bs = append(bs, private)
+
+	for _, name := range []string{"html", "json", "jsonl", "csv"} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			if err := Export(&buf, name, bs, []string{"url", "title", "tags"}); err != nil {
+				t.Fatalf("export: %v", err)
+			}
+
+			f, got, err := Import(&buf, "", "")
+			if err != nil {
+				t.Fatalf("import: %v", err)
+			}
+			if f.Name != name {
+				t.Errorf("detected %q, want %q", f.Name, name)
+			}
+			if len(got) != 3 {
+				t.Fatalf("expected 3 bookmarks without the private one, got %d", len(got))
+			}
+			for i, b := range got {
+				if b.URL != bs[i].URL || b.Title != bs[i].Title {
+					t.Errorf("bookmark %d: got %q %q, want %q %q", i, b.URL, b.Title, bs[i].URL, bs[i].Title)
+				}
+			}
+		})
+	}
+}
+
+func TestRegister(t *testing.T) {
+	t.Parallel()
+
+	if err := Register(&Format{Name: "JSON"}); !errors.Is(err, ErrFormatExists) {
+		t.Fatalf("expected ErrFormatExists, got %v", err)
+	}
+
+	lines := &Format{
+		Name:       "test-lines",
+		Extensions: []string{".lines"},
+		Importer: ImporterFunc(func(r io.Reader) ([]*bookmark.Bookmark, error) {
+			data, err := io.ReadAll(r)
+			if err != nil {
+				return nil, err
+			}
+			var bs []*bookmark.Bookmark
+			for u := range strings.FieldsSeq(string(data)) {
+				bs = append(bs, &bookmark.Bookmark{URL: u})
+			}
+			return bs, nil
+		}),
+	}
+	if err := Register(lines); err != nil {
+		t.Fatal(err)
+	}
+
+	f, bs, err := Import(strings.NewReader("https://a.dev\nhttps://b.dev\n"), "urls.lines", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f != lines || len(bs) != 2 {
+		t.Errorf("expected 2 bookmarks from %q, got %d from %q", lines.Name, len(bs), f.Name)
+	}
+
+	if err := Export(io.Discard, "test-lines", bs, nil); !errors.Is(err, ErrFormatNoExport) {
+		t.Errorf("expected ErrFormatNoExport, got %v", err)
+	}
+}
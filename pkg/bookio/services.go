@@ -0,0 +1,159 @@
+package bookio
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mateconpizza/gm/pkg/bookmark"
+)
+
+// UnreadTag marks the bookmarks a service keeps in its read-later queue.
+const UnreadTag = "unread"
+
+// serviceTags returns the tags of b to write in a service export, without
+// the default and unread tags, and whether b is unread.
+func serviceTags(b *bookmark.Bookmark) (tags []string, unread bool) {
+	for t := range strings.SplitSeq(b.Tags, ",") {
+		switch t {
+		case "", bookmark.DefaultTag:
+		case UnreadTag:
+			unread = true
+		default:
+			tags = append(tags, t)
+		}
+	}
+
+	return tags, unread
+}
+
+// importTags returns the bookmark tags for a service entry, adding the
+// unread tag when the entry is unread.
+func importTags(tags []string, unread bool) string {
+	if unread {
+		tags = append(tags, UnreadTag)
+	}
+
+	return bookmark.ParseTags(strings.Join(tags, ","))
+}
+
+// folderTag returns the tag for a service folder path, lowercase with
+// spaces replaced by dashes.
+func folderTag(folder string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(folder)), " ", "-")
+}
+
+// parseServiceTime parses a service timestamp, RFC3339 or unix seconds, and
+// returns it as RFC3339 UTC. Unknown values return an empty string.
+func parseServiceTime(s string) string {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return ""
+	}
+
+	if sec, err := strconv.ParseInt(s, 10, 64); err == nil {
+		if sec <= 0 {
+			return ""
+		}
+		return time.Unix(sec, 0).UTC().Format(time.RFC3339)
+	}
+
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t.UTC().Format(time.RFC3339)
+	}
+
+	return ""
+}
+
+// bookmarkTime returns the time of a bookmark timestamp, stored as RFC3339
+// or in the database layout.
+func bookmarkTime(s string) (time.Time, bool) {
+	for _, layout := range []string{time.RFC3339, time.DateTime} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.UTC(), true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// csvHeader returns the lowercase columns of the first line of head.
+func csvHeader(head []byte) []string {
+	line, _, _ := bytes.Cut(trimHead(head), []byte("\n"))
+
+	cols, err := csv.NewReader(bytes.NewReader(line)).Read()
+	if err != nil {
+		return nil
+	}
+
+	for i := range cols {
+		cols[i] = strings.ToLower(strings.TrimSpace(cols[i]))
+	}
+
+	return cols
+}
+
+// hasColumns reports whether head starts with a CSV header holding all the
+// columns.
+func hasColumns(head []byte, columns ...string) bool {
+	header := csvHeader(head)
+	for _, c := range columns {
+		if !slices.Contains(header, c) {
+			return false
+		}
+	}
+
+	return len(header) > 0
+}
+
+// csvRecords reads the CSV rows as maps keyed by the lowercase header
+// columns, the header must hold a url column.
+func csvRecords(r io.Reader) ([]map[string]string, error) {
+	cr := csv.NewReader(r)
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading header: %w", err)
+	}
+
+	for i := range header {
+		header[i] = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(header[i], "\ufeff")))
+	}
+
+	if !slices.Contains(header, "url") {
+		return nil, fmt.Errorf("%w: missing required field 'url'", ErrInvalidHeader)
+	}
+
+	var rows []map[string]string
+
+	for line := 2; ; line++ {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		row := make(map[string]string, len(header))
+		for i, h := range header {
+			if i < len(record) {
+				row[h] = record[i]
+			}
+		}
+
+		if row["url"] == "" {
+			return nil, fmt.Errorf("line %d: %w", line, ErrURLMissing)
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,232 @@
+package bookio
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	_ "time/tzdata"
+
+	"github.com/mateconpizza/gm/pkg/bookmark"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+// serviceFields are the bookmark fields the services carry.
+type serviceFields struct {
+	URL, Title, Desc, Notes, Tags string
+	CreatedAt, UpdatedAt          string
+	Favorite, Private             bool
+}
+
+func toServiceFields(bs []*bookmark.Bookmark) []serviceFields {
+	out := make([]serviceFields, 0, len(bs))
+	for _, b := range bs {
+		out = append(out, serviceFields{
+			URL: b.URL, Title: b.Title, Desc: b.Desc, Notes: b.Notes, Tags: b.Tags,
+			CreatedAt: b.CreatedAt, UpdatedAt: b.UpdatedAt, Favorite: b.Favorite, Private: b.Private,
+		})
+	}
+
+	return out
+}
+
+func TestServiceImport(t *testing.T) {
+	t.Parallel()
+
+	const (
+		goURL    = "https://go.dev/"
+		goTitle  = "The Go Programming Language"
+		goDesc   = "Build simple, secure, scalable systems with Go"
+		laterURL = "https://example.org/later"
+	)
+
+	// the datastore strings are byte counted
+	const shaarliDesc = "Build simple, secure, scalable systems with Gö"
+
+	// notes are skipped
+	shaarliWant := []serviceFields{
+		{
+			URL: goURL, Title: goTitle, Desc: shaarliDesc, Tags: "go,programming,",
+			CreatedAt: "2023-01-15T10:30:00Z", UpdatedAt: "2023-02-01T09:00:00Z", Favorite: true,
+		},
+		{URL: laterURL, Title: "Read later", Tags: bookmark.DefaultTag, CreatedAt: "2024-06-01T08:00:00Z", Private: true},
+	}
+
+	tests := []struct {
+		file   string
+		format string
+		want   []serviceFields
+	}{
+		{
+			file:   "pinboard.json",
+			format: "pinboard",
+			want: []serviceFields{
+				{URL: goURL, Title: goTitle, Desc: goDesc, Tags: "go,programming,", CreatedAt: "2023-01-15T10:30:00Z"},
+				{URL: laterURL, Title: "Read later", Tags: "unread,", CreatedAt: "2024-06-01T08:00:00Z", Private: true},
+			},
+		},
+		{
+			file:   "linkding.json",
+			format: "linkding",
+			want: []serviceFields{
+				{
+					URL: goURL, Title: goTitle, Desc: goDesc, Notes: "Start with the tour", Tags: "go,programming,",
+					CreatedAt: "2023-01-15T10:30:00Z", UpdatedAt: "2023-02-01T09:00:00Z",
+				},
+				{
+					URL: laterURL, Title: "Read later", Tags: "unread,",
+					CreatedAt: "2024-06-01T08:00:00Z", UpdatedAt: "2024-06-01T08:00:00Z",
+				},
+			},
+		},
+		{
+			file:   "pocket.html",
+			format: "pocket",
+			want: []serviceFields{
+				{URL: laterURL, Title: "Read later", Tags: "unread,", CreatedAt: "2024-06-01T08:00:00Z"},
+				{URL: goURL, Title: goTitle, Tags: "go,programming,", CreatedAt: "2023-01-15T10:30:00Z"},
+			},
+		},
+		{
+			file:   "pocket.csv",
+			format: "pocket",
+			want: []serviceFields{
+				{URL: laterURL, Title: "Read later", Tags: "unread,", CreatedAt: "2024-06-01T08:00:00Z"},
+				{URL: goURL, Title: goTitle, Tags: "go,programming,", CreatedAt: "2023-01-15T10:30:00Z"},
+			},
+		},
+		{
+			file:   "raindrop.csv",
+			format: "raindrop",
+			want: []serviceFields{
+				{
+					URL: goURL, Title: goTitle, Desc: goDesc, Notes: "Start with the tour",
+					Tags: "dev-tools,go,programming,", CreatedAt: "2023-01-15T10:30:00Z", Favorite: true,
+				},
+				{URL: laterURL, Title: "Read later", Tags: bookmark.DefaultTag, CreatedAt: "2024-06-01T08:00:00Z"},
+			},
+		},
+		{
+			file:   "shaarli.json",
+			format: "shaarli",
+			want:   shaarliWant,
+		},
+		{
+			file:   "shaarli-datastore.php",
+			format: "shaarli",
+			want:   shaarliWant,
+		},
+		{
+			file:   "shaarli-legacy.php",
+			format: "shaarli",
+			want: []serviceFields{
+				{URL: goURL, Title: goTitle, Desc: shaarliDesc, Tags: "go,programming,", CreatedAt: "2023-01-15T10:30:00Z"},
+				{URL: laterURL, Title: "Read later", Tags: bookmark.DefaultTag, CreatedAt: "2024-06-01T08:00:00Z", Private: true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			t.Parallel()
+
+			f, err := os.Open(filepath.Join("testdata", tt.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+
+			// the format is detected from the content
+			format, bs, err := Import(f, tt.file, "")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if format.Name != tt.format {
+				t.Errorf("detected %q, want %q", format.Name, tt.format)
+			}
+
+			got := toServiceFields(bs)
+			if len(got) != len(tt.want) {
+				t.Fatalf("expected %d bookmarks, got %d: %+v", len(tt.want), len(got), got)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("bookmark %d:\n got %+v\nwant %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+// testServiceBookmarks returns the bookmarks written by the service golden
+// files.
+func testServiceBookmarks() []*bookmark.Bookmark {
+	return []*bookmark.Bookmark{
+		{
+			ID: 1, URL: "https://go.dev/", Title: "The Go Programming Language",
+			Desc: "Build simple, secure, scalable systems with Go", Notes: "Start with the tour",
+			Tags: "go,programming,", CreatedAt: "2023-01-15T10:30:00Z", UpdatedAt: "2023-02-01 09:00:00",
+			Favorite: true,
+		},
+		{
+			ID: 2, URL: "https://example.org/later?a=1&b=2", Title: `Read "later"`,
+			Tags: "unread,", CreatedAt: "2024-06-01 08:00:00",
+		},
+		{ID: 3, URL: "https://private.example/", Title: "Private", Tags: "secret,", Private: true},
+	}
+}
+
+func TestServiceExportGolden(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		format string
+		golden string
+	}{
+		{"pinboard", "pinboard.golden.json"},
+		{"linkding", "linkding.golden.json"},
+		{"pocket", "pocket.golden.html"},
+		{"raindrop", "raindrop.golden.csv"},
+		{"shaarli", "shaarli.golden.json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			if err := Export(&buf, tt.format, testServiceBookmarks(), nil); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			path := filepath.Join("testdata", tt.golden)
+			if *update {
+				if err := os.WriteFile(path, buf.Bytes(), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(buf.Bytes(), want) {
+				t.Errorf("output differs from %s:\n%s", path, buf.String())
+			}
+
+			// the export reads back the same bookmarks
+			_, bs, err := Import(bytes.NewReader(want), tt.golden, tt.format)
+			if err != nil {
+				t.Fatalf("reading back: %v", err)
+			}
+			if len(bs) != 2 {
+				t.Fatalf("expected 2 bookmarks, got %d", len(bs))
+			}
+			if bs[0].Tags != "go,programming," && bs[1].Tags != "go,programming," {
+				t.Errorf("tags not kept: %q, %q", bs[0].Tags, bs[1].Tags)
+			}
+		})
+	}
+}
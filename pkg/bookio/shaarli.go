@@ -0,0 +1,238 @@
+package bookio
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/mateconpizza/gm/pkg/bookmark"
+)
+
+// Shaarli datastore.php wraps the serialized bookmarks in a PHP comment.
+const (
+	shaarliPrefix = "<?php /* "
+	shaarliSuffix = " */ ?>"
+)
+
+// shaarliLink is a bookmark of the Shaarli API.
+type shaarliLink struct {
+	ID          int      `json:"id"`
+	URL         string   `json:"url"`
+	ShortURL    string   `json:"shorturl"`
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	Tags        []string `json:"tags"`
+	Private     bool     `json:"private"`
+	Sticky      bool     `json:"sticky"`
+	Created     string   `json:"created"`
+	Updated     string   `json:"updated"`
+}
+
+func (l *shaarliLink) bookmark() *bookmark.Bookmark {
+	tags := make([]string, 0, len(l.Tags))
+	for _, t := range l.Tags {
+		if t = strings.TrimSpace(t); t != "" {
+			tags = append(tags, t)
+		}
+	}
+
+	return &bookmark.Bookmark{
+		URL:       l.URL,
+		Title:     l.Title,
+		Desc:      l.Description,
+		Tags:      importTags(tags, false),
+		CreatedAt: parseServiceTime(l.Created),
+		UpdatedAt: parseServiceTime(l.Updated),
+		Private:   l.Private,
+		Favorite:  l.Sticky,
+	}
+}
+
+// isNote reports whether the link is a Shaarli note, which has no URL of
+// its own.
+func (l *shaarliLink) isNote() bool {
+	return l.URL == "" || strings.HasPrefix(l.URL, "?") || strings.HasPrefix(l.URL, "/")
+}
+
+// ImportFromShaarli reads the Shaarli bookmarks, either its datastore.php
+// file or the JSON API export. Private links are imported as private,
+// sticky ones as favorites. Notes are skipped.
+func ImportFromShaarli(r io.Reader) ([]*bookmark.Bookmark, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var links []*shaarliLink
+	if head := trimHead(data); bytes.HasPrefix(head, []byte(shaarliPrefix)) {
+		links, err = decodeShaarliDatastore(head)
+	} else {
+		err = json.Unmarshal(head, &links)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	bs := make([]*bookmark.Bookmark, 0, len(links))
+	for _, l := range links {
+		if l.isNote() {
+			continue
+		}
+		bs = append(bs, l.bookmark())
+	}
+
+	return bs, nil
+}
+
+// decodeShaarliDatastore decodes a datastore.php file, the base64 of the
+// deflated PHP serialization of the bookmarks.
+func decodeShaarliDatastore(data []byte) ([]*shaarliLink, error) {
+	s := strings.TrimSuffix(strings.TrimPrefix(string(data), shaarliPrefix), shaarliSuffix)
+
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return nil, fmt.Errorf("decoding datastore: %w", err)
+	}
+
+	serialized, err := io.ReadAll(flate.NewReader(bytes.NewReader(raw)))
+	if err != nil {
+		return nil, fmt.Errorf("inflating datastore: %w", err)
+	}
+
+	v, err := phpUnserialize(string(serialized))
+	if err != nil {
+		return nil, err
+	}
+
+	root, ok := v.(*phpArray)
+	if !ok {
+		return nil, fmt.Errorf("%w: unexpected datastore content", ErrPHPSerialized)
+	}
+
+	// since v0.10 the datastore holds a BookmarkArray object
+	if bs, ok := root.get("bookmarks").(*phpArray); ok && root.class != "" {
+		root = bs
+	}
+
+	var links []*shaarliLink
+	for _, e := range root.list() {
+		a, ok := e.(*phpArray)
+		if !ok {
+			continue
+		}
+
+		l := &shaarliLink{
+			URL:         a.str("url"),
+			Title:       a.str("title"),
+			Description: a.str("description"),
+			Private:     a.bool("private"),
+			Sticky:      a.bool("sticky"),
+			Created:     phpTime(a.get("created")),
+			Updated:     phpTime(a.get("updated")),
+		}
+		if l.Created == "" {
+			l.Created = phpTime(a.get("linkdate"))
+		}
+
+		switch t := a.get("tags").(type) {
+		case string:
+			l.Tags = strings.Fields(t)
+		case *phpArray:
+			for _, v := range t.list() {
+				if s, ok := v.(string); ok {
+					l.Tags = append(l.Tags, s)
+				}
+			}
+		}
+
+		links = append(links, l)
+	}
+
+	return links, nil
+}
+
+// phpTime returns a datastore timestamp as RFC3339, it is a serialized
+// DateTime or, in older versions, a "20060102_150405" link date.
+func phpTime(v any) string {
+	switch v := v.(type) {
+	case string:
+		t, err := time.Parse("20060102_150405", v)
+		if err != nil {
+			return ""
+		}
+		return t.UTC().Format(time.RFC3339)
+	case *phpArray:
+		loc := time.UTC
+		if tz := v.str("timezone"); tz != "" {
+			if l, err := time.LoadLocation(tz); err == nil {
+				loc = l
+			} else if t, err := time.Parse("-07:00", tz); err == nil {
+				loc = t.Location()
+			}
+		}
+
+		t, err := time.ParseInLocation("2006-01-02 15:04:05.999999", v.str("date"), loc)
+		if err != nil {
+			return ""
+		}
+		return t.UTC().Format(time.RFC3339)
+	}
+
+	return ""
+}
+
+// ExportToShaarli writes the bookmarks as a Shaarli API JSON export,
+// favorites as sticky links. Private bookmarks are skipped.
+func ExportToShaarli(w io.Writer, bs []*bookmark.Bookmark) error {
+	bs = bookmark.WithoutPrivate(bs)
+	links := make([]*shaarliLink, 0, len(bs))
+	for _, b := range bs {
+		tags, unread := serviceTags(b)
+		if unread {
+			tags = append(tags, UnreadTag)
+		}
+		if tags == nil {
+			tags = []string{}
+		}
+
+		l := &shaarliLink{
+			ID:          b.ID,
+			URL:         b.URL,
+			Title:       b.Title,
+			Description: b.Desc,
+			Tags:        tags,
+			Sticky:      b.Favorite,
+		}
+		if t, ok := bookmarkTime(b.CreatedAt); ok {
+			l.Created = t.Format(time.RFC3339)
+		}
+		l.Updated = l.Created
+		if t, ok := bookmarkTime(b.UpdatedAt); ok {
+			l.Updated = t.Format(time.RFC3339)
+		}
+
+		links = append(links, l)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+
+	return enc.Encode(links)
+}
+
+// sniffShaarli reports whether head starts a Shaarli datastore or API
+// export.
+func sniffShaarli(head []byte) bool {
+	head = trimHead(head)
+	if bytes.HasPrefix(head, []byte(shaarliPrefix)) {
+		return true
+	}
+
+	return len(head) > 0 && head[0] == '[' && bytes.Contains(head, []byte(`"shorturl"`))
+}
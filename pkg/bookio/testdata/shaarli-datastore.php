@@ -0,0 +1 @@
+<?php /* vVTRbtMwFN2nWH4E2thOOzbnaQO0F0Qn6N4mTU5iJaZJHNnOWJn6W/wAP8a103aDVJ0IEn2pc3Lv9fE5x1nwmHD8pRTCVOr2UutVLcxqv7gwRqwxj/mj5W85Pnl1spJrixPBCX/cWE5ZANNteXgDxYqTZMHZ/MhkzCn1U+dhgMpx4pusRz1gS23cjakwQDOORZoRvzwNL7sep9BbOtdaHkWFnubyPvLwWahxylXSPzLgvSwlutLo2ujCiLpWTYE+iqboRBFK6Cy05NJmRrVO6SZsC42XnapyZFXdVvINsjLrjP/PRCXSSiK7tk7WFn1TrkRXP3/4tl4mJ4ogBuvFABocF9ofkm4P2T6RwcmTlK7s6rQRCk6YhsbzXg+nstXaY6GfBDAzUjgJ0i38od/DeqlqubULzpQDEiQA2Rhh8YTQCZ0j2B1MJ2RKwi8oEHPsoPe7buSdW7fSM417MXd4qAOSHzqjWxldC6Nsz7xn07X5X7JhQAiRc6Ayjg2U3SzfPefQGnUftvHabbzYI2JIj8WQDmLInsVQPggflak2RVQBEXMgkJ7qZylytC84nD8oG+bpt2v3YlbI6KzMJuQ0uHM22h0Y9Dr0HgzJp+SQadSbxsaYxo6ZxobfDlAwsn4DGe0K/vAJ6i9Qo92RTwTo/bWzDjn54IZe0d3dh95+zn+0jv2LdcOL9YJvcNk2O4VVvk/q5hc= */ ?>
\ No newline at end of file
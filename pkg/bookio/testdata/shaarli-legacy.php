@@ -0,0 +1 @@
+<?php /* dZBBTsMwEEWvYnmNmnHSlmqyY9MNC1Sxr4bEciyc2LKdFoRyLS7AxbCzKESQlUd/Zv73G8ISPwKKHfISygqE2J0FVADAa8J97qVW1NFIXgcs75E/d5IdLXvyVnnqez0o9kiDGknNIxXy0ZtcZdMuRhewKJTdtPJSzLJA3srQeO2itkOWtsn2YdSmZUH3zsg7FmQz+vw2ZOjFSBbeQ5R9YFcdO3b8+sxract5faGYgjVCUg7IjR5e21lawUpxiYhUmCdSrSxzPzC8nm6LW9iDOMMB1u4hAPlJUstMSvRL/PIXvnyjzLWxXhW30X8Okez+golVsOX/FmCz0zR9Aw== */ ?>
\ No newline at end of file
@@ -47,6 +47,12 @@ type Bookmark struct {
 	VisitCount int  `db:"visit_count" json:"visit_count"`
 	Favorite   bool `db:"favorite"    json:"favorite"`
 
+	// Privacy
+	Private bool `db:"private" json:"private"` // URL, Desc and Notes stored encrypted
+
+	// Attribution
+	AddedBy string `db:"added_by" json:"added_by"` // Who added the bookmark to a shared repository
+
 	// Link health
 	HTTPStatusCode int    `db:"status_code" json:"status_code"`
 	HTTPStatusText string `db:"status_text" json:"status_text"` // OK, Not Found, etc
@@ -91,6 +97,8 @@ type BookmarkJSON struct {
 	HTTPStatusCode    int      `json:"status_code"`       // HTTP status code (200, 404, etc.)
 	HTTPStatusText    string   `json:"status_text"`       // OK, Not Found, etc
 	IsActive          bool     `json:"is_active"`         // true if the URL is active (200-299)
+
+	AddedBy string `json:"added_by,omitempty"` // Teammate who added the bookmark
 }
 
 func NewFromBuffer(buf []byte) (*Bookmark, error) {
@@ -141,34 +149,27 @@ func (b *Bookmark) Bytes() []byte {
 	return toBytes(b)
 }
 
-// Field returns the value of a field.
+// Field returns the value of a field, by column name or alias, see
+// FieldKey.
 func (b *Bookmark) Field(f string) (string, error) {
-	var s string
-
-	switch f {
-	case "id", "i", "1":
-		s = strconv.Itoa(b.ID)
-	case "url", "u", "2":
-		s = b.URL
-	case "title", "t", "3":
-		s = b.Title
-	case "tags", "T", "4":
-		s = b.Tags
-	case "desc", "d", "5":
-		s = b.Desc
-	case "notes", "n", "6":
-		s = b.Notes
-	case "domain", "dom", "7":
-		d, err := b.Domain()
-		if err != nil {
-			return "", err
-		}
-		return d, nil
-	default:
-		return "", fmt.Errorf("%w: %q", ErrBookmarkUnknownField, f)
+	key, err := FieldKey(f)
+	if err != nil {
+		return "", err
+	}
+
+	if key == FieldDomain {
+		return b.Domain()
 	}
 
-	return s, nil
+	v := reflect.ValueOf(b).Elem().FieldByIndex(fieldIndex[key])
+	switch v.Kind() {
+	case reflect.Int:
+		return strconv.Itoa(int(v.Int())), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	default:
+		return v.String(), nil
+	}
 }
 
 // Equals reports whether b and o have the same URL, Tags, Title and Desc.
@@ -292,6 +293,18 @@ func (b *Bookmark) String() string {
 	return fmt.Sprintf("%d - %s", b.ID, b.URL)
 }
 
+// WithoutPrivate returns the bookmarks not marked as private.
+func WithoutPrivate(bs []*Bookmark) []*Bookmark {
+	out := make([]*Bookmark, 0, len(bs))
+	for _, b := range bs {
+		if !b.Private {
+			out = append(out, b)
+		}
+	}
+
+	return out
+}
+
 func NewJSON() *BookmarkJSON {
 	return &BookmarkJSON{}
 }
@@ -0,0 +1,98 @@
+package bookmark
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// FieldDomain is the domain of the URL, selectable as a field but not
+// stored.
+const FieldDomain = "domain"
+
+// fieldIndex maps the column names to the fields of Bookmark.
+var fieldIndex = func() map[string][]int {
+	t := reflect.TypeFor[Bookmark]()
+	m := make(map[string][]int, t.NumField())
+	for i := range t.NumField() {
+		if tag := t.Field(i).Tag.Get("db"); tag != "" && tag != "-" {
+			m[tag] = t.Field(i).Index
+		}
+	}
+
+	return m
+}()
+
+// fieldAliases maps the short names of the fields to their column.
+var fieldAliases = map[string]string{
+	"i": "id", "1": "id",
+	"u": "url", "2": "url",
+	"t": "title", "3": "title",
+	"T": "tags", "4": "tags",
+	"d": "desc", "5": "desc",
+	"n": "notes", "6": "notes",
+	"dom": FieldDomain, "7": FieldDomain,
+
+	"status":   "status_code",
+	"code":     "status_code",
+	"visits":   "visit_count",
+	"created":  "created_at",
+	"updated":  "updated_at",
+	"visited":  "last_visit",
+	"checked":  "last_checked",
+	"archive":  "archive_url",
+	"archived": "archive_timestamp",
+	"fav":      "favorite",
+	"by":       "added_by",
+	"favicon":  "favicon_url",
+	"active":   "is_active",
+}
+
+// FieldKey returns the column name of the field f, given by its column
+// name, e.g. visit_count, or an alias, e.g. visits.
+func FieldKey(f string) (string, error) {
+	f = strings.TrimSpace(f)
+	if k, ok := fieldAliases[f]; ok {
+		return k, nil
+	}
+
+	f = strings.ToLower(f)
+	if k, ok := fieldAliases[f]; ok {
+		return k, nil
+	}
+	if _, ok := fieldIndex[f]; ok || f == FieldDomain {
+		return f, nil
+	}
+
+	return "", fmt.Errorf("%w: %q", ErrBookmarkUnknownField, f)
+}
+
+// CompareField compares the field key of a and b, numbers and booleans by
+// value and text ignoring case. The key must be valid, see FieldKey.
+func CompareField(a, b *Bookmark, key string) int {
+	if key == FieldDomain {
+		da, _ := a.Domain()
+		db, _ := b.Domain()
+		return cmp.Compare(da, db)
+	}
+
+	va := reflect.ValueOf(a).Elem().FieldByIndex(fieldIndex[key])
+	vb := reflect.ValueOf(b).Elem().FieldByIndex(fieldIndex[key])
+	switch va.Kind() {
+	case reflect.Int:
+		return cmp.Compare(va.Int(), vb.Int())
+	case reflect.Bool:
+		return cmp.Compare(boolInt(va.Bool()), boolInt(vb.Bool()))
+	default:
+		return cmp.Compare(strings.ToLower(va.String()), strings.ToLower(vb.String()))
+	}
+}
+
+func boolInt(b bool) int {
+	if b {
+		return 1
+	}
+
+	return 0
+}
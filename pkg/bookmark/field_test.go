@@ -0,0 +1,70 @@
+package bookmark
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBookmarkField(t *testing.T) {
+	t.Parallel()
+
+	b := testSingleBookmark()
+	b.ID = 7
+	b.VisitCount = 12
+	b.HTTPStatusCode = 404
+
+	tests := []struct {
+		field string
+		want  string
+	}{
+		{"id", "7"},
+		{"1", "7"},
+		{"t", "Title"},
+		{"T", "test,tag1,go"},
+		{"domain", "example.com"},
+		{"visits", "12"},
+		{"visit_count", "12"},
+		{"status", "404"},
+		{"created", "2023-01-01T12:00:00Z"},
+		{"Favorite", "true"},
+		{"private", "false"},
+	}
+
+	for _, tt := range tests {
+		got, err := b.Field(tt.field)
+		if err != nil {
+			t.Errorf("Field(%q): unexpected error: %v", tt.field, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Field(%q) = %q, want %q", tt.field, got, tt.want)
+		}
+	}
+
+	if _, err := b.Field("bogus"); !errors.Is(err, ErrBookmarkUnknownField) {
+		t.Errorf("expected ErrBookmarkUnknownField, got %v", err)
+	}
+}
+
+func TestCompareField(t *testing.T) {
+	t.Parallel()
+
+	a := &Bookmark{Title: "alpha", VisitCount: 10, Favorite: true}
+	b := &Bookmark{Title: "Beta", VisitCount: 9}
+
+	tests := []struct {
+		key  string
+		want int
+	}{
+		{"title", -1},
+		{"visit_count", 1},
+		{"favorite", 1},
+		{"desc", 0},
+	}
+
+	for _, tt := range tests {
+		if got := CompareField(a, b, tt.key); got != tt.want {
+			t.Errorf("CompareField(%q) = %d, want %d", tt.key, got, tt.want)
+		}
+	}
+}
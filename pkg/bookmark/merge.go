@@ -0,0 +1,140 @@
+package bookmark
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FieldDiff is a field that differs between two bookmark versions.
+type FieldDiff struct {
+	Field string
+	Old   string
+	New   string
+}
+
+// Diff returns the user-facing fields that differ between a and b.
+func Diff(a, b *Bookmark) []FieldDiff {
+	if a == nil {
+		a = New()
+	}
+	if b == nil {
+		b = New()
+	}
+
+	fields := []struct {
+		name     string
+		old, new string
+	}{
+		{"url", a.URL, b.URL},
+		{"title", a.Title, b.Title},
+		{"tags", ParseTags(a.Tags), ParseTags(b.Tags)},
+		{"desc", a.Desc, b.Desc},
+		{"notes", a.Notes, b.Notes},
+		{"favorite", strconv.FormatBool(a.Favorite), strconv.FormatBool(b.Favorite)},
+		{"archive_url", a.ArchiveURL, b.ArchiveURL},
+	}
+
+	var diffs []FieldDiff
+	for _, f := range fields {
+		if f.old != f.new {
+			diffs = append(diffs, FieldDiff{Field: f.name, Old: f.old, New: f.new})
+		}
+	}
+
+	return diffs
+}
+
+// Merge returns a copy of dst with src merged in. Tags are joined, empty
+// fields are filled from src and the title of the most recently updated
+// bookmark wins.
+func Merge(dst, src *Bookmark) *Bookmark {
+	b := dst.Copy()
+	b.Tags = joinTags(dst.Tags, src.Tags)
+
+	switch {
+	case src.Title == "":
+	case b.Title == "", newer(src, dst):
+		b.Title = src.Title
+	}
+
+	fill := func(dst *string, src string) {
+		if *dst == "" {
+			*dst = src
+		}
+	}
+	fill(&b.Desc, src.Desc)
+	fill(&b.Notes, src.Notes)
+	fill(&b.FaviconURL, src.FaviconURL)
+	fill(&b.ArchiveURL, src.ArchiveURL)
+	fill(&b.ArchiveTimestamp, src.ArchiveTimestamp)
+	fill(&b.AddedBy, src.AddedBy)
+
+	b.Favorite = dst.Favorite || src.Favorite
+
+	return b
+}
+
+// Overwrite returns a copy of dst with its user-editable content replaced by
+// src. Identity, timestamps and link health are kept.
+func Overwrite(dst, src *Bookmark) *Bookmark {
+	b := dst.Copy()
+	b.Title = src.Title
+	b.Desc = src.Desc
+	b.Tags = ParseTags(src.Tags)
+	b.Notes = src.Notes
+	b.Favorite = src.Favorite
+
+	if src.ArchiveURL != "" {
+		b.ArchiveURL = src.ArchiveURL
+		b.ArchiveTimestamp = src.ArchiveTimestamp
+	}
+
+	if src.AddedBy != "" {
+		b.AddedBy = src.AddedBy
+	}
+
+	return b
+}
+
+// joinTags returns the union of both sets of tags, dropping the default tag
+// when any other is present.
+func joinTags(a, b string) string {
+	var tags []string
+	for t := range strings.SplitSeq(ParseTags(a+","+b), ",") {
+		if t != "" && t != DefaultTag {
+			tags = append(tags, t)
+		}
+	}
+
+	return ParseTags(strings.Join(tags, ","))
+}
+
+// newer reports whether a was updated after b. A bookmark without a valid
+// timestamp is never newer.
+func newer(a, b *Bookmark) bool {
+	ta, ok := lastUpdate(a)
+	if !ok {
+		return false
+	}
+
+	tb, ok := lastUpdate(b)
+	if !ok {
+		return true
+	}
+
+	return ta.After(tb)
+}
+
+// lastUpdate returns the update time of b, falling back to its creation time.
+func lastUpdate(b *Bookmark) (time.Time, bool) {
+	for _, s := range []string{b.UpdatedAt, b.CreatedAt} {
+		for _, layout := range []string{time.RFC3339, time.DateTime} {
+			if t, err := time.Parse(layout, s); err == nil {
+				return t, true
+			}
+		}
+	}
+
+	return time.Time{}, false
+}
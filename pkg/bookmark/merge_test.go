@@ -0,0 +1,144 @@
+package bookmark
+
+import "testing"
+
+func TestMerge(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		dst, src  *Bookmark
+		wantTitle string
+		wantTags  string
+		wantDesc  string
+		wantNotes string
+	}{
+		{
+			name:      "union tags and fill empty fields",
+			dst:       &Bookmark{URL: "u", Title: "Go", Tags: "go,", Notes: "mine"},
+			src:       &Bookmark{URL: "u", Title: "", Tags: "dev,go", Desc: "desc", Notes: "theirs"},
+			wantTitle: "Go",
+			wantTags:  "dev,go,",
+			wantDesc:  "desc",
+			wantNotes: "mine",
+		},
+		{
+			name:      "newer title wins",
+			dst:       &Bookmark{URL: "u", Title: "old", Tags: "go,", UpdatedAt: "2024-01-01T00:00:00Z"},
+			src:       &Bookmark{URL: "u", Title: "new", Tags: "go,", UpdatedAt: "2025-01-01T00:00:00Z"},
+			wantTitle: "new",
+			wantTags:  "go,",
+		},
+		{
+			name:      "older title loses",
+			dst:       &Bookmark{URL: "u", Title: "current", Tags: "go,", UpdatedAt: "2025-01-01T00:00:00Z"},
+			src:       &Bookmark{URL: "u", Title: "stale", Tags: "go,", UpdatedAt: "2024-06-01 10:00:00"},
+			wantTitle: "current",
+			wantTags:  "go,",
+		},
+		{
+			name:      "title without timestamp fills empty one",
+			dst:       &Bookmark{URL: "u", Tags: "notag,"},
+			src:       &Bookmark{URL: "u", Title: "Go", Tags: "go"},
+			wantTitle: "Go",
+			wantTags:  "go,",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := Merge(tt.dst, tt.src)
+			if got == tt.dst {
+				t.Fatal("expected a copy of dst")
+			}
+			if got.Title != tt.wantTitle {
+				t.Errorf("title: got %q, want %q", got.Title, tt.wantTitle)
+			}
+			if got.Tags != tt.wantTags {
+				t.Errorf("tags: got %q, want %q", got.Tags, tt.wantTags)
+			}
+			if got.Desc != tt.wantDesc {
+				t.Errorf("desc: got %q, want %q", got.Desc, tt.wantDesc)
+			}
+			if got.Notes != tt.wantNotes {
+				t.Errorf("notes: got %q, want %q", got.Notes, tt.wantNotes)
+			}
+		})
+	}
+}
+
+func TestOverwrite(t *testing.T) {
+	t.Parallel()
+
+	dst := &Bookmark{ID: 7, URL: "u", Title: "old", Tags: "a,b,", Notes: "keep?", CreatedAt: "c", HTTPStatusCode: 200}
+	src := &Bookmark{URL: "u", Title: "new", Tags: "c", AddedBy: "bob"}
+
+	got := Overwrite(dst, src)
+	if got.ID != 7 || got.CreatedAt != "c" || got.HTTPStatusCode != 200 {
+		t.Errorf("identity and metadata not kept: %+v", got)
+	}
+	if got.Title != "new" || got.Tags != "c," || got.Notes != "" {
+		t.Errorf("content not replaced: title %q tags %q notes %q", got.Title, got.Tags, got.Notes)
+	}
+	if got.AddedBy != "bob" {
+		t.Errorf("attribution not replaced: %q", got.AddedBy)
+	}
+
+	diffs := Diff(dst, got)
+	want := []string{"title", "tags", "notes"}
+	if len(diffs) != len(want) {
+		t.Fatalf("expected %d diffs, got %+v", len(want), diffs)
+	}
+	for i, d := range diffs {
+		if d.Field != want[i] {
+			t.Errorf("diff %d: got %q, want %q", i, d.Field, want[i])
+		}
+	}
+}
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		a, b *Bookmark
+		want []string
+	}{
+		{
+			name: "equal",
+			a:    &Bookmark{URL: "u", Title: "t", Tags: "go,"},
+			b:    &Bookmark{URL: "u", Title: "t", Tags: "go"},
+			want: nil,
+		},
+		{
+			name: "title and tags",
+			a:    &Bookmark{URL: "u", Title: "t", Tags: "go,"},
+			b:    &Bookmark{URL: "u", Title: "T", Tags: "go,dev"},
+			want: []string{"title", "tags"},
+		},
+		{
+			name: "favorite",
+			a:    &Bookmark{URL: "u"},
+			b:    &Bookmark{URL: "u", Favorite: true},
+			want: []string{"favorite"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			diffs := Diff(tt.a, tt.b)
+			if len(diffs) != len(tt.want) {
+				t.Fatalf("expected %v, got %+v", tt.want, diffs)
+			}
+			for i, fd := range diffs {
+				if fd.Field != tt.want[i] {
+					t.Errorf("expected field %q, got %q", tt.want[i], fd.Field)
+				}
+			}
+		})
+	}
+}
@@ -46,24 +46,8 @@ func (r *SQLite) DeleteMany(ctx context.Context, ids []int) error {
 	slog.DebugContext(ctx, "delete many", "ids", ids)
 
 	return r.WithTx(ctx, func(tx *sqlx.Tx) error {
-		// Delete from bookmark_tags first (foreign key constraint)
-		q1, args1, err := sqlx.In("DELETE FROM bookmark_tags WHERE bookmark_id IN (?)", ids)
-		if err != nil {
-			return fmt.Errorf("preparing bookmark_tags delete: %w", err)
-		}
-		_, err = tx.ExecContext(ctx, q1, args1...)
-		if err != nil {
-			return fmt.Errorf("deleting from bookmark_tags: %w", err)
-		}
-
-		// Delete from bookmarks table
-		q2, args2, err := sqlx.In("DELETE FROM bookmarks WHERE id IN (?)", ids)
-		if err != nil {
-			return fmt.Errorf("preparing bookmarks delete: %w", err)
-		}
-		_, err = tx.ExecContext(ctx, q2, args2...)
-		if err != nil {
-			return fmt.Errorf("deleting from bookmarks: %w", err)
+		if err := deleteManyTx(ctx, tx, ids); err != nil {
+			return err
 		}
 
 		// Clean up orphaned tags
@@ -71,36 +55,140 @@ func (r *SQLite) DeleteMany(ctx context.Context, ids []int) error {
 	})
 }
 
+// deleteManyTx deletes multiple records and their tag associations inside a
+// transaction.
+func deleteManyTx(ctx context.Context, tx *sqlx.Tx, ids []int) error {
+	// Delete from bookmark_tags first (foreign key constraint)
+	q1, args1, err := sqlx.In("DELETE FROM bookmark_tags WHERE bookmark_id IN (?)", ids)
+	if err != nil {
+		return fmt.Errorf("preparing bookmark_tags delete: %w", err)
+	}
+	_, err = tx.ExecContext(ctx, q1, args1...)
+	if err != nil {
+		return fmt.Errorf("deleting from bookmark_tags: %w", err)
+	}
+
+	// Delete from bookmarks table
+	q2, args2, err := sqlx.In("DELETE FROM bookmarks WHERE id IN (?)", ids)
+	if err != nil {
+		return fmt.Errorf("preparing bookmarks delete: %w", err)
+	}
+	_, err = tx.ExecContext(ctx, q2, args2...)
+	if err != nil {
+		return fmt.Errorf("deleting from bookmarks: %w", err)
+	}
+
+	return nil
+}
+
 // UpdateOne updates an existing bookmark by ID (or URL).
 func (r *SQLite) UpdateOne(ctx context.Context, b *bookmark.Bookmark) error {
 	return r.WithTx(ctx, func(tx *sqlx.Tx) error {
-		// Generate checksum before saving
-		b.GenChecksum()
+		if err := r.updateOneTx(ctx, tx, b); err != nil {
+			return err
+		}
+
+		return r.cleanOrphanTagsTx(ctx, tx)
+	})
+}
 
-		// Update record
-		if err := r.updateRecordTx(ctx, tx, b); err != nil {
-			return fmt.Errorf("update record: %w", err)
+// UpdateMany updates existing bookmarks in a single transaction, none is
+// updated when one fails.
+func (r *SQLite) UpdateMany(ctx context.Context, bs []*bookmark.Bookmark) error {
+	return r.WithTx(ctx, func(tx *sqlx.Tx) error {
+		for _, b := range bs {
+			if err := r.updateOneTx(ctx, tx, b); err != nil {
+				return fmt.Errorf("bookmark %d: %w", b.ID, err)
+			}
 		}
 
-		// Remove old tag associations
-		if _, err := tx.ExecContext(ctx, "DELETE FROM bookmark_tags WHERE bookmark_id = ?", b.ID); err != nil {
-			return fmt.Errorf("clear tags: %w", err)
+		return r.cleanOrphanTagsTx(ctx, tx)
+	})
+}
+
+// InsertAndUpdate inserts fresh and updates the existing bookmarks in a
+// single transaction, nothing is saved when one fails.
+func (r *SQLite) InsertAndUpdate(ctx context.Context, fresh, updated []*bookmark.Bookmark) error {
+	return r.ApplyChanges(ctx, fresh, updated, nil)
+}
+
+// ApplyChanges inserts fresh, updates the existing bookmarks and deletes the
+// records with the given IDs in a single transaction, nothing is saved when
+// one fails.
+func (r *SQLite) ApplyChanges(ctx context.Context, fresh, updated []*bookmark.Bookmark, deleted []int) error {
+	sort.Slice(fresh, func(i, j int) bool {
+		return fresh[i].ID < fresh[j].ID
+	})
+
+	return r.WithTx(ctx, func(tx *sqlx.Tx) error {
+		for _, b := range fresh {
+			if _, err := r.insertIntoTx(ctx, tx, b); err != nil {
+				return err
+			}
 		}
 
-		// Re-associate tags
-		if err := r.associateTags(ctx, tx, b); err != nil {
-			return fmt.Errorf("associate tags: %w", err)
+		for _, b := range updated {
+			if err := r.updateOneTx(ctx, tx, b); err != nil {
+				return fmt.Errorf("updating %q: %w", b.URL, err)
+			}
+		}
+
+		if len(deleted) > 0 {
+			if err := deleteManyTx(ctx, tx, deleted); err != nil {
+				return err
+			}
 		}
 
 		return r.cleanOrphanTagsTx(ctx, tx)
 	})
 }
 
+// updateOneTx updates a bookmark and its tags inside a transaction.
+func (r *SQLite) updateOneTx(ctx context.Context, tx *sqlx.Tx, b *bookmark.Bookmark) error {
+	// Generate checksum before saving
+	b.GenChecksum()
+
+	// Update record
+	if err := r.updateRecordTx(ctx, tx, b); err != nil {
+		return fmt.Errorf("update record: %w", err)
+	}
+
+	// Remove old tag associations
+	if _, err := tx.ExecContext(ctx, "DELETE FROM bookmark_tags WHERE bookmark_id = ?", b.ID); err != nil {
+		return fmt.Errorf("clear tags: %w", err)
+	}
+
+	// Re-associate tags
+	if err := r.associateTags(ctx, tx, b); err != nil {
+		return fmt.Errorf("associate tags: %w", err)
+	}
+
+	return nil
+}
+
 // UpdateNotes updates the bookmak's notes.
 func (r *SQLite) UpdateNotes(ctx context.Context, bID int, notes string) error {
 	slog.DebugContext(ctx, "updating notes", "id", bID)
 
 	return r.WithTx(ctx, func(tx *sqlx.Tx) error {
+		var private bool
+		if err := tx.GetContext(ctx, &private, "SELECT private FROM bookmarks WHERE id = ?", bID); err != nil {
+			return fmt.Errorf("failed to read record (id=%d): %w", bID, err)
+		}
+
+		if private {
+			c := cipherFor(r.Fullpath())
+			if c == nil {
+				return fmt.Errorf("%w: bookmark id %d", ErrPrivateLocked, bID)
+			}
+
+			var err error
+			notes, err = sealField(c, notes)
+			if err != nil {
+				return err
+			}
+		}
+
 		_, err := tx.ExecContext(
 			ctx,
 			"UPDATE bookmarks SET notes = ? WHERE id = ?",
@@ -117,6 +205,11 @@ func (r *SQLite) UpdateNotes(ctx context.Context, bID int, notes string) error {
 func (r *SQLite) updateRecordTx(ctx context.Context, tx *sqlx.Tx, b *bookmark.Bookmark) error {
 	b.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
 
+	sb, err := r.seal(b)
+	if err != nil {
+		return err
+	}
+
 	query := `
 	UPDATE bookmarks
 	SET
@@ -136,11 +229,13 @@ func (r *SQLite) updateRecordTx(ctx context.Context, tx *sqlx.Tx, b *bookmark.Bo
 		last_checked = :last_checked,
 		status_code = :status_code,
 		status_text = :status_text,
-		is_active = :is_active
+		is_active = :is_active,
+		private = :private,
+		added_by = :added_by
 	WHERE id = :id OR url = :url
 	`
 
-	if _, err := tx.NamedExecContext(ctx, query, b); err != nil {
+	if _, err := tx.NamedExecContext(ctx, query, sb); err != nil {
 		return fmt.Errorf("%w", err)
 	}
 
@@ -173,6 +268,55 @@ func (r *SQLite) All(ctx context.Context) ([]*bookmark.Bookmark, error) {
 	return bs, nil
 }
 
+// Each calls fn with each bookmark, ordered by ID, reading one row at a
+// time instead of loading them all. Locked private bookmarks are skipped.
+// It stops at the first error returned by fn.
+func (r *SQLite) Each(ctx context.Context, fn func(*bookmark.Bookmark) error) error {
+	q := `
+    SELECT
+      b.*,
+      COALESCE(GROUP_CONCAT(t.name, ','), '') AS tags
+    FROM
+      bookmarks b
+      LEFT JOIN bookmark_tags bt ON b.id = bt.bookmark_id
+      LEFT JOIN tags t ON bt.tag_id = t.id
+    GROUP BY
+      b.id
+    ORDER BY
+      b.id ASC;`
+
+	rows, err := r.DB.QueryxContext(ctx, q)
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+	defer rows.Close()
+
+	c := cipherFor(r.Fullpath())
+	for rows.Next() {
+		b := &bookmark.Bookmark{}
+		if err := rows.StructScan(b); err != nil {
+			return fmt.Errorf("%w", err)
+		}
+		b.Tags = bookmark.ParseTags(b.Tags)
+
+		if b.Private {
+			if c == nil {
+				slog.DebugContext(ctx, "skipping locked private bookmark", "id", b.ID)
+				continue
+			}
+			if err := openFields(c, b); err != nil {
+				return fmt.Errorf("bookmark id %d: %w", b.ID, err)
+			}
+		}
+
+		if err := fn(b); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
 // ByID returns a record by its ID in the give table.
 func (r *SQLite) ByID(ctx context.Context, bID int) (*bookmark.Bookmark, error) {
 	if bID > r.MaxID(ctx) {
@@ -202,6 +346,10 @@ func (r *SQLite) ByID(ctx context.Context, bID int) (*bookmark.Bookmark, error)
 		return nil, fmt.Errorf("getting by ID: %w", err)
 	}
 
+	if err := r.revealOne(&b); err != nil {
+		return nil, err
+	}
+
 	b.Tags = bookmark.ParseTags(b.Tags)
 
 	return &b, nil
@@ -263,6 +411,10 @@ func (r *SQLite) ByURL(ctx context.Context, bURL string) (*bookmark.Bookmark, er
 		return nil, fmt.Errorf("ByURL %w: %w", ErrRecordScan, err)
 	}
 
+	if err := r.revealOne(&b); err != nil {
+		return nil, err
+	}
+
 	return &b, nil
 }
 
@@ -292,6 +444,44 @@ func (r *SQLite) ByTag(ctx context.Context, tag string) ([]*bookmark.Bookmark, e
 	return bs, nil
 }
 
+// RelatedTo returns the records sharing a tag with b or whose URL holds its
+// domain, b excluded.
+func (r *SQLite) RelatedTo(ctx context.Context, b *bookmark.Bookmark) ([]*bookmark.Bookmark, error) {
+	domain, _ := bookmark.Domain(b.URL)
+	tags := strings.FieldsFunc(b.Tags, func(r rune) bool { return r == ',' })
+	if domain == "" && len(tags) == 0 {
+		return nil, nil
+	}
+
+	// an empty list never matches, IN needs at least one value
+	tags = append(tags, "")
+	q, args, err := sqlx.In(`
+    SELECT
+      b.*,
+      COALESCE(GROUP_CONCAT(t.name, ','), '') AS tags
+    FROM
+      bookmarks b
+      LEFT JOIN bookmark_tags bt ON b.id = bt.bookmark_id
+      LEFT JOIN tags t ON bt.tag_id = t.id
+    WHERE
+      b.id != ? AND b.id IN (
+        SELECT bt.bookmark_id FROM bookmark_tags bt
+        JOIN tags t ON t.id = bt.tag_id
+        WHERE t.name IN (?)
+        UNION
+        SELECT id FROM bookmarks
+        WHERE ? != '' AND (LOWER(url) LIKE ? OR LOWER(url) LIKE ?)
+      )
+    GROUP BY
+      b.id
+    `, b.ID, tags, domain, "%://"+domain+"%", "%://www."+domain+"%")
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	return r.bySQL(ctx, r.DB.Rebind(q), args...)
+}
+
 // ByQuery returns records by query in the give table.
 func (r *SQLite) ByQuery(ctx context.Context, query string) ([]*bookmark.Bookmark, error) {
 	slog.InfoContext(ctx, "getting records by query", "query", query)
@@ -304,6 +494,7 @@ func (r *SQLite) ByQuery(ctx context.Context, query string) ([]*bookmark.Bookmar
 		LEFT JOIN bookmark_tags bt ON b.id = bt.bookmark_id
     LEFT JOIN tags t ON bt.tag_id = t.id
     WHERE
+        b.private = 0 AND
         (LOWER(b.id || b.title || b.url || b.desc || b.notes) LIKE LOWER(?) OR
         LOWER(t.name) LIKE LOWER(?))
       GROUP BY b.id
@@ -315,6 +506,18 @@ func (r *SQLite) ByQuery(ctx context.Context, query string) ([]*bookmark.Bookmar
 		return nil, err
 	}
 
+	// private fields are encrypted, match them after decrypting
+	pbs, err := r.privateByQuery(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if len(pbs) > 0 {
+		bs = append(bs, pbs...)
+		slices.SortFunc(bs, func(a, b *bookmark.Bookmark) int {
+			return cmp.Compare(a.ID, b.ID)
+		})
+	}
+
 	// FIX: remove
 	if len(bs) == 0 {
 		return nil, ErrRecordNoMatch
@@ -440,7 +643,7 @@ func (r *SQLite) ByOrder(ctx context.Context, column, sortBy string) ([]*bookmar
 		b.Tags = bookmark.ParseTags(b.Tags)
 	}
 
-	return bb, nil
+	return r.reveal(bb)
 }
 
 // Count returns the number of records in the given table.
@@ -474,7 +677,8 @@ func (r *SQLite) Has(ctx context.Context, bURL string) (*bookmark.Bookmark, bool
 	}
 
 	if !exists {
-		return nil, false
+		// private URLs are stored encrypted
+		return r.privateByURL(ctx, bURL)
 	}
 
 	item, err := r.ByURL(ctx, bURL)
@@ -501,7 +705,7 @@ func (r *SQLite) bySQL(ctx context.Context, q string, args ...any) ([]*bookmark.
 		b.Tags = bookmark.ParseTags(b.Tags)
 	}
 
-	return bb, nil
+	return r.reveal(bb)
 }
 
 // deleteOneTx deletes an single record in the given table.
@@ -568,11 +772,17 @@ func (r *SQLite) insertBulkPtr(ctx context.Context, bs []*bookmark.Bookmark) err
 
 // insertIntoTx inserts a record inside an existing transaction.
 func (r *SQLite) insertIntoTx(ctx context.Context, tx *sqlx.Tx, b *bookmark.Bookmark) (int64, error) {
+	sb, err := r.seal(b)
+	if err != nil {
+		return 0, err
+	}
+
 	// insert record and associate tags in the same transaction.
-	bID, err := insertRecord(ctx, tx, b)
+	bID, err := insertRecord(ctx, tx, sb)
 	if err != nil {
 		return 0, fmt.Errorf("%w: %s", err, b.URL)
 	}
+	b.ID, b.CreatedAt = sb.ID, sb.CreatedAt
 
 	if err := r.associateTags(ctx, tx, b); err != nil {
 		return 0, fmt.Errorf("failed to associate tags: %w", err)
@@ -589,7 +799,10 @@ func insertRecord(ctx context.Context, tx *sqlx.Tx, b *bookmark.Bookmark) (int64
 		return 0, ErrChecksumEmpty
 	}
 
-	b.CreatedAt = time.Now().UTC().Format(time.RFC3339)
+	// keep the original creation time of imported bookmarks
+	if b.CreatedAt == "" {
+		b.CreatedAt = time.Now().UTC().Format(time.RFC3339)
+	}
 
 	r, err := tx.NamedExecContext(
 		ctx,
@@ -610,7 +823,9 @@ func insertRecord(ctx context.Context, tx *sqlx.Tx, b *bookmark.Bookmark) (int64
 			archive_timestamp,
 			last_checked,
 			status_code,
-			status_text
+			status_text,
+			private,
+			added_by
 		)
 		VALUES (
 			:url,
@@ -629,7 +844,9 @@ func insertRecord(ctx context.Context, tx *sqlx.Tx, b *bookmark.Bookmark) (int64
 			:archive_timestamp,
 			:last_checked,
 			:status_code,
-			:status_text
+			:status_text,
+			:private,
+			:added_by
 	)`, b,
 	)
 	if err != nil {
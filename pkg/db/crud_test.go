@@ -157,6 +157,7 @@ func TestUpdateOne(t *testing.T) {
 	newB.Checksum = "new-checksum"
 	newB.Tags = "tagNew1,tagNew2,"
 	newB.Desc = "new description"
+	newB.AddedBy = "alice"
 	wantTags := len(extractTags(t, []*bookmark.Bookmark{&newB}))
 
 	// Keep old UpdatedAt for later comparison
@@ -192,6 +193,9 @@ func TestUpdateOne(t *testing.T) {
 	if updatedB.Tags != newB.Tags {
 		t.Errorf("expected tags %q, got %q", newB.Tags, updatedB.Tags)
 	}
+	if updatedB.AddedBy != newB.AddedBy {
+		t.Errorf("expected added by %q, got %q", newB.AddedBy, updatedB.AddedBy)
+	}
 	if updatedB.UpdatedAt == oldUpdatedAt {
 		t.Errorf("UpdatedAt should have changed: old=%v new=%v", oldUpdatedAt, updatedB.UpdatedAt)
 	}
@@ -213,6 +217,122 @@ func TestUpdateOne(t *testing.T) {
 	// }
 }
 
+func TestUpdateMany(t *testing.T) {
+	r := testPopulatedDB(t, 3)
+
+	bs, err := r.All(t.Context())
+	if err != nil {
+		t.Fatalf("failed to get all bookmarks: %v", err)
+	}
+
+	for _, b := range bs {
+		b.Tags = "bulk,"
+		b.Title = "bulk title"
+	}
+
+	if err := r.UpdateMany(t.Context(), bs); err != nil {
+		t.Fatalf("failed to update bookmarks: %v", err)
+	}
+
+	for _, b := range bs {
+		got, err := r.ByID(t.Context(), b.ID)
+		if err != nil {
+			t.Fatalf("failed to retrieve bookmark %d: %v", b.ID, err)
+		}
+		if got.Tags != "bulk," || got.Title != "bulk title" {
+			t.Errorf("bookmark %d not updated: tags=%q title=%q", b.ID, got.Tags, got.Title)
+		}
+	}
+
+	tags, err := r.TagsCounter(t.Context())
+	if err != nil {
+		t.Fatalf("unexpected error getting tags counter: %v", err)
+	}
+	if len(tags) != 1 {
+		t.Errorf("expected orphan tags to be removed, got %v", tags)
+	}
+}
+
+func TestUpdateManyRollback(t *testing.T) {
+	r := testPopulatedDB(t, 2)
+
+	bs, err := r.All(t.Context())
+	if err != nil {
+		t.Fatalf("failed to get all bookmarks: %v", err)
+	}
+
+	bs[0].Title = "changed"
+	bs[1].URL = bs[0].URL // violates the unique URL constraint
+
+	if err := r.UpdateMany(t.Context(), bs); err == nil {
+		t.Fatal("expected error updating duplicate URLs")
+	}
+
+	got, err := r.ByID(t.Context(), bs[0].ID)
+	if err != nil {
+		t.Fatalf("failed to retrieve bookmark: %v", err)
+	}
+	if got.Title == "changed" {
+		t.Error("expected update to be rolled back")
+	}
+}
+
+func TestInsertAndUpdateRollback(t *testing.T) {
+	r := testPopulatedDB(t, 2)
+
+	bs, err := r.All(t.Context())
+	if err != nil {
+		t.Fatalf("failed to get all bookmarks: %v", err)
+	}
+
+	fresh := testSingleBookmark()
+	fresh.URL = "https://fresh.example.com"
+	fresh.GenChecksum()
+
+	bs[0].Title = "changed"
+	bs[1].URL = bs[0].URL // violates the unique URL constraint
+
+	if err := r.InsertAndUpdate(t.Context(), []*bookmark.Bookmark{fresh}, bs); err == nil {
+		t.Fatal("expected error updating duplicate URLs")
+	}
+
+	if _, ok := r.Has(t.Context(), fresh.URL); ok {
+		t.Error("expected the insert to be rolled back")
+	}
+	got, err := r.ByID(t.Context(), bs[0].ID)
+	if err != nil {
+		t.Fatalf("failed to retrieve bookmark: %v", err)
+	}
+	if got.Title == "changed" {
+		t.Error("expected update to be rolled back")
+	}
+}
+
+func TestApplyChangesRollback(t *testing.T) {
+	r := testPopulatedDB(t, 3)
+	bs, err := r.All(t.Context())
+	if err != nil {
+		t.Fatalf("failed to get all bookmarks: %v", err)
+	}
+
+	bs[1].URL = bs[0].URL // violates the unique URL constraint
+
+	if err := r.ApplyChanges(t.Context(), nil, bs[1:2], []int{bs[2].ID}); err == nil {
+		t.Fatal("expected error updating duplicate URLs")
+	}
+
+	if _, err := r.ByID(t.Context(), bs[2].ID); err != nil {
+		t.Errorf("expected the delete to be rolled back: %v", err)
+	}
+
+	if err := r.ApplyChanges(t.Context(), nil, nil, []int{bs[2].ID}); err != nil {
+		t.Fatalf("ApplyChanges: %v", err)
+	}
+	if _, err := r.ByID(t.Context(), bs[2].ID); err == nil {
+		t.Error("expected the record to be deleted")
+	}
+}
+
 func TestAllRecords(t *testing.T) {
 	const want = 10
 	r := testPopulatedDB(t, want)
@@ -229,6 +349,45 @@ func TestAllRecords(t *testing.T) {
 	}
 }
 
+func TestEach(t *testing.T) {
+	const want = 10
+	r := testPopulatedDB(t, want)
+
+	all, err := r.All(t.Context())
+	if err != nil {
+		t.Fatalf("failed to get all bookmarks: %v", err)
+	}
+
+	var got []*bookmark.Bookmark
+	err = r.Each(t.Context(), func(b *bookmark.Bookmark) error {
+		got = append(got, b)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to iterate bookmarks: %v", err)
+	}
+
+	if len(got) != want {
+		t.Fatalf("expected %d records, got %d", want, len(got))
+	}
+	for i := range got {
+		if *got[i] != *all[i] {
+			t.Errorf("record %d differs from All:\n got %+v\nwant %+v", i, got[i], all[i])
+		}
+	}
+
+	// fn errors stop the iteration
+	errStop := errors.New("stop")
+	n := 0
+	err = r.Each(t.Context(), func(*bookmark.Bookmark) error {
+		n++
+		return errStop
+	})
+	if !errors.Is(err, errStop) || n != 1 {
+		t.Errorf("expected to stop after 1 record with errStop, got %d: %v", n, err)
+	}
+}
+
 func TestByID(t *testing.T) {
 	const want = 10
 	r := testPopulatedDB(t, want)
@@ -327,6 +486,43 @@ func TestByTag(t *testing.T) {
 	}
 }
 
+func TestRelatedTo(t *testing.T) {
+	r := setupTestDB(t)
+	ctx := t.Context()
+
+	bs := []*bookmark.Bookmark{
+		{URL: "https://go.dev/doc", Tags: "go,docs,"},
+		{URL: "https://example.com", Tags: "go,"},
+		{URL: "https://www.go.dev/blog", Tags: "blog,"},
+		{URL: "https://example.org", Tags: "misc,"},
+	}
+	for _, b := range bs {
+		b.Checksum = "checksum"
+		if _, err := r.InsertOne(ctx, b); err != nil {
+			t.Fatalf("failed to insert bookmark: %v", err)
+		}
+	}
+
+	b, err := r.ByURL(ctx, "https://go.dev/doc")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	related, err := r.RelatedTo(ctx, b)
+	if err != nil {
+		t.Fatalf("RelatedTo failed: %v", err)
+	}
+
+	var urls []string
+	for _, rb := range related {
+		urls = append(urls, rb.URL)
+	}
+	want := []string{"https://example.com", "https://www.go.dev/blog"}
+	if !slices.Equal(urls, want) {
+		t.Errorf("expected %v, got %v", want, urls)
+	}
+}
+
 func TestByQuery(t *testing.T) {
 	r := setupTestDB(t)
 	ctx := t.Context()
@@ -486,6 +682,8 @@ func TestInsertRecord(t *testing.T) {
 		preInsert  *bookmark.Bookmark // inserted first, to trigger constraint violations
 		wantErr    error
 		wantErrAny bool // true when we only care that some error occurred (DB constraint, etc.)
+
+		wantCreatedAt string // expected creation time, when preset
 	}{
 		{
 			name: "normal_insert",
@@ -540,6 +738,15 @@ func TestInsertRecord(t *testing.T) {
 				Checksum:   "checksum-max-visits",
 			},
 		},
+		{
+			name: "created_at_preserved",
+			b: &bookmark.Bookmark{
+				URL:       "https://www.example.com/imported",
+				CreatedAt: "2019-05-04T10:20:30Z",
+				Checksum:  "checksum-created-at",
+			},
+			wantCreatedAt: "2019-05-04T10:20:30Z",
+		},
 	}
 
 	for _, tt := range tests {
@@ -596,6 +803,9 @@ func TestInsertRecord(t *testing.T) {
 			if _, err := time.Parse(time.RFC3339, tt.b.CreatedAt); err != nil {
 				t.Fatalf("b.CreatedAt = %q; want RFC3339 timestamp: %v", tt.b.CreatedAt, err)
 			}
+			if tt.wantCreatedAt != "" && tt.b.CreatedAt != tt.wantCreatedAt {
+				t.Fatalf("b.CreatedAt = %q; want %q", tt.b.CreatedAt, tt.wantCreatedAt)
+			}
 		})
 	}
 }
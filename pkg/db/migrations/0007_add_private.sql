@@ -0,0 +1,8 @@
+-- migration: 0007_add_private
+-- description: add `private` flag. URL, description and notes of private
+-- bookmarks are stored encrypted by the application.
+
+ALTER TABLE bookmarks ADD COLUMN private BOOLEAN DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS idx_bookmarks_private
+ON bookmarks(private);
@@ -0,0 +1,8 @@
+-- migration: 0008_add_added_by
+-- description: add `added_by` to attribute bookmarks to the teammate who
+-- added them to a shared repository.
+
+ALTER TABLE bookmarks ADD COLUMN added_by TEXT DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_bookmarks_added_by
+ON bookmarks(added_by);
@@ -30,6 +30,11 @@ const (
 func (r *SQLite) ReorderIDs(ctx context.Context) error {
 	slog.DebugContext(ctx, "Reordering bookmark IDs")
 
+	// locked private records are not readable and would be lost
+	if r.hasLockedPrivate(ctx) {
+		return fmt.Errorf("reorder: %w", ErrPrivateLocked)
+	}
+
 	bs, err := r.All(ctx)
 	if err != nil && !errors.Is(err, ErrRecordNotFound) {
 		return err
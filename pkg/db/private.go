@@ -0,0 +1,295 @@
+package db
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+
+	"github.com/mateconpizza/gm/pkg/bookmark"
+)
+
+// MetaKeyPrivateKey holds the key derivation parameters and a verifier for
+// the private fields key.
+const MetaKeyPrivateKey MetaKey = "private_key"
+
+// sealedPrefix marks an encrypted field value.
+const sealedPrefix = "gmenc:"
+
+var (
+	ErrPrivateLocked  = errors.New("private bookmarks are locked")
+	ErrPrivateNoKey   = errors.New("private bookmarks key not set")
+	ErrPrivateCorrupt = errors.New("private field corrupted")
+)
+
+// FieldCipher encrypts and decrypts the private fields of a bookmark.
+type FieldCipher interface {
+	Seal(plaintext []byte) ([]byte, error)
+	Open(ciphertext []byte) ([]byte, error)
+}
+
+// ciphers holds the field ciphers registered per database path, so every
+// connection opened in the process shares the unlocked state.
+var ciphers = struct {
+	sync.RWMutex
+	m map[string]FieldCipher
+}{m: make(map[string]FieldCipher)}
+
+// RegisterCipher unlocks private bookmarks of the database at path for the
+// rest of the process.
+func RegisterCipher(path string, c FieldCipher) {
+	ciphers.Lock()
+	defer ciphers.Unlock()
+
+	ciphers.m[path] = c
+}
+
+// UnregisterCipher locks private bookmarks of the database at path again.
+func UnregisterCipher(path string) {
+	ciphers.Lock()
+	defer ciphers.Unlock()
+
+	delete(ciphers.m, path)
+}
+
+func cipherFor(path string) FieldCipher {
+	ciphers.RLock()
+	defer ciphers.RUnlock()
+
+	return ciphers.m[path]
+}
+
+// Locked reports whether the private fields of b are still encrypted.
+func Locked(b *bookmark.Bookmark) bool {
+	return b.Private && strings.HasPrefix(b.URL, sealedPrefix)
+}
+
+// PrivateField reports whether the column key holds an encrypted value on
+// locked bookmarks, the domain included since it comes from the URL.
+func PrivateField(key string) bool {
+	switch key {
+	case "url", "desc", "notes", bookmark.FieldDomain:
+		return true
+	}
+
+	return false
+}
+
+// PrivateUnlocked reports whether private bookmarks can be read and written.
+func (r *SQLite) PrivateUnlocked() bool {
+	return cipherFor(r.Fullpath()) != nil
+}
+
+// CountPrivate returns the number of private records.
+func (r *SQLite) CountPrivate(ctx context.Context) int {
+	var n int
+	if err := r.DB.QueryRowxContext(ctx, "SELECT COUNT(*) FROM bookmarks WHERE private = 1").Scan(&n); err != nil {
+		return 0
+	}
+
+	return n
+}
+
+// CheckMatchable returns ErrPrivateLocked when the database holds private
+// bookmarks that cannot be decrypted, their URLs cannot be matched by Has.
+func (r *SQLite) CheckMatchable(ctx context.Context) error {
+	if r.PrivateUnlocked() {
+		return nil
+	}
+
+	if n := r.CountPrivate(ctx); n > 0 {
+		return fmt.Errorf("%w: %d private bookmarks cannot be matched, use --private to unlock", ErrPrivateLocked, n)
+	}
+
+	return nil
+}
+
+// seal returns a copy of b with its private fields encrypted. Public
+// bookmarks are returned as is. The checksum of the copy is computed over
+// the encrypted fields, one of the plaintext would let anyone holding the
+// database confirm a guessed URL.
+func (r *SQLite) seal(b *bookmark.Bookmark) (*bookmark.Bookmark, error) {
+	if !b.Private {
+		return b, nil
+	}
+
+	c := cipherFor(r.Fullpath())
+	if c == nil {
+		return nil, fmt.Errorf("%w: %q", ErrPrivateLocked, r.Name())
+	}
+
+	sb := b.Copy()
+	for _, f := range []*string{&sb.URL, &sb.Desc, &sb.Notes} {
+		s, err := sealField(c, *f)
+		if err != nil {
+			return nil, err
+		}
+		*f = s
+	}
+	sb.GenChecksum()
+
+	return sb, nil
+}
+
+// reveal decrypts the private fields in place. Private bookmarks are dropped
+// from the result while locked.
+func (r *SQLite) reveal(bs []*bookmark.Bookmark) ([]*bookmark.Bookmark, error) {
+	c := cipherFor(r.Fullpath())
+
+	out := bs[:0]
+	for _, b := range bs {
+		if !b.Private {
+			out = append(out, b)
+			continue
+		}
+
+		if c == nil {
+			slog.Debug("skipping locked private bookmark", "id", b.ID)
+			continue
+		}
+
+		if err := openFields(c, b); err != nil {
+			return nil, fmt.Errorf("bookmark id %d: %w", b.ID, err)
+		}
+
+		out = append(out, b)
+	}
+
+	return out, nil
+}
+
+// revealOne decrypts a single bookmark, failing if it is private and locked.
+func (r *SQLite) revealOne(b *bookmark.Bookmark) error {
+	if !b.Private {
+		return nil
+	}
+
+	c := cipherFor(r.Fullpath())
+	if c == nil {
+		return fmt.Errorf("%w: bookmark id %d", ErrPrivateLocked, b.ID)
+	}
+
+	if err := openFields(c, b); err != nil {
+		return fmt.Errorf("bookmark id %d: %w", b.ID, err)
+	}
+
+	return nil
+}
+
+// privateByQuery returns the unlocked private bookmarks matching query.
+func (r *SQLite) privateByQuery(ctx context.Context, query string) ([]*bookmark.Bookmark, error) {
+	if !r.PrivateUnlocked() {
+		return nil, nil
+	}
+
+	bs, err := r.bySQL(ctx, `
+    SELECT
+      b.*,
+      COALESCE(GROUP_CONCAT(t.name, ','), '') AS tags
+    FROM bookmarks b
+    LEFT JOIN bookmark_tags bt ON b.id = bt.bookmark_id
+    LEFT JOIN tags t ON bt.tag_id = t.id
+    WHERE b.private = 1
+    GROUP BY b.id
+    ORDER BY b.id ASC;`)
+	if err != nil {
+		return nil, err
+	}
+
+	q := strings.ToLower(query)
+	out := make([]*bookmark.Bookmark, 0, len(bs))
+	for _, b := range bs {
+		s := fmt.Sprintf("%d%s%s%s%s%s", b.ID, b.Title, b.URL, b.Desc, b.Notes, b.Tags)
+		if strings.Contains(strings.ToLower(s), q) {
+			out = append(out, b)
+		}
+	}
+
+	return out, nil
+}
+
+// privateByURL looks up an unlocked private bookmark by its plaintext URL.
+func (r *SQLite) privateByURL(ctx context.Context, bURL string) (*bookmark.Bookmark, bool) {
+	if !r.PrivateUnlocked() {
+		return nil, false
+	}
+
+	bs, err := r.bySQL(ctx, `
+    SELECT
+      b.*,
+      COALESCE(GROUP_CONCAT(t.name, ','), '') AS tags
+    FROM bookmarks b
+    LEFT JOIN bookmark_tags bt ON b.id = bt.bookmark_id
+    LEFT JOIN tags t ON bt.tag_id = t.id
+    WHERE b.private = 1
+    GROUP BY b.id;`)
+	if err != nil {
+		slog.DebugContext(ctx, "private by url", "error", err)
+		return nil, false
+	}
+
+	for _, b := range bs {
+		if b.URL == bURL {
+			return b, true
+		}
+	}
+
+	return nil, false
+}
+
+// hasLockedPrivate reports whether the database holds private bookmarks that
+// cannot be decrypted.
+func (r *SQLite) hasLockedPrivate(ctx context.Context) bool {
+	return !r.PrivateUnlocked() && r.CountPrivate(ctx) > 0
+}
+
+func sealField(c FieldCipher, s string) (string, error) {
+	if s == "" {
+		return "", nil
+	}
+
+	ct, err := c.Seal([]byte(s))
+	if err != nil {
+		return "", fmt.Errorf("sealing field: %w", err)
+	}
+
+	return sealedPrefix + base64.StdEncoding.EncodeToString(ct), nil
+}
+
+func openField(c FieldCipher, s string) (string, error) {
+	if s == "" {
+		return "", nil
+	}
+
+	enc, ok := strings.CutPrefix(s, sealedPrefix)
+	if !ok {
+		return "", ErrPrivateCorrupt
+	}
+
+	ct, err := base64.StdEncoding.DecodeString(enc)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrPrivateCorrupt, err)
+	}
+
+	pt, err := c.Open(ct)
+	if err != nil {
+		return "", err
+	}
+
+	return string(pt), nil
+}
+
+func openFields(c FieldCipher, b *bookmark.Bookmark) error {
+	for _, f := range []*string{&b.URL, &b.Desc, &b.Notes} {
+		s, err := openField(c, *f)
+		if err != nil {
+			return err
+		}
+		*f = s
+	}
+
+	return nil
+}
@@ -0,0 +1,142 @@
+package db
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// testCipher is a reversible, non-cryptographic cipher for tests.
+type testCipher struct{}
+
+func (testCipher) Seal(p []byte) ([]byte, error) {
+	out := bytes.Clone(p)
+	slices.Reverse(out)
+	return append([]byte("sealed"), out...), nil
+}
+
+func (testCipher) Open(c []byte) ([]byte, error) {
+	out, ok := bytes.CutPrefix(c, []byte("sealed"))
+	if !ok {
+		return nil, errors.New("bad ciphertext")
+	}
+	out = bytes.Clone(out)
+	slices.Reverse(out)
+	return out, nil
+}
+
+func TestPrivateRecords(t *testing.T) {
+	r := testPopulatedDB(t, 3)
+	ctx := t.Context()
+
+	const secretURL = "https://intranet.example.com/?token=s3cret"
+
+	pb := testSingleBookmark()
+	pb.URL = secretURL
+	pb.Title = "Intranet"
+	pb.Notes = "vpn required"
+	pb.Private = true
+
+	if _, err := r.InsertOne(ctx, pb); !errors.Is(err, ErrPrivateLocked) {
+		t.Fatalf("expected ErrPrivateLocked inserting while locked, got %v", err)
+	}
+
+	RegisterCipher(r.Fullpath(), testCipher{})
+	t.Cleanup(func() { UnregisterCipher(r.Fullpath()) })
+
+	id, err := r.InsertOne(ctx, pb)
+	if err != nil {
+		t.Fatalf("insert private: %v", err)
+	}
+
+	t.Run("stored encrypted", func(t *testing.T) {
+		var url, notes string
+		row := r.DB.QueryRowxContext(ctx, "SELECT url, notes FROM bookmarks WHERE id = ?", id)
+		if err := row.Scan(&url, &notes); err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(url, "intranet") || strings.Contains(notes, "vpn") {
+			t.Errorf("private fields stored in plaintext: %q, %q", url, notes)
+		}
+		if !strings.HasPrefix(url, sealedPrefix) {
+			t.Errorf("expected sealed prefix, got %q", url)
+		}
+
+		var checksum string
+		if err := r.DB.GetContext(ctx, &checksum, "SELECT checksum FROM bookmarks WHERE id = ?", id); err != nil {
+			t.Fatal(err)
+		}
+		plain := pb.Copy()
+		plain.GenChecksum()
+		if checksum == "" || checksum == plain.Checksum {
+			t.Errorf("checksum of the plaintext stored: %q", checksum)
+		}
+	})
+
+	t.Run("unlocked", func(t *testing.T) {
+		b, err := r.ByID(ctx, int(id))
+		if err != nil {
+			t.Fatalf("ByID: %v", err)
+		}
+		if b.URL != secretURL || b.Notes != "vpn required" {
+			t.Errorf("unexpected decrypted record: %q, %q", b.URL, b.Notes)
+		}
+
+		bs, err := r.ByQuery(ctx, "vpn")
+		if err != nil {
+			t.Fatalf("ByQuery: %v", err)
+		}
+		if len(bs) != 1 || bs[0].ID != int(id) {
+			t.Errorf("expected private match, got %d records", len(bs))
+		}
+
+		if _, ok := r.Has(ctx, secretURL); !ok {
+			t.Error("expected Has to find private URL")
+		}
+		if err := r.CheckMatchable(ctx); err != nil {
+			t.Errorf("CheckMatchable: %v", err)
+		}
+
+		if err := r.UpdateNotes(ctx, int(id), "new notes"); err != nil {
+			t.Fatalf("UpdateNotes: %v", err)
+		}
+		var notes string
+		if err := r.DB.GetContext(ctx, &notes, "SELECT notes FROM bookmarks WHERE id = ?", id); err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(notes, "new notes") {
+			t.Error("notes updated in plaintext")
+		}
+	})
+
+	t.Run("locked", func(t *testing.T) {
+		UnregisterCipher(r.Fullpath())
+		defer RegisterCipher(r.Fullpath(), testCipher{})
+
+		bs, err := r.All(ctx)
+		if err != nil {
+			t.Fatalf("All: %v", err)
+		}
+		if len(bs) != 3 {
+			t.Errorf("expected private record hidden, got %d records", len(bs))
+		}
+
+		if _, err := r.ByID(ctx, int(id)); !errors.Is(err, ErrPrivateLocked) {
+			t.Errorf("expected ErrPrivateLocked, got %v", err)
+		}
+
+		if _, err := r.ByQuery(ctx, "intranet.example"); !errors.Is(err, ErrRecordNoMatch) {
+			t.Errorf("expected no match while locked, got %v", err)
+		}
+
+		if err := r.ReorderIDs(ctx); !errors.Is(err, ErrPrivateLocked) {
+			t.Errorf("expected reorder to refuse, got %v", err)
+		}
+
+		if err := r.CheckMatchable(ctx); !errors.Is(err, ErrPrivateLocked) {
+			t.Errorf("expected CheckMatchable to refuse, got %v", err)
+		}
+	})
+}
@@ -45,3 +45,30 @@ func (rs *RepoStats) String() string {
 func (r *SQLite) Stats(ctx context.Context, dest any) error {
 	return r.DB.GetContext(ctx, dest, `SELECT * FROM stats`)
 }
+
+// Contributors returns the number of bookmarks added by each teammate.
+func (r *SQLite) Contributors(ctx context.Context) (map[string]int, error) {
+	rows, err := r.DB.QueryxContext(ctx, `
+    SELECT added_by, COUNT(*)
+    FROM bookmarks
+    WHERE added_by != '' AND private = 0
+    GROUP BY added_by`)
+	if err != nil {
+		return nil, fmt.Errorf("contributors: %w", err)
+	}
+	defer rows.Close()
+
+	m := make(map[string]int)
+	for rows.Next() {
+		var (
+			name string
+			n    int
+		)
+		if err := rows.Scan(&name, &n); err != nil {
+			return nil, fmt.Errorf("contributors: %w", err)
+		}
+		m[name] = n
+	}
+
+	return m, rows.Err()
+}
@@ -0,0 +1,39 @@
+package db
+
+import "testing"
+
+func TestContributors(t *testing.T) {
+	t.Parallel()
+
+	r := setupTestDB(t)
+	ctx := t.Context()
+
+	bs := testSliceBookmarks(5)
+	for i, b := range bs {
+		switch {
+		case i < 3:
+			b.AddedBy = "alice"
+		case i == 3:
+			b.AddedBy = "bob"
+		}
+	}
+
+	if err := r.InsertMany(ctx, bs); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+
+	got, err := r.Contributors(ctx)
+	if err != nil {
+		t.Fatalf("contributors: %v", err)
+	}
+
+	want := map[string]int{"alice": 3, "bob": 1}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for name, n := range want {
+		if got[name] != n {
+			t.Errorf("%s: expected %d, got %d", name, n, got[name])
+		}
+	}
+}
@@ -154,6 +154,16 @@ func Remote(ctx context.Context, repoPath string) (string, error) {
 	return runWithOutput(ctx, repoPath, "config", "--get", "remote.origin.url")
 }
 
+// UserName returns the configured git user.name, empty if not set.
+func UserName(ctx context.Context, repoPath string) string {
+	name, err := runWithOutput(ctx, repoPath, "config", "--get", "user.name")
+	if err != nil {
+		return ""
+	}
+
+	return name
+}
+
 // IsInitialized checks if the repo is initialized.
 func IsInitialized(repoPath string) bool {
 	return fileExists(filepath.Join(repoPath, ".git"))
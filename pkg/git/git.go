@@ -53,6 +53,20 @@ func (g *Git) HasChanges(ctx context.Context) (bool, error) { return HasChanges(
 func (g *Git) AddAll(ctx context.Context) error             { return g.run(ctx, g.fullpath, "add", ".") }
 func (g *Git) Push(ctx context.Context) error               { return g.doPush(ctx) }
 
+// Pull fetches and rebases local commits onto the upstream branch.
+func (g *Git) Pull(ctx context.Context) error {
+	if err := HasUpstream(ctx, g.fullpath); err != nil {
+		return err
+	}
+	if err := g.run(ctx, g.fullpath, "pull", "--rebase", "--autostash"); err != nil {
+		// leave the repository as it was on conflicts
+		_ = runWithWriter(ctx, io.Discard, g.fullpath, "rebase", "--abort")
+		return err
+	}
+
+	return nil
+}
+
 func (g *Git) Commit(ctx context.Context, msg string) error {
 	return g.run(ctx, g.fullpath, "commit", "-m", msg)
 }
@@ -0,0 +1,191 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrGitRevNotFound = errors.New("git: revision not found")
+
+// separators used in the log format.
+const (
+	logRecordSep = "\x1e"
+	logFieldSep  = "\x1f"
+)
+
+// FileChange is a file added, modified or deleted by a commit.
+type FileChange struct {
+	Status string // A, M or D
+	Path   string // relative to the repository root
+}
+
+// Commit is an entry of the repository history.
+type Commit struct {
+	Hash    string
+	Parent  string // first parent, empty for the root commit
+	Author  string
+	Date    time.Time
+	Subject string
+	Changes []FileChange
+}
+
+// Short returns the abbreviated commit hash.
+func (c *Commit) Short() string {
+	if len(c.Hash) > 7 {
+		return c.Hash[:7]
+	}
+	return c.Hash
+}
+
+// Log returns the commits touching the given paths, newest first.
+func (g *Git) Log(ctx context.Context, paths ...string) ([]*Commit, error) {
+	args := []string{
+		"log",
+		"-z",
+		"--no-renames",
+		"--name-status",
+		"--format=" + logRecordSep + "%H" + logFieldSep + "%P" + logFieldSep + "%an" +
+			logFieldSep + "%at" + logFieldSep + "%s",
+		"--",
+	}
+
+	out, err := runBytes(ctx, g.fullpath, append(args, paths...)...)
+	if err != nil {
+		return nil, fmt.Errorf("git log: %w", err)
+	}
+
+	return parseLog(string(out))
+}
+
+// Show returns the content of path at revision rev.
+func (g *Git) Show(ctx context.Context, rev, path string) ([]byte, error) {
+	return runBytes(ctx, g.fullpath, "show", rev+":"+filepath.ToSlash(path))
+}
+
+// ListFiles returns the files under dir at revision rev.
+func (g *Git) ListFiles(ctx context.Context, rev, dir string) ([]string, error) {
+	out, err := runBytes(ctx, g.fullpath, "ls-tree", "-r", "-z", "--name-only", rev, "--", filepath.ToSlash(dir))
+	if err != nil {
+		return nil, fmt.Errorf("git ls-tree: %w", err)
+	}
+
+	return splitNUL(string(out)), nil
+}
+
+// FileAuthors maps each file ever added under dir to the author of the
+// commit that first added it.
+func (g *Git) FileAuthors(ctx context.Context, dir string) (map[string]string, error) {
+	out, err := runBytes(ctx, g.fullpath,
+		"log", "-z", "--no-renames", "--diff-filter=A", "--name-only",
+		"--format="+logRecordSep+"%an", "--", filepath.ToSlash(dir))
+	if err != nil {
+		return nil, fmt.Errorf("git log: %w", err)
+	}
+
+	// newest first, the oldest addition wins
+	authors := make(map[string]string)
+	for rec := range strings.SplitSeq(string(out), logRecordSep) {
+		author, body, _ := strings.Cut(rec, "\x00")
+		for _, path := range splitNUL(strings.TrimPrefix(body, "\n")) {
+			authors[path] = author
+		}
+	}
+
+	return authors, nil
+}
+
+// DiffNames returns the files under dir changed between revisions from and to.
+func (g *Git) DiffNames(ctx context.Context, from, to, dir string) ([]FileChange, error) {
+	out, err := runBytes(ctx, g.fullpath,
+		"diff", "-z", "--no-renames", "--name-status", from, to, "--", filepath.ToSlash(dir))
+	if err != nil {
+		return nil, fmt.Errorf("git diff: %w", err)
+	}
+
+	return parseNameStatus(string(out)), nil
+}
+
+// ResolveRev returns the full commit hash for rev.
+func (g *Git) ResolveRev(ctx context.Context, rev string) (string, error) {
+	out, err := runBytes(ctx, g.fullpath, "rev-parse", "--verify", "--quiet", rev+"^{commit}")
+	if err != nil {
+		return "", fmt.Errorf("%w: %q", ErrGitRevNotFound, rev)
+	}
+
+	return strings.TrimSpace(string(out)), nil
+}
+
+func parseLog(s string) ([]*Commit, error) {
+	var commits []*Commit
+	for rec := range strings.SplitSeq(s, logRecordSep) {
+		if rec == "" {
+			continue
+		}
+
+		header, body, _ := strings.Cut(rec, "\x00")
+		fields := strings.SplitN(header, logFieldSep, 5)
+		if len(fields) != 5 {
+			return nil, fmt.Errorf("git log: malformed record %q", header)
+		}
+
+		ts, err := strconv.ParseInt(fields[3], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("git log: parse timestamp %q: %w", fields[3], err)
+		}
+
+		parent, _, _ := strings.Cut(fields[1], " ")
+		c := &Commit{
+			Hash:    fields[0],
+			Parent:  parent,
+			Author:  fields[2],
+			Date:    time.Unix(ts, 0),
+			Subject: fields[4],
+		}
+
+		c.Changes = parseNameStatus(strings.TrimPrefix(body, "\n"))
+		commits = append(commits, c)
+	}
+
+	return commits, nil
+}
+
+// parseNameStatus parses the output of --name-status -z, status and path
+// pairs separated by NUL.
+func parseNameStatus(s string) []FileChange {
+	fields := splitNUL(s)
+	changes := make([]FileChange, 0, len(fields)/2)
+	for i := 0; i+1 < len(fields); i += 2 {
+		changes = append(changes, FileChange{Status: fields[i][:1], Path: fields[i+1]})
+	}
+
+	return changes
+}
+
+// splitNUL splits the output of a -z command, paths may hold any other
+// character.
+func splitNUL(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool { return r == 0 })
+}
+
+// runBytes executes a git command and returns its standard output untouched.
+func runBytes(ctx context.Context, repoPath string, args ...string) ([]byte, error) {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, command, args...)
+	cmd.Dir = repoPath
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		//nolint:err113 //dynamic error is fine for command output
+		return nil, fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return out, nil
+}
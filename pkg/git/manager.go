@@ -17,6 +17,7 @@ type MgrOptFunc func(*MgrOptions)
 type MgrOptions struct {
 	g       *Git
 	version string
+	author  string
 }
 
 func WithGit(g *Git) MgrOptFunc {
@@ -31,6 +32,14 @@ func WithVersion(ver string) MgrOptFunc {
 	}
 }
 
+// WithAuthor sets the name recorded in the contributors index. Defaults to
+// the git user.name.
+func WithAuthor(name string) MgrOptFunc {
+	return func(mo *MgrOptions) {
+		mo.author = name
+	}
+}
+
 type Mgr struct {
 	root  string
 	track *Tracker
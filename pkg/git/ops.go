@@ -109,6 +109,20 @@ func saveChanges(ctx context.Context, m *Mgr, gr *Repo, ver, msg string) error {
 		return err
 	}
 
+	prev, err := gr.Summary()
+	if err != nil {
+		return err
+	}
+
+	author := m.author
+	if author == "" {
+		author = UserName(ctx, m.Root())
+	}
+
+	if err := updateContributors(ctx, sum, prev, gr.DB(), author); err != nil {
+		return err
+	}
+
 	if err := gr.WriteSummary(sum); err != nil {
 		return err
 	}
@@ -22,6 +22,12 @@ type RepoDB interface {
 	Stats(ctx context.Context, dest any) error
 }
 
+// ContributorsDB is implemented by stores that attribute bookmarks to
+// teammates.
+type ContributorsDB interface {
+	Contributors(ctx context.Context) (map[string]int, error)
+}
+
 type (
 	ReaderFunc      func(ctx context.Context, path string, total int) ([]*bookmark.Bookmark, error)
 	WriterFunc      func(ctx context.Context, path string, bs []*bookmark.Bookmark) error
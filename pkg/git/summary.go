@@ -37,6 +37,16 @@ type Summary struct {
 	RepoStats          *RepoStats  `json:"stats"`               // RepoStats contains statistics for the repository.
 	ClientInfo         *ClientInfo `json:"client_info"`         // ClientInfo contains details about the client.
 	Checksum           string      `json:"checksum"`            // Checksum is the summary's generated checksum.
+
+	// Contributors indexes every teammate pushing to a shared repository.
+	Contributors map[string]*Contributor `json:"contributors,omitempty"`
+}
+
+// Contributor holds the attribution details of a teammate.
+type Contributor struct {
+	Bookmarks  int         `json:"bookmarks"`   // Bookmarks added by the teammate.
+	LastSync   string      `json:"last_sync"`   // LastSync is the timestamp of the teammate's last sync.
+	ClientInfo *ClientInfo `json:"client_info"` // ClientInfo of the teammate's last sync.
 }
 
 func NewSummary() *Summary {
@@ -174,3 +184,48 @@ func summaryComplete(ctx context.Context, g *Git, s *RepoStats, ver string) (*Su
 
 	return summary, nil
 }
+
+// updateContributors carries the contributors index of prev over to s,
+// refreshing the bookmark counts from db and the client info of author.
+func updateContributors(ctx context.Context, s, prev *Summary, db RepoDB, author string) error {
+	s.Contributors = make(map[string]*Contributor)
+	if prev != nil {
+		for name, c := range prev.Contributors {
+			cp := *c
+			cp.Bookmarks = 0
+			s.Contributors[name] = &cp
+		}
+	}
+
+	if cdb, ok := db.(ContributorsDB); ok {
+		counts, err := cdb.Contributors(ctx)
+		if err != nil {
+			return err
+		}
+
+		for name, n := range counts {
+			c, ok := s.Contributors[name]
+			if !ok {
+				c = &Contributor{}
+				s.Contributors[name] = c
+			}
+			c.Bookmarks = n
+		}
+	}
+
+	if author != "" {
+		c, ok := s.Contributors[author]
+		if !ok {
+			c = &Contributor{}
+			s.Contributors[author] = c
+		}
+		c.LastSync = s.LastSync
+		c.ClientInfo = s.ClientInfo
+	}
+
+	if len(s.Contributors) == 0 {
+		s.Contributors = nil
+	}
+
+	return nil
+}